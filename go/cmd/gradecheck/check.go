@@ -0,0 +1,153 @@
+package main
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/legosandorigami/opentelemetry-tutorial/go/lib/testutil"
+)
+
+// result is the outcome of a check
+type result struct {
+	// check describes what was checked
+	check string
+	// err tells why the check failed, nil if it passed
+	err error
+}
+
+// findSpan returns the first span named name sent by service, nil if there is none
+func findSpan(spans []testutil.ReceivedSpan, service, name string) *testutil.ReceivedSpan {
+	for i := range spans {
+		if spans[i].Service() == service && spans[i].GetName() == name {
+			return &spans[i]
+		}
+	}
+	return nil
+}
+
+// allReceived tells whether every expected span has been received
+func allReceived(want []wantSpan, spans []testutil.ReceivedSpan) bool {
+	for _, w := range want {
+		if findSpan(spans, w.service, w.name) == nil {
+			return false
+		}
+	}
+	return true
+}
+
+// check checks the received spans against the expected ones, returning the result of every check
+func check(want []wantSpan, spans []testutil.ReceivedSpan) []result {
+	var results []result
+	for _, w := range want {
+		span := findSpan(spans, w.service, w.name)
+		results = append(results, result{
+			check: fmt.Sprintf("%s sends a %q span", w.service, w.name),
+			err:   checkFound(span, spans, w.service),
+		})
+		if span == nil {
+			continue
+		}
+
+		// checking the parent, which must be in the same trace
+		if w.parent == "" {
+			results = append(results, result{check: fmt.Sprintf("%q is the root of the trace", w.name), err: checkRoot(span)})
+		} else {
+			results = append(results, result{
+				check: fmt.Sprintf("%q is a child of %q", w.name, w.parent),
+				err:   checkParent(span, spans, w.parent),
+			})
+		}
+
+		for k, v := range w.attributes {
+			results = append(results, result{
+				check: fmt.Sprintf("%q has the attribute %s=%q", w.name, k, v),
+				err:   checkAttribute(span, k, v),
+			})
+		}
+
+		if w.event != "" {
+			results = append(results, result{
+				check: fmt.Sprintf("%q has an event with %q", w.name, w.event),
+				err:   checkEvent(span, w.event),
+			})
+		}
+	}
+	return results
+}
+
+// checkFound fails if span is nil, listing the spans received from service
+func checkFound(span *testutil.ReceivedSpan, spans []testutil.ReceivedSpan, service string) error {
+	if span != nil {
+		return nil
+	}
+	var names []string
+	for _, s := range spans {
+		if s.Service() == service {
+			names = append(names, fmt.Sprintf("%q", s.GetName()))
+		}
+	}
+	if len(names) == 0 {
+		return fmt.Errorf("no span received from %s, is the TracerProvider initialized, and shut down before exiting?", service)
+	}
+	return fmt.Errorf("received %s from %s", strings.Join(names, ", "), service)
+}
+
+// checkRoot fails if span has a parent
+func checkRoot(span *testutil.ReceivedSpan) error {
+	if span.ParentSpanID() != "" {
+		return fmt.Errorf("it has the parent %s", span.ParentSpanID())
+	}
+	return nil
+}
+
+// checkParent fails if the parent of span is not a span named parent of the same trace
+func checkParent(span *testutil.ReceivedSpan, spans []testutil.ReceivedSpan, parent string) error {
+	if span.ParentSpanID() == "" {
+		return fmt.Errorf("it is the root of its trace, is the context of %q passed along?", parent)
+	}
+	for _, s := range spans {
+		if s.SpanID() != span.ParentSpanID() {
+			continue
+		}
+		if s.TraceID() != span.TraceID() {
+			return fmt.Errorf("its parent is in the trace %s, not %s", s.TraceID(), span.TraceID())
+		}
+		if s.GetName() != parent {
+			return fmt.Errorf("its parent is %q", s.GetName())
+		}
+		return nil
+	}
+	return fmt.Errorf("its parent %s was not received", span.ParentSpanID())
+}
+
+// checkAttribute fails if span has no attribute key with the value value
+func checkAttribute(span *testutil.ReceivedSpan, key, value string) error {
+	for _, kv := range span.GetAttributes() {
+		if kv.GetKey() != key {
+			continue
+		}
+		if got := kv.GetValue().GetStringValue(); got != value {
+			return fmt.Errorf("its value is %q", got)
+		}
+		return nil
+	}
+	return fmt.Errorf("the attribute is missing")
+}
+
+// checkEvent fails if no event of span has an attribute whose value contains substr
+func checkEvent(span *testutil.ReceivedSpan, substr string) error {
+	var values []string
+	for _, e := range span.GetEvents() {
+		for _, kv := range e.GetAttributes() {
+			v := kv.GetValue().GetStringValue()
+			if strings.Contains(v, substr) {
+				return nil
+			}
+			values = append(values, fmt.Sprintf("%q", v))
+		}
+	}
+	if len(values) == 0 {
+		return fmt.Errorf("it has no event with attributes")
+	}
+	return fmt.Errorf("its events hold %s", strings.Join(values, ", "))
+}
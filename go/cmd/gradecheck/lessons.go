@@ -0,0 +1,84 @@
+package main
+
+// lesson describes how to run the programs of a lesson, and the spans they must send
+type lesson struct {
+	// title is the title of the lesson
+	title string
+	// services are the directories of the servers, relative to the directory of the exercise or of the solution
+	services []string
+	// client is the directory of the client, "." when the program is the directory itself
+	client string
+	// args are the arguments of the client
+	args []string
+	// config tells whether the programs read their addresses from the environment through the config package. The
+	// others listen on the default ports and export to the default endpoint, localhost:4318.
+	config bool
+	// spans are the spans the programs must send
+	spans []wantSpan
+}
+
+// wantSpan describes a span expected from a lesson
+type wantSpan struct {
+	// service is the service.name of the resource of the span
+	service string
+	// name is the name of the span
+	name string
+	// parent is the name of the parent span, empty for a root span
+	parent string
+	// attributes are the attributes the span must hold, with their values
+	attributes map[string]string
+	// event is a substring of the value of an attribute of one of the events of the span, empty for none
+	event string
+}
+
+// HELLO_TO is the name greeted by the clients
+const HELLO_TO = "Bryan"
+
+var lessons = map[string]lesson{
+	"01": {
+		title:  "Hello World",
+		client: ".",
+		args:   []string{HELLO_TO},
+		spans: []wantSpan{
+			{service: "hello-world", name: "say-hello", attributes: map[string]string{"hello-to": HELLO_TO}, event: "Hello, " + HELLO_TO + "!"},
+		},
+	},
+	"02": {
+		title:  "Context and Tracing Functions",
+		client: ".",
+		args:   []string{HELLO_TO},
+		spans: []wantSpan{
+			{service: "hello-world", name: "say-hello", attributes: map[string]string{"hello-to": HELLO_TO}},
+			{service: "hello-world", name: "formatString", parent: "say-hello", event: "Hello, " + HELLO_TO + "!"},
+			{service: "hello-world", name: "printHello", parent: "say-hello", event: "Hello, " + HELLO_TO + "!"},
+		},
+	},
+	"03": {
+		title:    "Tracing RPC Requests",
+		services: []string{"formatter", "publisher"},
+		client:   "client",
+		args:     []string{HELLO_TO},
+		spans: []wantSpan{
+			{service: "hello-world", name: "say-hello", attributes: map[string]string{"hello-to": HELLO_TO}},
+			{service: "hello-world", name: "formatString", parent: "say-hello", attributes: map[string]string{"http.method": "GET"}},
+			{service: "formatter", name: "format", parent: "formatString", event: "Hello, " + HELLO_TO + "!"},
+			{service: "hello-world", name: "printHello", parent: "say-hello", attributes: map[string]string{"http.method": "GET"}},
+			{service: "publisher", name: "publish", parent: "printHello"},
+		},
+	},
+	"04": {
+		title:    "Baggage",
+		services: []string{"formatter", "publisher"},
+		client:   "client",
+		args:     []string{HELLO_TO, "--greeting", "Bonjour"},
+		config:   true,
+		spans: []wantSpan{
+			{service: "hello-world", name: "say-hello", attributes: map[string]string{"hello-to": HELLO_TO}},
+			{service: "hello-world", name: "formatString", parent: "say-hello"},
+			// the greeting only reaches the formatter in the baggage
+			{service: "formatter", name: "format", parent: "formatString", event: "Bonjour, " + HELLO_TO + "!"},
+			{service: "hello-world", name: "printHello", parent: "say-hello"},
+			{service: "publisher", name: "publish", parent: "printHello"},
+		},
+	},
+}
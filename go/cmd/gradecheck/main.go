@@ -0,0 +1,194 @@
+// The gradecheck command checks the programs of a lesson: it builds them, runs the servers and the client against an
+// OTLP receiver of its own, and checks the spans they send against those the lesson expects, printing what passes and
+// what fails. Run it from the go directory, e.g.
+//
+//	go run ./cmd/gradecheck -lesson 03
+//	go run ./cmd/gradecheck -lesson 03 -dir ./my-lesson03
+package main
+
+import (
+	"flag"
+	"fmt"
+	"log"
+	"net"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/legosandorigami/opentelemetry-tutorial/go/lib/testutil"
+)
+
+const (
+	// DEFAULT_OTLP_ENDPOINT is the endpoint the programs which do not read their configuration export to
+	DEFAULT_OTLP_ENDPOINT = "localhost:4318"
+	// FLUSH_TIMEOUT is how long the spans of the servers are waited for. The servers are killed rather than shut down,
+	// so their spans only arrive when the batch span processor exports them on its own, every 5 seconds.
+	FLUSH_TIMEOUT = 10 * time.Second
+)
+
+func main() {
+	lessonID := flag.String("lesson", "", "number of the lesson to check, e.g. 03")
+	variant := flag.String("variant", "exercise", "programs to check, exercise or solution")
+	dir := flag.String("dir", "", "directory of the programs to check, instead of the exercise or the solution of the lesson")
+	flag.Parse()
+
+	l, ok := lessons[*lessonID]
+	if !ok {
+		log.Fatalf("unknown lesson %q, expecting one of %s", *lessonID, strings.Join(lessonIDs(), ", "))
+	}
+	if *dir == "" {
+		*dir = filepath.Join(".", "lesson"+*lessonID, *variant)
+	}
+	if _, err := os.Stat(*dir); err != nil {
+		log.Fatalf("no programs to check: %v", err)
+	}
+
+	fmt.Printf("Checking Lesson %s - %s in %s\n\n", *lessonID, l.title, *dir)
+	results, err := run(l, *dir)
+	if err != nil {
+		log.Fatal(err)
+	}
+
+	failed := 0
+	for _, r := range results {
+		status := "PASS"
+		if r.err != nil {
+			status = "FAIL"
+			failed++
+		}
+		fmt.Printf("%s  %s\n", status, r.check)
+		if r.err != nil {
+			fmt.Printf("      %v\n", r.err)
+		}
+	}
+
+	fmt.Println()
+	if failed > 0 {
+		fmt.Printf("%d of %d checks failed\n", failed, len(results))
+		os.Exit(1)
+	}
+	fmt.Printf("all %d checks passed\n", len(results))
+}
+
+// run builds and runs the programs of the lesson found in dir, and checks the spans they send
+func run(l lesson, dir string) ([]result, error) {
+	bin, err := os.MkdirTemp("", "gradecheck")
+	if err != nil {
+		return nil, err
+	}
+	defer os.RemoveAll(bin)
+
+	// building the programs, so that the servers can be killed without leaving a child of go run behind
+	programs := append(append([]string(nil), l.services...), l.client)
+	for _, p := range programs {
+		fmt.Printf("building %s\n", filepath.Join(dir, p))
+		cmd := exec.Command("go", "build", "-o", filepath.Join(bin, binaryName(p)), "./"+filepath.Join(dir, p))
+		cmd.Stdout, cmd.Stderr = os.Stdout, os.Stderr
+		if err := cmd.Run(); err != nil {
+			return nil, fmt.Errorf("failed to build %s: %v", p, err)
+		}
+	}
+
+	// the programs which do not read their configuration use the default addresses
+	endpoint, addrs, env := DEFAULT_OTLP_ENDPOINT, []string{"localhost:8081", "localhost:8082"}, os.Environ()
+	if l.config {
+		endpoint = "127.0.0.1:0"
+		for i := range addrs {
+			if addrs[i], err = freeAddr(); err != nil {
+				return nil, err
+			}
+		}
+		env = append(env, "FORMATTER_ADDR="+addrs[0], "PUBLISHER_ADDR="+addrs[1])
+	}
+	receiver, err := testutil.StartOTLPReceiver(endpoint)
+	if err != nil {
+		return nil, fmt.Errorf("failed to start the OTLP receiver on %s, is a collector or a backend running? %v", endpoint, err)
+	}
+	defer receiver.Close()
+	env = append(env, "OTLP_ENDPOINT="+receiver.Endpoint())
+
+	// starting the servers, and waiting for them to listen
+	for _, s := range l.services {
+		cmd := exec.Command(filepath.Join(bin, binaryName(s)))
+		cmd.Env = env
+		if err := cmd.Start(); err != nil {
+			return nil, fmt.Errorf("failed to start %s: %v", s, err)
+		}
+		defer cmd.Process.Kill()
+	}
+	for _, addr := range addrs[:len(l.services)] {
+		if err := waitForListener(addr, 10*time.Second); err != nil {
+			return nil, err
+		}
+	}
+
+	// running the client, whose spans are exported when it exits
+	fmt.Printf("running %s %s\n\n", l.client, strings.Join(l.args, " "))
+	client := exec.Command(filepath.Join(bin, binaryName(l.client)), l.args...)
+	client.Env = env
+	out, err := client.CombinedOutput()
+	results := []result{{check: "the client runs successfully", err: commandError(err, out)}}
+
+	// waiting for the spans of the servers, exported by their batch span processor
+	deadline := time.Now().Add(FLUSH_TIMEOUT)
+	for !allReceived(l.spans, receiver.Spans()) && time.Now().Before(deadline) {
+		time.Sleep(100 * time.Millisecond)
+	}
+
+	return append(results, check(l.spans, receiver.Spans())...), nil
+}
+
+// binaryName returns the name of the binary built from the program directory p
+func binaryName(p string) string {
+	if p == "." {
+		return "program"
+	}
+	return filepath.Base(p)
+}
+
+// freeAddr returns a localhost address with a port nobody listens on
+func freeAddr() (string, error) {
+	l, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		return "", err
+	}
+	defer l.Close()
+	return l.Addr().String(), nil
+}
+
+// waitForListener waits until something listens on addr
+func waitForListener(addr string, timeout time.Duration) error {
+	deadline := time.Now().Add(timeout)
+	for {
+		conn, err := net.DialTimeout("tcp", addr, time.Second)
+		if err == nil {
+			conn.Close()
+			return nil
+		}
+		if time.Now().After(deadline) {
+			return fmt.Errorf("no server listening on %s after %s: %v", addr, timeout, err)
+		}
+		time.Sleep(100 * time.Millisecond)
+	}
+}
+
+// commandError returns err with the output of the command, nil if the command succeeded
+func commandError(err error, out []byte) error {
+	if err == nil {
+		return nil
+	}
+	return fmt.Errorf("%v\n%s", err, strings.TrimSpace(string(out)))
+}
+
+// lessonIDs returns the lessons gradecheck knows about
+func lessonIDs() []string {
+	var ids []string
+	for id := range lessons {
+		ids = append(ids, id)
+	}
+	sort.Strings(ids)
+	return ids
+}
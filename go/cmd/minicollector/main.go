@@ -0,0 +1,129 @@
+// The minicollector command is a collector reduced to its bare bones, to read a complete telemetry pipeline end to end:
+// it receives the spans over OTLP/HTTP and OTLP/gRPC, runs them through processors dropping and redacting spans, and
+// forwards what is left to a backend over OTLP/HTTP. Point the programs to it and it to the backend:
+//
+//	go run ./cmd/minicollector -backend localhost:4318 -drop-names "GET /healthz" -redact hello-to
+//	OTLP_ENDPOINT=localhost:14318 go run ./lesson04/solution/client Bryan
+//
+// With -spanmetrics, it also derives the rate, errors and duration of the operations from the spans received, and
+// exposes them to Prometheus on the OTLP/HTTP address:
+//
+//	go run ./cmd/minicollector -backend localhost:4318 -spanmetrics -spanmetrics-dimensions http.status_code
+//	curl -H 'Accept: application/openmetrics-text' localhost:14318/metrics
+//
+// Unlike the OpenTelemetry Collector, it handles the traces only, does not batch nor retry, and forwards every
+// request as soon as it is processed: a failure of the backend is returned to the program which sent the spans.
+//
+// An outage of the backend is simulated with -outage, or at runtime on the OTLP/HTTP address, to see what the
+// programs do with their spans meanwhile:
+//
+//	curl -X POST 'localhost:14318/outage?down=true'
+//	curl -X POST 'localhost:14318/outage?down=false'
+package main
+
+import (
+	"flag"
+	"fmt"
+	"log"
+	"net"
+	"net/http"
+	"strconv"
+	"strings"
+
+	"github.com/legosandorigami/opentelemetry-tutorial/go/lib/config"
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/promhttp"
+	coltracepb "go.opentelemetry.io/proto/otlp/collector/trace/v1"
+	"google.golang.org/grpc"
+)
+
+func main() {
+	httpAddr := flag.String("http-addr", "localhost:14318", "address the OTLP/HTTP receiver listens on")
+	grpcAddr := flag.String("grpc-addr", "localhost:14317", "address the OTLP/gRPC receiver listens on")
+	backend := flag.String("backend", config.Getenv("OTLP_ENDPOINT", config.DEFAULT_OTLP_ENDPOINT), "host:port of the OTLP/HTTP backend the spans are forwarded to")
+	dropNames := flag.String("drop-names", "", "comma-separated names of the spans dropped")
+	dropAttributes := flag.String("drop-attributes", "", "comma-separated key=value attributes of the spans dropped")
+	redact := flag.String("redact", "", "comma-separated keys of the attributes whose values are redacted")
+	spanmetrics := flag.Bool("spanmetrics", false, "derive metrics from the spans received, exposed on GET /metrics")
+	dimensions := flag.String("spanmetrics-dimensions", "", "comma-separated keys of the span attributes labeling the span-derived metrics")
+	verbose := flag.Bool("verbose", false, "log every request")
+	outage := flag.Bool("outage", false, "start with the backend down, refusing the spans until POST /outage?down=false")
+	flag.Parse()
+
+	// assembling the pipeline: the processors run in order, then the exporter forwards the spans left
+	p := &pipeline{
+		exporter: newExporter(*backend),
+		verbose:  *verbose,
+	}
+	if *spanmetrics {
+		// deriving the metrics first, so that they count every span received, even those dropped afterwards
+		registry := prometheus.NewRegistry()
+		m, err := newSpanMetrics(registry, split(*dimensions))
+		if err != nil {
+			log.Fatalf("failed to create the span metrics: %v", err)
+		}
+		p.processors = append(p.processors, m.processor())
+
+		// exposing the metrics in the OpenMetrics format when asked for it, the only one carrying the exemplars
+		http.Handle("GET /metrics", promhttp.HandlerFor(registry, promhttp.HandlerOpts{EnableOpenMetrics: true}))
+	}
+	if *dropNames != "" || *dropAttributes != "" {
+		p.processors = append(p.processors, filter(split(*dropNames), config.ParseKeyValues(*dropAttributes)))
+	}
+	if *redact != "" {
+		p.processors = append(p.processors, redactor(split(*redact)))
+	}
+	p.down.Store(*outage)
+
+	// receiving over OTLP/gRPC
+	l, err := net.Listen("tcp", *grpcAddr)
+	if err != nil {
+		log.Fatalf("failed to listen on %s: %v", *grpcAddr, err)
+	}
+	server := grpc.NewServer()
+	coltracepb.RegisterTraceServiceServer(server, &grpcReceiver{pipeline: p})
+	go func() {
+		log.Fatal(server.Serve(l))
+	}()
+
+	// receiving over OTLP/HTTP
+	http.Handle("POST /v1/traces", &httpReceiver{pipeline: p})
+
+	// simulating an outage of the backend on demand
+	http.HandleFunc("/outage", outageHandler(p))
+	log.Printf("receiving spans on %s (OTLP/HTTP) and %s (OTLP/gRPC), forwarding them to %s", *httpAddr, *grpcAddr, *backend)
+	log.Fatal(http.ListenAndServe(*httpAddr, nil))
+}
+
+// split splits a comma-separated list, ignoring the empty items
+func split(s string) []string {
+	var items []string
+	for _, item := range strings.Split(s, ",") {
+		if item = strings.TrimSpace(item); item != "" {
+			items = append(items, item)
+		}
+	}
+	return items
+}
+
+// outageHandler returns the handler starting and ending a simulated outage of the backend with POST /outage?down=true
+// and POST /outage?down=false, and telling whether the backend is down with GET /outage
+func outageHandler(p *pipeline) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		if r.Method == http.MethodPost {
+			down, err := strconv.ParseBool(r.FormValue("down"))
+			if err != nil {
+				http.Error(w, "down must be true or false", http.StatusBadRequest)
+				return
+			}
+			if p.down.Swap(down) != down {
+				if down {
+					log.Printf("outage started: refusing the spans with 503 Service Unavailable")
+				} else {
+					log.Printf("outage ended: forwarding the spans to the backend")
+				}
+			}
+		}
+		fmt.Fprintf(w, "down: %t\n", p.down.Load())
+	}
+}
@@ -0,0 +1,175 @@
+package main
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"log"
+	"net/http"
+	"slices"
+	"sync/atomic"
+
+	coltracepb "go.opentelemetry.io/proto/otlp/collector/trace/v1"
+	commonpb "go.opentelemetry.io/proto/otlp/common/v1"
+	tracepb "go.opentelemetry.io/proto/otlp/trace/v1"
+	"google.golang.org/protobuf/proto"
+)
+
+// REDACTED is the value of the redacted attributes
+const REDACTED = "REDACTED"
+
+// errOutage is returned for the spans received during a simulated outage of the backend
+var errOutage = errors.New("the backend is down (simulated outage)")
+
+// processor transforms the spans of an export request in place
+type processor func(req *coltracepb.ExportTraceServiceRequest)
+
+// pipeline runs the spans received through the processors, then forwards them with the exporter
+type pipeline struct {
+	processors []processor
+	exporter   *exporter
+	verbose    bool
+
+	// down simulates an outage of the backend, the spans being refused rather than forwarded
+	down atomic.Bool
+}
+
+// consume processes and forwards the spans of an export request
+func (p *pipeline) consume(ctx context.Context, req *coltracepb.ExportTraceServiceRequest) error {
+	received := countSpans(req)
+	if p.down.Load() {
+		if p.verbose {
+			log.Printf("received %d spans, refused during the outage", received)
+		}
+		return errOutage
+	}
+
+	for _, process := range p.processors {
+		process(req)
+	}
+	forwarded := countSpans(req)
+
+	if p.verbose {
+		log.Printf("received %d spans, dropped %d, forwarding %d", received, received-forwarded, forwarded)
+	}
+	if forwarded == 0 {
+		return nil
+	}
+	return p.exporter.export(ctx, req)
+}
+
+// countSpans returns the number of spans in an export request
+func countSpans(req *coltracepb.ExportTraceServiceRequest) int {
+	n := 0
+	for _, rs := range req.ResourceSpans {
+		for _, ss := range rs.ScopeSpans {
+			n += len(ss.Spans)
+		}
+	}
+	return n
+}
+
+// filter returns a processor dropping the spans with one of the names, or one of the attributes with the given value
+func filter(names []string, attributes map[string]string) processor {
+	drop := func(s *tracepb.Span) bool {
+		if slices.Contains(names, s.Name) {
+			return true
+		}
+		for _, kv := range s.Attributes {
+			if v, ok := attributes[kv.Key]; ok && v == stringValue(kv.Value) {
+				return true
+			}
+		}
+		return false
+	}
+
+	return func(req *coltracepb.ExportTraceServiceRequest) {
+		for _, rs := range req.ResourceSpans {
+			for _, ss := range rs.ScopeSpans {
+				ss.Spans = slices.DeleteFunc(ss.Spans, drop)
+			}
+		}
+	}
+}
+
+// redactor returns a processor replacing the values of the attributes with the given keys by REDACTED, on the
+// resources, the spans, their events and their links
+func redactor(keys []string) processor {
+	redact := func(kvs []*commonpb.KeyValue) {
+		for _, kv := range kvs {
+			if slices.Contains(keys, kv.Key) {
+				kv.Value = &commonpb.AnyValue{Value: &commonpb.AnyValue_StringValue{StringValue: REDACTED}}
+			}
+		}
+	}
+
+	return func(req *coltracepb.ExportTraceServiceRequest) {
+		for _, rs := range req.ResourceSpans {
+			redact(rs.GetResource().GetAttributes())
+			for _, ss := range rs.ScopeSpans {
+				for _, s := range ss.Spans {
+					redact(s.Attributes)
+					for _, e := range s.Events {
+						redact(e.Attributes)
+					}
+					for _, l := range s.Links {
+						redact(l.Attributes)
+					}
+				}
+			}
+		}
+	}
+}
+
+// stringValue returns the value of an attribute as a string, as written in the flags
+func stringValue(v *commonpb.AnyValue) string {
+	switch v := v.GetValue().(type) {
+	case *commonpb.AnyValue_StringValue:
+		return v.StringValue
+	case *commonpb.AnyValue_BoolValue:
+		return fmt.Sprint(v.BoolValue)
+	case *commonpb.AnyValue_IntValue:
+		return fmt.Sprint(v.IntValue)
+	case *commonpb.AnyValue_DoubleValue:
+		return fmt.Sprint(v.DoubleValue)
+	default:
+		return ""
+	}
+}
+
+// exporter forwards the export requests to a backend over OTLP/HTTP
+type exporter struct {
+	url    string
+	client *http.Client
+}
+
+// newExporter returns an exporter forwarding to the backend at host:port
+func newExporter(backend string) *exporter {
+	return &exporter{url: "http://" + backend + "/v1/traces", client: &http.Client{}}
+}
+
+// export sends the export request to the backend
+func (e *exporter) export(ctx context.Context, req *coltracepb.ExportTraceServiceRequest) error {
+	body, err := proto.Marshal(req)
+	if err != nil {
+		return err
+	}
+	httpReq, err := http.NewRequestWithContext(ctx, "POST", e.url, bytes.NewReader(body))
+	if err != nil {
+		return err
+	}
+	httpReq.Header.Set("Content-Type", "application/x-protobuf")
+
+	resp, err := e.client.Do(httpReq)
+	if err != nil {
+		return fmt.Errorf("failed to forward the spans: %v", err)
+	}
+	defer resp.Body.Close()
+	io.Copy(io.Discard, resp.Body)
+	if resp.StatusCode != http.StatusOK {
+		return fmt.Errorf("the backend answered %s", resp.Status)
+	}
+	return nil
+}
@@ -0,0 +1,76 @@
+package main
+
+import (
+	"compress/gzip"
+	"context"
+	"fmt"
+	"io"
+	"net/http"
+
+	coltracepb "go.opentelemetry.io/proto/otlp/collector/trace/v1"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+	"google.golang.org/protobuf/proto"
+)
+
+// httpReceiver receives the spans over OTLP/HTTP, encoded in protobuf as the exporters of the SDK send them
+type httpReceiver struct {
+	pipeline *pipeline
+}
+
+func (h *httpReceiver) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	if ct := r.Header.Get("Content-Type"); ct != "application/x-protobuf" {
+		http.Error(w, fmt.Sprintf("unsupported content type %q, only application/x-protobuf is", ct), http.StatusUnsupportedMediaType)
+		return
+	}
+
+	// decompressing the body, the exporters compressing it with gzip when asked to
+	body := io.Reader(r.Body)
+	if r.Header.Get("Content-Encoding") == "gzip" {
+		gz, err := gzip.NewReader(r.Body)
+		if err != nil {
+			http.Error(w, err.Error(), http.StatusBadRequest)
+			return
+		}
+		defer gz.Close()
+		body = gz
+	}
+	b, err := io.ReadAll(body)
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+
+	var req coltracepb.ExportTraceServiceRequest
+	if err := proto.Unmarshal(b, &req); err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+
+	// the backend being down is a transient failure, which the exporter of the program may retry
+	if err := h.pipeline.consume(r.Context(), &req); err != nil {
+		http.Error(w, err.Error(), http.StatusServiceUnavailable)
+		return
+	}
+
+	resp, err := proto.Marshal(&coltracepb.ExportTraceServiceResponse{})
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+		return
+	}
+	w.Header().Set("Content-Type", "application/x-protobuf")
+	w.Write(resp)
+}
+
+// grpcReceiver receives the spans over OTLP/gRPC
+type grpcReceiver struct {
+	coltracepb.UnimplementedTraceServiceServer
+	pipeline *pipeline
+}
+
+func (g *grpcReceiver) Export(ctx context.Context, req *coltracepb.ExportTraceServiceRequest) (*coltracepb.ExportTraceServiceResponse, error) {
+	if err := g.pipeline.consume(ctx, req); err != nil {
+		return nil, status.Error(codes.Unavailable, err.Error())
+	}
+	return &coltracepb.ExportTraceServiceResponse{}, nil
+}
@@ -0,0 +1,120 @@
+package main
+
+import (
+	"encoding/hex"
+	"time"
+
+	"github.com/prometheus/client_golang/prometheus"
+	coltracepb "go.opentelemetry.io/proto/otlp/collector/trace/v1"
+	commonpb "go.opentelemetry.io/proto/otlp/common/v1"
+	tracepb "go.opentelemetry.io/proto/otlp/trace/v1"
+)
+
+// SPANMETRICS_BUCKETS are the bounds of the buckets of the duration histogram, in seconds, those of the spanmetrics
+// connector of the OpenTelemetry Collector
+var SPANMETRICS_BUCKETS = []float64{0.002, 0.004, 0.006, 0.008, 0.01, 0.05, 0.1, 0.2, 0.4, 0.8, 1, 1.4, 2, 5, 10, 15}
+
+// SPANMETRICS_LABELS are the labels of every span-derived metric, the dimensions given with -spanmetrics-dimensions
+// coming after them
+var SPANMETRICS_LABELS = []string{"service_name", "span_name", "span_kind", "status_code"}
+
+// spanMetrics derives the rate, errors and duration of the operations from the spans, as the spanmetrics connector of
+// the OpenTelemetry Collector does: every span counts as one call, whose duration is the one of the span
+type spanMetrics struct {
+	dimensions []string
+	calls      *prometheus.CounterVec
+	duration   *prometheus.HistogramVec
+}
+
+// newSpanMetrics creates the span-derived metrics and registers them, labeled by the service, the name, the kind and
+// the status of the spans, and by the values of the attributes with the keys of dimensions
+func newSpanMetrics(registerer prometheus.Registerer, dimensions []string) (*spanMetrics, error) {
+	labels := append(append([]string{}, SPANMETRICS_LABELS...), prometheusLabels(dimensions)...)
+
+	m := &spanMetrics{
+		dimensions: dimensions,
+		calls: prometheus.NewCounterVec(prometheus.CounterOpts{
+			Name: "traces_span_metrics_calls_total",
+			Help: "Number of spans received",
+		}, labels),
+		duration: prometheus.NewHistogramVec(prometheus.HistogramOpts{
+			Name:    "traces_span_metrics_duration_seconds",
+			Help:    "Duration of the spans received",
+			Buckets: SPANMETRICS_BUCKETS,
+		}, labels),
+	}
+	for _, c := range []prometheus.Collector{m.calls, m.duration} {
+		if err := registerer.Register(c); err != nil {
+			return nil, err
+		}
+	}
+	return m, nil
+}
+
+// processor returns a processor recording every span of the export requests, which it leaves untouched
+func (m *spanMetrics) processor() processor {
+	return func(req *coltracepb.ExportTraceServiceRequest) {
+		for _, rs := range req.ResourceSpans {
+			service := attributeValue(rs.GetResource().GetAttributes(), "service.name")
+			for _, ss := range rs.ScopeSpans {
+				for _, s := range ss.Spans {
+					m.record(service, s)
+				}
+			}
+		}
+	}
+}
+
+// record counts a span and observes its duration, keeping its trace ID as the exemplar of the bucket
+func (m *spanMetrics) record(service string, s *tracepb.Span) {
+	values := []string{service, s.Name, s.Kind.String(), s.GetStatus().GetCode().String()}
+	for _, key := range m.dimensions {
+		values = append(values, attributeValue(s.Attributes, key))
+	}
+
+	m.calls.WithLabelValues(values...).Inc()
+
+	// the spans are timestamped, their duration is the difference of their timestamps, 0 for a span ending before it
+	// starts, whose unsigned difference would wrap around
+	var d float64
+	if s.EndTimeUnixNano > s.StartTimeUnixNano {
+		d = time.Duration(s.EndTimeUnixNano - s.StartTimeUnixNano).Seconds()
+	}
+	observer := m.duration.WithLabelValues(values...)
+	if eo, ok := observer.(prometheus.ExemplarObserver); ok && len(s.TraceId) > 0 {
+		eo.ObserveWithExemplar(d, prometheus.Labels{"trace_id": hex.EncodeToString(s.TraceId)})
+		return
+	}
+	observer.Observe(d)
+}
+
+// attributeValue returns the value of the attribute with the given key as a string, or "" without such an attribute
+func attributeValue(kvs []*commonpb.KeyValue, key string) string {
+	for _, kv := range kvs {
+		if kv.Key == key {
+			return stringValue(kv.Value)
+		}
+	}
+	return ""
+}
+
+// prometheusLabels returns the names of the labels of the attributes with the given keys, Prometheus allowing neither
+// dots nor dashes in a label name
+func prometheusLabels(keys []string) []string {
+	labels := make([]string, len(keys))
+	for i, key := range keys {
+		labels[i] = sanitize(key)
+	}
+	return labels
+}
+
+// sanitize replaces the characters of an attribute key which are not allowed in a Prometheus label name by '_'
+func sanitize(key string) string {
+	b := []byte(key)
+	for i, c := range b {
+		if !(c == '_' || c >= 'a' && c <= 'z' || c >= 'A' && c <= 'Z' || c >= '0' && c <= '9' && i > 0) {
+			b[i] = '_'
+		}
+	}
+	return string(b)
+}
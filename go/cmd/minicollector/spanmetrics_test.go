@@ -0,0 +1,109 @@
+package main
+
+import (
+	"testing"
+
+	"github.com/prometheus/client_golang/prometheus"
+	dto "github.com/prometheus/client_model/go"
+	coltracepb "go.opentelemetry.io/proto/otlp/collector/trace/v1"
+	commonpb "go.opentelemetry.io/proto/otlp/common/v1"
+	resourcepb "go.opentelemetry.io/proto/otlp/resource/v1"
+	tracepb "go.opentelemetry.io/proto/otlp/trace/v1"
+)
+
+// stringAttribute returns a string attribute of an OTLP span or resource
+func stringAttribute(key, value string) *commonpb.KeyValue {
+	return &commonpb.KeyValue{Key: key, Value: &commonpb.AnyValue{Value: &commonpb.AnyValue_StringValue{StringValue: value}}}
+}
+
+// gather returns the metric of the family with the given name, failing the test without exactly one such metric
+func gather(t *testing.T, registry *prometheus.Registry, name string) *dto.Metric {
+	t.Helper()
+	families, err := registry.Gather()
+	if err != nil {
+		t.Fatalf("gathering the metrics: %v", err)
+	}
+	for _, f := range families {
+		if f.GetName() == name {
+			if len(f.Metric) != 1 {
+				t.Fatalf("%s has %d metrics, want 1", name, len(f.Metric))
+			}
+			return f.Metric[0]
+		}
+	}
+	t.Fatalf("no %s metric", name)
+	return nil
+}
+
+func TestSpanMetrics(t *testing.T) {
+	for _, tt := range []struct {
+		name         string
+		start, end   uint64
+		wantDuration float64
+	}{
+		{"span", 1_000_000_000, 1_300_000_000, 0.3},
+		// a span ending before it starts must not wrap around to a duration of centuries
+		{"span ending before it starts", 1_300_000_000, 1_000_000_000, 0},
+	} {
+		t.Run(tt.name, func(t *testing.T) {
+			registry := prometheus.NewRegistry()
+			m, err := newSpanMetrics(registry, []string{"http.route"})
+			if err != nil {
+				t.Fatalf("creating the span metrics: %v", err)
+			}
+
+			traceID := []byte{0x01, 0x02, 0x03, 0x04, 0x05, 0x06, 0x07, 0x08, 0x09, 0x0a, 0x0b, 0x0c, 0x0d, 0x0e, 0x0f, 0x10}
+			m.processor()(&coltracepb.ExportTraceServiceRequest{ResourceSpans: []*tracepb.ResourceSpans{{
+				Resource: &resourcepb.Resource{Attributes: []*commonpb.KeyValue{stringAttribute("service.name", "formatter")}},
+				ScopeSpans: []*tracepb.ScopeSpans{{Spans: []*tracepb.Span{{
+					TraceId:           traceID,
+					Name:              "format",
+					Kind:              tracepb.Span_SPAN_KIND_SERVER,
+					StartTimeUnixNano: tt.start,
+					EndTimeUnixNano:   tt.end,
+					Attributes:        []*commonpb.KeyValue{stringAttribute("http.route", "/format")},
+				}}}},
+			}}})
+
+			calls := gather(t, registry, "traces_span_metrics_calls_total")
+			if got := calls.GetCounter().GetValue(); got != 1 {
+				t.Errorf("calls = %v, want 1", got)
+			}
+			wantLabels := map[string]string{
+				"service_name": "formatter",
+				"span_name":    "format",
+				"span_kind":    "SPAN_KIND_SERVER",
+				"status_code":  "STATUS_CODE_UNSET",
+				"http_route":   "/format",
+			}
+			for _, l := range calls.Label {
+				if want := wantLabels[l.GetName()]; l.GetValue() != want {
+					t.Errorf("label %s = %q, want %q", l.GetName(), l.GetValue(), want)
+				}
+			}
+
+			histogram := gather(t, registry, "traces_span_metrics_duration_seconds").GetHistogram()
+			if got := histogram.GetSampleCount(); got != 1 {
+				t.Errorf("duration count = %d, want 1", got)
+			}
+			if got := histogram.GetSampleSum(); got != tt.wantDuration {
+				t.Errorf("duration sum = %v, want %v", got, tt.wantDuration)
+			}
+			var exemplar *dto.Exemplar
+			for _, b := range histogram.Bucket {
+				if b.Exemplar != nil {
+					exemplar = b.Exemplar
+				}
+			}
+			if exemplar == nil {
+				t.Fatal("no exemplar in the duration buckets")
+			}
+			if got := exemplar.Label[0].GetValue(); exemplar.Label[0].GetName() != "trace_id" || got != "0102030405060708090a0b0c0d0e0f10" {
+				t.Errorf("exemplar label %s=%q, want the trace ID", exemplar.Label[0].GetName(), got)
+			}
+			if got := exemplar.GetValue(); got != tt.wantDuration {
+				t.Errorf("exemplar value = %v, want %v", got, tt.wantDuration)
+			}
+		})
+	}
+}
@@ -0,0 +1,101 @@
+// The replay command sends the spans recorded by the programs run with SPANS_FILE to a backend over OTLP/HTTP, e.g.
+// to look at the traces of a whole class after the fact:
+//
+//	SPANS_FILE=class.jsonl go run ./lesson03/solution/client Bryan
+//	go run ./cmd/replay -otlp-endpoint localhost:4318 class.jsonl
+//
+// With -now, the spans are moved in time so that the latest of them ends now, for the backends which only accept the
+// recent spans, or to find the traces among the last ones.
+package main
+
+import (
+	"context"
+	"flag"
+	"log"
+	"os"
+	"time"
+
+	"github.com/legosandorigami/opentelemetry-tutorial/go/lib/config"
+	"github.com/legosandorigami/opentelemetry-tutorial/go/lib/tracing"
+	traceSdk "go.opentelemetry.io/otel/sdk/trace"
+	"go.opentelemetry.io/otel/sdk/trace/tracetest"
+)
+
+func main() {
+	endpoint := flag.String("otlp-endpoint", config.Getenv("OTLP_ENDPOINT", config.DEFAULT_OTLP_ENDPOINT), "host:port of the OTLP/HTTP backend")
+	now := flag.Bool("now", false, "move the spans in time so that the latest one ends now")
+	batchSize := flag.Int("batch-size", 512, "number of spans sent per export request")
+	encoding := flag.String("otlp-encoding", config.Getenv(tracing.OTLP_ENCODING_ENV, tracing.OTLP_PROTOBUF), "encoding of the OTLP/HTTP payloads: protobuf or json")
+	flag.Parse()
+
+	if flag.NArg() == 0 {
+		log.Fatalf("usage: replay [flags] FILE...")
+	}
+
+	// reading the spans of every file
+	var stubs tracetest.SpanStubs
+	for _, path := range flag.Args() {
+		f, err := os.Open(path)
+		if err != nil {
+			log.Fatalf("failed to open %s: %v", path, err)
+		}
+		read, err := tracing.ReadSpans(f)
+		f.Close()
+		if err != nil {
+			log.Fatalf("failed to read %s: %v", path, err)
+		}
+		stubs = append(stubs, read...)
+	}
+	if len(stubs) == 0 {
+		log.Printf("no span to replay")
+		return
+	}
+
+	if *now {
+		shift(stubs, time.Now())
+	}
+
+	// creating an OTLP trace exporter to send the spans to the specified backend
+	ctx := context.Background()
+	exporter, err := tracing.NewOTLPExporter(ctx, *endpoint, *encoding)
+	if err != nil {
+		log.Fatalf("failed to create otel exporter: %v", err)
+	}
+	defer func() {
+		if err := exporter.Shutdown(ctx); err != nil {
+			log.Fatalf("failed to shutdown the exporter: %v", err)
+		}
+	}()
+
+	// sending the spans in batches, as the batch span processor of the programs would have
+	spans := stubs.Snapshots()
+	for start := 0; start < len(spans); start += *batchSize {
+		batch := spans[start:min(start+*batchSize, len(spans))]
+		if err := exporter.ExportSpans(ctx, batch); err != nil {
+			log.Fatalf("failed to export the spans: %v", err)
+		}
+	}
+	log.Printf("replayed %d spans to %s", len(spans), *endpoint)
+}
+
+// shift moves the spans, and their events, in time so that the latest span ends at end
+func shift(stubs tracetest.SpanStubs, end time.Time) {
+	latest := stubs[0].EndTime
+	for _, s := range stubs[1:] {
+		if s.EndTime.After(latest) {
+			latest = s.EndTime
+		}
+	}
+	offset := end.Sub(latest)
+
+	for i := range stubs {
+		stubs[i].StartTime = stubs[i].StartTime.Add(offset)
+		stubs[i].EndTime = stubs[i].EndTime.Add(offset)
+		events := make([]traceSdk.Event, len(stubs[i].Events))
+		for j, e := range stubs[i].Events {
+			e.Time = e.Time.Add(offset)
+			events[j] = e
+		}
+		stubs[i].Events = events
+	}
+}
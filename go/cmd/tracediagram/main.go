@@ -0,0 +1,75 @@
+// The tracediagram command draws a trace recorded by the programs run with SPANS_FILE, as a Mermaid sequence diagram
+// of the calls between the services, or as a Graphviz graph of the spans:
+//
+//	SPANS_FILE=hello.jsonl go run ./lesson03/solution/client Bryan
+//	go run ./cmd/tracediagram hello.jsonl
+//	go run ./cmd/tracediagram -format dot hello.jsonl | dot -Tsvg > trace.svg
+//
+// The most recent trace of the files is drawn, unless another one is chosen with -trace.
+package main
+
+import (
+	"flag"
+	"fmt"
+	"log"
+	"os"
+
+	"github.com/legosandorigami/opentelemetry-tutorial/go/lib/tracing"
+	"go.opentelemetry.io/otel/sdk/trace/tracetest"
+)
+
+func main() {
+	format := flag.String("format", "mermaid", "format of the diagram: mermaid or dot")
+	traceID := flag.String("trace", "", "ID of the trace to draw, the most recent one if empty")
+	flag.Parse()
+
+	if flag.NArg() == 0 {
+		log.Fatalf("usage: tracediagram [flags] FILE...")
+	}
+	draw, ok := map[string]func(tracetest.SpanStubs) string{
+		"mermaid": func(stubs tracetest.SpanStubs) string { return tracing.Mermaid(stubs.Snapshots()) },
+		"dot":     func(stubs tracetest.SpanStubs) string { return tracing.Graphviz(stubs.Snapshots()) },
+	}[*format]
+	if !ok {
+		log.Fatalf("unknown format %q, expecting mermaid or dot", *format)
+	}
+
+	// reading the spans of every file, grouped by trace
+	traces := make(map[string]tracetest.SpanStubs)
+	var latest string
+	var latestStub tracetest.SpanStub
+	for _, path := range flag.Args() {
+		f, err := os.Open(path)
+		if err != nil {
+			log.Fatalf("failed to open %s: %v", path, err)
+		}
+		stubs, err := tracing.ReadSpans(f)
+		f.Close()
+		if err != nil {
+			log.Fatalf("failed to read %s: %v", path, err)
+		}
+		for _, s := range stubs {
+			id := s.SpanContext.TraceID().String()
+			traces[id] = append(traces[id], s)
+			if latest == "" || s.StartTime.After(latestStub.StartTime) {
+				latest, latestStub = id, s
+			}
+		}
+	}
+
+	if *traceID == "" {
+		if latest == "" {
+			log.Fatalf("no span found")
+		}
+		*traceID = latest
+		if len(traces) > 1 {
+			log.Printf("drawing the most recent of %d traces, %s, choose another one with -trace", len(traces), latest)
+		}
+	}
+	stubs, ok := traces[*traceID]
+	if !ok {
+		log.Fatalf("no trace %s found", *traceID)
+	}
+
+	fmt.Print(draw(stubs))
+}
@@ -0,0 +1,107 @@
+// The tracegen command generates synthetic traces, of a configurable shape, and exports them over OTLP/HTTP, to load
+// test a collector or a backend used in the lessons:
+//
+//	go run ./cmd/tracegen -traces 1000 -rate 100 -depth 3 -fanout 2 -services 3 -error-rate 0.05
+//
+// Every trace is a tree of spans spread over several services, each service exporting with its own TracerProvider.
+// The spans are timestamped as if the work had been done, ending at the time they are generated, so that the rate
+// is not bounded by their durations.
+package main
+
+import (
+	"context"
+	"flag"
+	"fmt"
+	"log"
+	"math/rand"
+	"os"
+	"os/signal"
+	"time"
+
+	"github.com/legosandorigami/opentelemetry-tutorial/go/lib/config"
+	"github.com/legosandorigami/opentelemetry-tutorial/go/lib/latency"
+	"github.com/legosandorigami/opentelemetry-tutorial/go/lib/tracing"
+	"go.opentelemetry.io/otel/propagation"
+	traceSdk "go.opentelemetry.io/otel/sdk/trace"
+	"go.opentelemetry.io/otel/trace"
+)
+
+func main() {
+	endpoint := flag.String("otlp-endpoint", config.Getenv("OTLP_ENDPOINT", config.DEFAULT_OTLP_ENDPOINT), "host:port of the OTLP/HTTP backend")
+	traces := flag.Int("traces", 100, "number of traces generated, 0 to generate until interrupted")
+	rate := flag.Float64("rate", 10, "traces generated per second, 0 for as many as possible")
+	depth := flag.Int("depth", 3, "number of levels of spans below the root span")
+	fanout := flag.Int("fanout", 2, "number of children of every span above the last level")
+	services := flag.Int("services", 3, "number of services the spans are spread over")
+	errorRate := flag.Float64("error-rate", 0.01, "fraction of the spans failing")
+	latencySpec := flag.String("latency", "pareto:5ms:1.5", "time spent by every span on its own: fixed:<d>, uniform:<min>:<max> or pareto:<scale>:<shape>")
+	seed := flag.Int64("seed", 0, "seed of the random topologies, 0 for a random seed")
+	flag.Parse()
+
+	if *depth < 0 || *fanout < 0 || *services < 1 {
+		log.Fatalf("invalid topology: the depth and the fan-out cannot be negative, and there must be one service at least")
+	}
+	dist, err := latency.Parse(*latencySpec)
+	if err != nil {
+		log.Fatalf("invalid latency: %v", err)
+	}
+	topo := topology{depth: *depth, fanout: *fanout, services: *services, errorRate: *errorRate, latency: dist}
+	if *seed == 0 {
+		*seed = time.Now().UnixNano()
+	}
+	rng := rand.New(rand.NewSource(*seed))
+
+	// initializing a TracerProvider per service, all of them exporting to the same backend
+	gen := &generator{propagator: propagation.TraceContext{}}
+	var providers []*traceSdk.TracerProvider
+	for i := 0; i < *services; i++ {
+		res, err := tracing.NewResource(fmt.Sprintf("service-%d", i))
+		if err != nil {
+			log.Fatalf("failed to create the resource: %v", err)
+		}
+		tp, err := tracing.NewTracerProvider(res, *endpoint, traceSdk.AlwaysSample())
+		if err != nil {
+			log.Fatalf("failed to create otel exporter: %v", err)
+		}
+		providers = append(providers, tp)
+		gen.tracers = append(gen.tracers, tp.Tracer("tracegen"))
+	}
+
+	// stopping on the first interrupt, and flushing the spans generated so far
+	ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt)
+	defer stop()
+
+	var tick <-chan time.Time
+	if *rate > 0 {
+		ticker := time.NewTicker(time.Duration(float64(time.Second) / *rate))
+		defer ticker.Stop()
+		tick = ticker.C
+	}
+
+	started := time.Now()
+	generated, spans := 0, 0
+	for *traces == 0 || generated < *traces {
+		if tick != nil {
+			select {
+			case <-tick:
+			case <-ctx.Done():
+			}
+		}
+		if ctx.Err() != nil {
+			break
+		}
+
+		// planning the trace first, to start it early enough for it to end now
+		root := topo.plan(rng)
+		gen.emit(context.Background(), root, time.Now().Add(-root.duration()), trace.SpanKindServer)
+		generated++
+		spans += root.spans()
+	}
+
+	for _, tp := range providers {
+		if err := tp.Shutdown(context.Background()); err != nil {
+			log.Printf("failed to shutdown TracerProvider: %v", err)
+		}
+	}
+	log.Printf("generated %d traces, %d spans, in %s (seed %d)", generated, spans, time.Since(started).Round(time.Millisecond), *seed)
+}
@@ -0,0 +1,127 @@
+package main
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"math/rand"
+	"time"
+
+	"github.com/legosandorigami/opentelemetry-tutorial/go/lib/latency"
+	"go.opentelemetry.io/otel/codes"
+	"go.opentelemetry.io/otel/propagation"
+	"go.opentelemetry.io/otel/trace"
+)
+
+// NETWORK_DELAY is the time a call between two services spends on the wire, each way
+const NETWORK_DELAY = 100 * time.Microsecond
+
+// topology describes the shape of the generated traces
+type topology struct {
+	// depth is the number of levels of spans below the root
+	depth int
+	// fanout is the number of children of every span above the last level
+	fanout int
+	// services is the number of services the spans are spread over
+	services int
+	// errorRate is the fraction of spans failing
+	errorRate float64
+	// latency is the distribution of the time spent by every span on its own, apart from its children
+	latency latency.Distribution
+}
+
+// node is a span to be generated
+type node struct {
+	service  int
+	name     string
+	self     time.Duration
+	err      bool
+	children []*node
+}
+
+// plan draws the spans of a trace
+func (t topology) plan(rng *rand.Rand) *node {
+	return t.planNode(rng, rng.Intn(t.services), 0, 0)
+}
+
+func (t topology) planNode(rng *rand.Rand, service, level, index int) *node {
+	n := &node{
+		service: service,
+		name:    fmt.Sprintf("operation-%d-%d", level, index),
+		err:     rng.Float64() < t.errorRate,
+	}
+	if t.latency != nil {
+		n.self = t.latency.Sample()
+	}
+	if level < t.depth {
+		for i := 0; i < t.fanout; i++ {
+			n.children = append(n.children, t.planNode(rng, rng.Intn(t.services), level+1, i))
+		}
+	}
+	return n
+}
+
+// duration returns the duration of the span of n, including its children and the calls to the other services
+func (n *node) duration() time.Duration {
+	d := n.self
+	for _, c := range n.children {
+		d += c.duration()
+		if c.service != n.service {
+			d += 2 * NETWORK_DELAY
+		}
+	}
+	return d
+}
+
+// generator emits the planned spans with the tracers of the services
+type generator struct {
+	tracers    []trace.Tracer
+	propagator propagation.TextMapPropagator
+}
+
+// emit generates the span of n, and of its children, starting at start, and returns its end time. The spans are
+// timestamped rather than timed, so that a trace lasting seconds is generated in microseconds.
+func (g *generator) emit(ctx context.Context, n *node, start time.Time, kind trace.SpanKind) time.Time {
+	tracer := g.tracers[n.service]
+	ctx, span := tracer.Start(ctx, n.name, trace.WithTimestamp(start), trace.WithSpanKind(kind))
+
+	// doing half of its own work before calling its children one after the other, and the other half after
+	now := start.Add(n.self / 2)
+	for _, c := range n.children {
+		if c.service == n.service {
+			now = g.emit(ctx, c, now, trace.SpanKindInternal)
+			continue
+		}
+
+		// calling another service: the context goes through the propagator, as it would over the wire
+		cctx, client := tracer.Start(ctx, fmt.Sprintf("call service-%d", c.service), trace.WithTimestamp(now), trace.WithSpanKind(trace.SpanKindClient))
+		carrier := propagation.MapCarrier{}
+		g.propagator.Inject(cctx, carrier)
+		end := g.emit(g.propagator.Extract(context.Background(), carrier), c, now.Add(NETWORK_DELAY), trace.SpanKindServer)
+		now = end.Add(NETWORK_DELAY)
+		if c.err {
+			client.SetStatus(codes.Error, "the call failed")
+		}
+		client.End(trace.WithTimestamp(now))
+	}
+	end := now.Add(n.self - n.self/2)
+
+	if n.err {
+		span.RecordError(errors.New("synthetic error"), trace.WithTimestamp(end))
+		span.SetStatus(codes.Error, "synthetic error")
+	}
+	span.End(trace.WithTimestamp(end))
+	return end
+}
+
+// spans returns the number of spans generated for n and its children, including the client spans of the calls
+func (n *node) spans() int {
+	count := 1
+	for _, c := range n.children {
+		count += c.spans()
+		if c.service != n.service {
+			count++
+		}
+	}
+	return count
+}
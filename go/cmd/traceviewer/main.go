@@ -0,0 +1,70 @@
+// The traceviewer command is a minimal trace backend, for following the first lessons without running a real one. It
+// receives the spans the programs export over OTLP/HTTP, keeps them in memory, and serves a web page listing the
+// recent traces, each of them shown as a waterfall. Run it from the go directory:
+//
+//	go run ./cmd/traceviewer
+//
+// and open http://localhost:16686. It does not receive metrics nor logs, and forgets everything when it stops.
+package main
+
+import (
+	"embed"
+	"flag"
+	"html/template"
+	"log"
+	"net/http"
+
+	"github.com/legosandorigami/opentelemetry-tutorial/go/lib/testutil"
+)
+
+//go:embed templates
+var templates embed.FS
+
+func main() {
+	otlpAddr := flag.String("otlp-addr", "localhost:4318", "address the OTLP/HTTP receiver listens on")
+	addr := flag.String("addr", "localhost:16686", "address the web page is served on")
+	maxTraces := flag.Int("max-traces", 50, "number of traces listed, the most recent first")
+	flag.Parse()
+
+	tmpl := template.Must(template.New("").Funcs(template.FuncMap{"ms": ms}).ParseFS(templates, "templates/*.html"))
+
+	// receiving the spans, kept in memory until cleared
+	receiver, err := testutil.StartOTLPReceiver(*otlpAddr)
+	if err != nil {
+		log.Fatalf("failed to start the OTLP receiver on %s: %v", *otlpAddr, err)
+	}
+	defer receiver.Close()
+
+	http.HandleFunc("GET /{$}", func(w http.ResponseWriter, r *http.Request) {
+		traces := summarize(groupTraces(receiver.Spans()))
+		if len(traces) > *maxTraces {
+			traces = traces[:*maxTraces]
+		}
+		render(w, tmpl, "index.html", traces)
+	})
+
+	http.HandleFunc("GET /traces/{id}", func(w http.ResponseWriter, r *http.Request) {
+		spans, ok := groupTraces(receiver.Spans())[r.PathValue("id")]
+		if !ok {
+			http.NotFound(w, r)
+			return
+		}
+		render(w, tmpl, "trace.html", waterfall(r.PathValue("id"), spans))
+	})
+
+	http.HandleFunc("POST /clear", func(w http.ResponseWriter, r *http.Request) {
+		receiver.Reset()
+		http.Redirect(w, r, "/", http.StatusSeeOther)
+	})
+
+	log.Printf("receiving spans on http://%s/v1/traces, serving the traces on http://%s", receiver.Endpoint(), *addr)
+	log.Fatal(http.ListenAndServe(*addr, nil))
+}
+
+// render executes the template name with data
+func render(w http.ResponseWriter, tmpl *template.Template, name string, data any) {
+	w.Header().Set("Content-Type", "text/html; charset=utf-8")
+	if err := tmpl.ExecuteTemplate(w, name, data); err != nil {
+		log.Printf("failed to render %s: %v", name, err)
+	}
+}
@@ -0,0 +1,196 @@
+package main
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/legosandorigami/opentelemetry-tutorial/go/lib/testutil"
+	commonpb "go.opentelemetry.io/proto/otlp/common/v1"
+	tracepb "go.opentelemetry.io/proto/otlp/trace/v1"
+)
+
+// traceSummary is a line of the list of traces
+type traceSummary struct {
+	ID       string
+	Root     string
+	Service  string
+	Start    time.Time
+	Duration time.Duration
+	Spans    int
+	Services int
+	Errors   int
+}
+
+// row is a span of the waterfall of a trace
+type row struct {
+	Name       string
+	Service    string
+	Kind       string
+	Depth      int
+	Start      time.Duration
+	Duration   time.Duration
+	Offset     float64
+	Width      float64
+	Error      bool
+	Attributes []string
+	Events     []string
+}
+
+// traceView is the waterfall of a trace
+type traceView struct {
+	ID       string
+	Duration time.Duration
+	Rows     []row
+}
+
+// groupTraces groups the spans by trace ID
+func groupTraces(spans []testutil.ReceivedSpan) map[string][]testutil.ReceivedSpan {
+	traces := make(map[string][]testutil.ReceivedSpan)
+	for _, s := range spans {
+		traces[s.TraceID()] = append(traces[s.TraceID()], s)
+	}
+	return traces
+}
+
+// summarize returns a summary of every trace, the most recent first
+func summarize(traces map[string][]testutil.ReceivedSpan) []traceSummary {
+	summaries := make([]traceSummary, 0, len(traces))
+	for id, spans := range traces {
+		start, end := bounds(spans)
+		summary := traceSummary{ID: id, Start: time.Unix(0, int64(start)), Duration: time.Duration(end - start), Spans: len(spans)}
+
+		services := make(map[string]bool)
+		for _, s := range spans {
+			services[s.Service()] = true
+			if s.GetStatus().GetCode() == tracepb.Status_STATUS_CODE_ERROR {
+				summary.Errors++
+			}
+		}
+		summary.Services = len(services)
+
+		// naming the trace after its root span, or its earliest span while the root has not been received
+		root := roots(spans)[0]
+		summary.Root, summary.Service = root.GetName(), root.Service()
+		if root.ParentSpanID() != "" {
+			summary.Root += " (incomplete)"
+		}
+
+		summaries = append(summaries, summary)
+	}
+	sort.Slice(summaries, func(i, j int) bool { return summaries[i].Start.After(summaries[j].Start) })
+	return summaries
+}
+
+// waterfall lays out the spans of a trace, every span under its parent, the children in the order they started
+func waterfall(id string, spans []testutil.ReceivedSpan) traceView {
+	start, end := bounds(spans)
+	view := traceView{ID: id, Duration: time.Duration(end - start)}
+	total := float64(end - start)
+	if total == 0 {
+		total = 1
+	}
+
+	children := make(map[string][]testutil.ReceivedSpan)
+	for _, s := range spans {
+		children[s.ParentSpanID()] = append(children[s.ParentSpanID()], s)
+	}
+
+	var walk func(s testutil.ReceivedSpan, depth int)
+	walk = func(s testutil.ReceivedSpan, depth int) {
+		offset := s.GetStartTimeUnixNano() - start
+		duration := s.GetEndTimeUnixNano() - s.GetStartTimeUnixNano()
+		r := row{
+			Name:     s.GetName(),
+			Service:  s.Service(),
+			Kind:     strings.ToLower(strings.TrimPrefix(s.GetKind().String(), "SPAN_KIND_")),
+			Depth:    depth,
+			Start:    time.Duration(offset),
+			Duration: time.Duration(duration),
+			Offset:   100 * float64(offset) / total,
+			Width:    max(100*float64(duration)/total, 0.2),
+			Error:    s.GetStatus().GetCode() == tracepb.Status_STATUS_CODE_ERROR,
+		}
+		for _, kv := range s.GetAttributes() {
+			r.Attributes = append(r.Attributes, fmt.Sprintf("%s=%s", kv.GetKey(), value(kv.GetValue())))
+		}
+		for _, e := range s.GetEvents() {
+			event := fmt.Sprintf("%s %s", ms(time.Duration(e.GetTimeUnixNano()-start)), e.GetName())
+			for _, kv := range e.GetAttributes() {
+				event += fmt.Sprintf(" %s=%s", kv.GetKey(), value(kv.GetValue()))
+			}
+			r.Events = append(r.Events, event)
+		}
+		view.Rows = append(view.Rows, r)
+
+		kids := children[s.SpanID()]
+		sortByStart(kids)
+		for _, c := range kids {
+			walk(c, depth+1)
+		}
+	}
+	for _, s := range roots(spans) {
+		walk(s, 0)
+	}
+	return view
+}
+
+// roots returns the spans of a trace whose parent was not received, the earliest first. Besides the root span, these
+// are the spans whose parent is still in flight, or was lost.
+func roots(spans []testutil.ReceivedSpan) []testutil.ReceivedSpan {
+	ids := make(map[string]bool)
+	for _, s := range spans {
+		ids[s.SpanID()] = true
+	}
+	var roots []testutil.ReceivedSpan
+	for _, s := range spans {
+		if !ids[s.ParentSpanID()] {
+			roots = append(roots, s)
+		}
+	}
+	sortByStart(roots)
+	return roots
+}
+
+// bounds returns the start time of the earliest span and the end time of the latest one, in Unix nanoseconds
+func bounds(spans []testutil.ReceivedSpan) (uint64, uint64) {
+	start, end := spans[0].GetStartTimeUnixNano(), spans[0].GetEndTimeUnixNano()
+	for _, s := range spans[1:] {
+		start = min(start, s.GetStartTimeUnixNano())
+		end = max(end, s.GetEndTimeUnixNano())
+	}
+	return start, end
+}
+
+// sortByStart sorts the spans by start time
+func sortByStart(spans []testutil.ReceivedSpan) {
+	sort.SliceStable(spans, func(i, j int) bool { return spans[i].GetStartTimeUnixNano() < spans[j].GetStartTimeUnixNano() })
+}
+
+// value formats an attribute value
+func value(v *commonpb.AnyValue) string {
+	switch v := v.GetValue().(type) {
+	case *commonpb.AnyValue_StringValue:
+		return fmt.Sprintf("%q", v.StringValue)
+	case *commonpb.AnyValue_BoolValue:
+		return fmt.Sprint(v.BoolValue)
+	case *commonpb.AnyValue_IntValue:
+		return fmt.Sprint(v.IntValue)
+	case *commonpb.AnyValue_DoubleValue:
+		return fmt.Sprint(v.DoubleValue)
+	case *commonpb.AnyValue_ArrayValue:
+		var values []string
+		for _, e := range v.ArrayValue.GetValues() {
+			values = append(values, value(e))
+		}
+		return "[" + strings.Join(values, ", ") + "]"
+	default:
+		return fmt.Sprint(v)
+	}
+}
+
+// ms formats a duration in milliseconds
+func ms(d time.Duration) string {
+	return fmt.Sprintf("%.2fms", float64(d)/float64(time.Millisecond))
+}
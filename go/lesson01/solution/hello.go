@@ -7,7 +7,7 @@ import (
 	"os"
 	"time"
 
-	"github.com/legosandorigami/opentelemetry-tutorial/lib/tracing"
+	"github.com/legosandorigami/opentelemetry-tutorial/go/lib/tracing"
 	"go.opentelemetry.io/otel/attribute"
 	"go.opentelemetry.io/otel/trace"
 )
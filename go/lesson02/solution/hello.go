@@ -7,12 +7,15 @@ import (
 	"os"
 	"time"
 
-	"github.com/legosandorigami/opentelemetry-tutorial/lib/tracing"
+	"github.com/legosandorigami/opentelemetry-tutorial/go/lib/tracing"
 	"go.opentelemetry.io/otel"
 	"go.opentelemetry.io/otel/attribute"
 	"go.opentelemetry.io/otel/trace"
 )
 
+// retrieving the tracer named "say-hello-tracer" once, from the global TracerProvider
+var tracer = otel.Tracer("say-hello-tracer")
+
 func main() {
 	// checking if the number of command-line arguments is exactly 2 (program name and one argument).
 	if len(os.Args) != 2 {
@@ -33,9 +36,6 @@ func main() {
 		}
 	}()
 
-	// creating a tracer from the tracer provider named "say-hello-tracer"
-	tracer := tracerPovider.Tracer("say-hello-tracer")
-
 	helloTo := os.Args[1]
 
 	// starting a new span named "say-hello"
@@ -53,9 +53,6 @@ func main() {
 }
 
 func formatString(ctx context.Context, helloTo string) string {
-	// Retrieve or create a named tracer.
-	tracer := otel.Tracer("say-hello-tracer")
-
 	// Start a new span named "formatString".
 	_, span := tracer.Start(ctx, "formatString")
 	defer span.End()
@@ -73,9 +70,6 @@ func formatString(ctx context.Context, helloTo string) string {
 }
 
 func printHello(ctx context.Context, helloStr string) {
-	// Retrieve or create a named tracer
-	tracer := otel.Tracer("say-hello-tracer")
-
 	// Start a new span named "printHello"
 	_, span := tracer.Start(ctx, "printHello")
 	defer span.End()
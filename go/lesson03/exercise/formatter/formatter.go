@@ -1,17 +1,14 @@
 package main
 
 import (
-	"fmt"
 	"log"
 	"net/http"
+
+	"github.com/legosandorigami/opentelemetry-tutorial/lesson03/exercise/hello"
 )
 
 func main() {
-	http.HandleFunc("/format", func(w http.ResponseWriter, r *http.Request) {
-		helloTo := r.FormValue("helloTo")
-		helloStr := fmt.Sprintf("Hello, %s!", helloTo)
-		w.Write([]byte(helloStr))
-	})
+	http.Handle("/format", hello.FormatHandler())
 
 	log.Fatal(http.ListenAndServe(":8081", nil))
 }
@@ -0,0 +1,113 @@
+// Package hello holds the code of the client, the formatter and the publisher, out of their main packages, so that it
+// can be tested and reused.
+package hello
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+
+	xhttp "github.com/legosandorigami/opentelemetry-tutorial/go/lib/http"
+	"github.com/legosandorigami/opentelemetry-tutorial/go/lib/tracing"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/trace"
+)
+
+const (
+	// FORMATTER_ADDR and PUBLISHER_ADDR are the addresses the formatter and the publisher listen on
+	FORMATTER_ADDR = "localhost:8081"
+	PUBLISHER_ADDR = "localhost:8082"
+)
+
+// FormatString asks the formatter at formatterAddr to format the greeting of helloTo, in a "formatString" span.
+func FormatString(ctx context.Context, tracer trace.Tracer, formatterAddr, helloTo string) (string, error) {
+	// preparing to send an http get request to the "formatter" service
+	v := url.Values{}
+	v.Set("helloTo", helloTo)
+	url := "http://" + formatterAddr + "/format?" + v.Encode()
+
+	// starting a new span named "formatString"
+	_, span := tracer.Start(ctx, "formatString")
+	defer span.End()
+
+	// creating a new HTTP request to formatter microservice
+	req, err := http.NewRequest("GET", url, nil)
+	if err != nil {
+		// recording the error in the span and marking the span as failed
+		tracing.RecordFailure(span, err,
+			attribute.String("format-request-error", fmt.Sprintf("Failed to create a request to  the `formatter` service for the string %s", helloTo)))
+		return "", err
+	}
+
+	//sending a get request
+	resp, err := xhttp.Do(req)
+	if err != nil {
+		// recording the error in the span and marking the span as failed
+		tracing.RecordFailure(span, err,
+			attribute.String("format-response-error", fmt.Sprintf("Failed to format the string %s", helloTo)))
+		return "", err
+	}
+
+	helloStr := string(resp)
+
+	// adding an event to the span indicating a successful response was received
+	span.AddEvent("format-event-response", trace.WithAttributes(
+		attribute.String("format-response", fmt.Sprintf("string-format: %s", helloStr)),
+	))
+
+	// printing the span details
+	tracing.PrintSpanContents(span)
+
+	return helloStr, nil
+}
+
+// PrintHello asks the publisher at publisherAddr to print helloStr, in a "printHello" span.
+func PrintHello(ctx context.Context, tracer trace.Tracer, publisherAddr, helloStr string) error {
+	// starting a new span named "printHello"
+	_, span := tracer.Start(ctx, "printHello")
+	defer span.End()
+
+	// preparing to send an http get request to the "publisher" service
+	v := url.Values{}
+	v.Set("helloStr", helloStr)
+	url := "http://" + publisherAddr + "/publish?" + v.Encode()
+	req, err := http.NewRequest("GET", url, nil)
+	if err != nil {
+		// recording the error in the span and marking the span as failed
+		tracing.RecordFailure(span, err,
+			attribute.String("publish-request-error", fmt.Sprintf("Failed to create a request to  the `publisher` service for the string %s", helloStr)))
+		return err
+	}
+
+	//sending a get request
+	if _, err := xhttp.Do(req); err != nil {
+		// recording the error in the span and marking the span as failed
+		tracing.RecordFailure(span, err,
+			attribute.String("publish-response-error", fmt.Sprintf("Failed to publish the string %s", helloStr)))
+		return err
+	}
+
+	// printing the span details
+	tracing.PrintSpanContents(span)
+
+	return nil
+}
+
+// FormatHandler returns the handler of the formatter, formatting the greeting.
+func FormatHandler() http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		helloTo := r.FormValue("helloTo")
+		helloStr := fmt.Sprintf("Hello, %s!", helloTo)
+		w.Write([]byte(helloStr))
+	})
+}
+
+// PublishHandler returns the handler of the publisher, writing the greeting to out.
+func PublishHandler(out io.Writer) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		helloStr := r.FormValue("helloStr")
+		fmt.Fprintln(out, helloStr)
+	})
+}
@@ -3,13 +3,13 @@ package main
 import (
 	"log"
 	"net/http"
+	"os"
+
+	"github.com/legosandorigami/opentelemetry-tutorial/lesson03/exercise/hello"
 )
 
 func main() {
-	http.HandleFunc("/publish", func(w http.ResponseWriter, r *http.Request) {
-		helloStr := r.FormValue("helloStr")
-		println(helloStr)
-	})
+	http.Handle("/publish", hello.PublishHandler(os.Stdout))
 
 	log.Fatal(http.ListenAndServe(":8082", nil))
 }
@@ -0,0 +1,193 @@
+// Package hello holds the code of the client, the formatter and the publisher, out of their main packages, so that it
+// can be tested and reused.
+package hello
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+
+	"github.com/legosandorigami/opentelemetry-tutorial/go/lib/attrs"
+	xhttp "github.com/legosandorigami/opentelemetry-tutorial/go/lib/http"
+	"github.com/legosandorigami/opentelemetry-tutorial/go/lib/tracing"
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/propagation"
+	"go.opentelemetry.io/otel/trace"
+)
+
+const (
+	// FORMATTER_ADDR and PUBLISHER_ADDR are the addresses the formatter and the publisher listen on
+	FORMATTER_ADDR = "localhost:8081"
+	PUBLISHER_ADDR = "localhost:8082"
+)
+
+// FormatString asks the formatter at formatterAddr to format the greeting of helloTo, in a "formatString" span.
+func FormatString(ctx context.Context, tracer trace.Tracer, formatterAddr, helloTo string) (string, error) {
+	// preparing to send an http get request to the "formatter" service
+	v := url.Values{}
+	v.Set("helloTo", helloTo)
+	url := "http://" + formatterAddr + "/format?" + v.Encode()
+
+	// creating a client span indicating that it is an RPC
+	ctx, span := tracer.Start(ctx, "formatString", trace.WithSpanKind(trace.SpanKindClient))
+	defer span.End()
+
+	// creating a new HTTP request to formatter microservice
+	req, err := http.NewRequest("GET", url, nil)
+	if err != nil {
+		return "", err
+	}
+
+	// recording the attributes of the request following the semantic conventions
+	span.SetAttributes(append(attrs.HTTPClientAttrs(req), attrs.PeerService("formatter"))...)
+
+	// retrieve the propagator
+	propagator := otel.GetTextMapPropagator()
+
+	// injecting the span context into the request headers
+	propagator.Inject(ctx, propagation.HeaderCarrier(req.Header))
+
+	//sending a get request
+	resp, err := xhttp.Do(req)
+	if err != nil {
+		// recording the error in the span and marking the span as failed
+		tracing.RecordFailure(span, err,
+			attribute.String("format-response-error", fmt.Sprintf("Failed to format the string %s", helloTo)))
+		return "", err
+	}
+
+	helloStr := string(resp)
+
+	// adding an event to the span indicating a successful response was received
+	span.AddEvent("format-event-response", trace.WithAttributes(
+		attribute.String("format-response", fmt.Sprintf("string-format: %s", helloStr)),
+	))
+
+	// printing the span details
+	tracing.PrintSpanContents(span)
+
+	return helloStr, nil
+}
+
+// FormatStringWrapped is FormatString written with tracing.Trace, which starts and ends the "formatString" client span,
+// and records the error of the function in it. The span is started with the tracer of the global TracerProvider.
+func FormatStringWrapped(ctx context.Context, formatterAddr, helloTo string) (string, error) {
+	return tracing.Trace(ctx, "formatString", func(ctx context.Context) (string, error) {
+		// preparing to send an http get request to the "formatter" service
+		v := url.Values{}
+		v.Set("helloTo", helloTo)
+		req, err := http.NewRequest("GET", "http://"+formatterAddr+"/format?"+v.Encode(), nil)
+		if err != nil {
+			return "", err
+		}
+
+		// recording the attributes of the request in the span started by tracing.Trace
+		span := trace.SpanFromContext(ctx)
+		span.SetAttributes(append(attrs.HTTPClientAttrs(req), attrs.PeerService("formatter"))...)
+
+		// injecting the span context into the request headers, and sending the request
+		otel.GetTextMapPropagator().Inject(ctx, propagation.HeaderCarrier(req.Header))
+		resp, err := xhttp.Do(req)
+		if err != nil {
+			return "", err
+		}
+
+		helloStr := string(resp)
+		span.AddEvent("format-event-response", trace.WithAttributes(
+			attribute.String("format-response", fmt.Sprintf("string-format: %s", helloStr)),
+		))
+		return helloStr, nil
+	}, trace.WithSpanKind(trace.SpanKindClient))
+}
+
+// PrintHello asks the publisher at publisherAddr to print helloStr, in a "printHello" span.
+func PrintHello(ctx context.Context, tracer trace.Tracer, publisherAddr, helloStr string) error {
+	// preparing to send an http get request to the "publisher" service
+	v := url.Values{}
+	v.Set("helloStr", helloStr)
+	url := "http://" + publisherAddr + "/publish?" + v.Encode()
+
+	// creating a new HTTP request to printer microservice
+	req, err := http.NewRequest("GET", url, nil)
+	if err != nil {
+		return err
+	}
+
+	// creating a client span, with the attributes of the request following the semantic conventions
+	ctx, span := tracer.Start(ctx, "printHello",
+		trace.WithAttributes(append(attrs.HTTPClientAttrs(req), attrs.PeerService("publisher"))...),
+		trace.WithSpanKind(trace.SpanKindClient),
+	)
+	defer span.End()
+
+	// retrieve the propagator
+	propagator := otel.GetTextMapPropagator()
+
+	// injecting the span context into the request headers
+	propagator.Inject(ctx, propagation.HeaderCarrier(req.Header))
+
+	//sending a get request
+	if _, err := xhttp.Do(req); err != nil {
+		// recording the error in the span and marking the span as failed
+		tracing.RecordFailure(span, err,
+			attribute.String("publish-response-error", fmt.Sprintf("Failed to publish the string %s", helloStr)))
+		return err
+	}
+
+	// printing the span details
+	tracing.PrintSpanContents(span)
+
+	return nil
+}
+
+// FormatHandler returns the handler of the formatter, formatting the greeting in a "format" span.
+func FormatHandler(tracer trace.Tracer) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		// retrieving the global propagator
+		propagator := otel.GetTextMapPropagator()
+
+		// extracting the span context from the request headers
+		ctx := propagator.Extract(context.Background(), propagation.HeaderCarrier(r.Header))
+
+		// starting a new span named "format" as a child of the extracted span context
+		_, span := tracer.Start(ctx, "format", trace.WithSpanKind(trace.SpanKindServer))
+		defer span.End()
+
+		helloTo := r.FormValue("helloTo")
+		helloStr := fmt.Sprintf("Hello, %s!", helloTo)
+
+		// adding an event to the span indicating that the string was properly formatted
+		span.AddEvent("event", trace.WithAttributes(
+			attribute.String("string-format", helloStr),
+		))
+
+		// printing the span details
+		tracing.PrintSpanContents(span)
+
+		w.Write([]byte(helloStr))
+	})
+}
+
+// PublishHandler returns the handler of the publisher, writing the greeting to out in a "publish" span.
+func PublishHandler(tracer trace.Tracer, out io.Writer) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		// retrieve the global propagator
+		propagator := otel.GetTextMapPropagator()
+
+		// extracting the span context from the request headers
+		ctx := propagator.Extract(context.Background(), propagation.HeaderCarrier(r.Header))
+
+		// starting a new span named "publish" as a child of the extracted span context
+		_, span := tracer.Start(ctx, "publish")
+		defer span.End()
+
+		helloStr := r.FormValue("helloStr")
+		fmt.Fprintln(out, helloStr)
+
+		// printing the span details
+		tracing.PrintSpanContents(span)
+	})
+}
@@ -0,0 +1,121 @@
+package hello
+
+import (
+	"bytes"
+	"context"
+	"net/http/httptest"
+	"os"
+	"strings"
+	"testing"
+
+	"github.com/legosandorigami/opentelemetry-tutorial/go/lib/tracingtest"
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/propagation"
+	semconv "go.opentelemetry.io/otel/semconv/v1.4.0"
+	"go.opentelemetry.io/otel/trace"
+)
+
+func TestMain(m *testing.M) {
+	// installing the propagator the services install through tracing.InitTracerProvider
+	otel.SetTextMapPropagator(propagation.TraceContext{})
+	os.Exit(m.Run())
+}
+
+func TestSayHello(t *testing.T) {
+	tp, sr := tracingtest.NewRecorder()
+	tracer := tp.Tracer("test")
+
+	// running the formatter and the publisher in process
+	formatter := httptest.NewServer(FormatHandler(tracer))
+	defer formatter.Close()
+	var out bytes.Buffer
+	publisher := httptest.NewServer(PublishHandler(tracer, &out))
+	defer publisher.Close()
+
+	ctx, span := tracer.Start(context.Background(), "say-hello")
+	helloStr, err := FormatString(ctx, tracer, strings.TrimPrefix(formatter.URL, "http://"), "Bryan")
+	if err != nil {
+		t.Fatalf("FormatString() error = %v", err)
+	}
+	if err := PrintHello(ctx, tracer, strings.TrimPrefix(publisher.URL, "http://"), helloStr); err != nil {
+		t.Fatalf("PrintHello() error = %v", err)
+	}
+	span.End()
+
+	if got, want := out.String(), "Hello, Bryan!\n"; got != want {
+		t.Errorf("published %q, want %q", got, want)
+	}
+
+	tracingtest.AssertSpanTree(t, sr, tracingtest.Span{
+		Name: "say-hello",
+		Children: []tracingtest.Span{
+			{
+				Name: "formatString",
+				Matchers: []tracingtest.Matcher{
+					tracingtest.HasKind(trace.SpanKindClient),
+					tracingtest.HasAttributes(semconv.HTTPMethodKey.String("GET")),
+					tracingtest.HasEvent("format-event-response", attribute.String("format-response", "string-format: Hello, Bryan!")),
+				},
+				Children: []tracingtest.Span{{
+					Name: "format",
+					Matchers: []tracingtest.Matcher{
+						tracingtest.HasKind(trace.SpanKindServer),
+						tracingtest.HasRemoteParent(),
+						tracingtest.HasEvent("event", attribute.String("string-format", "Hello, Bryan!")),
+					},
+				}},
+			},
+			{
+				Name:     "printHello",
+				Matchers: []tracingtest.Matcher{tracingtest.HasKind(trace.SpanKindClient)},
+				Children: []tracingtest.Span{{
+					Name:     "publish",
+					Matchers: []tracingtest.Matcher{tracingtest.HasRemoteParent()},
+				}},
+			},
+		},
+	})
+}
+
+func TestFormatStringWrapped(t *testing.T) {
+	// the span of tracing.Trace is started with the tracer of the global TracerProvider
+	tp, sr := tracingtest.NewRecorder()
+	previous := otel.GetTracerProvider()
+	otel.SetTracerProvider(tp)
+	defer otel.SetTracerProvider(previous)
+	tracer := tp.Tracer("test")
+
+	formatter := httptest.NewServer(FormatHandler(tracer))
+	defer formatter.Close()
+
+	ctx, span := tracer.Start(context.Background(), "say-hello")
+	helloStr, err := FormatStringWrapped(ctx, strings.TrimPrefix(formatter.URL, "http://"), "Bryan")
+	if err != nil {
+		t.Fatalf("FormatStringWrapped() error = %v", err)
+	}
+	span.End()
+
+	if helloStr != "Hello, Bryan!" {
+		t.Errorf("FormatStringWrapped() = %q, want %q", helloStr, "Hello, Bryan!")
+	}
+
+	// the trace is the one of FormatString
+	tracingtest.AssertSpanTree(t, sr, tracingtest.Span{
+		Name: "say-hello",
+		Children: []tracingtest.Span{{
+			Name: "formatString",
+			Matchers: []tracingtest.Matcher{
+				tracingtest.HasKind(trace.SpanKindClient),
+				tracingtest.HasAttributes(semconv.HTTPMethodKey.String("GET")),
+				tracingtest.HasEvent("format-event-response", attribute.String("format-response", "string-format: Hello, Bryan!")),
+			},
+			Children: []tracingtest.Span{{Name: "format"}},
+		}},
+	})
+
+	// a failure of the formatter is recorded in the span, and returned
+	if _, err := FormatStringWrapped(context.Background(), "localhost:1", "Bryan"); err == nil {
+		t.Error("FormatStringWrapped() error = nil, want the error of the request")
+	}
+}
@@ -4,10 +4,10 @@ import (
 	"context"
 	"log"
 	"net/http"
+	"os"
 
-	"github.com/legosandorigami/opentelemetry-tutorial/lib/tracing"
-	"go.opentelemetry.io/otel"
-	"go.opentelemetry.io/otel/propagation"
+	"github.com/legosandorigami/opentelemetry-tutorial/go/lib/tracing"
+	"github.com/legosandorigami/opentelemetry-tutorial/lesson03/solution/hello"
 )
 
 func main() {
@@ -28,23 +28,8 @@ func main() {
 	// retrieving or creating a tracer with name "publisher-tracer"
 	tracer := tracerPovider.Tracer("publisher-tracer")
 
-	http.HandleFunc("/publish", func(w http.ResponseWriter, r *http.Request) {
-		// retrieve the global propagator
-		propagator := otel.GetTextMapPropagator()
-
-		// extracting the span context from the request headers
-		ctx := propagator.Extract(context.Background(), propagation.HeaderCarrier(r.Header))
-
-		// starting a new span named "publish" as a child of the extracted span context
-		_, span := tracer.Start(ctx, "publish")
-		defer span.End()
-
-		helloStr := r.FormValue("helloStr")
-		println(helloStr)
-
-		// printing the span details
-		tracing.PrintSpanContents(span)
-	})
+	// registering the handler of the hello package
+	http.Handle("/publish", hello.PublishHandler(tracer, os.Stdout))
 
 	log.Fatal(http.ListenAndServe(":8082", nil))
 }
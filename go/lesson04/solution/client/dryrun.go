@@ -0,0 +1,52 @@
+package main
+
+import (
+	"fmt"
+	"io"
+	"net/http"
+	"sort"
+	"strings"
+
+	"go.opentelemetry.io/otel"
+
+	xhttp "github.com/legosandorigami/opentelemetry-tutorial/go/lib/http"
+)
+
+// dryRunTransport prints the headers carrying the context of the requests instead of sending them, and answers every
+// request with an empty 200 OK, so that the client goes through all its calls as if the services were up
+type dryRunTransport struct {
+	out io.Writer
+}
+
+func (t *dryRunTransport) RoundTrip(req *http.Request) (*http.Response, error) {
+	// the headers of the propagators in use: traceparent, tracestate and baggage for the W3C formats, b3 or
+	// uber-trace-id for the others, and the time left before the deadline with --timeout
+	fields := map[string]bool{http.CanonicalHeaderKey(xhttp.TIMEOUT_HEADER): true}
+	for _, field := range otel.GetTextMapPropagator().Fields() {
+		fields[http.CanonicalHeaderKey(field)] = true
+	}
+
+	var lines []string
+	for key, values := range req.Header {
+		if fields[key] {
+			lines = append(lines, fmt.Sprintf("  %s: %s", strings.ToLower(key), strings.Join(values, ",")))
+		}
+	}
+	sort.Strings(lines)
+
+	fmt.Fprintf(t.out, "%s %s\n", req.Method, req.URL)
+	for _, line := range lines {
+		fmt.Fprintln(t.out, line)
+	}
+
+	return &http.Response{
+		Status:     "200 OK",
+		StatusCode: http.StatusOK,
+		Proto:      "HTTP/1.1",
+		ProtoMajor: 1,
+		ProtoMinor: 1,
+		Header:     http.Header{},
+		Body:       http.NoBody,
+		Request:    req,
+	}, nil
+}
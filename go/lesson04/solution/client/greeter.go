@@ -0,0 +1,77 @@
+package main
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"net/url"
+
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/baggage"
+	"go.opentelemetry.io/otel/propagation"
+	"go.opentelemetry.io/otel/trace"
+
+	"github.com/legosandorigami/opentelemetry-tutorial/go/lib/attrs"
+	xhttp "github.com/legosandorigami/opentelemetry-tutorial/go/lib/http"
+	"github.com/legosandorigami/opentelemetry-tutorial/go/lib/tracing"
+)
+
+// greet calls the greeter service, which formats the greeting through the formatter, which publishes it through the
+// publisher: the resulting trace is one level deeper at every hop.
+func greet(ctx context.Context, greeterAddr, helloTo string, baggageItems map[string]string) error {
+	// preparing to send an http get request to the "greeter" service
+	v := url.Values{}
+	v.Set("helloTo", helloTo)
+	url := xhttp.BaseURL(greeterAddr) + "/greet?" + v.Encode()
+
+	// creating a baggage containing the baggage items and adding it to the context ctx
+	members := make([]baggage.Member, 0, len(baggageItems))
+	for k, v := range baggageItems {
+		bm, err := baggage.NewMember(k, v)
+		if err != nil {
+			return fmt.Errorf("failed to create a new baggage member: %v", err)
+		}
+		members = append(members, bm)
+	}
+	b, err := baggage.New(members...)
+	if err != nil {
+		return fmt.Errorf("failed to create a new baggage: %v", err)
+	}
+	ctx = baggage.ContextWithBaggage(ctx, b)
+
+	// creating a new HTTP request, whose attributes the span records following the semantic conventions
+	req, err := http.NewRequestWithContext(ctx, "GET", url, nil)
+	if err != nil {
+		return err
+	}
+
+	// creating a span with custom attributes indicating that it is an RPC
+	ctx, span := tracer.Start(ctx, "greet",
+		trace.WithAttributes(attrs.HTTPClientAttrs(req)...),
+		trace.WithAttributes(xhttp.PeerAttributes(greeterAddr)...),
+		trace.WithSpanKind(trace.SpanKindClient),
+	)
+	defer span.End()
+
+	// sending the request with the context holding the span
+	req = req.WithContext(ctx)
+
+	// injecting the span context and the baggage into the request headers
+	otel.GetTextMapPropagator().Inject(ctx, propagation.HeaderCarrier(req.Header))
+
+	// passing the time left before the deadline of the greeting on, if any
+	xhttp.InjectDeadline(ctx, req.Header)
+
+	if _, err := xhttp.Do(req); err != nil {
+		// recording the error in the span and marking the span as failed
+		tracing.RecordFailure(span, err,
+			attribute.String("greet-response-error", fmt.Sprintf("Failed to greet %s", helloTo)))
+		return err
+	}
+
+	// printing the span details
+	tracing.PrintSpanContents(span)
+
+	return nil
+}
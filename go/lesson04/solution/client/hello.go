@@ -2,88 +2,280 @@ package main
 
 import (
 	"context"
+	"errors"
+	"flag"
 	"fmt"
 	"log"
 	"net/http"
 	"net/url"
 	"os"
+	"time"
+
+	"github.com/spf13/cobra"
 
 	"go.opentelemetry.io/otel"
 	"go.opentelemetry.io/otel/attribute"
 	"go.opentelemetry.io/otel/baggage"
 	"go.opentelemetry.io/otel/propagation"
-	semconv "go.opentelemetry.io/otel/semconv/v1.4.0"
+	traceSdk "go.opentelemetry.io/otel/sdk/trace"
 	"go.opentelemetry.io/otel/trace"
 
-	xhttp "github.com/legosandorigami/opentelemetry-tutorial/lib/http"
-	"github.com/legosandorigami/opentelemetry-tutorial/lib/tracing"
+	"github.com/legosandorigami/opentelemetry-tutorial/go/lib/attrs"
+	"github.com/legosandorigami/opentelemetry-tutorial/go/lib/config"
+	xhttp "github.com/legosandorigami/opentelemetry-tutorial/go/lib/http"
+	"github.com/legosandorigami/opentelemetry-tutorial/go/lib/tracing"
 )
 
+// retrieving the tracer named "say-hello-tracer" once, from the global TracerProvider
+var tracer = otel.Tracer("say-hello-tracer")
+
+// options holds the command-line flags of the client
+type options struct {
+	greeting    string
+	baggage     map[string]string
+	propagation string
+	repeat      int
+	retries     int
+	timeout     time.Duration
+	greeter     bool
+	stream      bool
+	interactive bool
+	dryRun      bool
+	load        loadOptions
+}
+
 func main() {
-	// checking if the number of command-line arguments is exactly 3 (program name and two arguments)
-	if len(os.Args) != 3 {
-		panic("ERROR: Expecting two arguments")
+	if err := newRootCmd().Execute(); err != nil {
+		os.Exit(1)
+	}
+}
+
+// newRootCmd creates the "hello" command, which greets the person given as argument through the formatter and the publisher
+func newRootCmd() *cobra.Command {
+	opts := &options{}
+
+	// registering the service addresses flags, their defaults are read from the environment variables
+	goFlags := flag.NewFlagSet("config", flag.ContinueOnError)
+	cfg := config.Register(goFlags)
+
+	cmd := &cobra.Command{
+		Use:   "hello [flags] NAME",
+		Short: "Greets NAME through the formatter and publisher services, producing one trace per greeting",
+		Args: func(cmd *cobra.Command, args []string) error {
+			// the names are read from the standard input in the interactive mode
+			if opts.interactive && len(args) > 0 {
+				return fmt.Errorf("NAME cannot be given with --interactive, the names are read from the standard input")
+			} else if opts.interactive {
+				return nil
+			}
+			return cobra.ExactArgs(1)(cmd, args)
+		},
+		RunE: func(cmd *cobra.Command, args []string) error {
+			cmd.SilenceUsage = true
+			if opts.interactive {
+				return run(cfg, opts, "")
+			}
+			return run(cfg, opts, args[0])
+		},
 	}
 
-	// initializing the OpenTelemetry TracerProvider with the service name "hello-world"
-	tracerPovider, err := tracing.InitTracerProvider("hello-world")
-	if err != nil {
-		log.Fatalf("failed to create otel exporter: %v", err)
+	for _, name := range []string{"formatter-addr", "publisher-addr", "greeter-addr", "otlp-endpoint", "trace-ui-url", "api-key", "h2c"} {
+		cmd.Flags().AddGoFlag(goFlags.Lookup(name))
+	}
+	cmd.Flags().StringVarP(&opts.greeting, "greeting", "g", "", "greeting propagated to the formatter in the baggage, the formatter picks one from the locale when empty")
+	cmd.Flags().StringToStringVarP(&opts.baggage, "baggage", "b", nil, "additional baggage items, e.g. --baggage locale=fr,tenant=acme")
+	cmd.Flags().StringVar(&opts.propagation, "propagation", "", "comma-separated propagation formats: tracecontext, baggage, w3c, b3, b3multi, jaeger, xray, cloudtrace, cloudtrace-oneway or composite, those of PROPAGATION or OTEL_PROPAGATORS when empty, else w3c")
+	cmd.Flags().IntVarP(&opts.repeat, "repeat", "n", 1, "number of greetings to send, each one in its own trace")
+	cmd.Flags().BoolVar(&opts.dryRun, "dry-run", false, "print the headers carrying the trace context and the baggage of every request instead of sending it, and export no span")
+	cmd.Flags().BoolVarP(&opts.interactive, "interactive", "i", false, "greet the names read from the standard input, one per line, each one in its own trace, instead of NAME")
+	cmd.Flags().BoolVar(&opts.greeter, "greeter", false, "call the greeter service, which calls the formatter, instead of the formatter and the publisher")
+	cmd.Flags().BoolVar(&opts.stream, "stream", false, "publish through the Server-Sent Events endpoint of the publisher")
+	cmd.Flags().IntVar(&opts.retries, "retries", 2, "number of times a failed call to the formatter is retried")
+	cmd.Flags().DurationVar(&opts.timeout, "timeout", 0, "deadline of every greeting, passed on to the services, e.g. 500ms, none when zero")
+	cmd.Flags().Float64Var(&opts.load.rate, "rate", 0, "greetings per second to send continuously instead of --repeat, a summary is printed on exit")
+	cmd.Flags().DurationVar(&opts.load.duration, "duration", 0, "how long to send greetings with --rate, until interrupted when zero")
+	cmd.Flags().IntVar(&opts.load.concurrency, "concurrency", 1, "number of concurrent greetings with --rate")
+
+	return cmd
+}
+
+func run(cfg *config.Config, opts *options, helloTo string) error {
+	// initializing the OpenTelemetry TracerProvider with the service name "hello-world", or a TracerProvider exporting
+	// nothing in a dry run, whose spans only provide the IDs injected into the headers. Both propagate the context in the
+	// formats of --propagation, or of the PROPAGATION and OTEL_PROPAGATORS variables when not given
+	var tracerPovider *traceSdk.TracerProvider
+	if opts.dryRun {
+		propagator, err := tracing.ResolvePropagator(opts.propagation)
+		if err != nil {
+			return err
+		}
+		otel.SetTextMapPropagator(propagator)
+		tracerPovider = traceSdk.NewTracerProvider()
+		otel.SetTracerProvider(tracerPovider)
+	} else {
+		var err error
+		tracerPovider, err = tracing.InitTracerProviderWithBackend("hello-world", cfg.OTLPEndpoint, tracing.WithPropagation(opts.propagation))
+		if err != nil {
+			return fmt.Errorf("failed to create otel exporter: %v", err)
+		}
 	}
 
 	// creating a context and defering the shutdown of the TracerProvider to ensure proper cleanup
 	ctx := context.Background()
 	defer func() {
 		if err := tracerPovider.Shutdown(ctx); err != nil {
-			log.Fatalf("failed to shutdown TracerProvider: %v", err)
+			log.Printf("failed to shutdown TracerProvider: %v", err)
 		}
 	}()
 
-	// creating a tracer from the tracer provider named "say-hello-tracer"
-	tracer := tracerPovider.Tracer("say-hello-tracer")
+	// speaking HTTP/2 over cleartext connections when h2c is enabled, dialing the unix domain sockets of the services
+	// listening on one either way, and sending the API key with every request
+	var transport http.RoundTripper = xhttp.NewUnixTransport(cfg.FormatterAddr, cfg.PublisherAddr, cfg.GreeterAddr)
+	if cfg.H2C {
+		transport = xhttp.NewH2CTransport(cfg.FormatterAddr, cfg.PublisherAddr, cfg.GreeterAddr)
+	}
+	if opts.dryRun {
+		transport = &dryRunTransport{out: os.Stdout}
+	}
+	if cfg.APIKey != "" {
+		transport = xhttp.NewAPIKeyTransport(cfg.APIKey, transport)
+	}
+	http.DefaultClient.Transport = transport
+
+	// creating baggage items map with the additional items and the "greeting"
+	baggageItems := map[string]string{}
+	for k, v := range opts.baggage {
+		baggageItems[k] = v
+	}
+	if opts.greeting != "" {
+		baggageItems["greeting"] = opts.greeting
+	}
 
-	helloTo := os.Args[1]
-	greeting := os.Args[2]
+	// greeting the names typed one after the other in the interactive mode
+	if opts.interactive {
+		return runREPL(ctx, cfg, opts, baggageItems, os.Stdin, os.Stdout, tracerPovider.ForceFlush)
+	}
 
-	// starting a new span named "say-hello" creating a span with the context that contains the baggage just created above
+	// generating continuous load when a rate is given
+	if opts.load.rate < 0 {
+		return fmt.Errorf("--rate must not be negative")
+	}
+	if opts.load.rate > 0 {
+		if opts.load.concurrency < 1 {
+			return fmt.Errorf("--concurrency must be at least 1")
+		}
+		if opts.load.interval() <= 0 {
+			return fmt.Errorf("--rate must be at most %d greetings per second", int64(time.Second))
+		}
+		runLoad(ctx, cfg, opts, helloTo, baggageItems)
+		return nil
+	}
+
+	for i := 0; i < opts.repeat; i++ {
+		if err := sayHello(ctx, cfg, opts, helloTo, baggageItems); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+func sayHello(ctx context.Context, cfg *config.Config, opts *options, helloTo string, baggageItems map[string]string) error {
+	// starting a new root span named "say-hello"
 	ctx, span := tracer.Start(ctx, "say-hello")
 	span.SetAttributes(attribute.String("hello-to", helloTo))
 	defer span.End()
 
-	// creating baggage items map and add "greeting"
-	baggageItems := map[string]string{"greeting": greeting}
+	// printing the trace ID, and a link to the trace when the UI of the backend is known, whether the greeting succeeded
+	// or not: the trace of a failed greeting is the one worth opening
+	defer func() {
+		traceID := span.SpanContext().TraceID()
+		if cfg.TraceUIURL != "" {
+			log.Printf("trace: %s", tracing.TraceURL(cfg.TraceUIURL, traceID))
+		} else {
+			log.Printf("trace ID: %s", traceID)
+		}
+	}()
 
-	// calling `formatString` function with the context ctx.
-	helloStr, err := formatString(ctx, helloTo, baggageItems)
-	if err != nil {
-		log.Fatalf(err.Error())
+	// bounding the whole greeting with the timeout, whose deadline travels to the services along with the requests
+	if opts.timeout > 0 {
+		var cancel context.CancelFunc
+		ctx, cancel = context.WithTimeout(ctx, opts.timeout)
+		defer cancel()
+		span.SetAttributes(attribute.Int64("deadline.timeout_ms", opts.timeout.Milliseconds()))
 	}
 
-	// calling `printHello` function with the context ctx.
-	err = printHello(ctx, helloStr)
-	if err != nil {
-		log.Fatalf(err.Error())
+	if opts.greeter {
+		// calling the greeter, the rest of the chain is up to the services
+		if err := greet(ctx, cfg.GreeterAddr, helloTo, baggageItems); err != nil {
+			return err
+		}
+	} else {
+		// calling `formatString` function with the context ctx.
+		helloStr, err := formatStringWithRetries(ctx, cfg.FormatterAddr, helloTo, baggageItems, opts.retries)
+		if err != nil {
+			return err
+		}
+
+		// calling `printHello` function with the context ctx, or its streaming variant
+		publish := printHello
+		if opts.stream {
+			publish = printHelloStream
+		}
+		if err := publish(ctx, cfg.PublisherAddr, helloStr); err != nil {
+			return err
+		}
 	}
 
 	// printing the span details
 	tracing.PrintSpanContents(span)
+
+	return nil
 }
 
-func formatString(ctx context.Context, helloTo string, baggageItems map[string]string) (string, error) {
-	// retreiving a tracer named "say-hello-tracer" from the tracer provider
-	tracer := otel.Tracer("say-hello-tracer")
+// formatStringWithRetries calls formatString up to retries+1 times. Each retry runs on a new span linked to the span
+// of the failed attempt, rather than being nested under it, which is the recommended way to connect retries.
+func formatStringWithRetries(ctx context.Context, formatterAddr, helloTo string, baggageItems map[string]string, retries int) (string, error) {
+	var links []trace.Link
+	for attempt := 0; ; attempt++ {
+		helloStr, spanCtx, err := formatString(ctx, formatterAddr, helloTo, baggageItems, attempt, links...)
+		if err == nil || attempt >= retries {
+			return helloStr, err
+		}
+
+		// giving up on the requests rejected by the formatter, such as an invalid name, which would be rejected again
+		var statusErr *xhttp.StatusError
+		if errors.As(err, &statusErr) && !statusErr.Retryable() {
+			return "", err
+		}
+
+		// linking the next attempt to the span of the attempt that just failed
+		links = []trace.Link{{
+			SpanContext: spanCtx,
+			Attributes:  []attribute.KeyValue{attribute.String("link.reason", "retry")},
+		}}
+
+		// backing off a little before retrying, unless the greeting is canceled or times out in the meantime
+		select {
+		case <-time.After(time.Duration(attempt+1) * 100 * time.Millisecond):
+		case <-ctx.Done():
+			return "", ctx.Err()
+		}
+	}
+}
 
+func formatString(ctx context.Context, formatterAddr, helloTo string, baggageItems map[string]string, attempt int, links ...trace.Link) (string, trace.SpanContext, error) {
 	// preparing to send an http get request to the "formatter" service
 	v := url.Values{}
 	v.Set("helloTo", helloTo)
-	url := "http://localhost:8081/format?" + v.Encode()
+	url := xhttp.BaseURL(formatterAddr) + "/format?" + v.Encode()
 
 	// creating baggage members from the baggage items
 	baggageMembers := make([]baggage.Member, 0)
 	for k, v := range baggageItems {
 		bm, err := baggage.NewMember(k, v)
 		if err != nil {
-			return "", fmt.Errorf("failed to create a new baggage member: %v", err)
+			return "", trace.SpanContext{}, fmt.Errorf("failed to create a new baggage member: %v", err)
 		}
 		baggageMembers = append(baggageMembers, bm)
 	}
@@ -91,42 +283,47 @@ func formatString(ctx context.Context, helloTo string, baggageItems map[string]s
 	// creating a baggage containing the baggage members
 	b, err := baggage.New(baggageMembers...)
 	if err != nil {
-		return "", fmt.Errorf("failed to create a new baggage: %v", err)
+		return "", trace.SpanContext{}, fmt.Errorf("failed to create a new baggage: %v", err)
 	}
 
 	// adding baggage to the context ctx
 	ctx = baggage.ContextWithBaggage(ctx, b)
 
+	// creating a new HTTP request, whose attributes the span records following the semantic conventions
+	req, err := http.NewRequestWithContext(ctx, "GET", url, nil)
+	if err != nil {
+		return "", trace.SpanContext{}, err
+	}
+
 	// creating a span with the context ctx that contains the baggage, and custom attributes indicating that it is an RPC
 	ctx, span := tracer.Start(ctx, "formatString",
-		trace.WithAttributes(
-			semconv.NetPeerNameKey.String(url),
-			semconv.HTTPMethodKey.String("GET"),
-		),
+		trace.WithAttributes(attrs.HTTPClientAttrs(req)...),
+		trace.WithAttributes(xhttp.PeerAttributes(formatterAddr)...),
 		trace.WithSpanKind(trace.SpanKindClient),
+		trace.WithLinks(links...),
 	)
 	defer span.End()
 
-	// creating a new HTTP request to formatter microservice
-	req, err := http.NewRequest("GET", url, nil)
-	if err != nil {
-		return "", err
-	}
+	// recording the attempt number, the first attempt being 0
+	span.SetAttributes(attribute.Int("retry.attempt", attempt))
+
+	// sending the request with the context holding the span
+	req = req.WithContext(ctx)
 
 	// retrieving the propagator and injecting the span context into the request headers
 	propagator := otel.GetTextMapPropagator()
 	propagator.Inject(ctx, propagation.HeaderCarrier(req.Header))
 
-	// Uncomment the line below to see the injected baggage and trace ID in the request headers
-	// fmt.Println(req.Header)
+	// passing the time left before the deadline of the greeting on, if any
+	xhttp.InjectDeadline(ctx, req.Header)
 
 	//sending a get request
 	resp, err := xhttp.Do(req)
 	if err != nil {
-		// recording the error in the span
-		span.RecordError(err, trace.WithAttributes(
-			attribute.String("format-response-error", fmt.Sprintf("Failed to format the string %s", helloTo))))
-		return "", err
+		// recording the error in the span and marking the span as failed
+		tracing.RecordFailure(span, err,
+			attribute.String("format-response-error", fmt.Sprintf("Failed to format the string %s", helloTo)))
+		return "", span.SpanContext(), err
 	}
 
 	helloStr := string(resp)
@@ -139,43 +336,44 @@ func formatString(ctx context.Context, helloTo string, baggageItems map[string]s
 	// printing the span details
 	tracing.PrintSpanContents(span)
 
-	return helloStr, nil
+	return helloStr, span.SpanContext(), nil
 }
 
-func printHello(ctx context.Context, helloStr string) error {
-	// retreiving a tracer from the tracer provider
-	tracer := otel.Tracer("say-hello-tracer")
-
+func printHello(ctx context.Context, publisherAddr, helloStr string) error {
 	// preparing to send an http get request to the "publisher" service
 	v := url.Values{}
 	v.Set("helloStr", helloStr)
-	url := "http://localhost:8082/publish?" + v.Encode()
+	url := xhttp.BaseURL(publisherAddr) + "/publish?" + v.Encode()
 
-	// creating a new HTTP request to printer microservice
-	req, err := http.NewRequest("GET", url, nil)
+	// creating a new HTTP request, whose attributes the span records following the semantic conventions
+	req, err := http.NewRequestWithContext(ctx, "GET", url, nil)
 	if err != nil {
 		return err
 	}
 
 	// creating a span with custom attributes
 	ctx, span := tracer.Start(ctx, "printHello",
-		trace.WithAttributes(
-			semconv.NetPeerNameKey.String(url),
-			semconv.HTTPMethodKey.String("GET"),
-		),
+		trace.WithAttributes(attrs.HTTPClientAttrs(req)...),
+		trace.WithAttributes(xhttp.PeerAttributes(publisherAddr)...),
 		trace.WithSpanKind(trace.SpanKindClient),
 	)
 	defer span.End()
 
+	// sending the request with the context holding the span
+	req = req.WithContext(ctx)
+
 	// retrieving the propagator and injecting the span context into the request headers
 	propagator := otel.GetTextMapPropagator()
 	propagator.Inject(ctx, propagation.HeaderCarrier(req.Header))
 
+	// passing the time left before the deadline of the greeting on, if any
+	xhttp.InjectDeadline(ctx, req.Header)
+
 	//sending a get request
 	if _, err := xhttp.Do(req); err != nil {
-		// recording the error in the span
-		span.RecordError(err, trace.WithAttributes(
-			attribute.String("publish-response-error", fmt.Sprintf("Failed to publish the string %s", helloStr))))
+		// recording the error in the span and marking the span as failed
+		tracing.RecordFailure(span, err,
+			attribute.String("publish-response-error", fmt.Sprintf("Failed to publish the string %s", helloStr)))
 		return err
 	}
 
@@ -0,0 +1,65 @@
+package main
+
+import (
+	"io"
+	"net/http"
+	"os"
+	"strings"
+	"testing"
+
+	"go.opentelemetry.io/otel"
+)
+
+// dryRun runs the client with --dry-run and args, and returns the headers it printed
+func dryRun(t *testing.T, args ...string) string {
+	t.Helper()
+	defer otel.SetTextMapPropagator(otel.GetTextMapPropagator())
+	defer otel.SetTracerProvider(otel.GetTracerProvider())
+	defer func(transport http.RoundTripper) { http.DefaultClient.Transport = transport }(http.DefaultClient.Transport)
+
+	// capturing the standard output, where the dry run prints the requests
+	r, w, err := os.Pipe()
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer func(stdout *os.File) { os.Stdout = stdout }(os.Stdout)
+	os.Stdout = w
+
+	cmd := newRootCmd()
+	cmd.SetArgs(append([]string{"--dry-run"}, args...))
+	err = cmd.Execute()
+	w.Close()
+	out, _ := io.ReadAll(r)
+	if err != nil {
+		t.Fatalf("the client failed: %v", err)
+	}
+	return string(out)
+}
+
+func TestPropagationFormats(t *testing.T) {
+	for _, tt := range []struct {
+		name           string
+		env            map[string]string
+		args           []string
+		wantHeader     string
+		unwantedHeader string
+	}{
+		{"w3c by default", nil, nil, "traceparent:", "b3:"},
+		{"OTEL_PROPAGATORS", map[string]string{"OTEL_PROPAGATORS": "b3"}, nil, "b3:", "traceparent:"},
+		{"PROPAGATION over OTEL_PROPAGATORS", map[string]string{"PROPAGATION": "jaeger", "OTEL_PROPAGATORS": "b3"}, nil, "uber-trace-id:", "b3:"},
+		{"--propagation over the variables", map[string]string{"PROPAGATION": "jaeger", "OTEL_PROPAGATORS": "b3"}, []string{"--propagation", "tracecontext"}, "traceparent:", "uber-trace-id:"},
+	} {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Setenv("PROPAGATION", "")
+			t.Setenv("OTEL_PROPAGATORS", "")
+			for k, v := range tt.env {
+				t.Setenv(k, v)
+			}
+
+			out := dryRun(t, append(tt.args, "Brian")...)
+			if !strings.Contains(out, tt.wantHeader) || strings.Contains(out, tt.unwantedHeader) {
+				t.Errorf("headers printed:\n%s\nwant %s without %s", out, tt.wantHeader, tt.unwantedHeader)
+			}
+		})
+	}
+}
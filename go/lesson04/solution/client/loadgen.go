@@ -0,0 +1,127 @@
+package main
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"log"
+	"os"
+	"os/signal"
+	"slices"
+	"sync"
+	"time"
+
+	"github.com/legosandorigami/opentelemetry-tutorial/go/lib/config"
+)
+
+// loadOptions configures the load-generation mode of the client
+type loadOptions struct {
+	rate        float64
+	duration    time.Duration
+	concurrency int
+}
+
+// interval returns the time between two greetings at the rate of the options, 0 when the rate is too high for a
+// time.Duration to hold the interval, i.e. above a greeting per nanosecond
+func (o *loadOptions) interval() time.Duration {
+	return time.Duration(float64(time.Second) / o.rate)
+}
+
+// loadStats collects the outcome of the iterations of the load-generation mode
+type loadStats struct {
+	mu        sync.Mutex
+	latencies []time.Duration
+	errors    int
+	// dropped counts the ticks skipped because all the workers were busy
+	dropped int
+}
+
+func (s *loadStats) record(d time.Duration, err error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.latencies = append(s.latencies, d)
+	if err != nil {
+		s.errors++
+	}
+}
+
+// runLoad greets helloTo at the given rate from concurrency workers until the duration elapses or the process is interrupted.
+// Every iteration starts its own root span, so each request produces a separate trace.
+func runLoad(ctx context.Context, cfg *config.Config, opts *options, helloTo string, baggageItems map[string]string) {
+	// silencing the span details printed on every iteration, only the summary is of interest
+	log.SetOutput(io.Discard)
+	defer log.SetOutput(os.Stderr)
+
+	// stopping the load after the duration, or on the first interrupt
+	runCtx, stop := signal.NotifyContext(ctx, os.Interrupt)
+	defer stop()
+	if opts.load.duration > 0 {
+		var cancel context.CancelFunc
+		runCtx, cancel = context.WithTimeout(runCtx, opts.load.duration)
+		defer cancel()
+	}
+
+	stats := &loadStats{}
+	jobs := make(chan struct{})
+
+	var wg sync.WaitGroup
+	for i := 0; i < opts.load.concurrency; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for range jobs {
+				// the iterations are started from ctx rather than runCtx, so that in-flight requests complete on exit
+				start := time.Now()
+				err := sayHello(ctx, cfg, opts, helloTo, baggageItems)
+				stats.record(time.Since(start), err)
+			}
+		}()
+	}
+
+	start := time.Now()
+	ticker := time.NewTicker(opts.load.interval())
+	defer ticker.Stop()
+
+loop:
+	for {
+		select {
+		case <-runCtx.Done():
+			break loop
+		case <-ticker.C:
+			// skipping the tick when all the workers are busy, rather than queueing an unbounded backlog
+			select {
+			case jobs <- struct{}{}:
+			default:
+				stats.dropped++
+			}
+		}
+	}
+	close(jobs)
+	wg.Wait()
+
+	stats.print(os.Stdout, time.Since(start))
+}
+
+// print writes the number of requests, the error rate and the latency percentiles
+func (s *loadStats) print(w io.Writer, elapsed time.Duration) {
+	n := len(s.latencies)
+	fmt.Fprintf(w, "requests: %d in %s (%.1f/s)\n", n, elapsed.Round(time.Millisecond), float64(n)/elapsed.Seconds())
+	if s.dropped > 0 {
+		fmt.Fprintf(w, "dropped:  %d, all the workers being busy, raise --concurrency to reach --rate\n", s.dropped)
+	}
+	if n == 0 {
+		return
+	}
+	fmt.Fprintf(w, "errors:   %d (%.1f%%)\n", s.errors, 100*float64(s.errors)/float64(n))
+
+	slices.Sort(s.latencies)
+	percentile := func(p float64) time.Duration {
+		return s.latencies[int(p*float64(n-1))]
+	}
+	fmt.Fprintf(w, "latency:  p50=%s p90=%s p99=%s max=%s\n",
+		percentile(0.5).Round(time.Microsecond),
+		percentile(0.9).Round(time.Microsecond),
+		percentile(0.99).Round(time.Microsecond),
+		s.latencies[n-1].Round(time.Microsecond),
+	)
+}
@@ -0,0 +1,43 @@
+package main
+
+import (
+	"bufio"
+	"context"
+	"fmt"
+	"io"
+	"strings"
+
+	"github.com/legosandorigami/opentelemetry-tutorial/go/lib/config"
+)
+
+// REPL_PROMPT is printed whenever the interactive mode waits for a name
+const REPL_PROMPT = "name> "
+
+// runREPL greets every name read from in, one per line, until the end of the input, e.g. Ctrl-D in a terminal.
+// Every name is greeted in its own root span, and the spans are flushed right away with flush, so that the trace shows
+// up in the backend while the next name is typed. A failed greeting is reported to out without ending the loop.
+func runREPL(ctx context.Context, cfg *config.Config, opts *options, baggageItems map[string]string, in io.Reader, out io.Writer, flush func(ctx context.Context) error) error {
+	scanner := bufio.NewScanner(in)
+	for {
+		fmt.Fprint(out, REPL_PROMPT)
+		if !scanner.Scan() {
+			fmt.Fprintln(out)
+			return scanner.Err()
+		}
+
+		// skipping the empty lines, as a shell does
+		helloTo := strings.TrimSpace(scanner.Text())
+		if helloTo == "" {
+			continue
+		}
+
+		if err := sayHello(ctx, cfg, opts, helloTo, baggageItems); err != nil {
+			fmt.Fprintf(out, "error: %s\n", strings.TrimSpace(err.Error()))
+		}
+
+		// exporting the spans of the greeting now, rather than when the batch span processor gets to it
+		if err := flush(ctx); err != nil {
+			fmt.Fprintf(out, "failed to export the spans: %v\n", err)
+		}
+	}
+}
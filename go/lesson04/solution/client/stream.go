@@ -0,0 +1,100 @@
+package main
+
+import (
+	"bufio"
+	"context"
+	"fmt"
+	"net/http"
+	"net/url"
+	"strings"
+
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/propagation"
+	"go.opentelemetry.io/otel/trace"
+
+	"github.com/legosandorigami/opentelemetry-tutorial/go/lib/attrs"
+	xhttp "github.com/legosandorigami/opentelemetry-tutorial/go/lib/http"
+	"github.com/legosandorigami/opentelemetry-tutorial/go/lib/tracing"
+)
+
+// printHelloStream publishes the greeting through the Server-Sent Events endpoint of the publisher, recording every
+// acknowledgement received as an event of the client span, until the stream ends.
+func printHelloStream(ctx context.Context, publisherAddr, helloStr string) error {
+	// preparing to send an http get request to the streaming endpoint of the "publisher" service
+	v := url.Values{}
+	v.Set("helloStr", helloStr)
+	url := xhttp.BaseURL(publisherAddr) + "/publish/stream?" + v.Encode()
+
+	// creating a new HTTP request, whose attributes the span records following the semantic conventions
+	req, err := http.NewRequestWithContext(ctx, "GET", url, nil)
+	if err != nil {
+		return err
+	}
+
+	// creating a span with custom attributes, covering the whole stream
+	ctx, span := tracer.Start(ctx, "printHello",
+		trace.WithAttributes(attrs.HTTPClientAttrs(req)...),
+		trace.WithAttributes(xhttp.PeerAttributes(publisherAddr)...),
+		trace.WithSpanKind(trace.SpanKindClient),
+	)
+	defer span.End()
+
+	// sending the request with the context holding the span
+	req = req.WithContext(ctx)
+	req.Header.Set("Accept", "text/event-stream")
+
+	// injecting the span context and the baggage into the request headers
+	otel.GetTextMapPropagator().Inject(ctx, propagation.HeaderCarrier(req.Header))
+
+	// passing the time left before the deadline of the greeting on, if any
+	xhttp.InjectDeadline(ctx, req.Header)
+
+	resp, err := http.DefaultClient.Do(req)
+	if err != nil {
+		tracing.RecordFailure(span, err)
+		return err
+	}
+	defer resp.Body.Close()
+
+	// recording the protocol negotiated for the request
+	span.SetAttributes(xhttp.Flavor(resp.ProtoMajor, resp.ProtoMinor))
+
+	if resp.StatusCode != http.StatusOK {
+		err := fmt.Errorf("StatusCode: %d", resp.StatusCode)
+		tracing.RecordFailure(span, err)
+		return err
+	}
+
+	// reading the events as they arrive, an event ends with an empty line
+	var event, data string
+	scanner := bufio.NewScanner(resp.Body)
+	for scanner.Scan() {
+		line := scanner.Text()
+		switch {
+		case strings.HasPrefix(line, "event: "):
+			event = strings.TrimPrefix(line, "event: ")
+		case strings.HasPrefix(line, "data: "):
+			data = strings.TrimPrefix(line, "data: ")
+		case line == "":
+			span.AddEvent("sse.event", trace.WithAttributes(
+				attribute.String("sse.event.name", event),
+				attribute.String("sse.event.data", data),
+			))
+			if event == "error" {
+				err := fmt.Errorf("failed to publish the string %s: %s", helloStr, data)
+				tracing.RecordFailure(span, err)
+				return err
+			}
+		}
+	}
+	if err := scanner.Err(); err != nil {
+		tracing.RecordFailure(span, err)
+		return err
+	}
+
+	// printing the span details
+	tracing.PrintSpanContents(span)
+
+	return nil
+}
@@ -0,0 +1,89 @@
+package main
+
+import (
+	"context"
+	"log"
+	"os"
+	"os/signal"
+	"strings"
+	"syscall"
+
+	"github.com/legosandorigami/opentelemetry-tutorial/go/lib/config"
+	"github.com/legosandorigami/opentelemetry-tutorial/go/lib/messaging"
+	"github.com/legosandorigami/opentelemetry-tutorial/go/lib/tracing"
+	"github.com/segmentio/kafka-go"
+	"go.opentelemetry.io/otel"
+	semconv "go.opentelemetry.io/otel/semconv/v1.4.0"
+	"go.opentelemetry.io/otel/trace"
+)
+
+func main() {
+	// loading the service addresses from the command-line flags and environment variables
+	cfg := config.Load()
+	if cfg.KafkaBrokers == "" {
+		log.Fatal("ERROR: Expecting the Kafka brokers to be set with -kafka-brokers or KAFKA_BROKERS")
+	}
+
+	// initialize the OpenTelemetry TracerProvider with the service name "consumer"
+	tracerPovider, err := tracing.InitTracerProviderWithBackend("consumer", cfg.OTLPEndpoint, tracing.WithPropagation(cfg.Propagation))
+	if err != nil {
+		log.Fatalf("failed to create otel exporter: %v", err)
+	}
+
+	// defering the shutdown of the TracerProvider to ensure proper cleanup, with a context of its own, which the
+	// interrupt does not cancel
+	defer func() {
+		if err := tracerPovider.Shutdown(context.Background()); err != nil {
+			log.Fatalf("failed to shutdown TracerProvider: %v", err)
+		}
+	}()
+
+	// creating a context canceled on an interrupt or a termination, which ends the loop below rather than the process,
+	// so that the deferred shutdown exports the spans still buffered
+	ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt, syscall.SIGTERM)
+	defer stop()
+
+	// retrieving or creating a tracer with name "consumer-tracer"
+	tracer := tracerPovider.Tracer("consumer-tracer")
+
+	reader := kafka.NewReader(kafka.ReaderConfig{
+		Brokers: strings.Split(cfg.KafkaBrokers, ","),
+		Topic:   cfg.KafkaTopic,
+		GroupID: "consumer",
+	})
+	defer reader.Close()
+
+	for {
+		msg, err := reader.ReadMessage(ctx)
+		if ctx.Err() != nil {
+			log.Printf("stopping the consumer")
+			return
+		}
+		if err != nil {
+			log.Printf("failed to read message: %v", err)
+			return
+		}
+
+		// extracting the span context injected by the publisher from the message headers
+		msgCtx := otel.GetTextMapPropagator().Extract(ctx, messaging.NewKafkaHeaderCarrier(&msg))
+
+		// starting a consumer span continuing the trace of the request that produced the message
+		_, span := tracer.Start(msgCtx, msg.Topic+" process",
+			trace.WithAttributes(
+				semconv.MessagingSystemKey.String("kafka"),
+				semconv.MessagingDestinationKey.String(msg.Topic),
+				semconv.MessagingDestinationKindTopic,
+				semconv.MessagingOperationProcess,
+				semconv.MessagingKafkaPartitionKey.Int(msg.Partition),
+			),
+			trace.WithSpanKind(trace.SpanKindConsumer),
+		)
+
+		println(string(msg.Value))
+
+		// printing the span details
+		tracing.PrintSpanContents(span)
+
+		span.End()
+	}
+}
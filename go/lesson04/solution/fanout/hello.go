@@ -0,0 +1,174 @@
+package main
+
+import (
+	"context"
+	"flag"
+	"fmt"
+	"log"
+	"net/http"
+	"net/url"
+
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/baggage"
+	"go.opentelemetry.io/otel/propagation"
+	"go.opentelemetry.io/otel/trace"
+	"golang.org/x/sync/errgroup"
+
+	"github.com/legosandorigami/opentelemetry-tutorial/go/lib/attrs"
+	"github.com/legosandorigami/opentelemetry-tutorial/go/lib/config"
+	xhttp "github.com/legosandorigami/opentelemetry-tutorial/go/lib/http"
+	"github.com/legosandorigami/opentelemetry-tutorial/go/lib/tracing"
+)
+
+// retrieving the tracer named "say-hello-tracer" once, from the global TracerProvider
+var tracer = otel.Tracer("say-hello-tracer")
+
+func main() {
+	// loading the service addresses from the command-line flags and environment variables
+	cfg := config.Load()
+
+	// checking that a greeting and at least one name were given
+	if flag.NArg() < 2 {
+		panic("ERROR: Expecting a greeting followed by one or more names")
+	}
+
+	// initializing the OpenTelemetry TracerProvider with the service name "hello-world"
+	tracerPovider, err := tracing.InitTracerProviderWithBackend("hello-world", cfg.OTLPEndpoint, tracing.WithPropagation(cfg.Propagation))
+	if err != nil {
+		log.Fatalf("failed to create otel exporter: %v", err)
+	}
+
+	// creating a context and defering the shutdown of the TracerProvider to ensure proper cleanup
+	ctx := context.Background()
+	defer func() {
+		if err := tracerPovider.Shutdown(ctx); err != nil {
+			log.Fatalf("failed to shutdown TracerProvider: %v", err)
+		}
+	}()
+
+	greeting := flag.Arg(0)
+	names := flag.Args()[1:]
+
+	// adding the greeting to the baggage of the root context, every goroutine inherits it
+	member, err := baggage.NewMember("greeting", greeting)
+	if err != nil {
+		log.Fatalf("failed to create a new baggage member: %v", err)
+	}
+	b, err := baggage.New(member)
+	if err != nil {
+		log.Fatalf("failed to create a new baggage: %v", err)
+	}
+	ctx = baggage.ContextWithBaggage(ctx, b)
+
+	// starting a new span named "say-hello", all the spans created by the goroutines below are its children
+	ctx, span := tracer.Start(ctx, "say-hello")
+	defer span.End()
+
+	if err := sayHello(ctx, cfg, names); err != nil {
+		log.Fatalf("%v", err)
+	}
+
+	// printing the span details
+	tracing.PrintSpanContents(span)
+}
+
+// sayHello formats the greetings of all the names concurrently, then publishes them concurrently.
+// Each goroutine receives the context carrying the "say-hello" span, so that its span becomes a child of it.
+// Starting the spans from context.Background() instead would create as many disconnected traces.
+func sayHello(ctx context.Context, cfg *config.Config, names []string) error {
+	helloStrs := make([]string, len(names))
+
+	// the errgroup context is derived from ctx, so it carries the parent span, and is cancelled on the first error
+	g, gctx := errgroup.WithContext(ctx)
+	for i, name := range names {
+		g.Go(func() error {
+			helloStr, err := formatString(gctx, cfg.FormatterAddr, name)
+			if err != nil {
+				return err
+			}
+			// every goroutine writes to its own index, no locking is required
+			helloStrs[i] = helloStr
+			return nil
+		})
+	}
+	if err := g.Wait(); err != nil {
+		return err
+	}
+
+	g, gctx = errgroup.WithContext(ctx)
+	for _, helloStr := range helloStrs {
+		g.Go(func() error {
+			return printHello(gctx, cfg.PublisherAddr, helloStr)
+		})
+	}
+	return g.Wait()
+}
+
+func formatString(ctx context.Context, formatterAddr, helloTo string) (string, error) {
+	// preparing to send an http get request to the "formatter" service
+	v := url.Values{}
+	v.Set("helloTo", helloTo)
+	url := "http://" + formatterAddr + "/format?" + v.Encode()
+
+	// creating a new HTTP request, whose attributes the span records following the semantic conventions
+	req, err := http.NewRequestWithContext(ctx, "GET", url, nil)
+	if err != nil {
+		return "", err
+	}
+
+	// creating a span with the context ctx, its parent is the span stored in ctx by the caller
+	ctx, span := tracer.Start(ctx, "formatString",
+		trace.WithAttributes(attrs.HTTPClientAttrs(req)...),
+		trace.WithAttributes(attribute.String("hello-to", helloTo)),
+		trace.WithSpanKind(trace.SpanKindClient),
+	)
+	defer span.End()
+
+	// sending the request with the context holding the span
+	req = req.WithContext(ctx)
+
+	// injecting the span context and the baggage into the request headers
+	otel.GetTextMapPropagator().Inject(ctx, propagation.HeaderCarrier(req.Header))
+
+	resp, err := xhttp.Do(req)
+	if err != nil {
+		// recording the error in the span and marking the span as failed
+		tracing.RecordFailure(span, err,
+			attribute.String("format-response-error", fmt.Sprintf("Failed to format the string %s", helloTo)))
+		return "", err
+	}
+
+	return string(resp), nil
+}
+
+func printHello(ctx context.Context, publisherAddr, helloStr string) error {
+	// preparing to send an http get request to the "publisher" service
+	v := url.Values{}
+	v.Set("helloStr", helloStr)
+	url := "http://" + publisherAddr + "/publish?" + v.Encode()
+
+	// creating a span with the context ctx, its parent is the span stored in ctx by the caller
+	ctx, span := tracer.Start(ctx, "printHello", trace.WithSpanKind(trace.SpanKindClient))
+	defer span.End()
+
+	req, err := http.NewRequestWithContext(ctx, "GET", url, nil)
+	if err != nil {
+		return err
+	}
+
+	// recording the attributes of the request following the semantic conventions
+	span.SetAttributes(append(attrs.HTTPClientAttrs(req), attrs.PeerService("publisher"))...)
+
+	// injecting the span context and the baggage into the request headers
+	otel.GetTextMapPropagator().Inject(ctx, propagation.HeaderCarrier(req.Header))
+
+	if _, err := xhttp.Do(req); err != nil {
+		// recording the error in the span and marking the span as failed
+		tracing.RecordFailure(span, err,
+			attribute.String("publish-response-error", fmt.Sprintf("Failed to publish the string %s", helloStr)))
+		return err
+	}
+
+	return nil
+}
@@ -2,47 +2,162 @@ package main
 
 import (
 	"context"
+	"errors"
 	"fmt"
 	"log"
+	"log/slog"
+	"math"
 	"net/http"
+	"net/url"
+	"os"
+	"os/signal"
+	"strings"
+	"syscall"
+	"time"
+	"unicode"
+	"unicode/utf8"
 
-	"github.com/legosandorigami/opentelemetry-tutorial/lib/tracing"
+	"github.com/legosandorigami/opentelemetry-tutorial/go/lib/attrs"
+	"github.com/legosandorigami/opentelemetry-tutorial/go/lib/config"
+	xhttp "github.com/legosandorigami/opentelemetry-tutorial/go/lib/http"
+	"github.com/legosandorigami/opentelemetry-tutorial/go/lib/i18n"
+	"github.com/legosandorigami/opentelemetry-tutorial/go/lib/latency"
+	xlog "github.com/legosandorigami/opentelemetry-tutorial/go/lib/log"
+	"github.com/legosandorigami/opentelemetry-tutorial/go/lib/metrics"
+	"github.com/legosandorigami/opentelemetry-tutorial/go/lib/tracing"
+	"github.com/redis/go-redis/extra/redisotel/v9"
+	"github.com/redis/go-redis/v9"
 	"go.opentelemetry.io/otel"
 	"go.opentelemetry.io/otel/attribute"
 	"go.opentelemetry.io/otel/baggage"
+	"go.opentelemetry.io/otel/metric"
 	"go.opentelemetry.io/otel/propagation"
+	semconv "go.opentelemetry.io/otel/semconv/v1.4.0"
 	"go.opentelemetry.io/otel/trace"
+	"golang.org/x/time/rate"
 )
 
 func main() {
+	// loading the service addresses from the command-line flags and environment variables
+	cfg := config.Load()
+
 	// initialize the OpenTelemetry TracerProvider with the service name "formatter"
-	tracerPovider, err := tracing.InitTracerProvider("formatter")
+	tracerPovider, err := tracing.InitTracerProviderWithBackend("formatter", cfg.OTLPEndpoint, tracing.WithPropagation(cfg.Propagation))
 	if err != nil {
 		log.Fatalf("failed to create otel exporter: %v", err)
 	}
 
-	// creating a context and defering the shutdown of the TracerProvider to ensure proper cleanup
-	ctx := context.Background()
-	defer func() {
-		if err := tracerPovider.Shutdown(ctx); err != nil {
-			log.Fatalf("failed to shutdown TracerProvider: %v", err)
+	// creating a context canceled on an interrupt or a termination, which stops the server at the end of main rather than
+	// the process, and registering the providers to shut down then, in a defined order and with a timeout each, the
+	// TracerProvider last
+	ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt, syscall.SIGTERM)
+	defer stop()
+	shutdown := config.NewShutdownCoordinator()
+	shutdown.Add(config.TRACES, 0, tracerPovider.Shutdown)
+
+	// initialize the OpenTelemetry MeterProvider, whose metrics are exposed to Prometheus on /metrics
+	meterProvider, metricsHandler, err := metrics.InitPrometheusMeterProvider("formatter")
+	if err != nil {
+		log.Fatalf("failed to create prometheus exporter: %v", err)
+	}
+	shutdown.Add(config.METRICS, 0, meterProvider.Shutdown)
+
+	// creating the middleware recording the request counter and latency histogram
+	metricsMiddleware, err := xhttp.Metrics(meterProvider.Meter("formatter-meter"))
+	if err != nil {
+		log.Fatalf("failed to create instruments: %v", err)
+	}
+
+	// parsing the distribution of the simulated work latency
+	workLatency, err := latency.Parse(cfg.Latency)
+	if err != nil {
+		log.Fatal(err)
+	}
+
+	// creating a Redis client caching the formatted greetings when an address is configured
+	var rdb *redis.Client
+	if cfg.RedisAddr != "" {
+		rdb = redis.NewClient(&redis.Options{Addr: cfg.RedisAddr})
+		defer rdb.Close()
+
+		// instrumenting the client, every Redis command becomes a child span of the span in its context
+		if err := redisotel.InstrumentTracing(rdb); err != nil {
+			log.Fatalf("failed to instrument the redis client: %v", err)
 		}
-	}()
+	}
+
+	// creating a token bucket limiting the rate of the requests, with a burst of one second worth of requests
+	limiter := rate.NewLimiter(rate.Inf, 0)
+	if cfg.RateLimit > 0 {
+		limiter = rate.NewLimiter(rate.Limit(cfg.RateLimit), int(math.Ceil(cfg.RateLimit)))
+	}
+
+	// creating the counter of the requests rejected by the limiter
+	rejections, err := meterProvider.Meter("formatter-meter").Int64Counter("ratelimit.rejections",
+		metric.WithDescription("Number of requests rejected by the rate limiter"),
+		metric.WithUnit("{request}"),
+	)
+	if err != nil {
+		log.Fatalf("failed to create instruments: %v", err)
+	}
 
 	// retrieving or creating a tracer with name "formatter-tracer"
 	tracer := tracerPovider.Tracer("formatter-tracer")
 
-	http.HandleFunc("/format", func(w http.ResponseWriter, r *http.Request) {
-		// retrieving the global propagator and extracting the span context from the request headers
-		ctx := otel.GetTextMapPropagator().Extract(context.Background(), propagation.HeaderCarrier(r.Header))
+	formatHandler := http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		// starting the server span named "format" as a child of the span context sent by the caller, with the attributes
+		// of the request, HTTP/2 included when the client speaks h2c: the span is printed and ended when the handler returns
+		ctx, span, end := xhttp.StartSpanFromRequest(tracer, r, "format")
+		defer end()
 
-		// starting a new span named "format" as a child of the extracted span context
-		_, span := tracer.Start(ctx, "format", trace.WithSpanKind(trace.SpanKindServer))
-		defer span.End()
+		// applying the deadline of the caller, if any, recorded in the span along with the time it left
+		ctx, cancel := xhttp.ExtractDeadline(ctx, r)
+		defer cancel()
+		if xhttp.DeadlineExceeded(ctx, w) {
+			return
+		}
+
+		// rejecting the request when the limiter has no token left, recording the rejection and the current limit
+		if !limiter.Allow() {
+			span.SetAttributes(
+				attribute.Bool("ratelimit.rejected", true),
+				attribute.Float64("ratelimit.limit", float64(limiter.Limit())),
+			)
+			rejections.Add(ctx, 1)
+			http.Error(w, "rate limit exceeded", http.StatusTooManyRequests)
+			return
+		}
+
+		// rejecting the invalid names, a mistake of the caller: the failure is recorded in an event, and the status of the
+		// server span is left unset for the 4xx status codes, as in Lesson 15
+		helloTo := r.FormValue("helloTo")
+		if err := validateHelloTo(helloTo); err != nil {
+			span.AddEvent("validation failed", trace.WithAttributes(
+				attribute.String("validation.field", "helloTo"),
+				attribute.String("validation.error", err.Error()),
+			))
+			span.SetAttributes(semconv.HTTPStatusCodeKey.Int(http.StatusBadRequest))
+			http.Error(w, err.Error(), http.StatusBadRequest)
+			return
+		}
+
+		// simulating some work inside a child span named "work"
+		latency.Simulate(ctx, tracer, workLatency)
+
+		// giving up when the deadline of the caller passed during the work, the caller having given up already
+		if xhttp.DeadlineExceeded(ctx, w) {
+			return
+		}
 
 		// Retrieving baggage items from the context
 		b := baggage.FromContext(ctx)
 
+		// recording the identity of the caller when authenticated by the middleware, rather than the user.id member of the
+		// baggage, which any caller can send
+		if userID, ok := xhttp.UserID(ctx); ok {
+			span.SetAttributes(attribute.String("user.id", userID))
+		}
+
 		// uncomment the lines below to view all the members propagated
 		// members := b.Members()
 		// for _, member := range members{
@@ -53,22 +168,182 @@ func main() {
 		greeting := b.Member("greeting").Value()
 		fmt.Println("from baggage: ", greeting)
 		if greeting == "" {
-			greeting = "Hello"
+			// falling back to the greeting of the "locale" baggage member, or of the Accept-Language header
+			var locale string
+			greeting, locale = i18n.Greeting(b.Member("locale").Value(), r.Header.Get("Accept-Language"))
+			span.SetAttributes(attribute.String("locale", locale))
 		}
 
-		helloTo := r.FormValue("helloTo")
-		helloStr := fmt.Sprintf("%s, %s!", greeting, helloTo)
+		helloStr := format(ctx, rdb, greeting, helloTo)
 
 		// adding an event to the span indicating that the string was properly formatted
 		span.AddEvent("event name", trace.WithAttributes(
 			attribute.String("event", fmt.Sprintf("string-format: %s", helloStr)),
 		))
 
-		// printing the span details
-		tracing.PrintSpanContents(span)
+		// sending the greeting to the publisher when the formatter sits in the middle of the chain
+		if cfg.ForwardToPublisher {
+			if err := publish(ctx, tracer, cfg.PublisherAddr, helloStr); err != nil {
+				http.Error(w, err.Error(), http.StatusBadGateway)
+				return
+			}
+		}
 
 		w.Write([]byte(helloStr))
 	})
 
-	log.Fatal(http.ListenAndServe(":8081", nil))
+	// registering the handler behind the logging middleware, which writes one log line per request correlated with the trace,
+	// the metrics middleware, the chaos middleware, which fails a fraction of the requests on purpose,
+	// and the authentication middleware when API keys are configured
+	middlewares := []xhttp.Middleware{
+		xhttp.Logging(xlog.New("formatter")),
+		metricsMiddleware,
+		xhttp.Chaos(xhttp.ChaosOptions{Rate: cfg.ChaosRate, Delay: cfg.ChaosDelay, BaggageOverride: cfg.ChaosBaggage}),
+	}
+	if cfg.APIKeys != "" {
+		middlewares = append(middlewares, xhttp.Auth(config.ParseKeyValues(cfg.APIKeys)))
+	}
+	http.Handle("/format", xhttp.Chain(formatHandler, middlewares...))
+
+	// registering the Prometheus scraping endpoint
+	http.Handle("/metrics", metricsHandler)
+
+	// registering the liveness and readiness probes, their spans are dropped by the tracer provider
+	xhttp.RegisterHealthHandlers(http.DefaultServeMux, cfg.OTLPEndpoint)
+
+	// serving HTTP/2 over cleartext connections in addition to HTTP/1.1 when h2c is enabled, and speaking it to the publisher,
+	// dialing the publisher on its unix domain socket when it listens on one
+	var handler http.Handler = http.DefaultServeMux
+	if cfg.H2C {
+		handler = xhttp.H2CHandler(handler)
+		http.DefaultClient.Transport = xhttp.NewH2CTransport(cfg.PublisherAddr)
+	} else {
+		http.DefaultClient.Transport = xhttp.NewUnixTransport(cfg.PublisherAddr)
+	}
+
+	// authenticating with an API key of its own to the publisher, which records this service as the caller, not the user
+	// who called this one
+	if cfg.APIKey != "" {
+		http.DefaultClient.Transport = xhttp.NewAPIKeyTransport(cfg.APIKey, http.DefaultClient.Transport)
+	}
+
+	// listening on a TCP port, or on a unix domain socket for addresses such as "unix:/tmp/formatter.sock"
+	lis, err := xhttp.Listen(config.ListenAddr(cfg.FormatterAddr))
+	if err != nil {
+		log.Fatalf("failed to listen: %v", err)
+	}
+
+	// serving in a goroutine rather than with log.Fatal, which would exit the program without shutting the providers
+	// down
+	server := &http.Server{Handler: handler}
+	go func() {
+		if err := server.Serve(lis); !errors.Is(err, http.ErrServerClosed) {
+			log.Fatal(err)
+		}
+	}()
+
+	// waiting for an interrupt or a termination, then letting the requests in flight end before shutting the providers
+	// down, so that the telemetry of those requests is exported as well
+	<-ctx.Done()
+	log.Printf("shutting down")
+	serverCtx, cancel := context.WithTimeout(context.WithoutCancel(ctx), config.DEFAULT_SHUTDOWN_TIMEOUT)
+	defer cancel()
+	if err := server.Shutdown(serverCtx); err != nil {
+		log.Printf("failed to shutdown the server: %v", err)
+	}
+	if err := shutdown.Shutdown(ctx); err != nil {
+		log.Fatalf("failed to shutdown the providers: %v", err)
+	}
+}
+
+// format returns the greeting for helloTo, reading it from the Redis cache when possible.
+// The outcome of the lookup is recorded in the "cache.hit" attribute of the span in ctx.
+func format(ctx context.Context, rdb *redis.Client, greeting, helloTo string) string {
+	if rdb == nil {
+		return fmt.Sprintf("%s, %s!", greeting, helloTo)
+	}
+
+	span := trace.SpanFromContext(ctx)
+	key := fmt.Sprintf("greeting:%s:%s", greeting, helloTo)
+
+	cached, err := rdb.Get(ctx, key).Result()
+	if err == nil {
+		span.SetAttributes(attribute.Bool("cache.hit", true))
+		return cached
+	}
+	if err != redis.Nil {
+		// the cache being unavailable is not fatal, the greeting is formatted anyway
+		span.RecordError(err, trace.WithAttributes(attribute.String("cache-error", "Failed to read the cache")))
+		tracing.LoggerFromContext(ctx).Warn("failed to read the cache", slog.Any("error", err))
+	}
+	span.SetAttributes(attribute.Bool("cache.hit", false))
+
+	helloStr := fmt.Sprintf("%s, %s!", greeting, helloTo)
+	if err := rdb.Set(ctx, key, helloStr, time.Hour).Err(); err != nil {
+		span.RecordError(err, trace.WithAttributes(attribute.String("cache-error", "Failed to write the cache")))
+		tracing.LoggerFromContext(ctx).Warn("failed to write the cache", slog.Any("error", err))
+	}
+
+	return helloStr
+}
+
+// publish sends the greeting to the publisher, propagating the context of the "format" span, so that the publisher
+// becomes one more level in the trace.
+func publish(ctx context.Context, tracer trace.Tracer, publisherAddr, helloStr string) error {
+	// preparing to send an http get request to the "publisher" service
+	v := url.Values{}
+	v.Set("helloStr", helloStr)
+	url := xhttp.BaseURL(publisherAddr) + "/publish?" + v.Encode()
+
+	// creating a new HTTP request, whose attributes the span records following the semantic conventions
+	req, err := http.NewRequestWithContext(ctx, "GET", url, nil)
+	if err != nil {
+		return err
+	}
+
+	// creating a span with custom attributes indicating that it is an RPC
+	ctx, span := tracer.Start(ctx, "printHello",
+		trace.WithAttributes(attrs.HTTPClientAttrs(req)...),
+		trace.WithAttributes(xhttp.PeerAttributes(publisherAddr)...),
+		trace.WithSpanKind(trace.SpanKindClient),
+	)
+	defer span.End()
+
+	// sending the request with the context holding the span
+	req = req.WithContext(ctx)
+
+	// injecting the span context and the baggage into the request headers
+	otel.GetTextMapPropagator().Inject(ctx, propagation.HeaderCarrier(req.Header))
+
+	// passing the time left before the deadline of the greeting on, if any
+	xhttp.InjectDeadline(ctx, req.Header)
+
+	if _, err := xhttp.Do(req); err != nil {
+		// recording the error in the span and marking the span as failed
+		tracing.RecordFailure(span, err,
+			attribute.String("publish-response-error", fmt.Sprintf("Failed to publish the string %s", helloStr)))
+		return err
+	}
+
+	return nil
+}
+
+// MAX_HELLO_TO_LENGTH is the longest name the formatter greets, in characters
+const MAX_HELLO_TO_LENGTH = 64
+
+// validateHelloTo returns why the name cannot be greeted, if it cannot: it must be made of at most MAX_HELLO_TO_LENGTH
+// letters, in any script, spaces, hyphens, apostrophes and periods, such as "Jean-Luc" or "Zoë O'Brien"
+func validateHelloTo(helloTo string) error {
+	if strings.TrimSpace(helloTo) == "" {
+		return errors.New("missing helloTo parameter")
+	}
+	if n := utf8.RuneCountInString(helloTo); n > MAX_HELLO_TO_LENGTH {
+		return fmt.Errorf("helloTo is %d characters long, at most %d are allowed", n, MAX_HELLO_TO_LENGTH)
+	}
+	for _, r := range helloTo {
+		if !unicode.IsLetter(r) && !unicode.Is(unicode.Mn, r) && !strings.ContainsRune(" -'.", r) {
+			return fmt.Errorf("helloTo holds the invalid character %q, only letters, spaces, hyphens, apostrophes and periods are allowed", r)
+		}
+	}
+	return nil
 }
@@ -0,0 +1,43 @@
+package main
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestValidateHelloTo(t *testing.T) {
+	for _, tt := range []struct {
+		name    string
+		helloTo string
+		// wantErr is a part of the expected error, none when empty
+		wantErr string
+	}{
+		{"name", "Brian", ""},
+		{"hyphen", "Jean-Luc", ""},
+		{"apostrophe and diaeresis", "Zoë O'Brien", ""},
+		{"period", "J. R. R. Tolkien", ""},
+		{"combining mark", "Zoe\u0308", ""},
+		{"other script", "Ζωή", ""},
+		{"longest name", strings.Repeat("é", MAX_HELLO_TO_LENGTH), ""},
+		{"empty", "", "missing helloTo"},
+		{"blank", "   ", "missing helloTo"},
+		{"too long", strings.Repeat("a", MAX_HELLO_TO_LENGTH+1), "65 characters long"},
+		{"newline", "Brian\nadmin", `'\n'`},
+		{"null", "Brian\x00", `'\x00'`},
+		{"escape", "\x1b[31mBrian", `'\x1b'`},
+		{"markup", "<script>", `'<'`},
+		{"digit", "Brian2", `'2'`},
+	} {
+		t.Run(tt.name, func(t *testing.T) {
+			err := validateHelloTo(tt.helloTo)
+			switch {
+			case tt.wantErr == "" && err != nil:
+				t.Errorf("validateHelloTo(%q) = %v, want no error", tt.helloTo, err)
+			case tt.wantErr != "" && err == nil:
+				t.Errorf("validateHelloTo(%q) accepted the name, want an error holding %s", tt.helloTo, tt.wantErr)
+			case tt.wantErr != "" && !strings.Contains(err.Error(), tt.wantErr):
+				t.Errorf("validateHelloTo(%q) = %v, want an error holding %s", tt.helloTo, err, tt.wantErr)
+			}
+		})
+	}
+}
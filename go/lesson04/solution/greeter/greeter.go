@@ -0,0 +1,144 @@
+package main
+
+import (
+	"context"
+	"fmt"
+	"log"
+	"net/http"
+	"net/url"
+
+	"github.com/legosandorigami/opentelemetry-tutorial/go/lib/attrs"
+	"github.com/legosandorigami/opentelemetry-tutorial/go/lib/config"
+	xhttp "github.com/legosandorigami/opentelemetry-tutorial/go/lib/http"
+	xlog "github.com/legosandorigami/opentelemetry-tutorial/go/lib/log"
+	"github.com/legosandorigami/opentelemetry-tutorial/go/lib/tracing"
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/propagation"
+	"go.opentelemetry.io/otel/trace"
+)
+
+func main() {
+	// loading the service addresses from the command-line flags and environment variables
+	cfg := config.Load()
+
+	// initialize the OpenTelemetry TracerProvider with the service name "greeter"
+	tracerPovider, err := tracing.InitTracerProviderWithBackend("greeter", cfg.OTLPEndpoint, tracing.WithPropagation(cfg.Propagation))
+	if err != nil {
+		log.Fatalf("failed to create otel exporter: %v", err)
+	}
+
+	// creating a context and defering the shutdown of the TracerProvider to ensure proper cleanup
+	ctx := context.Background()
+	defer func() {
+		if err := tracerPovider.Shutdown(ctx); err != nil {
+			log.Fatalf("failed to shutdown TracerProvider: %v", err)
+		}
+	}()
+
+	// retrieving or creating a tracer with name "greeter-tracer"
+	tracer := tracerPovider.Tracer("greeter-tracer")
+
+	greetHandler := http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		// retrieving the global propagator and extracting the span context from the request headers
+		ctx := otel.GetTextMapPropagator().Extract(context.Background(), propagation.HeaderCarrier(r.Header))
+
+		// starting a new span named "greet" as a child of the extracted span context
+		ctx, span := tracer.Start(ctx, "greet", trace.WithSpanKind(trace.SpanKindServer))
+		defer span.End()
+
+		// recording the protocol of the request, HTTP/2 when the client speaks h2c
+		span.SetAttributes(xhttp.Flavor(r.ProtoMajor, r.ProtoMinor))
+
+		// applying the deadline of the caller, if any, recorded in the span along with the time it left
+		ctx, cancel := xhttp.ExtractDeadline(ctx, r)
+		defer cancel()
+		if xhttp.DeadlineExceeded(ctx, w) {
+			return
+		}
+
+		helloTo := r.FormValue("helloTo")
+		span.SetAttributes(attribute.String("hello-to", helloTo))
+
+		// calling the formatter, which calls the publisher in turn, with the context of the "greet" span
+		helloStr, err := formatString(ctx, tracer, cfg.FormatterAddr, helloTo)
+		if err != nil {
+			http.Error(w, err.Error(), http.StatusBadGateway)
+			return
+		}
+
+		// printing the span details
+		tracing.PrintSpanContents(span)
+
+		w.Write([]byte(helloStr))
+	})
+
+	http.Handle("/greet", xhttp.Chain(greetHandler, xhttp.Logging(xlog.New("greeter"))))
+
+	// registering the liveness and readiness probes, their spans are dropped by the tracer provider
+	xhttp.RegisterHealthHandlers(http.DefaultServeMux, cfg.OTLPEndpoint)
+
+	// serving HTTP/2 over cleartext connections in addition to HTTP/1.1 when h2c is enabled, and speaking it to the formatter,
+	// dialing the formatter on its unix domain socket when it listens on one
+	var handler http.Handler = http.DefaultServeMux
+	if cfg.H2C {
+		handler = xhttp.H2CHandler(handler)
+		http.DefaultClient.Transport = xhttp.NewH2CTransport(cfg.FormatterAddr)
+	} else {
+		http.DefaultClient.Transport = xhttp.NewUnixTransport(cfg.FormatterAddr)
+	}
+
+	// authenticating with an API key of its own to the formatter, which records this service as the caller, not the user
+	// who called this one
+	if cfg.APIKey != "" {
+		http.DefaultClient.Transport = xhttp.NewAPIKeyTransport(cfg.APIKey, http.DefaultClient.Transport)
+	}
+
+	// listening on a TCP port, or on a unix domain socket for addresses such as "unix:/tmp/greeter.sock"
+	lis, err := xhttp.Listen(config.ListenAddr(cfg.GreeterAddr))
+	if err != nil {
+		log.Fatalf("failed to listen: %v", err)
+	}
+
+	log.Fatal(http.Serve(lis, handler))
+}
+
+func formatString(ctx context.Context, tracer trace.Tracer, formatterAddr, helloTo string) (string, error) {
+	// preparing to send an http get request to the "formatter" service
+	v := url.Values{}
+	v.Set("helloTo", helloTo)
+	url := xhttp.BaseURL(formatterAddr) + "/format?" + v.Encode()
+
+	// creating a new HTTP request, whose attributes the span records following the semantic conventions
+	req, err := http.NewRequestWithContext(ctx, "GET", url, nil)
+	if err != nil {
+		return "", err
+	}
+
+	// creating a span with custom attributes indicating that it is an RPC
+	ctx, span := tracer.Start(ctx, "formatString",
+		trace.WithAttributes(attrs.HTTPClientAttrs(req)...),
+		trace.WithAttributes(xhttp.PeerAttributes(formatterAddr)...),
+		trace.WithSpanKind(trace.SpanKindClient),
+	)
+	defer span.End()
+
+	// sending the request with the context holding the span
+	req = req.WithContext(ctx)
+
+	// injecting the span context and the baggage received from the client into the request headers
+	otel.GetTextMapPropagator().Inject(ctx, propagation.HeaderCarrier(req.Header))
+
+	// passing the time left before the deadline of the greeting on, if any
+	xhttp.InjectDeadline(ctx, req.Header)
+
+	resp, err := xhttp.Do(req)
+	if err != nil {
+		// recording the error in the span and marking the span as failed
+		tracing.RecordFailure(span, err,
+			attribute.String("format-response-error", fmt.Sprintf("Failed to format the string %s", helloTo)))
+		return "", err
+	}
+
+	return string(resp), nil
+}
@@ -0,0 +1,100 @@
+package main
+
+import (
+	"context"
+	"log"
+	"net/http"
+
+	"github.com/legosandorigami/opentelemetry-tutorial/go/lib/config"
+	xhttp "github.com/legosandorigami/opentelemetry-tutorial/go/lib/http"
+	"github.com/legosandorigami/opentelemetry-tutorial/go/lib/messaging"
+	"github.com/legosandorigami/opentelemetry-tutorial/go/lib/tracing"
+	"github.com/nats-io/nats.go"
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/propagation"
+	semconv "go.opentelemetry.io/otel/semconv/v1.4.0"
+	"go.opentelemetry.io/otel/trace"
+)
+
+// SUBJECT is the NATS subject carrying the greetings
+const SUBJECT = "greetings"
+
+func main() {
+	// loading the service addresses from the command-line flags and environment variables
+	cfg := config.Load()
+
+	// initialize the OpenTelemetry TracerProvider with the service name "publisher"
+	tracerPovider, err := tracing.InitTracerProviderWithBackend("publisher", cfg.OTLPEndpoint, tracing.WithPropagation(cfg.Propagation))
+	if err != nil {
+		log.Fatalf("failed to create otel exporter: %v", err)
+	}
+
+	// creating a context and defering the shutdown of the TracerProvider to ensure proper cleanup
+	ctx := context.Background()
+	defer func() {
+		if err := tracerPovider.Shutdown(ctx); err != nil {
+			log.Fatalf("failed to shutdown TracerProvider: %v", err)
+		}
+	}()
+
+	// connecting to the NATS server
+	nc, err := nats.Connect(cfg.NATSURL)
+	if err != nil {
+		log.Fatalf("failed to connect to NATS: %v", err)
+	}
+	defer nc.Close()
+
+	// retrieving or creating a tracer with name "publisher-tracer"
+	tracer := tracerPovider.Tracer("publisher-tracer")
+
+	http.HandleFunc("/publish", func(w http.ResponseWriter, r *http.Request) {
+		// retrieving the global propagator and extracting the span context from the request headers
+		ctx := otel.GetTextMapPropagator().Extract(context.Background(), propagation.HeaderCarrier(r.Header))
+
+		// starting a new span with name "publish" which would be a child span of span ctx obtained above
+		ctx, span := tracer.Start(ctx, "publish")
+		defer span.End()
+
+		helloStr := r.FormValue("helloStr")
+		if err := publish(ctx, tracer, nc, helloStr); err != nil {
+			http.Error(w, err.Error(), http.StatusInternalServerError)
+		}
+
+		// printing the span details
+		tracing.PrintSpanContents(span)
+	})
+
+	// registering the liveness and readiness probes, their spans are dropped by the tracer provider
+	xhttp.RegisterHealthHandlers(http.DefaultServeMux, cfg.OTLPEndpoint)
+
+	log.Fatal(http.ListenAndServe(config.ListenAddr(cfg.PublisherAddr), nil))
+}
+
+// publish sends the greeting to NATS inside a producer span, whose context is injected into the message headers
+// in the W3C format so that the subscriber can continue the trace.
+func publish(ctx context.Context, tracer trace.Tracer, nc *nats.Conn, helloStr string) error {
+	ctx, span := tracer.Start(ctx, SUBJECT+" send",
+		trace.WithAttributes(
+			semconv.MessagingSystemKey.String("nats"),
+			semconv.MessagingDestinationKey.String(SUBJECT),
+			semconv.MessagingDestinationKindTopic,
+		),
+		trace.WithSpanKind(trace.SpanKindProducer),
+	)
+	defer span.End()
+
+	msg := &nats.Msg{Subject: SUBJECT, Data: []byte(helloStr)}
+
+	// injecting the span context and the baggage into the message headers
+	otel.GetTextMapPropagator().Inject(ctx, messaging.NewNATSHeaderCarrier(msg))
+
+	if err := nc.PublishMsg(msg); err != nil {
+		// recording the error in the span and marking the span as failed
+		tracing.RecordFailure(span, err,
+			attribute.String("publish-error", "Failed to publish the greeting"))
+		return err
+	}
+
+	return nil
+}
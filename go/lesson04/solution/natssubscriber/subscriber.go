@@ -0,0 +1,79 @@
+package main
+
+import (
+	"context"
+	"log"
+	"os"
+	"os/signal"
+
+	"github.com/legosandorigami/opentelemetry-tutorial/go/lib/config"
+	"github.com/legosandorigami/opentelemetry-tutorial/go/lib/messaging"
+	"github.com/legosandorigami/opentelemetry-tutorial/go/lib/tracing"
+	"github.com/nats-io/nats.go"
+	"go.opentelemetry.io/otel"
+	semconv "go.opentelemetry.io/otel/semconv/v1.4.0"
+	"go.opentelemetry.io/otel/trace"
+)
+
+// SUBJECT is the NATS subject carrying the greetings
+const SUBJECT = "greetings"
+
+func main() {
+	// loading the service addresses from the command-line flags and environment variables
+	cfg := config.Load()
+
+	// initialize the OpenTelemetry TracerProvider with the service name "subscriber"
+	tracerPovider, err := tracing.InitTracerProviderWithBackend("subscriber", cfg.OTLPEndpoint, tracing.WithPropagation(cfg.Propagation))
+	if err != nil {
+		log.Fatalf("failed to create otel exporter: %v", err)
+	}
+
+	// creating a context and defering the shutdown of the TracerProvider to ensure proper cleanup
+	ctx := context.Background()
+	defer func() {
+		if err := tracerPovider.Shutdown(ctx); err != nil {
+			log.Fatalf("failed to shutdown TracerProvider: %v", err)
+		}
+	}()
+
+	// connecting to the NATS server
+	nc, err := nats.Connect(cfg.NATSURL)
+	if err != nil {
+		log.Fatalf("failed to connect to NATS: %v", err)
+	}
+	defer nc.Close()
+
+	// retrieving or creating a tracer with name "subscriber-tracer"
+	tracer := tracerPovider.Tracer("subscriber-tracer")
+
+	sub, err := nc.Subscribe(SUBJECT, func(msg *nats.Msg) {
+		// extracting the span context injected by the publisher from the message headers
+		msgCtx := otel.GetTextMapPropagator().Extract(ctx, messaging.NewNATSHeaderCarrier(msg))
+
+		// starting a consumer span continuing the trace of the request that published the message
+		_, span := tracer.Start(msgCtx, msg.Subject+" process",
+			trace.WithAttributes(
+				semconv.MessagingSystemKey.String("nats"),
+				semconv.MessagingDestinationKey.String(msg.Subject),
+				semconv.MessagingDestinationKindTopic,
+				semconv.MessagingOperationProcess,
+			),
+			trace.WithSpanKind(trace.SpanKindConsumer),
+		)
+		defer span.End()
+
+		println(string(msg.Data))
+
+		// printing the span details
+		tracing.PrintSpanContents(span)
+	})
+	if err != nil {
+		log.Fatalf("failed to subscribe to %s: %v", SUBJECT, err)
+	}
+	defer sub.Unsubscribe()
+
+	// waiting for an interrupt so that the deferred cleanup flushes the remaining spans
+	sigs := make(chan os.Signal, 1)
+	signal.Notify(sigs, os.Interrupt)
+	<-sigs
+}
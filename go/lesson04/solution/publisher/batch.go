@@ -0,0 +1,134 @@
+package main
+
+import (
+	"context"
+	"log"
+	"sync"
+	"time"
+
+	"github.com/legosandorigami/opentelemetry-tutorial/go/lib/tracing"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/codes"
+	"go.opentelemetry.io/otel/trace"
+)
+
+// queuedGreeting is a greeting waiting for its batch, along with the span context of the request that queued it
+type queuedGreeting struct {
+	helloStr string
+	spanCtx  trace.SpanContext
+	queuedAt time.Time
+}
+
+// batcher accumulates the greetings and publishes them in batches, once size greetings are queued or every interval,
+// in a span of its own linked to the span of every request
+type batcher struct {
+	tracer   trace.Tracer
+	size     int
+	interval time.Duration
+	publish  func(ctx context.Context, helloStr string) error
+
+	mu        sync.Mutex
+	greetings []queuedGreeting
+	full      chan struct{}
+}
+
+func newBatcher(tracer trace.Tracer, size int, interval time.Duration, publish func(ctx context.Context, helloStr string) error) *batcher {
+	return &batcher{
+		tracer:   tracer,
+		size:     size,
+		interval: interval,
+		publish:  publish,
+		full:     make(chan struct{}, 1),
+	}
+}
+
+// push queues the greeting with the span context of span, which the batch links to, and returns the number of
+// greetings queued
+func (b *batcher) push(span trace.Span, helloStr string) int {
+	b.mu.Lock()
+	b.greetings = append(b.greetings, queuedGreeting{helloStr: helloStr, spanCtx: span.SpanContext(), queuedAt: time.Now()})
+	n := len(b.greetings)
+	b.mu.Unlock()
+
+	// waking the batch job up once the batch is full
+	if n >= b.size {
+		select {
+		case b.full <- struct{}{}:
+		default:
+		}
+	}
+	return n
+}
+
+func (b *batcher) drain() []queuedGreeting {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	greetings := b.greetings
+	b.greetings = nil
+	return greetings
+}
+
+// run publishes the queued greetings whenever the batch is full, or the interval elapsed
+func (b *batcher) run() {
+	ticker := time.NewTicker(b.interval)
+	defer ticker.Stop()
+	for {
+		trigger := "interval"
+		select {
+		case <-ticker.C:
+		case <-b.full:
+			trigger = "size"
+		}
+		if greetings := b.drain(); len(greetings) > 0 {
+			b.flush(greetings, trigger)
+		}
+	}
+}
+
+// flush publishes the greetings in a single span. The span belongs to none of the traces that queued the greetings,
+// it starts a trace of its own and links to the span of every request instead, as in Lesson 11.
+func (b *batcher) flush(greetings []queuedGreeting, trigger string) {
+	// creating a link to the span of each request that queued a greeting, with how long the greeting waited
+	now := time.Now()
+	links := make([]trace.Link, 0, len(greetings))
+	for _, g := range greetings {
+		links = append(links, trace.Link{
+			SpanContext: g.spanCtx,
+			Attributes: []attribute.KeyValue{
+				attribute.String("link.reason", "batched"),
+				attribute.Int64("queue.wait_ms", now.Sub(g.queuedAt).Milliseconds()),
+			},
+		})
+	}
+
+	// starting a new root span linked to the spans of all the requests, given to the samplers along with the links
+	ctx, span := b.tracer.Start(context.Background(), "publish-batch",
+		trace.WithNewRoot(),
+		trace.WithLinks(links...),
+		trace.WithAttributes(
+			attribute.Int("batch.size", len(greetings)),
+			attribute.String("batch.trigger", trigger),
+		),
+	)
+	defer span.End()
+
+	// publishing every greeting, the spans of Kafka, the WebSocket clients and the database being children of the batch
+	failed := 0
+	for _, g := range greetings {
+		if err := b.publish(ctx, g.helloStr); err != nil {
+			failed++
+			span.AddEvent("publish failed", trace.WithAttributes(
+				attribute.String("greeting", g.helloStr),
+				attribute.String("error", err.Error()),
+			))
+			log.Printf("failed to publish %q in a batch: %v", g.helloStr, err)
+		}
+	}
+	if failed > 0 {
+		span.SetAttributes(attribute.Int("batch.failed", failed))
+		span.SetStatus(codes.Error, "some greetings of the batch were not published")
+	}
+
+	// printing the span details
+	tracing.PrintSpanContents(span)
+}
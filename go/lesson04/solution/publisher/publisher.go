@@ -2,46 +2,273 @@ package main
 
 import (
 	"context"
+	"database/sql"
+	"errors"
 	"log"
 	"net/http"
+	"os"
+	"os/signal"
+	"strings"
+	"syscall"
 
-	"github.com/legosandorigami/opentelemetry-tutorial/lib/tracing"
+	"github.com/XSAM/otelsql"
+	_ "github.com/jackc/pgx/v5/stdlib"
+	"github.com/legosandorigami/opentelemetry-tutorial/go/lib/config"
+	xhttp "github.com/legosandorigami/opentelemetry-tutorial/go/lib/http"
+	"github.com/legosandorigami/opentelemetry-tutorial/go/lib/latency"
+	xlog "github.com/legosandorigami/opentelemetry-tutorial/go/lib/log"
+	"github.com/legosandorigami/opentelemetry-tutorial/go/lib/messaging"
+	"github.com/legosandorigami/opentelemetry-tutorial/go/lib/metrics"
+	"github.com/legosandorigami/opentelemetry-tutorial/go/lib/tracing"
+	"github.com/segmentio/kafka-go"
 	"go.opentelemetry.io/otel"
-	"go.opentelemetry.io/otel/propagation"
+	"go.opentelemetry.io/otel/attribute"
+	semconv "go.opentelemetry.io/otel/semconv/v1.4.0"
+	"go.opentelemetry.io/otel/trace"
 )
 
 func main() {
+	// loading the service addresses from the command-line flags and environment variables
+	cfg := config.Load()
+
 	// initialize the OpenTelemetry TracerProvider with the service name "publisher"
-	tracerPovider, err := tracing.InitTracerProvider("publisher")
+	tracerPovider, err := tracing.InitTracerProviderWithBackend("publisher", cfg.OTLPEndpoint, tracing.WithPropagation(cfg.Propagation))
 	if err != nil {
 		log.Fatalf("failed to create otel exporter: %v", err)
 	}
 
-	// creating a context and defering the shutdown of the TracerProvider to ensure proper cleanup
-	ctx := context.Background()
-	defer func() {
-		if err := tracerPovider.Shutdown(ctx); err != nil {
-			log.Fatalf("failed to shutdown TracerProvider: %v", err)
+	// creating a context canceled on an interrupt or a termination, which stops the server at the end of main rather than
+	// the process, and registering the providers to shut down then, in a defined order and with a timeout each, the
+	// TracerProvider last
+	ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt, syscall.SIGTERM)
+	defer stop()
+	shutdown := config.NewShutdownCoordinator()
+	shutdown.Add(config.TRACES, 0, tracerPovider.Shutdown)
+
+	// initialize the OpenTelemetry MeterProvider, whose metrics are exposed to Prometheus on /metrics
+	meterProvider, metricsHandler, err := metrics.InitPrometheusMeterProvider("publisher")
+	if err != nil {
+		log.Fatalf("failed to create prometheus exporter: %v", err)
+	}
+	shutdown.Add(config.METRICS, 0, meterProvider.Shutdown)
+
+	// creating the middleware recording the request counter and latency histogram
+	metricsMiddleware, err := xhttp.Metrics(meterProvider.Meter("publisher-meter"))
+	if err != nil {
+		log.Fatalf("failed to create instruments: %v", err)
+	}
+
+	// parsing the distribution of the simulated work latency
+	workLatency, err := latency.Parse(cfg.Latency)
+	if err != nil {
+		log.Fatal(err)
+	}
+
+	// creating a Kafka writer when brokers are configured, the greetings are printed otherwise
+	var writer *kafka.Writer
+	if cfg.KafkaBrokers != "" {
+		writer = &kafka.Writer{
+			Addr:                   kafka.TCP(strings.Split(cfg.KafkaBrokers, ",")...),
+			Topic:                  cfg.KafkaTopic,
+			AllowAutoTopicCreation: true,
 		}
-	}()
+		defer writer.Close()
+	}
+
+	// opening the PostgreSQL database storing the published greetings when a connection string is configured
+	var db *sql.DB
+	if cfg.PostgresDSN != "" {
+		db, err = openDB(ctx, cfg.PostgresDSN)
+		if err != nil {
+			log.Fatalf("failed to open the database: %v", err)
+		}
+		defer db.Close()
+	}
 
 	// retrieving or creating a tracer with name "publisher-tracer"
 	tracer := tracerPovider.Tracer("publisher-tracer")
 
-	http.HandleFunc("/publish", func(w http.ResponseWriter, r *http.Request) {
-		// retrieving the global propagator and extracting the span context from the request headers
-		ctx := otel.GetTextMapPropagator().Extract(context.Background(), propagation.HeaderCarrier(r.Header))
+	// creating the hub pushing the greetings to the WebSocket clients connected to /ws
+	hub := newWSHub(tracer)
+
+	// publishGreeting prints or produces the greeting, pushes it to the WebSocket clients and stores it,
+	// all the spans it creates being children of the span in ctx
+	publishGreeting := func(ctx context.Context, helloStr string) error {
+		if writer == nil {
+			println(helloStr)
+		} else if err := produce(ctx, tracer, writer, helloStr); err != nil {
+			return err
+		}
+
+		// pushing the greeting to the connected WebSocket clients
+		hub.broadcast(ctx, helloStr)
 
-		// Starting a new span with name "publish" which would be a child span of span ctx obtained above. Ignoring the span context from tracer.Start as it is not used further
-		_, span := tracer.Start(ctx, "publish")
-		defer span.End()
+		// storing the greeting, the INSERT statement is traced as a child span of the span in ctx
+		if db != nil {
+			if _, err := db.ExecContext(ctx, "INSERT INTO greetings (greeting) VALUES ($1)", helloStr); err != nil {
+				tracing.RecordFailure(trace.SpanFromContext(ctx), err,
+					attribute.String("store-error", "Failed to store the greeting"))
+				return err
+			}
+		}
+
+		return nil
+	}
+
+	// publishing the greetings in batches linked to the requests when a batch size is configured, one by one otherwise
+	var batch *batcher
+	if cfg.BatchSize > 0 {
+		batch = newBatcher(tracer, cfg.BatchSize, cfg.BatchInterval, publishGreeting)
+		go batch.run()
+	}
+
+	publishHandler := http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		// starting the server span named "publish" as a child of the span context sent by the caller, with the attributes
+		// of the request, HTTP/2 included when the client speaks h2c: the span is printed and ended when the handler returns
+		ctx, span, end := xhttp.StartSpanFromRequest(tracer, r, "publish")
+		defer end()
+
+		// applying the deadline of the caller, if any, recorded in the span along with the time it left
+		ctx, cancel := xhttp.ExtractDeadline(ctx, r)
+		defer cancel()
+		if xhttp.DeadlineExceeded(ctx, w) {
+			return
+		}
+
+		// simulating some work inside a child span named "work"
+		latency.Simulate(ctx, tracer, workLatency)
+
+		// giving up when the deadline of the caller passed during the work, the caller having given up already
+		if xhttp.DeadlineExceeded(ctx, w) {
+			return
+		}
+
+		// recording the identity of the caller when authenticated by the middleware, rather than the user.id member of the
+		// baggage, which any caller can send
+		if userID, ok := xhttp.UserID(ctx); ok {
+			span.SetAttributes(attribute.String("user.id", userID))
+		}
 
 		helloStr := r.FormValue("helloStr")
-		println(helloStr)
 
-		// printing the span details
-		tracing.PrintSpanContents(span)
+		// queuing the greeting for the next batch, which links to this span, instead of publishing it now
+		if batch != nil {
+			n := batch.push(span, helloStr)
+			span.AddEvent("queued", trace.WithAttributes(attribute.Int("queue.length", n)))
+			return
+		}
+
+		if err := publishGreeting(ctx, helloStr); err != nil {
+			http.Error(w, err.Error(), http.StatusInternalServerError)
+			return
+		}
 	})
 
-	log.Fatal(http.ListenAndServe(":8082", nil))
+	// registering the handler behind the logging middleware, which writes one log line per request correlated with the trace,
+	// the metrics middleware, the chaos middleware, which fails a fraction of the requests on purpose,
+	// and the authentication middleware when API keys are configured
+	middlewares := []xhttp.Middleware{
+		xhttp.Logging(xlog.New("publisher")),
+		metricsMiddleware,
+		xhttp.Chaos(xhttp.ChaosOptions{Rate: cfg.ChaosRate, Delay: cfg.ChaosDelay, BaggageOverride: cfg.ChaosBaggage}),
+	}
+	if cfg.APIKeys != "" {
+		middlewares = append(middlewares, xhttp.Auth(config.ParseKeyValues(cfg.APIKeys)))
+	}
+	http.Handle("/publish", xhttp.Chain(publishHandler, middlewares...))
+
+	// registering the Server-Sent Events variant of /publish, streaming an acknowledgement per step
+	http.Handle("/publish/stream", xhttp.Chain(streamHandler(tracer, publishGreeting), middlewares...))
+
+	// registering the WebSocket endpoint the greetings are pushed to
+	http.Handle("/ws", hub)
+
+	// registering the Prometheus scraping endpoint
+	http.Handle("/metrics", metricsHandler)
+
+	// registering the liveness and readiness probes, their spans are dropped by the tracer provider
+	xhttp.RegisterHealthHandlers(http.DefaultServeMux, cfg.OTLPEndpoint)
+
+	// serving HTTP/2 over cleartext connections in addition to HTTP/1.1 when h2c is enabled
+	var handler http.Handler = http.DefaultServeMux
+	if cfg.H2C {
+		handler = xhttp.H2CHandler(handler)
+	}
+
+	// listening on a TCP port, or on a unix domain socket for addresses such as "unix:/tmp/publisher.sock"
+	lis, err := xhttp.Listen(config.ListenAddr(cfg.PublisherAddr))
+	if err != nil {
+		log.Fatalf("failed to listen: %v", err)
+	}
+
+	// serving in a goroutine rather than with log.Fatal, which would exit the program without shutting the providers
+	// down
+	server := &http.Server{Handler: handler}
+	go func() {
+		if err := server.Serve(lis); !errors.Is(err, http.ErrServerClosed) {
+			log.Fatal(err)
+		}
+	}()
+
+	// waiting for an interrupt or a termination, then letting the requests in flight end before shutting the providers
+	// down, so that the telemetry of those requests is exported as well
+	<-ctx.Done()
+	log.Printf("shutting down")
+	serverCtx, cancel := context.WithTimeout(context.WithoutCancel(ctx), config.DEFAULT_SHUTDOWN_TIMEOUT)
+	defer cancel()
+	if err := server.Shutdown(serverCtx); err != nil {
+		log.Printf("failed to shutdown the server: %v", err)
+	}
+	if err := shutdown.Shutdown(ctx); err != nil {
+		log.Fatalf("failed to shutdown the providers: %v", err)
+	}
+}
+
+// produce sends the greeting to Kafka inside a producer span, whose context is injected into the message headers
+// so that the consumer can continue the trace.
+func produce(ctx context.Context, tracer trace.Tracer, writer *kafka.Writer, helloStr string) error {
+	ctx, span := tracer.Start(ctx, writer.Topic+" send",
+		trace.WithAttributes(
+			semconv.MessagingSystemKey.String("kafka"),
+			semconv.MessagingDestinationKey.String(writer.Topic),
+			semconv.MessagingDestinationKindTopic,
+		),
+		trace.WithSpanKind(trace.SpanKindProducer),
+	)
+	defer span.End()
+
+	msg := kafka.Message{Value: []byte(helloStr)}
+
+	// injecting the span context and the baggage into the message headers
+	otel.GetTextMapPropagator().Inject(ctx, messaging.NewKafkaHeaderCarrier(&msg))
+
+	if err := writer.WriteMessages(ctx, msg); err != nil {
+		// recording the error in the span and marking the span as failed
+		tracing.RecordFailure(span, err,
+			attribute.String("produce-error", "Failed to produce the greeting"))
+		return err
+	}
+
+	return nil
+}
+
+// openDB opens the PostgreSQL database through an otelsql-wrapped driver, so that every statement is recorded as a span
+// carrying the db.statement attribute, and creates the greetings table if needed.
+func openDB(ctx context.Context, dsn string) (*sql.DB, error) {
+	db, err := otelsql.Open("pgx", dsn, otelsql.WithAttributes(semconv.DBSystemPostgreSQL))
+	if err != nil {
+		return nil, err
+	}
+
+	_, err = db.ExecContext(ctx, `CREATE TABLE IF NOT EXISTS greetings (
+		id SERIAL PRIMARY KEY,
+		greeting TEXT NOT NULL,
+		published_at TIMESTAMPTZ NOT NULL DEFAULT now()
+	)`)
+	if err != nil {
+		db.Close()
+		return nil, err
+	}
+
+	return db, nil
 }
@@ -0,0 +1,81 @@
+package main
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+
+	xhttp "github.com/legosandorigami/opentelemetry-tutorial/go/lib/http"
+	"github.com/legosandorigami/opentelemetry-tutorial/go/lib/tracing"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/trace"
+)
+
+// sseStream writes Server-Sent Events to the response, recording every event sent as an event of the span
+type sseStream struct {
+	w    http.ResponseWriter
+	rc   *http.ResponseController
+	span trace.Span
+	id   int
+}
+
+// send writes one event and flushes it to the client right away, instead of buffering the whole response
+func (s *sseStream) send(event, data string) error {
+	s.id++
+	if _, err := fmt.Fprintf(s.w, "id: %d\nevent: %s\ndata: %s\n\n", s.id, event, data); err != nil {
+		return err
+	}
+	if err := s.rc.Flush(); err != nil {
+		return err
+	}
+
+	s.span.AddEvent("sse.event", trace.WithAttributes(
+		attribute.Int("sse.event.id", s.id),
+		attribute.String("sse.event.name", event),
+		attribute.String("sse.event.data", data),
+	))
+	return nil
+}
+
+// streamHandler publishes the greeting like the /publish handler, but streams an acknowledgement to the client as each
+// step completes. The whole stream is covered by a single server span, holding one span event per message sent.
+func streamHandler(tracer trace.Tracer, publish func(ctx context.Context, helloStr string) error) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		// starting the server span named "publish-stream" as a child of the span context sent by the caller, from the
+		// context of the request, which is canceled when the client goes away and holds the identity set by the
+		// authentication middleware: the span is printed and ended when the handler returns
+		ctx, span, end := xhttp.StartSpanFromRequest(tracer, r, "publish-stream")
+		defer end()
+
+		// applying the deadline of the caller, if any, as the /publish handler does
+		ctx, cancel := xhttp.ExtractDeadline(ctx, r)
+		defer cancel()
+		if xhttp.DeadlineExceeded(ctx, w) {
+			return
+		}
+
+		// recording the identity of the caller when authenticated by the middleware
+		if userID, ok := xhttp.UserID(ctx); ok {
+			span.SetAttributes(attribute.String("user.id", userID))
+		}
+
+		w.Header().Set("Content-Type", "text/event-stream")
+		w.Header().Set("Cache-Control", "no-cache")
+		stream := &sseStream{w: w, rc: http.NewResponseController(w), span: span}
+
+		helloStr := r.FormValue("helloStr")
+		if err := stream.send("ack", "received"); err != nil {
+			tracing.RecordFailure(span, err)
+			return
+		}
+
+		if err := publish(ctx, helloStr); err != nil {
+			tracing.RecordFailure(span, err)
+			stream.send("error", err.Error())
+			return
+		}
+
+		stream.send("ack", "published")
+		stream.send("done", helloStr)
+	}
+}
@@ -0,0 +1,132 @@
+package main
+
+import (
+	"context"
+	"log"
+	"net"
+	"net/http"
+	"strconv"
+	"sync"
+
+	"github.com/gorilla/websocket"
+	"github.com/legosandorigami/opentelemetry-tutorial/go/lib/tracing"
+	"go.opentelemetry.io/otel/attribute"
+	semconv "go.opentelemetry.io/otel/semconv/v1.4.0"
+	"go.opentelemetry.io/otel/trace"
+)
+
+// wsMessage is a greeting waiting to be delivered, along with the span context of the request that published it
+type wsMessage struct {
+	helloStr string
+	origin   trace.SpanContext
+}
+
+// wsClient is a connected WebSocket client and its queue of pending greetings
+type wsClient struct {
+	conn *websocket.Conn
+	send chan wsMessage
+}
+
+// wsHub pushes the published greetings to every connected WebSocket client
+type wsHub struct {
+	tracer   trace.Tracer
+	upgrader websocket.Upgrader
+
+	mu      sync.Mutex
+	clients map[*wsClient]struct{}
+}
+
+func newWSHub(tracer trace.Tracer) *wsHub {
+	return &wsHub{tracer: tracer, clients: make(map[*wsClient]struct{})}
+}
+
+// ServeHTTP upgrades the connection to a WebSocket and keeps it registered until the client goes away.
+// No span covers the connection itself, as it can stay open for hours.
+func (h *wsHub) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	conn, err := h.upgrader.Upgrade(w, r, nil)
+	if err != nil {
+		log.Printf("failed to upgrade to websocket: %v", err)
+		return
+	}
+
+	c := &wsClient{conn: conn, send: make(chan wsMessage, 16)}
+	h.mu.Lock()
+	h.clients[c] = struct{}{}
+	h.mu.Unlock()
+
+	go h.deliver(c)
+
+	// reading until the client closes the connection, the messages it sends are ignored
+	for {
+		if _, _, err := conn.ReadMessage(); err != nil {
+			break
+		}
+	}
+
+	h.mu.Lock()
+	delete(h.clients, c)
+	h.mu.Unlock()
+	close(c.send)
+}
+
+// deliver writes the pending greetings to the client, each one inside its own span.
+// The delivery happens long after the publishing request has completed, and may happen once per client, so the span
+// starts a new trace linked to the originating request instead of being its child.
+func (h *wsHub) deliver(c *wsClient) {
+	defer c.conn.Close()
+
+	for msg := range c.send {
+		// the span is internal: the greeting is pushed on a connection the client opened long before, not answering a
+		// request of the client, nor handed to a broker as a producer would
+		_, span := h.tracer.Start(context.Background(), "websocket deliver",
+			trace.WithLinks(trace.Link{SpanContext: msg.origin}),
+			trace.WithAttributes(append(peerAttributes(c.conn.RemoteAddr()),
+				attribute.Int("message.size", len(msg.helloStr)),
+			)...),
+			trace.WithSpanKind(trace.SpanKindInternal),
+		)
+
+		if err := c.conn.WriteMessage(websocket.TextMessage, []byte(msg.helloStr)); err != nil {
+			tracing.RecordFailure(span, err, attribute.String("deliver-error", "Failed to deliver the greeting"))
+		}
+
+		span.End()
+	}
+}
+
+// peerAttributes returns the net.peer.ip and net.peer.port attributes of the client at addr, none when addr is not an
+// IP address and a port, e.g. for a client connected over a unix domain socket
+func peerAttributes(addr net.Addr) []attribute.KeyValue {
+	host, port, err := net.SplitHostPort(addr.String())
+	if err != nil {
+		return nil
+	}
+	attrs := []attribute.KeyValue{semconv.NetPeerIPKey.String(host)}
+	if p, err := strconv.Atoi(port); err == nil {
+		attrs = append(attrs, semconv.NetPeerPortKey.Int(p))
+	}
+	return attrs
+}
+
+// broadcast queues the greeting for every connected client, remembering the span in ctx as its origin.
+// Clients too slow to keep up miss the greeting rather than blocking the publisher.
+func (h *wsHub) broadcast(ctx context.Context, helloStr string) {
+	span := trace.SpanFromContext(ctx)
+	msg := wsMessage{helloStr: helloStr, origin: span.SpanContext()}
+
+	h.mu.Lock()
+	defer h.mu.Unlock()
+
+	dropped := 0
+	for c := range h.clients {
+		select {
+		case c.send <- msg:
+		default:
+			dropped++
+		}
+	}
+	span.SetAttributes(
+		attribute.Int("websocket.clients", len(h.clients)),
+		attribute.Int("websocket.dropped", dropped),
+	)
+}
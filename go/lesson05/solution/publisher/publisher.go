@@ -0,0 +1,128 @@
+package main
+
+import (
+	"context"
+	"errors"
+	"log"
+	"net/http"
+	"os"
+	"os/signal"
+	"syscall"
+	"time"
+
+	"github.com/legosandorigami/opentelemetry-tutorial/go/lib/config"
+	"github.com/legosandorigami/opentelemetry-tutorial/go/lib/metrics"
+	"github.com/legosandorigami/opentelemetry-tutorial/go/lib/tracing"
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/metric"
+	"go.opentelemetry.io/otel/propagation"
+	"go.opentelemetry.io/otel/trace"
+)
+
+func main() {
+	// loading the service addresses from the command-line flags and environment variables
+	cfg := config.Load()
+
+	// initialize the OpenTelemetry TracerProvider with the service name "publisher"
+	tracerPovider, err := tracing.InitTracerProviderWithBackend("publisher", cfg.OTLPEndpoint, tracing.WithPropagation(cfg.Propagation))
+	if err != nil {
+		log.Fatalf("failed to create otel exporter: %v", err)
+	}
+
+	// initialize the OpenTelemetry MeterProvider with the service name "publisher", exporting to the same backend
+	meterProvider, err := metrics.InitMeterProviderWithBackend("publisher", cfg.OTLPEndpoint)
+	if err != nil {
+		log.Fatalf("failed to create otel metric exporter: %v", err)
+	}
+
+	// creating a context canceled on an interrupt or a termination, which stops the server at the end of main rather than
+	// the process, and registering the providers to shut down then, in a defined order and with a timeout each, the
+	// TracerProvider last
+	ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt, syscall.SIGTERM)
+	defer stop()
+	shutdown := config.NewShutdownCoordinator()
+	shutdown.Add(config.TRACES, 0, tracerPovider.Shutdown)
+	shutdown.Add(config.METRICS, 0, meterProvider.Shutdown)
+
+	// retrieving or creating a tracer with name "publisher-tracer"
+	tracer := tracerPovider.Tracer("publisher-tracer")
+
+	// retrieving or creating a meter with name "publisher-meter"
+	meter := meterProvider.Meter("publisher-meter")
+
+	// creating a counter of the published greetings
+	requests, err := meter.Int64Counter("publish.requests",
+		metric.WithDescription("Number of greetings published"),
+		metric.WithUnit("{request}"),
+	)
+	if err != nil {
+		log.Fatalf("failed to create instruments: %v", err)
+	}
+
+	// creating an up-down counter of the requests being handled
+	inflight, err := meter.Int64UpDownCounter("publish.requests.inflight",
+		metric.WithDescription("Number of publish requests being handled"),
+		metric.WithUnit("{request}"),
+	)
+	if err != nil {
+		log.Fatalf("failed to create instruments: %v", err)
+	}
+
+	// creating a histogram of the time spent handling the requests
+	duration, err := meter.Float64Histogram("publish.duration",
+		metric.WithDescription("Duration of the publish requests"),
+		metric.WithUnit("s"),
+	)
+	if err != nil {
+		log.Fatalf("failed to create instruments: %v", err)
+	}
+
+	http.HandleFunc("/publish", func(w http.ResponseWriter, r *http.Request) {
+		start := time.Now()
+
+		// retrieving the global propagator and extracting the span context from the request headers
+		ctx := otel.GetTextMapPropagator().Extract(context.Background(), propagation.HeaderCarrier(r.Header))
+
+		// starting a new span named "publish" as a child of the extracted span context
+		ctx, span := tracer.Start(ctx, "publish", trace.WithSpanKind(trace.SpanKindServer))
+		defer span.End()
+
+		// counting the request as in flight until the handler returns
+		inflight.Add(ctx, 1)
+		defer inflight.Add(ctx, -1)
+
+		helloStr := r.FormValue("helloStr")
+		println(helloStr)
+
+		// printing the span details
+		tracing.PrintSpanContents(span)
+
+		// recording the request and its duration, with the context of the span so that the trace ID becomes an exemplar
+		attrs := metric.WithAttributes(attribute.String("http.route", "/publish"))
+		requests.Add(ctx, 1, attrs)
+		duration.Record(ctx, time.Since(start).Seconds(), attrs)
+	})
+
+	// serving in a goroutine rather than with log.Fatal, which would exit the program without shutting the providers
+	// down
+	server := &http.Server{Addr: config.ListenAddr(cfg.PublisherAddr)}
+	go func() {
+		if err := server.ListenAndServe(); !errors.Is(err, http.ErrServerClosed) {
+			log.Fatal(err)
+		}
+	}()
+
+	// waiting for an interrupt or a termination, then letting the requests in flight end before shutting the providers
+	// down, so that the telemetry of those requests is exported as well
+	<-ctx.Done()
+	log.Printf("shutting down")
+	serverCtx, cancel := context.WithTimeout(context.WithoutCancel(ctx), config.DEFAULT_SHUTDOWN_TIMEOUT)
+	defer cancel()
+	if err := server.Shutdown(serverCtx); err != nil {
+		log.Printf("failed to shutdown the server: %v", err)
+	}
+	if err := shutdown.Shutdown(ctx); err != nil {
+		log.Fatalf("failed to shutdown the providers: %v", err)
+	}
+}
@@ -0,0 +1,149 @@
+package main
+
+import (
+	"context"
+	"flag"
+	"fmt"
+	"log"
+	"net/http"
+	"net/url"
+
+	"github.com/legosandorigami/opentelemetry-tutorial/go/lib/attrs"
+	"github.com/legosandorigami/opentelemetry-tutorial/go/lib/config"
+	xhttp "github.com/legosandorigami/opentelemetry-tutorial/go/lib/http"
+	"github.com/legosandorigami/opentelemetry-tutorial/go/lib/tracing"
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/propagation"
+	"go.opentelemetry.io/otel/trace"
+)
+
+// retrieving the tracer named "say-hello-tracer" once, from the global TracerProvider
+var tracer = otel.Tracer("say-hello-tracer")
+
+func main() {
+	// loading the service addresses from the command-line flags and environment variables
+	cfg := config.Load()
+
+	// checking if the number of positional arguments is exactly 1
+	if flag.NArg() != 1 {
+		panic("ERROR: Expecting one argument")
+	}
+
+	// initializing the OpenTelemetry TracerProvider with the service name "hello-world"
+	tracerPovider, err := tracing.InitTracerProviderWithBackend("hello-world", cfg.OTLPEndpoint, tracing.WithPropagation(cfg.Propagation))
+	if err != nil {
+		log.Fatalf("failed to create otel exporter: %v", err)
+	}
+
+	// creating a context and defering the shutdown of the TracerProvider to ensure proper cleanup
+	ctx := context.Background()
+	defer func() {
+		if err := tracerPovider.Shutdown(ctx); err != nil {
+			log.Fatalf("failed to shutdown TracerProvider: %v", err)
+		}
+	}()
+
+	helloTo := flag.Arg(0)
+
+	// starting a new span named "say-hello"
+	ctx, span := tracer.Start(ctx, "say-hello")
+	span.SetAttributes(attribute.String("hello-to", helloTo))
+	defer span.End()
+	log.Printf("greeting %s", helloTo)
+
+	// calling `formatString` function with the context ctx.
+	helloStr, err := formatString(ctx, cfg.FormatterAddr, helloTo)
+	if err != nil {
+		log.Fatalf(err.Error())
+	}
+
+	// calling `printHello` function with the context ctx.
+	err = printHello(ctx, cfg.PublisherAddr, helloStr)
+	if err != nil {
+		log.Fatalf(err.Error())
+	}
+
+	// printing the span details
+	tracing.PrintSpanContents(span)
+}
+
+func formatString(ctx context.Context, formatterAddr, helloTo string) (string, error) {
+	// preparing to send an http get request to the "formatter" service
+	v := url.Values{}
+	v.Set("helloTo", helloTo)
+	url := "http://" + formatterAddr + "/format?" + v.Encode()
+
+	// creating a client span indicating that it is an RPC
+	ctx, span := tracer.Start(ctx, "formatString", trace.WithSpanKind(trace.SpanKindClient))
+	defer span.End()
+
+	// creating a new HTTP request to formatter microservice
+	req, err := http.NewRequestWithContext(ctx, "GET", url, nil)
+	if err != nil {
+		return "", err
+	}
+
+	// recording the attributes of the request following the semantic conventions
+	span.SetAttributes(append(attrs.HTTPClientAttrs(req), attrs.PeerService("formatter"))...)
+
+	// injecting the span context into the request headers
+	otel.GetTextMapPropagator().Inject(ctx, propagation.HeaderCarrier(req.Header))
+
+	//sending a get request
+	resp, err := xhttp.Do(req)
+	if err != nil {
+		// recording the error in the span and marking the span as failed
+		tracing.RecordFailure(span, err,
+			attribute.String("format-response-error", fmt.Sprintf("Failed to format the string %s", helloTo)))
+		return "", err
+	}
+
+	helloStr := string(resp)
+
+	// adding an event to the span indicating a successful response was received
+	span.AddEvent("format-event-response", trace.WithAttributes(
+		attribute.String("format-response", fmt.Sprintf("string-format: %s", helloStr)),
+	))
+
+	// printing the span details
+	tracing.PrintSpanContents(span)
+
+	return helloStr, nil
+}
+
+func printHello(ctx context.Context, publisherAddr, helloStr string) error {
+	// preparing to send an http get request to the "publisher" service
+	v := url.Values{}
+	v.Set("helloStr", helloStr)
+	url := "http://" + publisherAddr + "/publish?" + v.Encode()
+
+	// creating a client span
+	ctx, span := tracer.Start(ctx, "printHello", trace.WithSpanKind(trace.SpanKindClient))
+	defer span.End()
+
+	// creating a new HTTP request to printer microservice
+	req, err := http.NewRequestWithContext(ctx, "GET", url, nil)
+	if err != nil {
+		return err
+	}
+
+	// recording the attributes of the request following the semantic conventions
+	span.SetAttributes(append(attrs.HTTPClientAttrs(req), attrs.PeerService("publisher"))...)
+
+	// injecting the span context into the request headers
+	otel.GetTextMapPropagator().Inject(ctx, propagation.HeaderCarrier(req.Header))
+
+	//sending a get request
+	if _, err := xhttp.Do(req); err != nil {
+		// recording the error in the span and marking the span as failed
+		tracing.RecordFailure(span, err,
+			attribute.String("publish-response-error", fmt.Sprintf("Failed to publish the string %s", helloStr)))
+		return err
+	}
+
+	// printing the span details
+	tracing.PrintSpanContents(span)
+
+	return nil
+}
@@ -0,0 +1,100 @@
+package main
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log"
+	"log/slog"
+	"net/http"
+	"os"
+	"os/signal"
+	"syscall"
+
+	"github.com/legosandorigami/opentelemetry-tutorial/go/lib/config"
+	xlog "github.com/legosandorigami/opentelemetry-tutorial/go/lib/log"
+	"github.com/legosandorigami/opentelemetry-tutorial/go/lib/tracing"
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/propagation"
+	"go.opentelemetry.io/otel/trace"
+)
+
+func main() {
+	// loading the service addresses from the command-line flags and environment variables
+	cfg := config.Load()
+
+	// initialize the OpenTelemetry TracerProvider with the service name "formatter"
+	tracerPovider, err := tracing.InitTracerProviderWithBackend("formatter", cfg.OTLPEndpoint, tracing.WithPropagation(cfg.Propagation))
+	if err != nil {
+		log.Fatalf("failed to create otel exporter: %v", err)
+	}
+
+	// initialize the OpenTelemetry LoggerProvider with the service name "formatter", exporting to the same backend
+	loggerProvider, err := xlog.InitLoggerProviderWithBackend("formatter", cfg.OTLPEndpoint)
+	if err != nil {
+		log.Fatalf("failed to create otel log exporter: %v", err)
+	}
+
+	// creating a context canceled on an interrupt or a termination, which stops the server at the end of main rather than
+	// the process, and registering the providers to shut down then, in a defined order and with a timeout each, the
+	// TracerProvider last
+	ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt, syscall.SIGTERM)
+	defer stop()
+	shutdown := config.NewShutdownCoordinator()
+	shutdown.Add(config.TRACES, 0, tracerPovider.Shutdown)
+	shutdown.Add(config.LOGS, 0, loggerProvider.Shutdown)
+
+	// creating a logger emitting its records through the OpenTelemetry log bridge
+	logger := xlog.NewOTel("formatter-logger")
+
+	// retrieving or creating a tracer with name "formatter-tracer"
+	tracer := tracerPovider.Tracer("formatter-tracer")
+
+	http.HandleFunc("/format", func(w http.ResponseWriter, r *http.Request) {
+		// retrieving the global propagator and extracting the span context from the request headers
+		ctx := otel.GetTextMapPropagator().Extract(context.Background(), propagation.HeaderCarrier(r.Header))
+
+		// starting a new span named "format" as a child of the extracted span context
+		ctx, span := tracer.Start(ctx, "format", trace.WithSpanKind(trace.SpanKindServer))
+		defer span.End()
+
+		helloTo := r.FormValue("helloTo")
+		helloStr := fmt.Sprintf("Hello, %s!", helloTo)
+
+		// logging with the context of the span, the bridge adds its trace and span IDs to the record
+		logger.InfoContext(ctx, "formatted the greeting", slog.String("hello-to", helloTo))
+
+		// adding an event to the span indicating that the string was properly formatted
+		span.AddEvent("event", trace.WithAttributes(
+			attribute.String("string-format", helloStr),
+		))
+
+		// printing the span details
+		tracing.PrintSpanContents(span)
+
+		w.Write([]byte(helloStr))
+	})
+
+	// serving in a goroutine rather than with log.Fatal, which would exit the program without shutting the providers
+	// down
+	server := &http.Server{Addr: config.ListenAddr(cfg.FormatterAddr)}
+	go func() {
+		if err := server.ListenAndServe(); !errors.Is(err, http.ErrServerClosed) {
+			log.Fatal(err)
+		}
+	}()
+
+	// waiting for an interrupt or a termination, then letting the requests in flight end before shutting the providers
+	// down, so that the telemetry of those requests is exported as well
+	<-ctx.Done()
+	log.Printf("shutting down")
+	serverCtx, cancel := context.WithTimeout(context.WithoutCancel(ctx), config.DEFAULT_SHUTDOWN_TIMEOUT)
+	defer cancel()
+	if err := server.Shutdown(serverCtx); err != nil {
+		log.Printf("failed to shutdown the server: %v", err)
+	}
+	if err := shutdown.Shutdown(ctx); err != nil {
+		log.Fatalf("failed to shutdown the providers: %v", err)
+	}
+}
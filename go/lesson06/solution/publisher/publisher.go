@@ -0,0 +1,91 @@
+package main
+
+import (
+	"context"
+	"errors"
+	"log"
+	"log/slog"
+	"net/http"
+	"os"
+	"os/signal"
+	"syscall"
+
+	"github.com/legosandorigami/opentelemetry-tutorial/go/lib/config"
+	xlog "github.com/legosandorigami/opentelemetry-tutorial/go/lib/log"
+	"github.com/legosandorigami/opentelemetry-tutorial/go/lib/tracing"
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/propagation"
+	"go.opentelemetry.io/otel/trace"
+)
+
+func main() {
+	// loading the service addresses from the command-line flags and environment variables
+	cfg := config.Load()
+
+	// initialize the OpenTelemetry TracerProvider with the service name "publisher"
+	tracerPovider, err := tracing.InitTracerProviderWithBackend("publisher", cfg.OTLPEndpoint, tracing.WithPropagation(cfg.Propagation))
+	if err != nil {
+		log.Fatalf("failed to create otel exporter: %v", err)
+	}
+
+	// initialize the OpenTelemetry LoggerProvider with the service name "publisher", exporting to the same backend
+	loggerProvider, err := xlog.InitLoggerProviderWithBackend("publisher", cfg.OTLPEndpoint)
+	if err != nil {
+		log.Fatalf("failed to create otel log exporter: %v", err)
+	}
+
+	// creating a context canceled on an interrupt or a termination, which stops the server at the end of main rather than
+	// the process, and registering the providers to shut down then, in a defined order and with a timeout each, the
+	// TracerProvider last
+	ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt, syscall.SIGTERM)
+	defer stop()
+	shutdown := config.NewShutdownCoordinator()
+	shutdown.Add(config.TRACES, 0, tracerPovider.Shutdown)
+	shutdown.Add(config.LOGS, 0, loggerProvider.Shutdown)
+
+	// creating a logger emitting its records through the OpenTelemetry log bridge
+	logger := xlog.NewOTel("publisher-logger")
+
+	// retrieving or creating a tracer with name "publisher-tracer"
+	tracer := tracerPovider.Tracer("publisher-tracer")
+
+	http.HandleFunc("/publish", func(w http.ResponseWriter, r *http.Request) {
+		// retrieving the global propagator and extracting the span context from the request headers
+		ctx := otel.GetTextMapPropagator().Extract(context.Background(), propagation.HeaderCarrier(r.Header))
+
+		// starting a new span named "publish" as a child of the extracted span context
+		ctx, span := tracer.Start(ctx, "publish", trace.WithSpanKind(trace.SpanKindServer))
+		defer span.End()
+
+		helloStr := r.FormValue("helloStr")
+		println(helloStr)
+
+		// logging with the context of the span, the bridge adds its trace and span IDs to the record
+		logger.InfoContext(ctx, "published the greeting", slog.Int("length", len(helloStr)))
+
+		// printing the span details
+		tracing.PrintSpanContents(span)
+	})
+
+	// serving in a goroutine rather than with log.Fatal, which would exit the program without shutting the providers
+	// down
+	server := &http.Server{Addr: config.ListenAddr(cfg.PublisherAddr)}
+	go func() {
+		if err := server.ListenAndServe(); !errors.Is(err, http.ErrServerClosed) {
+			log.Fatal(err)
+		}
+	}()
+
+	// waiting for an interrupt or a termination, then letting the requests in flight end before shutting the providers
+	// down, so that the telemetry of those requests is exported as well
+	<-ctx.Done()
+	log.Printf("shutting down")
+	serverCtx, cancel := context.WithTimeout(context.WithoutCancel(ctx), config.DEFAULT_SHUTDOWN_TIMEOUT)
+	defer cancel()
+	if err := server.Shutdown(serverCtx); err != nil {
+		log.Printf("failed to shutdown the server: %v", err)
+	}
+	if err := shutdown.Shutdown(ctx); err != nil {
+		log.Fatalf("failed to shutdown the providers: %v", err)
+	}
+}
@@ -0,0 +1,94 @@
+package main
+
+import (
+	"context"
+	"flag"
+	"log"
+	"strings"
+
+	"github.com/legosandorigami/opentelemetry-tutorial/go/lib/config"
+	"github.com/legosandorigami/opentelemetry-tutorial/lesson07/hellopb"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/credentials/insecure"
+	"google.golang.org/grpc/status"
+)
+
+func main() {
+	// registering the flag selecting the client-streaming publisher RPC
+	stream := flag.Bool("stream", false, "publish all the greetings over a single client-streaming RPC")
+
+	// loading the service addresses from the command-line flags and environment variables
+	cfg := config.Load()
+
+	// checking if the number of positional arguments is exactly 1, a comma-separated list of names
+	if flag.NArg() != 1 {
+		panic("ERROR: Expecting one argument")
+	}
+
+	ctx := context.Background()
+
+	// creating plaintext client connections to the formatter and the publisher
+	formatterConn, err := grpc.NewClient(cfg.FormatterAddr, grpc.WithTransportCredentials(insecure.NewCredentials()))
+	if err != nil {
+		log.Fatalf("failed to dial the formatter: %v", err)
+	}
+	defer formatterConn.Close()
+
+	publisherConn, err := grpc.NewClient(cfg.PublisherAddr, grpc.WithTransportCredentials(insecure.NewCredentials()))
+	if err != nil {
+		log.Fatalf("failed to dial the publisher: %v", err)
+	}
+	defer publisherConn.Close()
+
+	helloTo := flag.Arg(0)
+
+	// formatting the greeting of every person
+	formatter := hellopb.NewFormatterClient(formatterConn)
+	var helloStrs []string
+	for _, name := range strings.Split(helloTo, ",") {
+		reply, err := formatter.Format(ctx, &hellopb.FormatRequest{HelloTo: name})
+		if err != nil {
+			// the status code tells why the call failed
+			log.Printf("failed to format the string: %s: %s", status.Code(err), status.Convert(err).Message())
+			continue
+		}
+		helloStrs = append(helloStrs, reply.GetHelloStr())
+	}
+
+	// calling the publisher, once per greeting or once for all of them over a stream
+	publisher := hellopb.NewPublisherClient(publisherConn)
+	if *stream {
+		if err := publishStream(ctx, publisher, helloStrs); err != nil {
+			log.Fatalf("failed to publish the strings: %v", err)
+		}
+	} else {
+		for _, helloStr := range helloStrs {
+			if _, err := publisher.Publish(ctx, &hellopb.PublishRequest{HelloStr: helloStr}); err != nil {
+				log.Fatalf("failed to publish the string: %v", err)
+			}
+		}
+	}
+}
+
+// publishStream sends the greetings to the publisher over a single client stream.
+func publishStream(ctx context.Context, publisher hellopb.PublisherClient, helloStrs []string) error {
+	stream, err := publisher.PublishStream(ctx)
+	if err != nil {
+		return err
+	}
+
+	for _, helloStr := range helloStrs {
+		if err := stream.Send(&hellopb.PublishRequest{HelloStr: helloStr}); err != nil {
+			return err
+		}
+	}
+
+	// closing the stream and waiting for the reply
+	reply, err := stream.CloseAndRecv()
+	if err != nil {
+		return err
+	}
+	log.Printf("published %d greetings", reply.GetCount())
+
+	return nil
+}
@@ -0,0 +1,45 @@
+package main
+
+import (
+	"context"
+	"fmt"
+	"log"
+	"net"
+
+	"github.com/legosandorigami/opentelemetry-tutorial/go/lib/config"
+	"github.com/legosandorigami/opentelemetry-tutorial/lesson07/hellopb"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+// formatter implements the hellopb.FormatterServer interface
+type formatter struct {
+	hellopb.UnimplementedFormatterServer
+}
+
+func (formatter) Format(ctx context.Context, req *hellopb.FormatRequest) (*hellopb.FormatReply, error) {
+	// rejecting the request with a gRPC status
+	if req.GetHelloTo() == "" {
+		return nil, status.Error(codes.InvalidArgument, "hello_to must not be empty")
+	}
+
+	helloStr := fmt.Sprintf("Hello, %s!", req.GetHelloTo())
+
+	return &hellopb.FormatReply{HelloStr: helloStr}, nil
+}
+
+func main() {
+	// loading the service addresses from the command-line flags and environment variables
+	cfg := config.Load()
+
+	lis, err := net.Listen("tcp", config.ListenAddr(cfg.FormatterAddr))
+	if err != nil {
+		log.Fatalf("failed to listen: %v", err)
+	}
+
+	server := grpc.NewServer()
+	hellopb.RegisterFormatterServer(server, formatter{})
+
+	log.Fatal(server.Serve(lis))
+}
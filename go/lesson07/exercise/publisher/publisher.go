@@ -0,0 +1,58 @@
+package main
+
+import (
+	"context"
+	"errors"
+	"io"
+	"log"
+	"net"
+
+	"github.com/legosandorigami/opentelemetry-tutorial/go/lib/config"
+	"github.com/legosandorigami/opentelemetry-tutorial/lesson07/hellopb"
+	"google.golang.org/grpc"
+)
+
+// publisher implements the hellopb.PublisherServer interface
+type publisher struct {
+	hellopb.UnimplementedPublisherServer
+}
+
+func (publisher) Publish(ctx context.Context, req *hellopb.PublishRequest) (*hellopb.PublishReply, error) {
+	println(req.GetHelloStr())
+
+	return &hellopb.PublishReply{}, nil
+}
+
+// PublishStream prints the greetings of a client stream.
+func (publisher) PublishStream(stream grpc.ClientStreamingServer[hellopb.PublishRequest, hellopb.PublishStreamReply]) error {
+	var count int32
+	for {
+		req, err := stream.Recv()
+		if errors.Is(err, io.EOF) {
+			break
+		}
+		if err != nil {
+			return err
+		}
+
+		println(req.GetHelloStr())
+		count++
+	}
+
+	return stream.SendAndClose(&hellopb.PublishStreamReply{Count: count})
+}
+
+func main() {
+	// loading the service addresses from the command-line flags and environment variables
+	cfg := config.Load()
+
+	lis, err := net.Listen("tcp", config.ListenAddr(cfg.PublisherAddr))
+	if err != nil {
+		log.Fatalf("failed to listen: %v", err)
+	}
+
+	server := grpc.NewServer()
+	hellopb.RegisterPublisherServer(server, publisher{})
+
+	log.Fatal(server.Serve(lis))
+}
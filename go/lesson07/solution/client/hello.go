@@ -0,0 +1,183 @@
+package main
+
+import (
+	"context"
+	"errors"
+	"flag"
+	"io"
+	"log"
+	"strings"
+	"time"
+
+	"github.com/legosandorigami/opentelemetry-tutorial/go/lib/config"
+	xgrpc "github.com/legosandorigami/opentelemetry-tutorial/go/lib/grpc"
+	"github.com/legosandorigami/opentelemetry-tutorial/go/lib/tracing"
+	"github.com/legosandorigami/opentelemetry-tutorial/lesson07/hellopb"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/baggage"
+	"go.opentelemetry.io/otel/trace"
+	"google.golang.org/grpc/status"
+)
+
+func main() {
+	// registering the flag selecting the client-streaming publisher RPC
+	stream := flag.Bool("stream", false, "publish all the greetings over a single client-streaming RPC")
+
+	// registering the flag selecting the server-streaming formatter RPC
+	formatStreamed := flag.Bool("format-stream", false, "receive every greeting one word at a time over a server-streaming RPC")
+
+	// loading the service addresses from the command-line flags and environment variables
+	cfg := config.Load()
+
+	// checking if the number of positional arguments is exactly 2, the first one being a comma-separated list of names
+	if flag.NArg() != 2 {
+		panic("ERROR: Expecting two arguments")
+	}
+
+	// initializing the OpenTelemetry TracerProvider with the service name "hello-world"
+	tracerPovider, err := tracing.InitTracerProviderWithBackend("hello-world", cfg.OTLPEndpoint, tracing.WithPropagation(cfg.Propagation))
+	if err != nil {
+		log.Fatalf("failed to create otel exporter: %v", err)
+	}
+
+	// creating a context and defering the shutdown of the TracerProvider to ensure proper cleanup
+	ctx := context.Background()
+	defer func() {
+		if err := tracerPovider.Shutdown(ctx); err != nil {
+			log.Fatalf("failed to shutdown TracerProvider: %v", err)
+		}
+	}()
+
+	// creating traced client connections to the formatter and the publisher
+	formatterConn, err := xgrpc.Dial(cfg.FormatterAddr)
+	if err != nil {
+		log.Fatalf("failed to dial the formatter: %v", err)
+	}
+	defer formatterConn.Close()
+
+	publisherConn, err := xgrpc.Dial(cfg.PublisherAddr)
+	if err != nil {
+		log.Fatalf("failed to dial the publisher: %v", err)
+	}
+	defer publisherConn.Close()
+
+	// creating a tracer from the tracer provider named "say-hello-tracer"
+	tracer := tracerPovider.Tracer("say-hello-tracer")
+
+	helloTo := flag.Arg(0)
+	greeting := flag.Arg(1)
+
+	// adding the greeting to the baggage, it travels in the gRPC metadata along with the span context
+	member, err := baggage.NewMember("greeting", greeting)
+	if err != nil {
+		log.Fatalf("failed to create a new baggage member: %v", err)
+	}
+	b, err := baggage.New(member)
+	if err != nil {
+		log.Fatalf("failed to create a new baggage: %v", err)
+	}
+	ctx = baggage.ContextWithBaggage(ctx, b)
+
+	// starting a new span named "say-hello"
+	ctx, span := tracer.Start(ctx, "say-hello")
+	span.SetAttributes(attribute.String("hello-to", helloTo))
+	defer span.End()
+
+	// formatting the greeting of every person, the client span of each call is created by the stats handler
+	formatter := hellopb.NewFormatterClient(formatterConn)
+	var helloStrs []string
+	for _, name := range strings.Split(helloTo, ",") {
+		var helloStr string
+		if *formatStreamed {
+			helloStr, err = formatStream(ctx, formatter, name)
+		} else {
+			var reply *hellopb.FormatReply
+			reply, err = formatter.Format(ctx, &hellopb.FormatRequest{HelloTo: name})
+			helloStr = reply.GetHelloStr()
+		}
+		if err != nil {
+			// the status code tells why the call failed, the stats handler has already recorded it in the client span
+			log.Printf("failed to format the string: %s: %s", status.Code(err), status.Convert(err).Message())
+			continue
+		}
+		helloStrs = append(helloStrs, helloStr)
+	}
+
+	// calling the publisher with the same context, once per greeting or once for all of them over a stream
+	publisher := hellopb.NewPublisherClient(publisherConn)
+	if *stream {
+		if err := publishStream(ctx, publisher, helloStrs); err != nil {
+			log.Printf("failed to publish the strings: %v", err)
+			return
+		}
+	} else {
+		for _, helloStr := range helloStrs {
+			if _, err := publisher.Publish(ctx, &hellopb.PublishRequest{HelloStr: helloStr}); err != nil {
+				log.Printf("failed to publish the string: %v", err)
+				return
+			}
+		}
+	}
+
+	// printing the span details
+	tracing.PrintSpanContents(span)
+}
+
+// formatStream receives the greeting of name one chunk at a time over a server stream. The stats handler creates one
+// client span for the whole stream, to which a "chunk received" event is added for every chunk, with the time spent
+// waiting for it.
+func formatStream(ctx context.Context, formatter hellopb.FormatterClient, name string) (string, error) {
+	stream, err := formatter.FormatStream(ctx, &hellopb.FormatRequest{HelloTo: name})
+	if err != nil {
+		return "", err
+	}
+
+	// the context of the stream holds the client span started by the stats handler
+	span := trace.SpanFromContext(stream.Context())
+
+	var words []string
+	last := time.Now()
+	for {
+		chunk, err := stream.Recv()
+		if errors.Is(err, io.EOF) {
+			// the server ended the stream, the client span ends here
+			break
+		}
+		if err != nil {
+			return "", err
+		}
+
+		span.AddEvent("chunk received", trace.WithAttributes(
+			attribute.Int("chunk.index", int(chunk.GetIndex())),
+			attribute.Int64("chunk.wait_ms", time.Since(last).Milliseconds()),
+		))
+		last = time.Now()
+		words = append(words, chunk.GetText())
+	}
+
+	return strings.Join(words, " "), nil
+}
+
+// publishStream sends the greetings to the publisher over a single client stream. The stats handler creates one client
+// span for the whole stream, and records every message sent and received as an event of that span.
+func publishStream(ctx context.Context, publisher hellopb.PublisherClient, helloStrs []string) error {
+	stream, err := publisher.PublishStream(ctx)
+	if err != nil {
+		return err
+	}
+
+	for _, helloStr := range helloStrs {
+		if err := stream.Send(&hellopb.PublishRequest{HelloStr: helloStr}); err != nil {
+			return err
+		}
+	}
+
+	// closing the stream and waiting for the reply, the client span ends here
+	reply, err := stream.CloseAndRecv()
+	if err != nil {
+		return err
+	}
+	log.Printf("published %d greetings", reply.GetCount())
+
+	return nil
+}
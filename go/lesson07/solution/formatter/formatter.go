@@ -0,0 +1,154 @@
+package main
+
+import (
+	"context"
+	"flag"
+	"fmt"
+	"log"
+	"net"
+	"strings"
+	"time"
+
+	"github.com/legosandorigami/opentelemetry-tutorial/go/lib/config"
+	xgrpc "github.com/legosandorigami/opentelemetry-tutorial/go/lib/grpc"
+	"github.com/legosandorigami/opentelemetry-tutorial/go/lib/tracing"
+	"github.com/legosandorigami/opentelemetry-tutorial/lesson07/hellopb"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/baggage"
+	otelcodes "go.opentelemetry.io/otel/codes"
+	"go.opentelemetry.io/otel/trace"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+// formatter implements the hellopb.FormatterServer interface
+type formatter struct {
+	hellopb.UnimplementedFormatterServer
+
+	tracer     trace.Tracer
+	chunkDelay time.Duration
+}
+
+func (formatter) Format(ctx context.Context, req *hellopb.FormatRequest) (*hellopb.FormatReply, error) {
+	// the server span was started by the stats handler from the context extracted from the request metadata
+	span := trace.SpanFromContext(ctx)
+
+	// rejecting the request with a gRPC status, which the stats handlers map to the status of the spans
+	if req.GetHelloTo() == "" {
+		return nil, status.Error(codes.InvalidArgument, "hello_to must not be empty")
+	}
+
+	// retrieving the member from the baggage with the key "greeting"
+	greeting := baggage.FromContext(ctx).Member("greeting").Value()
+	if greeting == "" {
+		greeting = "Hello"
+	}
+
+	helloStr := fmt.Sprintf("%s, %s!", greeting, req.GetHelloTo())
+
+	// adding an event to the span indicating that the string was properly formatted
+	span.AddEvent("event name", trace.WithAttributes(
+		attribute.String("event", fmt.Sprintf("string-format: %s", helloStr)),
+	))
+
+	// printing the span details
+	tracing.PrintSpanContents(span)
+
+	return &hellopb.FormatReply{HelloStr: helloStr}, nil
+}
+
+// FormatStream streams the greeting back one word at a time. The whole stream is a single RPC, hence a single server
+// span, in which the stats handler records one event per message sent. Each chunk also gets a child span of its own,
+// which times the work behind it and shows where a slow or failed stream spent its time.
+func (f formatter) FormatStream(req *hellopb.FormatRequest, stream grpc.ServerStreamingServer[hellopb.FormatChunk]) error {
+	// the context of the stream holds the server span started by the stats handler, and the baggage
+	ctx := stream.Context()
+	span := trace.SpanFromContext(ctx)
+
+	if req.GetHelloTo() == "" {
+		return status.Error(codes.InvalidArgument, "hello_to must not be empty")
+	}
+
+	greeting := baggage.FromContext(ctx).Member("greeting").Value()
+	if greeting == "" {
+		greeting = "Hello"
+	}
+	words := strings.Fields(fmt.Sprintf("%s, %s!", greeting, req.GetHelloTo()))
+
+	for i, word := range words {
+		if err := f.sendChunk(ctx, stream, int32(i), word); err != nil {
+			return err
+		}
+	}
+
+	// adding an event to the span once the whole greeting was sent
+	span.AddEvent("stream sent", trace.WithAttributes(
+		attribute.String("event", fmt.Sprintf("sent %d chunks", len(words))),
+	))
+
+	// printing the span details
+	tracing.PrintSpanContents(span)
+
+	return nil
+}
+
+// sendChunk sends a single chunk of the greeting in a child span of the server span
+func (f formatter) sendChunk(ctx context.Context, stream grpc.ServerStreamingServer[hellopb.FormatChunk], index int32, text string) error {
+	_, span := f.tracer.Start(ctx, "format-chunk", trace.WithAttributes(
+		attribute.Int("chunk.index", int(index)),
+		attribute.String("chunk.text", text),
+	))
+	defer span.End()
+
+	// simulating the work producing the chunk, and giving up as soon as the client does
+	select {
+	case <-time.After(f.chunkDelay):
+	case <-ctx.Done():
+		span.SetStatus(otelcodes.Error, "client went away")
+		return status.FromContextError(ctx.Err()).Err()
+	}
+
+	if err := stream.Send(&hellopb.FormatChunk{Index: index, Text: text}); err != nil {
+		span.RecordError(err)
+		span.SetStatus(otelcodes.Error, "failed to send the chunk")
+		return err
+	}
+	return nil
+}
+
+func main() {
+	// registering the flag setting how long each chunk of a streamed greeting takes to produce
+	chunkDelay := flag.Duration("chunk-delay", 100*time.Millisecond, "time taken to produce each chunk of a streamed greeting")
+
+	// loading the service addresses from the command-line flags and environment variables
+	cfg := config.Load()
+
+	// initialize the OpenTelemetry TracerProvider with the service name "formatter"
+	tracerPovider, err := tracing.InitTracerProviderWithBackend("formatter", cfg.OTLPEndpoint, tracing.WithPropagation(cfg.Propagation))
+	if err != nil {
+		log.Fatalf("failed to create otel exporter: %v", err)
+	}
+
+	// creating a context and defering the shutdown of the TracerProvider to ensure proper cleanup
+	ctx := context.Background()
+	defer func() {
+		if err := tracerPovider.Shutdown(ctx); err != nil {
+			log.Fatalf("failed to shutdown TracerProvider: %v", err)
+		}
+	}()
+
+	lis, err := net.Listen("tcp", config.ListenAddr(cfg.FormatterAddr))
+	if err != nil {
+		log.Fatalf("failed to listen: %v", err)
+	}
+
+	// creating a gRPC server traced by the otelgrpc stats handler
+	server := xgrpc.NewServer()
+	hellopb.RegisterFormatterServer(server, formatter{
+		tracer:     tracerPovider.Tracer("formatter-tracer"),
+		chunkDelay: *chunkDelay,
+	})
+
+	log.Fatal(server.Serve(lis))
+}
@@ -0,0 +1,92 @@
+package main
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"log"
+	"net"
+
+	"github.com/legosandorigami/opentelemetry-tutorial/go/lib/config"
+	xgrpc "github.com/legosandorigami/opentelemetry-tutorial/go/lib/grpc"
+	"github.com/legosandorigami/opentelemetry-tutorial/go/lib/tracing"
+	"github.com/legosandorigami/opentelemetry-tutorial/lesson07/hellopb"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/trace"
+	"google.golang.org/grpc"
+)
+
+// publisher implements the hellopb.PublisherServer interface
+type publisher struct {
+	hellopb.UnimplementedPublisherServer
+}
+
+func (publisher) Publish(ctx context.Context, req *hellopb.PublishRequest) (*hellopb.PublishReply, error) {
+	println(req.GetHelloStr())
+
+	// printing the details of the server span started by the stats handler
+	tracing.PrintSpanContents(trace.SpanFromContext(ctx))
+
+	return &hellopb.PublishReply{}, nil
+}
+
+// PublishStream prints the greetings of a client stream. The whole stream is a single RPC, hence a single server span,
+// in which the stats handler records one event per message received.
+func (publisher) PublishStream(stream grpc.ClientStreamingServer[hellopb.PublishRequest, hellopb.PublishStreamReply]) error {
+	span := trace.SpanFromContext(stream.Context())
+
+	var count int32
+	for {
+		req, err := stream.Recv()
+		if errors.Is(err, io.EOF) {
+			break
+		}
+		if err != nil {
+			return err
+		}
+
+		println(req.GetHelloStr())
+		count++
+	}
+
+	// adding an event to the span once the client closed the stream
+	span.AddEvent("stream closed", trace.WithAttributes(
+		attribute.String("event", fmt.Sprintf("published %d greetings", count)),
+	))
+
+	// printing the details of the server span started by the stats handler
+	tracing.PrintSpanContents(span)
+
+	return stream.SendAndClose(&hellopb.PublishStreamReply{Count: count})
+}
+
+func main() {
+	// loading the service addresses from the command-line flags and environment variables
+	cfg := config.Load()
+
+	// initialize the OpenTelemetry TracerProvider with the service name "publisher"
+	tracerPovider, err := tracing.InitTracerProviderWithBackend("publisher", cfg.OTLPEndpoint, tracing.WithPropagation(cfg.Propagation))
+	if err != nil {
+		log.Fatalf("failed to create otel exporter: %v", err)
+	}
+
+	// creating a context and defering the shutdown of the TracerProvider to ensure proper cleanup
+	ctx := context.Background()
+	defer func() {
+		if err := tracerPovider.Shutdown(ctx); err != nil {
+			log.Fatalf("failed to shutdown TracerProvider: %v", err)
+		}
+	}()
+
+	lis, err := net.Listen("tcp", config.ListenAddr(cfg.PublisherAddr))
+	if err != nil {
+		log.Fatalf("failed to listen: %v", err)
+	}
+
+	// creating a gRPC server traced by the otelgrpc stats handler
+	server := xgrpc.NewServer()
+	hellopb.RegisterPublisherServer(server, publisher{})
+
+	log.Fatal(server.Serve(lis))
+}
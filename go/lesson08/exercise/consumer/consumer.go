@@ -0,0 +1,41 @@
+package main
+
+import (
+	"context"
+	"log"
+	"strings"
+
+	"github.com/legosandorigami/opentelemetry-tutorial/go/lib/config"
+	"github.com/segmentio/kafka-go"
+)
+
+const CONSUMER_GROUP = "consumer"
+
+func main() {
+	// loading the service addresses from the command-line flags and environment variables
+	cfg := config.Load()
+	brokers := cfg.KafkaBrokers
+	if brokers == "" {
+		brokers = config.DEFAULT_KAFKA_BROKERS
+	}
+
+	ctx := context.Background()
+
+	// creating a Kafka reader consuming the greetings topic as a member of the consumer group
+	reader := kafka.NewReader(kafka.ReaderConfig{
+		Brokers: strings.Split(brokers, ","),
+		Topic:   cfg.KafkaTopic,
+		GroupID: CONSUMER_GROUP,
+	})
+	defer reader.Close()
+
+	for {
+		msg, err := reader.ReadMessage(ctx)
+		if err != nil {
+			log.Printf("failed to read message: %v", err)
+			return
+		}
+
+		println(string(msg.Value))
+	}
+}
@@ -0,0 +1,78 @@
+package main
+
+import (
+	"context"
+	"log"
+	"net/http"
+	"strings"
+
+	"github.com/legosandorigami/opentelemetry-tutorial/go/lib/config"
+	"github.com/legosandorigami/opentelemetry-tutorial/go/lib/tracing"
+	"github.com/segmentio/kafka-go"
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/propagation"
+	"go.opentelemetry.io/otel/trace"
+)
+
+func main() {
+	// loading the service addresses from the command-line flags and environment variables
+	cfg := config.Load()
+	brokers := cfg.KafkaBrokers
+	if brokers == "" {
+		brokers = config.DEFAULT_KAFKA_BROKERS
+	}
+
+	// initialize the OpenTelemetry TracerProvider with the service name "publisher"
+	tracerPovider, err := tracing.InitTracerProviderWithBackend("publisher", cfg.OTLPEndpoint, tracing.WithPropagation(cfg.Propagation))
+	if err != nil {
+		log.Fatalf("failed to create otel exporter: %v", err)
+	}
+
+	// creating a context and defering the shutdown of the TracerProvider to ensure proper cleanup
+	ctx := context.Background()
+	defer func() {
+		if err := tracerPovider.Shutdown(ctx); err != nil {
+			log.Fatalf("failed to shutdown TracerProvider: %v", err)
+		}
+	}()
+
+	// retrieving or creating a tracer with name "publisher-tracer"
+	tracer := tracerPovider.Tracer("publisher-tracer")
+
+	// creating a Kafka writer producing to the greetings topic
+	writer := &kafka.Writer{
+		Addr:                   kafka.TCP(strings.Split(brokers, ",")...),
+		Topic:                  cfg.KafkaTopic,
+		AllowAutoTopicCreation: true,
+	}
+	defer writer.Close()
+
+	http.HandleFunc("/publish", func(w http.ResponseWriter, r *http.Request) {
+		// retrieving the global propagator and extracting the span context from the request headers
+		ctx := otel.GetTextMapPropagator().Extract(context.Background(), propagation.HeaderCarrier(r.Header))
+
+		// starting a new span named "publish" as a child of the extracted span context
+		ctx, span := tracer.Start(ctx, "publish", trace.WithSpanKind(trace.SpanKindServer))
+		defer span.End()
+
+		helloStr := r.FormValue("helloStr")
+
+		// handing the greeting over to Kafka, the consumer prints it later
+		if err := produce(ctx, writer, helloStr); err != nil {
+			http.Error(w, err.Error(), http.StatusInternalServerError)
+			return
+		}
+
+		// printing the span details
+		tracing.PrintSpanContents(span)
+	})
+
+	log.Fatal(http.ListenAndServe(config.ListenAddr(cfg.PublisherAddr), nil))
+}
+
+// produce sends the greeting to Kafka.
+func produce(ctx context.Context, writer *kafka.Writer, helloStr string) error {
+	msg := kafka.Message{Value: []byte(helloStr)}
+
+	return writer.WriteMessages(ctx, msg)
+}
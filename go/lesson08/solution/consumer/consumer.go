@@ -0,0 +1,84 @@
+package main
+
+import (
+	"context"
+	"log"
+	"strings"
+
+	"github.com/legosandorigami/opentelemetry-tutorial/go/lib/config"
+	"github.com/legosandorigami/opentelemetry-tutorial/go/lib/messaging"
+	"github.com/legosandorigami/opentelemetry-tutorial/go/lib/tracing"
+	"github.com/segmentio/kafka-go"
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/attribute"
+	semconv "go.opentelemetry.io/otel/semconv/v1.4.0"
+	"go.opentelemetry.io/otel/trace"
+)
+
+const CONSUMER_GROUP = "consumer"
+
+func main() {
+	// loading the service addresses from the command-line flags and environment variables
+	cfg := config.Load()
+	brokers := cfg.KafkaBrokers
+	if brokers == "" {
+		brokers = config.DEFAULT_KAFKA_BROKERS
+	}
+
+	// initialize the OpenTelemetry TracerProvider with the service name "consumer"
+	tracerPovider, err := tracing.InitTracerProviderWithBackend("consumer", cfg.OTLPEndpoint, tracing.WithPropagation(cfg.Propagation))
+	if err != nil {
+		log.Fatalf("failed to create otel exporter: %v", err)
+	}
+
+	// creating a context and defering the shutdown of the TracerProvider to ensure proper cleanup
+	ctx := context.Background()
+	defer func() {
+		if err := tracerPovider.Shutdown(ctx); err != nil {
+			log.Fatalf("failed to shutdown TracerProvider: %v", err)
+		}
+	}()
+
+	// retrieving or creating a tracer with name "consumer-tracer"
+	tracer := tracerPovider.Tracer("consumer-tracer")
+
+	// creating a Kafka reader consuming the greetings topic as a member of the consumer group
+	reader := kafka.NewReader(kafka.ReaderConfig{
+		Brokers: strings.Split(brokers, ","),
+		Topic:   cfg.KafkaTopic,
+		GroupID: CONSUMER_GROUP,
+	})
+	defer reader.Close()
+
+	for {
+		msg, err := reader.ReadMessage(ctx)
+		if err != nil {
+			log.Printf("failed to read message: %v", err)
+			return
+		}
+
+		// extracting the span context injected by the publisher from the message headers
+		msgCtx := otel.GetTextMapPropagator().Extract(ctx, messaging.NewKafkaHeaderCarrier(&msg))
+
+		// starting a span of kind consumer continuing the trace of the request that produced the message
+		_, span := tracer.Start(msgCtx, msg.Topic+" process",
+			trace.WithAttributes(
+				semconv.MessagingSystemKey.String("kafka"),
+				semconv.MessagingDestinationKey.String(msg.Topic),
+				semconv.MessagingDestinationKindTopic,
+				semconv.MessagingOperationProcess,
+				semconv.MessagingKafkaConsumerGroupKey.String(CONSUMER_GROUP),
+				semconv.MessagingKafkaPartitionKey.Int(msg.Partition),
+				attribute.Int64("messaging.kafka.message.offset", msg.Offset),
+			),
+			trace.WithSpanKind(trace.SpanKindConsumer),
+		)
+
+		println(string(msg.Value))
+
+		// printing the span details
+		tracing.PrintSpanContents(span)
+
+		span.End()
+	}
+}
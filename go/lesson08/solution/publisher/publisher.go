@@ -0,0 +1,104 @@
+package main
+
+import (
+	"context"
+	"log"
+	"net/http"
+	"strings"
+
+	"github.com/legosandorigami/opentelemetry-tutorial/go/lib/config"
+	"github.com/legosandorigami/opentelemetry-tutorial/go/lib/messaging"
+	"github.com/legosandorigami/opentelemetry-tutorial/go/lib/tracing"
+	"github.com/segmentio/kafka-go"
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/propagation"
+	semconv "go.opentelemetry.io/otel/semconv/v1.4.0"
+	"go.opentelemetry.io/otel/trace"
+)
+
+func main() {
+	// loading the service addresses from the command-line flags and environment variables
+	cfg := config.Load()
+	brokers := cfg.KafkaBrokers
+	if brokers == "" {
+		brokers = config.DEFAULT_KAFKA_BROKERS
+	}
+
+	// initialize the OpenTelemetry TracerProvider with the service name "publisher"
+	tracerPovider, err := tracing.InitTracerProviderWithBackend("publisher", cfg.OTLPEndpoint, tracing.WithPropagation(cfg.Propagation))
+	if err != nil {
+		log.Fatalf("failed to create otel exporter: %v", err)
+	}
+
+	// creating a context and defering the shutdown of the TracerProvider to ensure proper cleanup
+	ctx := context.Background()
+	defer func() {
+		if err := tracerPovider.Shutdown(ctx); err != nil {
+			log.Fatalf("failed to shutdown TracerProvider: %v", err)
+		}
+	}()
+
+	// retrieving or creating a tracer with name "publisher-tracer"
+	tracer := tracerPovider.Tracer("publisher-tracer")
+
+	// creating a Kafka writer producing to the greetings topic
+	writer := &kafka.Writer{
+		Addr:                   kafka.TCP(strings.Split(brokers, ",")...),
+		Topic:                  cfg.KafkaTopic,
+		AllowAutoTopicCreation: true,
+	}
+	defer writer.Close()
+
+	http.HandleFunc("/publish", func(w http.ResponseWriter, r *http.Request) {
+		// retrieving the global propagator and extracting the span context from the request headers
+		ctx := otel.GetTextMapPropagator().Extract(context.Background(), propagation.HeaderCarrier(r.Header))
+
+		// starting a new span named "publish" as a child of the extracted span context
+		ctx, span := tracer.Start(ctx, "publish", trace.WithSpanKind(trace.SpanKindServer))
+		defer span.End()
+
+		helloStr := r.FormValue("helloStr")
+
+		// handing the greeting over to Kafka, the consumer prints it later
+		if err := produce(ctx, tracer, writer, helloStr); err != nil {
+			http.Error(w, err.Error(), http.StatusInternalServerError)
+			return
+		}
+
+		// printing the span details
+		tracing.PrintSpanContents(span)
+	})
+
+	log.Fatal(http.ListenAndServe(config.ListenAddr(cfg.PublisherAddr), nil))
+}
+
+// produce sends the greeting to Kafka inside a producer span, whose context is injected into the message headers
+// so that the consumer can continue the trace.
+func produce(ctx context.Context, tracer trace.Tracer, writer *kafka.Writer, helloStr string) error {
+	// starting a span of kind producer, named after the destination and the operation as the semantic conventions ask
+	ctx, span := tracer.Start(ctx, writer.Topic+" send",
+		trace.WithAttributes(
+			semconv.MessagingSystemKey.String("kafka"),
+			semconv.MessagingDestinationKey.String(writer.Topic),
+			semconv.MessagingDestinationKindTopic,
+			semconv.MessagingMessagePayloadSizeBytesKey.Int(len(helloStr)),
+		),
+		trace.WithSpanKind(trace.SpanKindProducer),
+	)
+	defer span.End()
+
+	msg := kafka.Message{Value: []byte(helloStr)}
+
+	// injecting the context of the producer span and the baggage into the message headers
+	otel.GetTextMapPropagator().Inject(ctx, messaging.NewKafkaHeaderCarrier(&msg))
+
+	if err := writer.WriteMessages(ctx, msg); err != nil {
+		// recording the error in the span and marking the span as failed
+		tracing.RecordFailure(span, err,
+			attribute.String("produce-error", "Failed to produce the greeting"))
+		return err
+	}
+
+	return nil
+}
@@ -0,0 +1,130 @@
+package main
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"log"
+	"net/http"
+	"strings"
+
+	_ "github.com/jackc/pgx/v5/stdlib"
+	"github.com/legosandorigami/opentelemetry-tutorial/go/lib/config"
+	"github.com/legosandorigami/opentelemetry-tutorial/go/lib/tracing"
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/propagation"
+	"go.opentelemetry.io/otel/trace"
+	_ "modernc.org/sqlite"
+)
+
+// SQLITE_DSN is the SQLite database file used when no PostgreSQL connection string is configured
+const SQLITE_DSN = "file:greetings.db"
+
+func main() {
+	// loading the service addresses from the command-line flags and environment variables
+	cfg := config.Load()
+
+	// initialize the OpenTelemetry TracerProvider with the service name "formatter"
+	tracerPovider, err := tracing.InitTracerProviderWithBackend("formatter", cfg.OTLPEndpoint, tracing.WithPropagation(cfg.Propagation))
+	if err != nil {
+		log.Fatalf("failed to create otel exporter: %v", err)
+	}
+
+	// creating a context and defering the shutdown of the TracerProvider to ensure proper cleanup
+	ctx := context.Background()
+	defer func() {
+		if err := tracerPovider.Shutdown(ctx); err != nil {
+			log.Fatalf("failed to shutdown TracerProvider: %v", err)
+		}
+	}()
+
+	// opening the database holding the greeting templates, PostgreSQL when a connection string is configured
+	db, err := openDB(ctx, cfg.PostgresDSN)
+	if err != nil {
+		log.Fatalf("failed to open the database: %v", err)
+	}
+	defer db.Close()
+
+	// retrieving or creating a tracer with name "formatter-tracer"
+	tracer := tracerPovider.Tracer("formatter-tracer")
+
+	http.HandleFunc("/format", func(w http.ResponseWriter, r *http.Request) {
+		// retrieving the global propagator and extracting the span context from the request headers
+		ctx := otel.GetTextMapPropagator().Extract(context.Background(), propagation.HeaderCarrier(r.Header))
+
+		// starting a new span named "format" as a child of the extracted span context
+		ctx, span := tracer.Start(ctx, "format", trace.WithSpanKind(trace.SpanKindServer))
+		defer span.End()
+
+		helloTo := r.FormValue("helloTo")
+		lang := r.FormValue("lang")
+		if lang == "" {
+			lang = "en"
+		}
+		span.SetAttributes(attribute.String("lang", lang))
+
+		// reading the template of the language
+		var template string
+		err := db.QueryRowContext(ctx, "SELECT template FROM templates WHERE lang = $1", lang).Scan(&template)
+		if errors.Is(err, sql.ErrNoRows) {
+			http.Error(w, "no template for language "+lang, http.StatusNotFound)
+			return
+		}
+		if err != nil {
+			// recording the error in the span and marking the span as failed
+			tracing.RecordFailure(span, err, attribute.String("template-error", "Failed to read the template"))
+			http.Error(w, err.Error(), http.StatusInternalServerError)
+			return
+		}
+
+		helloStr := strings.ReplaceAll(template, "{name}", helloTo)
+
+		// adding an event to the span indicating that the string was properly formatted
+		span.AddEvent("event", trace.WithAttributes(
+			attribute.String("string-format", helloStr),
+		))
+
+		// printing the span details
+		tracing.PrintSpanContents(span)
+
+		w.Write([]byte(helloStr))
+	})
+
+	log.Fatal(http.ListenAndServe(config.ListenAddr(cfg.FormatterAddr), nil))
+}
+
+// openDB opens the PostgreSQL database at dsn, or the SQLite database file when dsn is empty, and creates and fills
+// the templates table if needed.
+func openDB(ctx context.Context, dsn string) (*sql.DB, error) {
+	driver := "pgx"
+	if dsn == "" {
+		driver, dsn = "sqlite", SQLITE_DSN
+	}
+
+	db, err := sql.Open(driver, dsn)
+	if err != nil {
+		return nil, err
+	}
+
+	_, err = db.ExecContext(ctx, `CREATE TABLE IF NOT EXISTS templates (
+		lang TEXT PRIMARY KEY,
+		template TEXT NOT NULL
+	)`)
+	if err != nil {
+		db.Close()
+		return nil, err
+	}
+
+	_, err = db.ExecContext(ctx, `INSERT INTO templates (lang, template) VALUES
+		('en', 'Hello, {name}!'),
+		('fr', 'Bonjour, {name} !'),
+		('es', '¡Hola, {name}!')
+		ON CONFLICT (lang) DO NOTHING`)
+	if err != nil {
+		db.Close()
+		return nil, err
+	}
+
+	return db, nil
+}
@@ -0,0 +1,171 @@
+package main
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"log"
+	"net/http"
+	"os"
+	"os/signal"
+	"strings"
+	"syscall"
+
+	"github.com/XSAM/otelsql"
+	_ "github.com/jackc/pgx/v5/stdlib"
+	"github.com/legosandorigami/opentelemetry-tutorial/go/lib/config"
+	"github.com/legosandorigami/opentelemetry-tutorial/go/lib/metrics"
+	"github.com/legosandorigami/opentelemetry-tutorial/go/lib/tracing"
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/propagation"
+	semconv "go.opentelemetry.io/otel/semconv/v1.4.0"
+	"go.opentelemetry.io/otel/trace"
+	_ "modernc.org/sqlite"
+)
+
+// SQLITE_DSN is the SQLite database file used when no PostgreSQL connection string is configured
+const SQLITE_DSN = "file:greetings.db"
+
+func main() {
+	// loading the service addresses from the command-line flags and environment variables
+	cfg := config.Load()
+
+	// initialize the OpenTelemetry TracerProvider with the service name "formatter"
+	tracerPovider, err := tracing.InitTracerProviderWithBackend("formatter", cfg.OTLPEndpoint, tracing.WithPropagation(cfg.Propagation))
+	if err != nil {
+		log.Fatalf("failed to create otel exporter: %v", err)
+	}
+
+	// initialize the OpenTelemetry MeterProvider with the service name "formatter", which receives the pool metrics
+	meterProvider, err := metrics.InitMeterProviderWithBackend("formatter", cfg.OTLPEndpoint)
+	if err != nil {
+		log.Fatalf("failed to create otel metric exporter: %v", err)
+	}
+
+	// creating a context canceled on an interrupt or a termination, which stops the server at the end of main rather than
+	// the process, and registering the providers to shut down then, in a defined order and with a timeout each, the
+	// TracerProvider last
+	ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt, syscall.SIGTERM)
+	defer stop()
+	shutdown := config.NewShutdownCoordinator()
+	shutdown.Add(config.TRACES, 0, tracerPovider.Shutdown)
+	shutdown.Add(config.METRICS, 0, meterProvider.Shutdown)
+
+	// opening the database holding the greeting templates, PostgreSQL when a connection string is configured
+	db, err := openDB(ctx, cfg.PostgresDSN)
+	if err != nil {
+		log.Fatalf("failed to open the database: %v", err)
+	}
+	defer db.Close()
+
+	// retrieving or creating a tracer with name "formatter-tracer"
+	tracer := tracerPovider.Tracer("formatter-tracer")
+
+	http.HandleFunc("/format", func(w http.ResponseWriter, r *http.Request) {
+		// retrieving the global propagator and extracting the span context from the request headers
+		ctx := otel.GetTextMapPropagator().Extract(context.Background(), propagation.HeaderCarrier(r.Header))
+
+		// starting a new span named "format" as a child of the extracted span context, the spans of the queries
+		// made with its context become its children
+		ctx, span := tracer.Start(ctx, "format", trace.WithSpanKind(trace.SpanKindServer))
+		defer span.End()
+
+		helloTo := r.FormValue("helloTo")
+		lang := r.FormValue("lang")
+		if lang == "" {
+			lang = "en"
+		}
+		span.SetAttributes(attribute.String("lang", lang))
+
+		// reading the template of the language, the query being recorded in a child span by otelsql
+		var template string
+		err := db.QueryRowContext(ctx, "SELECT template FROM templates WHERE lang = $1", lang).Scan(&template)
+		if errors.Is(err, sql.ErrNoRows) {
+			http.Error(w, "no template for language "+lang, http.StatusNotFound)
+			return
+		}
+		if err != nil {
+			// recording the error in the span and marking the span as failed
+			tracing.RecordFailure(span, err, attribute.String("template-error", "Failed to read the template"))
+			http.Error(w, err.Error(), http.StatusInternalServerError)
+			return
+		}
+
+		helloStr := strings.ReplaceAll(template, "{name}", helloTo)
+
+		// adding an event to the span indicating that the string was properly formatted
+		span.AddEvent("event", trace.WithAttributes(
+			attribute.String("string-format", helloStr),
+		))
+
+		// printing the span details
+		tracing.PrintSpanContents(span)
+
+		w.Write([]byte(helloStr))
+	})
+
+	// serving in a goroutine rather than with log.Fatal, which would exit the program without shutting the providers
+	// down
+	server := &http.Server{Addr: config.ListenAddr(cfg.FormatterAddr)}
+	go func() {
+		if err := server.ListenAndServe(); !errors.Is(err, http.ErrServerClosed) {
+			log.Fatal(err)
+		}
+	}()
+
+	// waiting for an interrupt or a termination, then letting the requests in flight end before shutting the providers
+	// down, so that the telemetry of those requests is exported as well
+	<-ctx.Done()
+	log.Printf("shutting down")
+	serverCtx, cancel := context.WithTimeout(context.WithoutCancel(ctx), config.DEFAULT_SHUTDOWN_TIMEOUT)
+	defer cancel()
+	if err := server.Shutdown(serverCtx); err != nil {
+		log.Printf("failed to shutdown the server: %v", err)
+	}
+	if err := shutdown.Shutdown(ctx); err != nil {
+		log.Fatalf("failed to shutdown the providers: %v", err)
+	}
+}
+
+// openDB opens the PostgreSQL database at dsn, or the SQLite database file when dsn is empty, through an
+// otelsql-wrapped driver, so that every statement is recorded as a span carrying the db.* attributes, and the
+// statistics of the connection pool are recorded as metrics. It creates and fills the templates table if needed.
+func openDB(ctx context.Context, dsn string) (*sql.DB, error) {
+	driver, system := "pgx", semconv.DBSystemPostgreSQL
+	if dsn == "" {
+		driver, dsn, system = "sqlite", SQLITE_DSN, semconv.DBSystemSqlite
+	}
+
+	db, err := otelsql.Open(driver, dsn, otelsql.WithAttributes(system))
+	if err != nil {
+		return nil, err
+	}
+
+	// recording the db.sql.connection.* metrics of the pool, e.g. the number of open and idle connections
+	if err := otelsql.RegisterDBStatsMetrics(db, otelsql.WithAttributes(system)); err != nil {
+		db.Close()
+		return nil, err
+	}
+
+	_, err = db.ExecContext(ctx, `CREATE TABLE IF NOT EXISTS templates (
+		lang TEXT PRIMARY KEY,
+		template TEXT NOT NULL
+	)`)
+	if err != nil {
+		db.Close()
+		return nil, err
+	}
+
+	_, err = db.ExecContext(ctx, `INSERT INTO templates (lang, template) VALUES
+		('en', 'Hello, {name}!'),
+		('fr', 'Bonjour, {name} !'),
+		('es', '¡Hola, {name}!')
+		ON CONFLICT (lang) DO NOTHING`)
+	if err != nil {
+		db.Close()
+		return nil, err
+	}
+
+	return db, nil
+}
@@ -0,0 +1,183 @@
+package main
+
+import (
+	"context"
+	"flag"
+	"fmt"
+	"log"
+	"net/http"
+	"net/url"
+	"strings"
+	"time"
+
+	"github.com/legosandorigami/opentelemetry-tutorial/go/lib/attrs"
+	"github.com/legosandorigami/opentelemetry-tutorial/go/lib/config"
+	xhttp "github.com/legosandorigami/opentelemetry-tutorial/go/lib/http"
+	"github.com/legosandorigami/opentelemetry-tutorial/go/lib/tracing"
+	"github.com/legosandorigami/opentelemetry-tutorial/lesson10/exercise/sampler"
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/propagation"
+	"go.opentelemetry.io/otel/trace"
+)
+
+// retrieving the tracer named "say-hello-tracer" once, from the global TracerProvider
+var tracer = otel.Tracer("say-hello-tracer")
+
+func main() {
+	// registering the flags of the load generator
+	rate := flag.Float64("rate", 10, "greetings per second, each one in its own trace")
+	duration := flag.Duration("duration", 10*time.Second, "how long to send greetings")
+	vip := flag.String("vip", "", "comma-separated names whose traces are always sampled, whatever -sampler decides")
+
+	// loading the service addresses and the sampler from the command-line flags and environment variables
+	cfg := config.Load()
+
+	// checking if at least one name was given, the greetings cycle through the names
+	if flag.NArg() < 1 || *rate <= 0 {
+		panic("ERROR: Expecting at least one argument and a positive rate")
+	}
+
+	// creating the sampler deciding which traces are recorded
+	s, err := tracing.ParseSampler(cfg.Sampler)
+	if err != nil {
+		log.Fatal(err)
+	}
+	if *vip != "" {
+		s = sampler.NewHelloToSampler(strings.Split(*vip, ","), s)
+	}
+	log.Printf("sampler: %s", s.Description())
+
+	// initializing the OpenTelemetry TracerProvider with the service name "hello-world" and the sampler
+	tracerPovider, err := tracing.InitTracerProviderWithSampler("hello-world", cfg.OTLPEndpoint, s, tracing.WithPropagation(cfg.Propagation))
+	if err != nil {
+		log.Fatalf("failed to create otel exporter: %v", err)
+	}
+
+	// creating a context and defering the shutdown of the TracerProvider to ensure proper cleanup
+	ctx := context.Background()
+	defer func() {
+		if err := tracerPovider.Shutdown(ctx); err != nil {
+			log.Fatalf("failed to shutdown TracerProvider: %v", err)
+		}
+	}()
+
+	// sending the greetings at the requested rate until the duration elapses
+	ticker := time.NewTicker(time.Duration(float64(time.Second) / *rate))
+	defer ticker.Stop()
+	deadline := time.After(*duration)
+
+	var sent, sampled int
+	for {
+		select {
+		case <-deadline:
+			log.Printf("%d of %d traces sampled", sampled, sent)
+			return
+		case <-ticker.C:
+			helloTo := flag.Arg(sent % flag.NArg())
+			sent++
+			if sayHello(ctx, cfg, helloTo) {
+				sampled++
+			}
+		}
+	}
+}
+
+// sayHello greets helloTo in a new trace, and returns whether the trace was sampled
+func sayHello(ctx context.Context, cfg *config.Config, helloTo string) bool {
+	// starting a new root span named "say-hello", the attribute is passed to Start for the sampler to see it
+	ctx, span := tracer.Start(ctx, "say-hello", trace.WithAttributes(attribute.String("hello-to", helloTo)))
+	defer span.End()
+
+	// the sampling decision is made once the span starts, and carried by the sampled flag of its context
+	sampled := span.SpanContext().IsSampled()
+	log.Printf("trace %s for %s sampled: %t", span.SpanContext().TraceID(), helloTo, sampled)
+
+	// calling `formatString` function with the context ctx.
+	helloStr, err := formatString(ctx, cfg.FormatterAddr, helloTo)
+	if err != nil {
+		log.Printf("failed to format the string: %v", err)
+		return sampled
+	}
+
+	// calling `printHello` function with the context ctx.
+	if err := printHello(ctx, cfg.PublisherAddr, helloStr); err != nil {
+		log.Printf("failed to publish the string: %v", err)
+	}
+
+	return sampled
+}
+
+func formatString(ctx context.Context, formatterAddr, helloTo string) (string, error) {
+	// preparing to send an http get request to the "formatter" service
+	v := url.Values{}
+	v.Set("helloTo", helloTo)
+	url := "http://" + formatterAddr + "/format?" + v.Encode()
+
+	// creating a client span indicating that it is an RPC
+	ctx, span := tracer.Start(ctx, "formatString", trace.WithSpanKind(trace.SpanKindClient))
+	defer span.End()
+
+	// creating a new HTTP request to formatter microservice
+	req, err := http.NewRequestWithContext(ctx, "GET", url, nil)
+	if err != nil {
+		return "", err
+	}
+
+	// recording the attributes of the request following the semantic conventions
+	span.SetAttributes(append(attrs.HTTPClientAttrs(req), attrs.PeerService("formatter"))...)
+
+	// injecting the span context into the request headers
+	otel.GetTextMapPropagator().Inject(ctx, propagation.HeaderCarrier(req.Header))
+
+	//sending a get request
+	resp, err := xhttp.Do(req)
+	if err != nil {
+		// recording the error in the span and marking the span as failed
+		tracing.RecordFailure(span, err,
+			attribute.String("format-response-error", fmt.Sprintf("Failed to format the string %s", helloTo)))
+		return "", err
+	}
+
+	helloStr := string(resp)
+
+	// adding an event to the span indicating a successful response was received
+	span.AddEvent("format-event-response", trace.WithAttributes(
+		attribute.String("format-response", fmt.Sprintf("string-format: %s", helloStr)),
+	))
+
+	return helloStr, nil
+}
+
+func printHello(ctx context.Context, publisherAddr, helloStr string) error {
+	// preparing to send an http get request to the "publisher" service
+	v := url.Values{}
+	v.Set("helloStr", helloStr)
+	url := "http://" + publisherAddr + "/publish?" + v.Encode()
+
+	// creating a client span
+	ctx, span := tracer.Start(ctx, "printHello", trace.WithSpanKind(trace.SpanKindClient))
+	defer span.End()
+
+	// creating a new HTTP request to printer microservice
+	req, err := http.NewRequestWithContext(ctx, "GET", url, nil)
+	if err != nil {
+		return err
+	}
+
+	// recording the attributes of the request following the semantic conventions
+	span.SetAttributes(append(attrs.HTTPClientAttrs(req), attrs.PeerService("publisher"))...)
+
+	// injecting the span context into the request headers
+	otel.GetTextMapPropagator().Inject(ctx, propagation.HeaderCarrier(req.Header))
+
+	//sending a get request
+	if _, err := xhttp.Do(req); err != nil {
+		// recording the error in the span and marking the span as failed
+		tracing.RecordFailure(span, err,
+			attribute.String("publish-response-error", fmt.Sprintf("Failed to publish the string %s", helloStr)))
+		return err
+	}
+
+	return nil
+}
@@ -0,0 +1,58 @@
+package main
+
+import (
+	"context"
+	"log"
+	"net/http"
+
+	"github.com/legosandorigami/opentelemetry-tutorial/go/lib/config"
+	"github.com/legosandorigami/opentelemetry-tutorial/go/lib/tracing"
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/propagation"
+	"go.opentelemetry.io/otel/trace"
+)
+
+func main() {
+	// loading the service addresses from the command-line flags and environment variables
+	cfg := config.Load()
+
+	// creating the sampler deciding which spans of the service are recorded
+	sampler, err := tracing.ParseSampler(cfg.Sampler)
+	if err != nil {
+		log.Fatal(err)
+	}
+
+	// initialize the OpenTelemetry TracerProvider with the service name "publisher" and the sampler
+	tracerPovider, err := tracing.InitTracerProviderWithSampler("publisher", cfg.OTLPEndpoint, sampler, tracing.WithPropagation(cfg.Propagation))
+	if err != nil {
+		log.Fatalf("failed to create otel exporter: %v", err)
+	}
+
+	// creating a context and defering the shutdown of the TracerProvider to ensure proper cleanup
+	ctx := context.Background()
+	defer func() {
+		if err := tracerPovider.Shutdown(ctx); err != nil {
+			log.Fatalf("failed to shutdown TracerProvider: %v", err)
+		}
+	}()
+
+	// retrieving or creating a tracer with name "publisher-tracer"
+	tracer := tracerPovider.Tracer("publisher-tracer")
+
+	http.HandleFunc("/publish", func(w http.ResponseWriter, r *http.Request) {
+		// retrieving the global propagator and extracting the span context from the request headers
+		ctx := otel.GetTextMapPropagator().Extract(context.Background(), propagation.HeaderCarrier(r.Header))
+
+		// starting a new span named "publish" as a child of the extracted span context
+		_, span := tracer.Start(ctx, "publish", trace.WithSpanKind(trace.SpanKindServer))
+		defer span.End()
+
+		helloStr := r.FormValue("helloStr")
+		println(helloStr)
+
+		// printing whether the span is sampled, i.e. recorded and exported, along with the sampled flag of its parent
+		log.Printf("publisher span sampled: %t, parent sampled: %t", span.SpanContext().IsSampled(), trace.SpanContextFromContext(ctx).IsSampled())
+	})
+
+	log.Fatal(http.ListenAndServe(config.ListenAddr(cfg.PublisherAddr), nil))
+}
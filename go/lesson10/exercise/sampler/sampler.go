@@ -0,0 +1,34 @@
+package sampler
+
+import (
+	"fmt"
+	"strings"
+
+	"go.opentelemetry.io/otel/attribute"
+	traceSdk "go.opentelemetry.io/otel/sdk/trace"
+)
+
+// HELLO_TO_KEY is the attribute holding the name of the person greeted, set by the client on its root span
+const HELLO_TO_KEY = attribute.Key("hello-to")
+
+// helloToSampler records every trace greeting one of a few people, and leaves the other traces to a fallback sampler
+type helloToSampler struct {
+	names    []string
+	fallback traceSdk.Sampler
+}
+
+// NewHelloToSampler returns a sampler recording the spans whose "hello-to" attribute is one of names, and delegating
+// the decision for every other span to fallback.
+func NewHelloToSampler(names []string, fallback traceSdk.Sampler) traceSdk.Sampler {
+	return &helloToSampler{names: names, fallback: fallback}
+}
+
+func (s *helloToSampler) ShouldSample(p traceSdk.SamplingParameters) traceSdk.SamplingResult {
+	// the exercise: look for the "hello-to" attribute in p.Attributes, and record the span when its value is one of
+	// s.names, whatever the fallback decides
+	return s.fallback.ShouldSample(p)
+}
+
+func (s *helloToSampler) Description() string {
+	return fmt.Sprintf("HelloToSampler{%s,%s}", strings.Join(s.names, ","), s.fallback.Description())
+}
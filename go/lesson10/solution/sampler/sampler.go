@@ -0,0 +1,43 @@
+package sampler
+
+import (
+	"fmt"
+	"slices"
+	"strings"
+
+	"go.opentelemetry.io/otel/attribute"
+	traceSdk "go.opentelemetry.io/otel/sdk/trace"
+)
+
+// HELLO_TO_KEY is the attribute holding the name of the person greeted, set by the client on its root span
+const HELLO_TO_KEY = attribute.Key("hello-to")
+
+// helloToSampler records every trace greeting one of a few people, and leaves the other traces to a fallback sampler
+type helloToSampler struct {
+	names    []string
+	fallback traceSdk.Sampler
+}
+
+// NewHelloToSampler returns a sampler recording the spans whose "hello-to" attribute is one of names, and delegating
+// the decision for every other span to fallback.
+// Samplers only see the attributes passed to tracer.Start, not the ones set on the span afterwards.
+func NewHelloToSampler(names []string, fallback traceSdk.Sampler) traceSdk.Sampler {
+	return &helloToSampler{names: names, fallback: fallback}
+}
+
+func (s *helloToSampler) ShouldSample(p traceSdk.SamplingParameters) traceSdk.SamplingResult {
+	for _, attr := range p.Attributes {
+		if attr.Key == HELLO_TO_KEY && slices.Contains(s.names, attr.Value.AsString()) {
+			// recording the span whatever the fallback decides, and telling why in an attribute added to the span
+			result := s.fallback.ShouldSample(p)
+			result.Decision = traceSdk.RecordAndSample
+			result.Attributes = append(result.Attributes, attribute.String("sampling.reason", "hello-to"))
+			return result
+		}
+	}
+	return s.fallback.ShouldSample(p)
+}
+
+func (s *helloToSampler) Description() string {
+	return fmt.Sprintf("HelloToSampler{%s,%s}", strings.Join(s.names, ","), s.fallback.Description())
+}
@@ -0,0 +1,129 @@
+package main
+
+import (
+	"context"
+	"log"
+	"net/http"
+	"sync"
+	"time"
+
+	"github.com/legosandorigami/opentelemetry-tutorial/go/lib/config"
+	"github.com/legosandorigami/opentelemetry-tutorial/go/lib/tracing"
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/propagation"
+	"go.opentelemetry.io/otel/trace"
+)
+
+// greeting is a greeting waiting in the queue, along with the span context of the request that queued it
+type greeting struct {
+	helloStr string
+	spanCtx  trace.SpanContext
+}
+
+// queue holds the greetings until the batch job publishes them
+type queue struct {
+	mu        sync.Mutex
+	greetings []greeting
+}
+
+func (q *queue) push(g greeting) int {
+	q.mu.Lock()
+	defer q.mu.Unlock()
+	q.greetings = append(q.greetings, g)
+	return len(q.greetings)
+}
+
+func (q *queue) drain() []greeting {
+	q.mu.Lock()
+	defer q.mu.Unlock()
+	greetings := q.greetings
+	q.greetings = nil
+	return greetings
+}
+
+func main() {
+	// loading the service addresses and the settings of the batch job from the command-line flags and environment variables
+	cfg := config.Load()
+
+	// batching the greetings by 5 unless told otherwise, the publisher of this lesson always batches them
+	batchSize := cfg.BatchSize
+	if batchSize <= 0 {
+		batchSize = 5
+	}
+
+	// initialize the OpenTelemetry TracerProvider with the service name "publisher"
+	tracerPovider, err := tracing.InitTracerProviderWithBackend("publisher", cfg.OTLPEndpoint, tracing.WithPropagation(cfg.Propagation))
+	if err != nil {
+		log.Fatalf("failed to create otel exporter: %v", err)
+	}
+
+	// creating a context and defering the shutdown of the TracerProvider to ensure proper cleanup
+	ctx := context.Background()
+	defer func() {
+		if err := tracerPovider.Shutdown(ctx); err != nil {
+			log.Fatalf("failed to shutdown TracerProvider: %v", err)
+		}
+	}()
+
+	// retrieving or creating a tracer with name "publisher-tracer"
+	tracer := tracerPovider.Tracer("publisher-tracer")
+
+	q := &queue{}
+	full := make(chan struct{}, 1)
+
+	// running the batch job whenever the queue is full, or the interval elapsed
+	go func() {
+		ticker := time.NewTicker(cfg.BatchInterval)
+		defer ticker.Stop()
+		for {
+			select {
+			case <-ticker.C:
+			case <-full:
+			}
+			if greetings := q.drain(); len(greetings) > 0 {
+				publishBatch(tracer, greetings)
+			}
+		}
+	}()
+
+	http.HandleFunc("/publish", func(w http.ResponseWriter, r *http.Request) {
+		// retrieving the global propagator and extracting the span context from the request headers
+		ctx := otel.GetTextMapPropagator().Extract(context.Background(), propagation.HeaderCarrier(r.Header))
+
+		// starting a new span named "publish" as a child of the extracted span context
+		_, span := tracer.Start(ctx, "publish", trace.WithSpanKind(trace.SpanKindServer))
+		defer span.End()
+
+		// queuing the greeting with the context of the span, the batch job links to it later
+		n := q.push(greeting{helloStr: r.FormValue("helloStr"), spanCtx: span.SpanContext()})
+		span.AddEvent("queued", trace.WithAttributes(attribute.Int("queue.length", n)))
+		if n >= batchSize {
+			select {
+			case full <- struct{}{}:
+			default:
+			}
+		}
+
+		// printing the span details
+		tracing.PrintSpanContents(span)
+	})
+
+	log.Fatal(http.ListenAndServe(config.ListenAddr(cfg.PublisherAddr), nil))
+}
+
+// publishBatch prints the greetings in a single span.
+func publishBatch(tracer trace.Tracer, greetings []greeting) {
+	// starting a new root span for the batch
+	_, span := tracer.Start(context.Background(), "publish-batch",
+		trace.WithAttributes(attribute.Int("batch.size", len(greetings))),
+	)
+	defer span.End()
+
+	for _, g := range greetings {
+		println(g.helloStr)
+	}
+
+	// printing the span details
+	tracing.PrintSpanContents(span)
+}
@@ -0,0 +1,104 @@
+package main
+
+import (
+	"context"
+	"flag"
+	"log"
+	"net/http"
+	"time"
+
+	"github.com/legosandorigami/opentelemetry-tutorial/go/lib/config"
+	"github.com/legosandorigami/opentelemetry-tutorial/go/lib/tracing"
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/codes"
+	"go.opentelemetry.io/otel/propagation"
+	"go.opentelemetry.io/otel/trace"
+)
+
+// job is a greeting waiting to be printed by a worker
+type job struct {
+	helloStr string
+	queuedAt time.Time
+}
+
+func main() {
+	// registering the flags of the worker pool
+	workers := flag.Int("workers", 2, "number of workers printing the greetings")
+	queueSize := flag.Int("queue-size", 10, "number of greetings the queue holds before the requests are rejected")
+	workDuration := flag.Duration("work-duration", 500*time.Millisecond, "time a worker takes to print a greeting")
+
+	// loading the service addresses from the command-line flags and environment variables
+	cfg := config.Load()
+
+	// initialize the OpenTelemetry TracerProvider with the service name "publisher"
+	tracerPovider, err := tracing.InitTracerProviderWithBackend("publisher", cfg.OTLPEndpoint, tracing.WithPropagation(cfg.Propagation))
+	if err != nil {
+		log.Fatalf("failed to create otel exporter: %v", err)
+	}
+
+	// creating a context and defering the shutdown of the TracerProvider to ensure proper cleanup
+	ctx := context.Background()
+	defer func() {
+		if err := tracerPovider.Shutdown(ctx); err != nil {
+			log.Fatalf("failed to shutdown TracerProvider: %v", err)
+		}
+	}()
+
+	// retrieving or creating a tracer with name "publisher-tracer"
+	tracer := tracerPovider.Tracer("publisher-tracer")
+
+	// creating the bounded queue and starting the workers consuming it
+	jobs := make(chan job, *queueSize)
+	for i := 0; i < *workers; i++ {
+		go work(tracer, i, jobs, *workDuration)
+	}
+
+	http.HandleFunc("/publish", func(w http.ResponseWriter, r *http.Request) {
+		// retrieving the global propagator and extracting the span context from the request headers
+		ctx := otel.GetTextMapPropagator().Extract(context.Background(), propagation.HeaderCarrier(r.Header))
+
+		// starting a new span named "publish" as a child of the extracted span context
+		_, span := tracer.Start(ctx, "publish", trace.WithSpanKind(trace.SpanKindServer))
+		defer span.End()
+
+		// queuing the greeting, without waiting when the queue is full
+		select {
+		case jobs <- job{helloStr: r.FormValue("helloStr"), queuedAt: time.Now()}:
+			span.AddEvent("queued", trace.WithAttributes(attribute.Int("queue.length", len(jobs))))
+		default:
+			span.SetStatus(codes.Error, "queue full")
+			http.Error(w, "queue full", http.StatusServiceUnavailable)
+			return
+		}
+
+		// printing the span details
+		tracing.PrintSpanContents(span)
+	})
+
+	log.Fatal(http.ListenAndServe(config.ListenAddr(cfg.PublisherAddr), nil))
+}
+
+// work prints the greetings of the jobs, each one in a "print" span.
+func work(tracer trace.Tracer, id int, jobs <-chan job, workDuration time.Duration) {
+	for j := range jobs {
+		opts := []trace.SpanStartOption{
+			trace.WithSpanKind(trace.SpanKindConsumer),
+			trace.WithAttributes(
+				attribute.Int("worker.id", id),
+				attribute.Int64("queue.wait_ms", time.Since(j.queuedAt).Milliseconds()),
+			),
+		}
+
+		_, span := tracer.Start(context.Background(), "print", opts...)
+
+		// simulating a slow printer
+		time.Sleep(workDuration)
+		println(j.helloStr)
+
+		// printing the span details
+		tracing.PrintSpanContents(span)
+
+		span.End()
+	}
+}
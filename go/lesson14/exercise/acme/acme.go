@@ -0,0 +1,36 @@
+package acme
+
+import (
+	"context"
+
+	"go.opentelemetry.io/otel/propagation"
+)
+
+// HEADER is the header of the legacy Acme format, holding "<trace-id>:<span-id>:<sampled>", e.g.
+// "4bf92f3577b34da6a3ce929d0e0e4736:00f067aa0ba902b7:1"
+const HEADER = "X-Acme-Trace"
+
+// Propagator propagates the span context in the X-Acme-Trace header of the legacy Acme services. It carries no
+// baggage and no trace state, which the Acme format has no room for.
+type Propagator struct{}
+
+var _ propagation.TextMapPropagator = Propagator{}
+
+// Inject sets the X-Acme-Trace header from the span context of ctx, if it holds a valid one.
+func (Propagator) Inject(ctx context.Context, carrier propagation.TextMapCarrier) {
+	// the exercise: retrieve the span context of ctx with trace.SpanContextFromContext, and when it is valid, set the
+	// header to its trace ID, span ID and sampled flag separated by colons
+}
+
+// Extract returns a copy of ctx holding the remote span context read from the X-Acme-Trace header. ctx is returned
+// unchanged when the header is missing or malformed.
+func (Propagator) Extract(ctx context.Context, carrier propagation.TextMapCarrier) context.Context {
+	// the exercise: parse the header, build the span context with trace.NewSpanContext, marking it as remote, and
+	// return the context returned by trace.ContextWithRemoteSpanContext
+	return ctx
+}
+
+// Fields returns the header set by Inject.
+func (Propagator) Fields() []string {
+	return []string{HEADER}
+}
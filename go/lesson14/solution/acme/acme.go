@@ -0,0 +1,82 @@
+package acme
+
+import (
+	"context"
+	"fmt"
+	"strings"
+
+	"go.opentelemetry.io/otel/propagation"
+	"go.opentelemetry.io/otel/trace"
+)
+
+// HEADER is the header of the legacy Acme format, holding "<trace-id>:<span-id>:<sampled>", e.g.
+// "4bf92f3577b34da6a3ce929d0e0e4736:00f067aa0ba902b7:1"
+const HEADER = "X-Acme-Trace"
+
+// Propagator propagates the span context in the X-Acme-Trace header of the legacy Acme services. It carries no
+// baggage and no trace state, which the Acme format has no room for.
+type Propagator struct{}
+
+var _ propagation.TextMapPropagator = Propagator{}
+
+// Inject sets the X-Acme-Trace header from the span context of ctx, if it holds a valid one.
+func (Propagator) Inject(ctx context.Context, carrier propagation.TextMapCarrier) {
+	sc := trace.SpanContextFromContext(ctx)
+	if !sc.IsValid() {
+		return
+	}
+
+	sampled := "0"
+	if sc.IsSampled() {
+		sampled = "1"
+	}
+	carrier.Set(HEADER, fmt.Sprintf("%s:%s:%s", sc.TraceID(), sc.SpanID(), sampled))
+}
+
+// Extract returns a copy of ctx holding the remote span context read from the X-Acme-Trace header. ctx is returned
+// unchanged when the header is missing or malformed.
+func (Propagator) Extract(ctx context.Context, carrier propagation.TextMapCarrier) context.Context {
+	sc, err := parse(carrier.Get(HEADER))
+	if err != nil {
+		return ctx
+	}
+	return trace.ContextWithRemoteSpanContext(ctx, sc)
+}
+
+// Fields returns the header set by Inject.
+func (Propagator) Fields() []string {
+	return []string{HEADER}
+}
+
+// parse parses the value of an X-Acme-Trace header
+func parse(value string) (trace.SpanContext, error) {
+	parts := strings.Split(value, ":")
+	if len(parts) != 3 {
+		return trace.SpanContext{}, fmt.Errorf("malformed %s header %q", HEADER, value)
+	}
+
+	traceID, err := trace.TraceIDFromHex(parts[0])
+	if err != nil {
+		return trace.SpanContext{}, err
+	}
+	spanID, err := trace.SpanIDFromHex(parts[1])
+	if err != nil {
+		return trace.SpanContext{}, err
+	}
+
+	var flags trace.TraceFlags
+	switch parts[2] {
+	case "1":
+		flags = trace.FlagsSampled
+	case "0":
+	default:
+		return trace.SpanContext{}, fmt.Errorf("malformed sampled flag %q", parts[2])
+	}
+
+	return trace.NewSpanContext(trace.SpanContextConfig{
+		TraceID:    traceID,
+		SpanID:     spanID,
+		TraceFlags: flags,
+		Remote:     true,
+	}), nil
+}
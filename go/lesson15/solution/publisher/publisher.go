@@ -0,0 +1,95 @@
+package main
+
+import (
+	"bufio"
+	"context"
+	"log"
+	"net"
+	"net/http"
+
+	"github.com/legosandorigami/opentelemetry-tutorial/go/lib/config"
+	xhttp "github.com/legosandorigami/opentelemetry-tutorial/go/lib/http"
+	"github.com/legosandorigami/opentelemetry-tutorial/go/lib/tracing"
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/codes"
+	"go.opentelemetry.io/otel/propagation"
+	semconv "go.opentelemetry.io/otel/semconv/v1.4.0"
+	"go.opentelemetry.io/otel/trace"
+)
+
+// statusRecorder is an http.ResponseWriter remembering the status code written by the handler, and whether the
+// connection was dropped without a response
+type statusRecorder struct {
+	http.ResponseWriter
+	status  int
+	dropped bool
+}
+
+func (r *statusRecorder) WriteHeader(status int) {
+	r.status = status
+	r.ResponseWriter.WriteHeader(status)
+}
+
+// Hijack lets the chaos middleware take the connection over, which it does to drop the request
+func (r *statusRecorder) Hijack() (net.Conn, *bufio.ReadWriter, error) {
+	r.dropped = true
+	return http.NewResponseController(r.ResponseWriter).Hijack()
+}
+
+func main() {
+	// loading the service addresses from the command-line flags and environment variables
+	cfg := config.Load()
+
+	// initialize the OpenTelemetry TracerProvider with the service name "publisher"
+	tracerPovider, err := tracing.InitTracerProviderWithBackend("publisher", cfg.OTLPEndpoint, tracing.WithPropagation(cfg.Propagation))
+	if err != nil {
+		log.Fatalf("failed to create otel exporter: %v", err)
+	}
+
+	// creating a context and defering the shutdown of the TracerProvider to ensure proper cleanup
+	ctx := context.Background()
+	defer func() {
+		if err := tracerPovider.Shutdown(ctx); err != nil {
+			log.Fatalf("failed to shutdown TracerProvider: %v", err)
+		}
+	}()
+
+	// retrieving or creating a tracer with name "publisher-tracer"
+	tracer := tracerPovider.Tracer("publisher-tracer")
+
+	publishHandler := http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		helloStr := r.FormValue("helloStr")
+		println(helloStr)
+
+		// printing the details of the span started before the chaos middleware
+		tracing.PrintSpanContents(trace.SpanFromContext(r.Context()))
+	})
+
+	// failing a fraction of the requests on purpose, with a 500, a delay or a dropped connection
+	chaos := xhttp.Chaos(xhttp.ChaosOptions{Rate: cfg.ChaosRate, Delay: cfg.ChaosDelay})(publishHandler)
+
+	http.HandleFunc("/publish", func(w http.ResponseWriter, r *http.Request) {
+		// retrieving the global propagator and extracting the span context from the request headers
+		ctx := otel.GetTextMapPropagator().Extract(context.Background(), propagation.HeaderCarrier(r.Header))
+
+		// starting a new span named "publish" as a child of the extracted span context, before the chaos middleware
+		// runs, so that the injected failures are recorded in it
+		ctx, span := tracer.Start(ctx, "publish", trace.WithSpanKind(trace.SpanKindServer))
+		defer span.End()
+
+		rec := &statusRecorder{ResponseWriter: w, status: http.StatusOK}
+		chaos.ServeHTTP(rec, r.WithContext(ctx))
+
+		// setting the status of the span from the outcome of the request, a server span fails on the 5xx status codes only
+		if rec.dropped {
+			span.SetStatus(codes.Error, "connection dropped")
+			return
+		}
+		span.SetAttributes(semconv.HTTPStatusCodeKey.Int(rec.status))
+		if rec.status >= http.StatusInternalServerError {
+			span.SetStatus(codes.Error, http.StatusText(rec.status))
+		}
+	})
+
+	log.Fatal(http.ListenAndServe(config.ListenAddr(cfg.PublisherAddr), nil))
+}
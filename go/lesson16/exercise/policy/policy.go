@@ -0,0 +1,29 @@
+package policy
+
+import (
+	"net/http"
+
+	xhttp "github.com/legosandorigami/opentelemetry-tutorial/go/lib/http"
+)
+
+const (
+	// BAGGAGE_HEADER is the W3C header carrying the baggage
+	BAGGAGE_HEADER = "baggage"
+
+	// MAX_VALUE_BYTES is the longest value of a baggage member let through, well below the limits of the W3C
+	// specification, as every member is copied to every request downstream
+	MAX_VALUE_BYTES = 128
+)
+
+// Allowlist returns a middleware dropping the baggage members whose key is not one of keys, or whose value is longer
+// than MAX_VALUE_BYTES, before the request reaches next. Malformed baggage, including baggage exceeding the W3C limits,
+// is dropped as a whole. The keys of the dropped members are logged, never their values, which may hold secrets.
+func Allowlist(keys ...string) xhttp.Middleware {
+	return func(next http.Handler) http.Handler {
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			// the exercise: parse the baggage header with baggage.Parse, delete the members which are not allowed, and
+			// pass a clone of the request carrying the filtered baggage to next
+			next.ServeHTTP(w, r)
+		})
+	}
+}
@@ -0,0 +1,131 @@
+package main
+
+import (
+	"context"
+	"flag"
+	"fmt"
+	"log"
+	"net/http"
+	"net/url"
+	"strings"
+
+	"github.com/legosandorigami/opentelemetry-tutorial/go/lib/attrs"
+	"github.com/legosandorigami/opentelemetry-tutorial/go/lib/config"
+	xhttp "github.com/legosandorigami/opentelemetry-tutorial/go/lib/http"
+	"github.com/legosandorigami/opentelemetry-tutorial/go/lib/tracing"
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/baggage"
+	"go.opentelemetry.io/otel/propagation"
+	"go.opentelemetry.io/otel/trace"
+)
+
+// retrieving the tracer named "say-hello-tracer" once, from the global TracerProvider
+var tracer = otel.Tracer("say-hello-tracer")
+
+func main() {
+	// registering the flags of the baggage sent along with the greeting
+	greeting := flag.String("greeting", "", "greeting propagated to the formatter in the baggage")
+	items := flag.String("baggage", "", "additional baggage members, e.g. locale=fr,tenant=acme")
+	padding := flag.Int("padding", 0, "size in bytes of a \"padding\" baggage member, to experiment with the limits")
+
+	// loading the service addresses from the command-line flags and environment variables
+	cfg := config.Load()
+
+	// checking if exactly one name was given
+	if flag.NArg() != 1 {
+		panic("ERROR: Expecting one argument")
+	}
+	helloTo := flag.Arg(0)
+
+	// initializing the OpenTelemetry TracerProvider with the service name "hello-world"
+	tracerPovider, err := tracing.InitTracerProviderWithBackend("hello-world", cfg.OTLPEndpoint, tracing.WithPropagation(cfg.Propagation))
+	if err != nil {
+		log.Fatalf("failed to create otel exporter: %v", err)
+	}
+
+	// creating a context and defering the shutdown of the TracerProvider to ensure proper cleanup
+	ctx := context.Background()
+	defer func() {
+		if err := tracerPovider.Shutdown(ctx); err != nil {
+			log.Fatalf("failed to shutdown TracerProvider: %v", err)
+		}
+	}()
+
+	// creating the baggage members, the raw values are percent-encoded when the baggage is injected
+	baggageItems := config.ParseKeyValues(*items)
+	if *greeting != "" {
+		baggageItems["greeting"] = *greeting
+	}
+	if *padding > 0 {
+		baggageItems["padding"] = strings.Repeat("x", *padding)
+	}
+
+	var members []baggage.Member
+	for k, v := range baggageItems {
+		m, err := baggage.NewMemberRaw(k, v)
+		if err != nil {
+			log.Fatalf("invalid baggage member %q: %v", k, err)
+		}
+		members = append(members, m)
+	}
+
+	// creating the baggage, which fails when it exceeds the limits of the W3C specification
+	bag, err := baggage.New(members...)
+	if err != nil {
+		log.Fatalf("invalid baggage: %v", err)
+	}
+	ctx = baggage.ContextWithBaggage(ctx, bag)
+	log.Printf("sending %d baggage members in %d bytes", bag.Len(), len(bag.String()))
+
+	// starting a new root span named "say-hello"
+	ctx, span := tracer.Start(ctx, "say-hello")
+	span.SetAttributes(attribute.String("hello-to", helloTo))
+	defer span.End()
+
+	// calling `formatString` function with the context ctx, the formatter publishes the greeting itself
+	helloStr, err := formatString(ctx, cfg.FormatterAddr, helloTo)
+	if err != nil {
+		log.Printf("failed to greet %q: %v", helloTo, err)
+		return
+	}
+
+	println(helloStr)
+
+	// printing the span details
+	tracing.PrintSpanContents(span)
+}
+
+func formatString(ctx context.Context, formatterAddr, helloTo string) (string, error) {
+	// preparing to send an http get request to the "formatter" service
+	v := url.Values{}
+	v.Set("helloTo", helloTo)
+	url := "http://" + formatterAddr + "/format?" + v.Encode()
+
+	// creating a client span indicating that it is an RPC
+	ctx, span := tracer.Start(ctx, "formatString", trace.WithSpanKind(trace.SpanKindClient))
+	defer span.End()
+
+	// creating a new HTTP request to formatter microservice
+	req, err := http.NewRequestWithContext(ctx, "GET", url, nil)
+	if err != nil {
+		return "", err
+	}
+
+	// recording the attributes of the request following the semantic conventions
+	span.SetAttributes(append(attrs.HTTPClientAttrs(req), attrs.PeerService("formatter"))...)
+
+	// injecting the span context and the baggage into the request headers
+	otel.GetTextMapPropagator().Inject(ctx, propagation.HeaderCarrier(req.Header))
+
+	//sending a get request
+	resp, err := xhttp.Do(req)
+	if err != nil {
+		// recording the error in the span and marking the span as failed
+		tracing.RecordFailure(span, err,
+			attribute.String("format-response-error", fmt.Sprintf("Failed to format the string %s", helloTo)))
+		return "", err
+	}
+
+	return string(resp), nil
+}
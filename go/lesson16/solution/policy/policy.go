@@ -0,0 +1,62 @@
+package policy
+
+import (
+	"log"
+	"net/http"
+
+	xhttp "github.com/legosandorigami/opentelemetry-tutorial/go/lib/http"
+	"go.opentelemetry.io/otel/baggage"
+)
+
+const (
+	// BAGGAGE_HEADER is the W3C header carrying the baggage
+	BAGGAGE_HEADER = "baggage"
+
+	// MAX_VALUE_BYTES is the longest value of a baggage member let through, well below the limits of the W3C
+	// specification, as every member is copied to every request downstream
+	MAX_VALUE_BYTES = 128
+)
+
+// Allowlist returns a middleware dropping the baggage members whose key is not one of keys, or whose value is longer
+// than MAX_VALUE_BYTES, before the request reaches next. Malformed baggage, including baggage exceeding the W3C limits,
+// is dropped as a whole. The keys of the dropped members are logged, never their values, which may hold secrets.
+func Allowlist(keys ...string) xhttp.Middleware {
+	allowed := make(map[string]bool, len(keys))
+	for _, k := range keys {
+		allowed[k] = true
+	}
+
+	return func(next http.Handler) http.Handler {
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			header := r.Header.Get(BAGGAGE_HEADER)
+			if header == "" {
+				next.ServeHTTP(w, r)
+				return
+			}
+
+			// parsing the header the way the baggage propagator does, which rejects the whole baggage when it is
+			// malformed or exceeds the W3C limits
+			bag, err := baggage.Parse(header)
+			if err != nil {
+				log.Printf("dropping malformed baggage of %d bytes", len(header))
+			}
+
+			for _, m := range bag.Members() {
+				if !allowed[m.Key()] || len(m.Value()) > MAX_VALUE_BYTES {
+					log.Printf("dropping baggage member %q", m.Key())
+					bag = bag.DeleteMember(m.Key())
+				}
+			}
+
+			// handing a copy of the request with the filtered baggage over to the handler, whose propagator
+			// extracts it as usual
+			r = r.Clone(r.Context())
+			r.Header.Del(BAGGAGE_HEADER)
+			if bag.Len() > 0 {
+				r.Header.Set(BAGGAGE_HEADER, bag.String())
+			}
+
+			next.ServeHTTP(w, r)
+		})
+	}
+}
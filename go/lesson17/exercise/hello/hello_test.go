@@ -0,0 +1,56 @@
+package hello
+
+import (
+	"bytes"
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"go.opentelemetry.io/otel/trace/noop"
+)
+
+func TestFormatHandler(t *testing.T) {
+	// the exercise: replace the no-op tracer with one from a TracerProvider recording the spans in a
+	// tracetest.SpanRecorder, and check the name, the kind and the parent of the "format" span
+	tracer := noop.NewTracerProvider().Tracer("test")
+
+	rec := httptest.NewRecorder()
+	FormatHandler(tracer).ServeHTTP(rec, httptest.NewRequest("GET", "/format?helloTo=Alice", nil))
+
+	if got, want := rec.Body.String(), "Hello, Alice!"; got != want {
+		t.Errorf("body = %q, want %q", got, want)
+	}
+}
+
+func TestSayHello(t *testing.T) {
+	// the exercise: record the spans of the three services, and check that they form a single trace
+	tracer := noop.NewTracerProvider().Tracer("test")
+
+	var out bytes.Buffer
+	formatter := httptest.NewServer(FormatHandler(tracer))
+	defer formatter.Close()
+	publisher := httptest.NewServer(PublishHandler(tracer, &out))
+	defer publisher.Close()
+
+	if err := SayHello(context.Background(), tracer, formatter.URL, publisher.URL, "Alice"); err != nil {
+		t.Fatalf("SayHello() = %v", err)
+	}
+
+	if got, want := out.String(), "Hello, Alice!\n"; got != want {
+		t.Errorf("published %q, want %q", got, want)
+	}
+}
+
+func TestSayHelloPublisherDown(t *testing.T) {
+	tracer := noop.NewTracerProvider().Tracer("test")
+
+	formatter := httptest.NewServer(FormatHandler(tracer))
+	defer formatter.Close()
+	publisher := httptest.NewServer(http.NotFoundHandler())
+	defer publisher.Close()
+
+	if err := SayHello(context.Background(), tracer, formatter.URL, publisher.URL, "Alice"); err == nil {
+		t.Fatal("SayHello() = nil, want an error")
+	}
+}
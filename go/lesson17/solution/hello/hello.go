@@ -0,0 +1,114 @@
+// Package hello holds the instrumented code of the client, the formatter and the publisher, out of their main
+// packages, so that it can be tested.
+package hello
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+
+	xhttp "github.com/legosandorigami/opentelemetry-tutorial/go/lib/http"
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/codes"
+	"go.opentelemetry.io/otel/propagation"
+	semconv "go.opentelemetry.io/otel/semconv/v1.4.0"
+	"go.opentelemetry.io/otel/trace"
+)
+
+// HELLO_TO_KEY is the attribute holding the name of the person greeted
+const HELLO_TO_KEY = attribute.Key("hello-to")
+
+// FormatHandler returns the handler of the formatter, formatting the greeting in a "format" span.
+func FormatHandler(tracer trace.Tracer) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		// retrieving the global propagator and extracting the span context from the request headers
+		ctx := otel.GetTextMapPropagator().Extract(r.Context(), propagation.HeaderCarrier(r.Header))
+
+		// starting a new span named "format" as a child of the extracted span context
+		_, span := tracer.Start(ctx, "format", trace.WithSpanKind(trace.SpanKindServer))
+		defer span.End()
+
+		helloTo := r.FormValue("helloTo")
+		helloStr := fmt.Sprintf("Hello, %s!", helloTo)
+
+		// adding an event to the span indicating that the string was properly formatted
+		span.AddEvent("event", trace.WithAttributes(
+			attribute.String("string-format", helloStr),
+		))
+
+		w.Write([]byte(helloStr))
+	})
+}
+
+// PublishHandler returns the handler of the publisher, writing the greeting to out in a "publish" span.
+func PublishHandler(tracer trace.Tracer, out io.Writer) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		// retrieving the global propagator and extracting the span context from the request headers
+		ctx := otel.GetTextMapPropagator().Extract(r.Context(), propagation.HeaderCarrier(r.Header))
+
+		// starting a new span named "publish" as a child of the extracted span context
+		_, span := tracer.Start(ctx, "publish", trace.WithSpanKind(trace.SpanKindServer))
+		defer span.End()
+
+		fmt.Fprintln(out, r.FormValue("helloStr"))
+	})
+}
+
+// SayHello greets helloTo in a "say-hello" span, calling the formatter and the publisher at the given base URLs, e.g.
+// "http://localhost:8081".
+func SayHello(ctx context.Context, tracer trace.Tracer, formatterURL, publisherURL, helloTo string) error {
+	// starting a new span named "say-hello"
+	ctx, span := tracer.Start(ctx, "say-hello", trace.WithAttributes(HELLO_TO_KEY.String(helloTo)))
+	defer span.End()
+
+	v := url.Values{}
+	v.Set("helloTo", helloTo)
+	helloStr, err := call(ctx, tracer, "formatString", formatterURL+"/format?"+v.Encode())
+	if err != nil {
+		span.SetStatus(codes.Error, "failed to format the greeting")
+		return err
+	}
+
+	v = url.Values{}
+	v.Set("helloStr", string(helloStr))
+	if _, err := call(ctx, tracer, "printHello", publisherURL+"/publish?"+v.Encode()); err != nil {
+		span.SetStatus(codes.Error, "failed to publish the greeting")
+		return err
+	}
+
+	return nil
+}
+
+// call sends a GET request to url in a client span named name, and returns the response body
+func call(ctx context.Context, tracer trace.Tracer, name, url string) ([]byte, error) {
+	// creating a span with custom attributes indicating that it is an RPC
+	ctx, span := tracer.Start(ctx, name,
+		trace.WithAttributes(
+			semconv.HTTPURLKey.String(url),
+			semconv.HTTPMethodKey.String("GET"),
+		),
+		trace.WithSpanKind(trace.SpanKindClient),
+	)
+	defer span.End()
+
+	req, err := http.NewRequestWithContext(ctx, "GET", url, nil)
+	if err != nil {
+		return nil, err
+	}
+
+	// injecting the span context into the request headers
+	otel.GetTextMapPropagator().Inject(ctx, propagation.HeaderCarrier(req.Header))
+
+	body, err := xhttp.Do(req)
+	if err != nil {
+		// recording the error in the span, and marking it as failed
+		span.RecordError(err)
+		span.SetStatus(codes.Error, err.Error())
+		return nil, err
+	}
+
+	return body, nil
+}
@@ -0,0 +1,136 @@
+package hello
+
+import (
+	"context"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/propagation"
+	traceSdk "go.opentelemetry.io/otel/sdk/trace"
+	"go.opentelemetry.io/otel/sdk/trace/tracetest"
+	"go.opentelemetry.io/otel/trace"
+	"go.opentelemetry.io/otel/trace/noop"
+)
+
+// benchTracer is one of the setups the benchmarks compare
+type benchTracer struct {
+	name   string
+	tracer func(b *testing.B) trace.Tracer
+}
+
+// benchTracers are the setups the benchmarks compare: a no-op tracer, as if the code were not instrumented, an SDK
+// dropping every trace, and an SDK recording every span and exporting it, through a batch processor, to an exporter
+// throwing it away. The cost of the export itself, serializing and sending the spans, is not measured.
+var benchTracers = []benchTracer{
+	{"noop", func(b *testing.B) trace.Tracer {
+		return noop.NewTracerProvider().Tracer("bench")
+	}},
+	{"unsampled", func(b *testing.B) trace.Tracer {
+		return newBenchProvider(b, traceSdk.NeverSample()).Tracer("bench")
+	}},
+	{"sampled", func(b *testing.B) trace.Tracer {
+		return newBenchProvider(b, traceSdk.AlwaysSample()).Tracer("bench")
+	}},
+}
+
+// newBenchProvider returns a TracerProvider with the given sampler, shut down at the end of the benchmark
+func newBenchProvider(b *testing.B, sampler traceSdk.Sampler) *traceSdk.TracerProvider {
+	tp := traceSdk.NewTracerProvider(
+		traceSdk.WithBatcher(tracetest.NewNoopExporter()),
+		traceSdk.WithSampler(sampler),
+	)
+	b.Cleanup(func() { tp.Shutdown(context.Background()) })
+	return tp
+}
+
+// newTracedRequest returns a request to target carrying the context of a sampled remote span
+func newTracedRequest(target string) *http.Request {
+	parent := trace.NewSpanContext(trace.SpanContextConfig{
+		TraceID:    trace.TraceID{0x01},
+		SpanID:     trace.SpanID{0x02},
+		TraceFlags: trace.FlagsSampled,
+	})
+	req := httptest.NewRequest("GET", target, nil)
+	otel.GetTextMapPropagator().Inject(trace.ContextWithSpanContext(context.Background(), parent), propagation.HeaderCarrier(req.Header))
+	return req
+}
+
+// benchmarkHandler measures the handler built by newHandler, called directly without any network
+func benchmarkHandler(b *testing.B, target string, newHandler func(trace.Tracer) http.Handler) {
+	for _, bt := range benchTracers {
+		b.Run(bt.name, func(b *testing.B) {
+			handler := newHandler(bt.tracer(b))
+			b.ReportAllocs()
+			b.ResetTimer()
+			for i := 0; i < b.N; i++ {
+				handler.ServeHTTP(httptest.NewRecorder(), newTracedRequest(target))
+			}
+		})
+	}
+}
+
+func BenchmarkFormatHandler(b *testing.B) {
+	benchmarkHandler(b, "/format?helloTo=Alice", FormatHandler)
+}
+
+func BenchmarkPublishHandler(b *testing.B) {
+	benchmarkHandler(b, "/publish?helloStr=Hello%2C+Alice%21", func(tracer trace.Tracer) http.Handler {
+		return PublishHandler(tracer, io.Discard)
+	})
+}
+
+// benchmarkCall measures the client side of a call, to a server which is not traced
+func benchmarkCall(b *testing.B, name, path string) {
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Write([]byte("Hello, Alice!"))
+	}))
+	defer srv.Close()
+
+	for _, bt := range benchTracers {
+		b.Run(bt.name, func(b *testing.B) {
+			tracer := bt.tracer(b)
+			ctx, span := tracer.Start(context.Background(), "say-hello")
+			defer span.End()
+
+			b.ReportAllocs()
+			b.ResetTimer()
+			for i := 0; i < b.N; i++ {
+				if _, err := call(ctx, tracer, name, srv.URL+path); err != nil {
+					b.Fatal(err)
+				}
+			}
+		})
+	}
+}
+
+func BenchmarkFormatString(b *testing.B) {
+	benchmarkCall(b, "formatString", "/format?helloTo=Alice")
+}
+
+func BenchmarkPrintHello(b *testing.B) {
+	benchmarkCall(b, "printHello", "/publish?helloStr=Hello%2C+Alice%21")
+}
+
+// BenchmarkSayHello measures a whole greeting, the three programs tracing with the same setup
+func BenchmarkSayHello(b *testing.B) {
+	for _, bt := range benchTracers {
+		b.Run(bt.name, func(b *testing.B) {
+			tracer := bt.tracer(b)
+			formatter := httptest.NewServer(FormatHandler(tracer))
+			defer formatter.Close()
+			publisher := httptest.NewServer(PublishHandler(tracer, io.Discard))
+			defer publisher.Close()
+
+			b.ReportAllocs()
+			b.ResetTimer()
+			for i := 0; i < b.N; i++ {
+				if err := SayHello(context.Background(), tracer, formatter.URL, publisher.URL, "Alice"); err != nil {
+					b.Fatal(err)
+				}
+			}
+		})
+	}
+}
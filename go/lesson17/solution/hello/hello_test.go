@@ -0,0 +1,243 @@
+package hello
+
+import (
+	"bytes"
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"testing"
+
+	"github.com/legosandorigami/opentelemetry-tutorial/go/lib/testutil"
+	"github.com/legosandorigami/opentelemetry-tutorial/go/lib/tracingtest"
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/codes"
+	"go.opentelemetry.io/otel/propagation"
+	traceSdk "go.opentelemetry.io/otel/sdk/trace"
+	"go.opentelemetry.io/otel/sdk/trace/tracetest"
+	semconv "go.opentelemetry.io/otel/semconv/v1.4.0"
+	"go.opentelemetry.io/otel/trace"
+)
+
+func TestMain(m *testing.M) {
+	// installing the propagator the services install through tracing.InitTracerProvider
+	otel.SetTextMapPropagator(propagation.TraceContext{})
+	os.Exit(m.Run())
+}
+
+// newRecordingTracer returns a tracer whose spans are recorded by the returned SpanRecorder once ended
+func newRecordingTracer() (trace.Tracer, *tracetest.SpanRecorder) {
+	sr := tracetest.NewSpanRecorder()
+	tp := traceSdk.NewTracerProvider(traceSdk.WithSpanProcessor(sr))
+	return tp.Tracer("test"), sr
+}
+
+// spansByName indexes the ended spans of sr by name, failing the test when two spans share a name
+func spansByName(t *testing.T, sr *tracetest.SpanRecorder) map[string]traceSdk.ReadOnlySpan {
+	t.Helper()
+	spans := make(map[string]traceSdk.ReadOnlySpan)
+	for _, s := range sr.Ended() {
+		if _, ok := spans[s.Name()]; ok {
+			t.Fatalf("span %q recorded twice", s.Name())
+		}
+		spans[s.Name()] = s
+	}
+	return spans
+}
+
+func TestFormatHandler(t *testing.T) {
+	tracer, sr := newRecordingTracer()
+
+	// sending a request carrying the context of a remote parent span
+	parent := trace.NewSpanContext(trace.SpanContextConfig{
+		TraceID:    trace.TraceID{0x01},
+		SpanID:     trace.SpanID{0x02},
+		TraceFlags: trace.FlagsSampled,
+	})
+	req := httptest.NewRequest("GET", "/format?helloTo=Alice", nil)
+	otel.GetTextMapPropagator().Inject(trace.ContextWithSpanContext(context.Background(), parent), propagation.HeaderCarrier(req.Header))
+
+	rec := httptest.NewRecorder()
+	FormatHandler(tracer).ServeHTTP(rec, req)
+
+	if got, want := rec.Body.String(), "Hello, Alice!"; got != want {
+		t.Errorf("body = %q, want %q", got, want)
+	}
+
+	spans := sr.Ended()
+	if len(spans) != 1 {
+		t.Fatalf("recorded %d spans, want 1", len(spans))
+	}
+	span := spans[0]
+
+	if span.Name() != "format" {
+		t.Errorf("name = %q, want %q", span.Name(), "format")
+	}
+	if span.SpanKind() != trace.SpanKindServer {
+		t.Errorf("kind = %v, want %v", span.SpanKind(), trace.SpanKindServer)
+	}
+	if !span.Parent().IsRemote() || span.Parent().SpanID() != parent.SpanID() {
+		t.Errorf("parent = %v, want the remote span %v", span.Parent().SpanID(), parent.SpanID())
+	}
+	if span.SpanContext().TraceID() != parent.TraceID() {
+		t.Errorf("trace ID = %v, want %v", span.SpanContext().TraceID(), parent.TraceID())
+	}
+
+	// checking the event recording the formatted string
+	events := span.Events()
+	if len(events) != 1 {
+		t.Fatalf("recorded %d events, want 1", len(events))
+	}
+	if got := events[0].Attributes; len(got) != 1 || got[0].Value.AsString() != "Hello, Alice!" {
+		t.Errorf("event attributes = %v, want string-format=Hello, Alice!", got)
+	}
+}
+
+func TestSayHello(t *testing.T) {
+	tracer, sr := newRecordingTracer()
+
+	// running the formatter and the publisher in process, the spans of the three services end up in the same recorder
+	var out bytes.Buffer
+	formatter := httptest.NewServer(FormatHandler(tracer))
+	defer formatter.Close()
+	publisher := httptest.NewServer(PublishHandler(tracer, &out))
+	defer publisher.Close()
+
+	if err := SayHello(context.Background(), tracer, formatter.URL, publisher.URL, "Alice"); err != nil {
+		t.Fatalf("SayHello() = %v", err)
+	}
+
+	if got, want := out.String(), "Hello, Alice!\n"; got != want {
+		t.Errorf("published %q, want %q", got, want)
+	}
+
+	spans := spansByName(t, sr)
+
+	// checking the shape of the trace, each span is listed with the name of its parent
+	root := spans["say-hello"]
+	if root == nil {
+		t.Fatal("no say-hello span recorded")
+	}
+	if root.Parent().IsValid() {
+		t.Errorf("say-hello has parent %v, want none", root.Parent().SpanID())
+	}
+	for name, parentName := range map[string]string{
+		"formatString": "say-hello",
+		"format":       "formatString",
+		"printHello":   "say-hello",
+		"publish":      "printHello",
+	} {
+		span, parent := spans[name], spans[parentName]
+		if span == nil {
+			t.Errorf("no %s span recorded", name)
+			continue
+		}
+		if span.SpanContext().TraceID() != root.SpanContext().TraceID() {
+			t.Errorf("%s belongs to trace %v, want %v", name, span.SpanContext().TraceID(), root.SpanContext().TraceID())
+		}
+		if parent != nil && span.Parent().SpanID() != parent.SpanContext().SpanID() {
+			t.Errorf("%s is a child of %v, want %s", name, span.Parent().SpanID(), parentName)
+		}
+	}
+
+	// checking the attributes of the root span
+	var helloTo string
+	for _, kv := range root.Attributes() {
+		if kv.Key == HELLO_TO_KEY {
+			helloTo = kv.Value.AsString()
+		}
+	}
+	if helloTo != "Alice" {
+		t.Errorf("%s = %q, want %q", HELLO_TO_KEY, helloTo, "Alice")
+	}
+}
+
+func TestSayHelloPublisherDown(t *testing.T) {
+	tracer, sr := newRecordingTracer()
+
+	formatter := httptest.NewServer(FormatHandler(tracer))
+	defer formatter.Close()
+	publisher := httptest.NewServer(http.NotFoundHandler())
+	defer publisher.Close()
+
+	if err := SayHello(context.Background(), tracer, formatter.URL, publisher.URL, "Alice"); err == nil {
+		t.Fatal("SayHello() = nil, want an error")
+	}
+
+	// checking that the failure is recorded by the client span of the call, and marks the root span as failed
+	spans := spansByName(t, sr)
+	for _, name := range []string{"printHello", "say-hello"} {
+		if span := spans[name]; span == nil || span.Status().Code != codes.Error {
+			t.Errorf("%s is not marked as failed", name)
+		}
+	}
+	if events := spans["printHello"].Events(); len(events) != 1 || events[0].Name != "exception" {
+		t.Errorf("printHello events = %v, want an exception", events)
+	}
+}
+
+func TestSayHelloGolden(t *testing.T) {
+	tp, sr := tracingtest.NewRecorder()
+	tracer := tp.Tracer("test")
+
+	var out bytes.Buffer
+	formatter := httptest.NewServer(FormatHandler(tracer))
+	defer formatter.Close()
+	publisher := httptest.NewServer(PublishHandler(tracer, &out))
+	defer publisher.Close()
+
+	if err := SayHello(context.Background(), tracer, formatter.URL, publisher.URL, "Alice"); err != nil {
+		t.Fatalf("SayHello() = %v", err)
+	}
+
+	// comparing the whole trace with the snapshot in testdata, the URLs holding the ports of the test servers
+	tracingtest.AssertGolden(t, sr, "testdata/say-hello.golden.json", semconv.HTTPURLKey)
+}
+
+func TestEndToEnd(t *testing.T) {
+	var out bytes.Buffer
+	sr, err := testutil.RunHello(t, testutil.HelloServices{
+		Formatter: FormatHandler,
+		Publisher: func(tracer trace.Tracer) http.Handler { return PublishHandler(tracer, &out) },
+		Client:    SayHello,
+	}, "Alice")
+	if err != nil {
+		t.Fatalf("SayHello() = %v", err)
+	}
+	if got, want := out.String(), "Hello, Alice!\n"; got != want {
+		t.Errorf("published %q, want %q", got, want)
+	}
+
+	// checking the whole trace, each service recording its spans with a provider of its own
+	tracingtest.AssertSpanTree(t, sr, tracingtest.Span{
+		Name:     "say-hello",
+		Matchers: []tracingtest.Matcher{tracingtest.HasAttributes(HELLO_TO_KEY.String("Alice"))},
+		Children: []tracingtest.Span{
+			{
+				Name:     "formatString",
+				Matchers: []tracingtest.Matcher{tracingtest.HasKind(trace.SpanKindClient)},
+				Children: []tracingtest.Span{{Name: "format", Matchers: []tracingtest.Matcher{tracingtest.HasRemoteParent()}}},
+			},
+			{
+				Name:     "printHello",
+				Matchers: []tracingtest.Matcher{tracingtest.HasKind(trace.SpanKindClient)},
+				Children: []tracingtest.Span{{Name: "publish", Matchers: []tracingtest.Matcher{tracingtest.HasRemoteParent()}}},
+			},
+		},
+	})
+
+	// checking that each span was recorded by the provider of its service
+	services := map[string]string{
+		"say-hello":    "hello-world",
+		"formatString": "hello-world",
+		"printHello":   "hello-world",
+		"format":       "formatter",
+		"publish":      "publisher",
+	}
+	for _, s := range sr.Ended() {
+		service, _ := s.Resource().Set().Value(semconv.ServiceNameKey)
+		if want := services[s.Name()]; service.AsString() != want {
+			t.Errorf("%s recorded by %q, want %q", s.Name(), service.AsString(), want)
+		}
+	}
+}
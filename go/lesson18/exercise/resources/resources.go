@@ -0,0 +1,15 @@
+package resources
+
+import (
+	"context"
+
+	"github.com/legosandorigami/opentelemetry-tutorial/go/lib/tracing"
+	"go.opentelemetry.io/otel/sdk/resource"
+)
+
+// New returns the resource describing the process running the service.
+func New(ctx context.Context, service string) (*resource.Resource, error) {
+	// the exercise: add the ID of the instance, the attributes given in the environment, and the attributes detected
+	// on the host, the process and the container, as well as the Kubernetes attributes
+	return tracing.NewResource(service)
+}
@@ -0,0 +1,71 @@
+package resources
+
+import (
+	"context"
+	"os"
+
+	"github.com/google/uuid"
+	"github.com/legosandorigami/opentelemetry-tutorial/go/lib/tracing"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/sdk/resource"
+	semconv "go.opentelemetry.io/otel/semconv/v1.4.0"
+)
+
+// instanceID identifies the running process among the instances of the service. It is generated once, so that the
+// traces, metrics and logs of the process share it.
+var instanceID = uuid.NewString()
+
+// New returns the resource describing the process running the service: the attributes of tracing.NewResource, the ID of
+// the instance, the attributes detected on the host, the operating system, the process and the container, the
+// Kubernetes attributes exposed by the downward API, and finally the attributes of the OTEL_RESOURCE_ATTRIBUTES and
+// OTEL_SERVICE_NAME environment variables, which take precedence over all the others.
+func New(ctx context.Context, service string) (*resource.Resource, error) {
+	base, err := tracing.NewResource(service)
+	if err != nil {
+		return nil, err
+	}
+
+	detected, err := resource.New(ctx,
+		resource.WithAttributes(semconv.ServiceInstanceIDKey.String(instanceID)),
+		// host.name, os.type and os.description
+		resource.WithHost(),
+		resource.WithOSType(),
+		resource.WithOSDescription(),
+		// process.pid, process.executable.name, process.runtime.name and friends
+		resource.WithProcess(),
+		// container.id, when running in a container
+		resource.WithContainer(),
+		// the k8s.* attributes, when running in Kubernetes
+		resource.WithAttributes(k8sAttributes()...),
+		// the attributes of the environment, e.g. OTEL_RESOURCE_ATTRIBUTES=deployment.environment=staging
+		resource.WithFromEnv(),
+	)
+	if err != nil {
+		return nil, err
+	}
+
+	// merging the detected resource into the base one, the attributes of the second resource win on conflict
+	return resource.Merge(base, detected)
+}
+
+// k8sAttributes returns the Kubernetes attributes exposed to the pod as environment variables by the downward API,
+// e.g.
+//
+//	env:
+//	  - name: K8S_POD_NAME
+//	    valueFrom:
+//	      fieldRef:
+//	        fieldPath: metadata.name
+func k8sAttributes() []attribute.KeyValue {
+	var attrs []attribute.KeyValue
+	for key, env := range map[attribute.Key]string{
+		semconv.K8SPodNameKey:       "K8S_POD_NAME",
+		semconv.K8SNamespaceNameKey: "K8S_NAMESPACE_NAME",
+		semconv.K8SNodeNameKey:      "K8S_NODE_NAME",
+	} {
+		if v := os.Getenv(env); v != "" {
+			attrs = append(attrs, key.String(v))
+		}
+	}
+	return attrs
+}
@@ -0,0 +1,46 @@
+package main
+
+import (
+	"log"
+
+	"github.com/legosandorigami/opentelemetry-tutorial/go/lib/config"
+	amqp "github.com/rabbitmq/amqp091-go"
+)
+
+func main() {
+	// loading the service addresses from the command-line flags and environment variables
+	cfg := config.Load()
+
+	// connecting to RabbitMQ, declaring the greetings queue, and consuming it
+	conn, err := amqp.Dial(cfg.AMQPURL)
+	if err != nil {
+		log.Fatalf("failed to connect to RabbitMQ: %v", err)
+	}
+	defer conn.Close()
+
+	ch, err := conn.Channel()
+	if err != nil {
+		log.Fatalf("failed to open a channel: %v", err)
+	}
+	defer ch.Close()
+
+	if _, err := ch.QueueDeclare(cfg.AMQPQueue, true, false, false, false, nil); err != nil {
+		log.Fatalf("failed to declare the queue: %v", err)
+	}
+
+	deliveries, err := ch.Consume(cfg.AMQPQueue, "", false, false, false, false, nil)
+	if err != nil {
+		log.Fatalf("failed to consume the queue: %v", err)
+	}
+
+	for d := range deliveries {
+		println(string(d.Body))
+
+		// acknowledging the message once processed, RabbitMQ redelivers it otherwise
+		if err := d.Ack(false); err != nil {
+			log.Printf("failed to acknowledge the message: %v", err)
+		}
+	}
+
+	log.Printf("the channel was closed")
+}
@@ -0,0 +1,92 @@
+package main
+
+import (
+	"context"
+	"log"
+
+	"github.com/legosandorigami/opentelemetry-tutorial/go/lib/config"
+	"github.com/legosandorigami/opentelemetry-tutorial/go/lib/messaging"
+	"github.com/legosandorigami/opentelemetry-tutorial/go/lib/tracing"
+	amqp "github.com/rabbitmq/amqp091-go"
+	"go.opentelemetry.io/otel"
+	semconv "go.opentelemetry.io/otel/semconv/v1.4.0"
+	"go.opentelemetry.io/otel/trace"
+)
+
+func main() {
+	// loading the service addresses from the command-line flags and environment variables
+	cfg := config.Load()
+
+	// initialize the OpenTelemetry TracerProvider with the service name "consumer"
+	tracerPovider, err := tracing.InitTracerProviderWithBackend("consumer", cfg.OTLPEndpoint, tracing.WithPropagation(cfg.Propagation))
+	if err != nil {
+		log.Fatalf("failed to create otel exporter: %v", err)
+	}
+
+	// creating a context and defering the shutdown of the TracerProvider to ensure proper cleanup
+	ctx := context.Background()
+	defer func() {
+		if err := tracerPovider.Shutdown(ctx); err != nil {
+			log.Fatalf("failed to shutdown TracerProvider: %v", err)
+		}
+	}()
+
+	// retrieving or creating a tracer with name "consumer-tracer"
+	tracer := tracerPovider.Tracer("consumer-tracer")
+
+	// connecting to RabbitMQ, declaring the greetings queue, and consuming it
+	conn, err := amqp.Dial(cfg.AMQPURL)
+	if err != nil {
+		log.Fatalf("failed to connect to RabbitMQ: %v", err)
+	}
+	defer conn.Close()
+
+	ch, err := conn.Channel()
+	if err != nil {
+		log.Fatalf("failed to open a channel: %v", err)
+	}
+	defer ch.Close()
+
+	if _, err := ch.QueueDeclare(cfg.AMQPQueue, true, false, false, false, nil); err != nil {
+		log.Fatalf("failed to declare the queue: %v", err)
+	}
+
+	deliveries, err := ch.Consume(cfg.AMQPQueue, "", false, false, false, false, nil)
+	if err != nil {
+		log.Fatalf("failed to consume the queue: %v", err)
+	}
+
+	for d := range deliveries {
+		// extracting the span context injected by the publisher from the message headers
+		msgCtx := otel.GetTextMapPropagator().Extract(ctx, messaging.NewAMQPHeaderCarrier(&d.Headers))
+
+		// starting a span of kind consumer continuing the trace of the request that produced the message
+		_, span := tracer.Start(msgCtx, cfg.AMQPQueue+" process",
+			trace.WithAttributes(
+				semconv.MessagingSystemKey.String("rabbitmq"),
+				semconv.MessagingProtocolKey.String("AMQP"),
+				semconv.MessagingProtocolVersionKey.String("0.9.1"),
+				semconv.MessagingDestinationKey.String(cfg.AMQPQueue),
+				semconv.MessagingDestinationKindQueue,
+				semconv.MessagingRabbitmqRoutingKeyKey.String(d.RoutingKey),
+				semconv.MessagingOperationProcess,
+				semconv.MessagingMessagePayloadSizeBytesKey.Int(len(d.Body)),
+			),
+			trace.WithSpanKind(trace.SpanKindConsumer),
+		)
+
+		println(string(d.Body))
+
+		// acknowledging the message once processed, RabbitMQ redelivers it otherwise
+		if err := d.Ack(false); err != nil {
+			span.RecordError(err)
+		}
+
+		// printing the span details
+		tracing.PrintSpanContents(span)
+
+		span.End()
+	}
+
+	log.Printf("the channel was closed")
+}
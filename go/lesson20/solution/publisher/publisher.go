@@ -0,0 +1,116 @@
+package main
+
+import (
+	"context"
+	"log"
+	"net/http"
+
+	"github.com/legosandorigami/opentelemetry-tutorial/go/lib/config"
+	"github.com/legosandorigami/opentelemetry-tutorial/go/lib/messaging"
+	"github.com/legosandorigami/opentelemetry-tutorial/go/lib/tracing"
+	amqp "github.com/rabbitmq/amqp091-go"
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/propagation"
+	semconv "go.opentelemetry.io/otel/semconv/v1.4.0"
+	"go.opentelemetry.io/otel/trace"
+)
+
+func main() {
+	// loading the service addresses from the command-line flags and environment variables
+	cfg := config.Load()
+
+	// initialize the OpenTelemetry TracerProvider with the service name "publisher"
+	tracerPovider, err := tracing.InitTracerProviderWithBackend("publisher", cfg.OTLPEndpoint, tracing.WithPropagation(cfg.Propagation))
+	if err != nil {
+		log.Fatalf("failed to create otel exporter: %v", err)
+	}
+
+	// creating a context and defering the shutdown of the TracerProvider to ensure proper cleanup
+	ctx := context.Background()
+	defer func() {
+		if err := tracerPovider.Shutdown(ctx); err != nil {
+			log.Fatalf("failed to shutdown TracerProvider: %v", err)
+		}
+	}()
+
+	// retrieving or creating a tracer with name "publisher-tracer"
+	tracer := tracerPovider.Tracer("publisher-tracer")
+
+	// connecting to RabbitMQ, and declaring the greetings queue
+	conn, err := amqp.Dial(cfg.AMQPURL)
+	if err != nil {
+		log.Fatalf("failed to connect to RabbitMQ: %v", err)
+	}
+	defer conn.Close()
+
+	ch, err := conn.Channel()
+	if err != nil {
+		log.Fatalf("failed to open a channel: %v", err)
+	}
+	defer ch.Close()
+
+	if _, err := ch.QueueDeclare(cfg.AMQPQueue, true, false, false, false, nil); err != nil {
+		log.Fatalf("failed to declare the queue: %v", err)
+	}
+
+	http.HandleFunc("/publish", func(w http.ResponseWriter, r *http.Request) {
+		// retrieving the global propagator and extracting the span context from the request headers
+		ctx := otel.GetTextMapPropagator().Extract(context.Background(), propagation.HeaderCarrier(r.Header))
+
+		// starting a new span named "publish" as a child of the extracted span context
+		ctx, span := tracer.Start(ctx, "publish", trace.WithSpanKind(trace.SpanKindServer))
+		defer span.End()
+
+		helloStr := r.FormValue("helloStr")
+
+		// handing the greeting over to RabbitMQ, the consumer prints it later
+		if err := produce(ctx, tracer, ch, cfg.AMQPQueue, helloStr); err != nil {
+			http.Error(w, err.Error(), http.StatusInternalServerError)
+			return
+		}
+
+		// printing the span details
+		tracing.PrintSpanContents(span)
+	})
+
+	log.Fatal(http.ListenAndServe(config.ListenAddr(cfg.PublisherAddr), nil))
+}
+
+// produce publishes the greeting to the queue through the default exchange inside a producer span, whose context is
+// injected into the message headers so that the consumer can continue the trace.
+func produce(ctx context.Context, tracer trace.Tracer, ch *amqp.Channel, queue, helloStr string) error {
+	// starting a span of kind producer, named after the destination and the operation as the semantic conventions ask.
+	// The default exchange routes the message to the queue named by the routing key
+	ctx, span := tracer.Start(ctx, queue+" send",
+		trace.WithAttributes(
+			semconv.MessagingSystemKey.String("rabbitmq"),
+			semconv.MessagingProtocolKey.String("AMQP"),
+			semconv.MessagingProtocolVersionKey.String("0.9.1"),
+			semconv.MessagingDestinationKey.String(queue),
+			semconv.MessagingDestinationKindQueue,
+			semconv.MessagingRabbitmqRoutingKeyKey.String(queue),
+			semconv.MessagingMessagePayloadSizeBytesKey.Int(len(helloStr)),
+		),
+		trace.WithSpanKind(trace.SpanKindProducer),
+	)
+	defer span.End()
+
+	msg := amqp.Publishing{
+		ContentType:  "text/plain",
+		DeliveryMode: amqp.Persistent,
+		Body:         []byte(helloStr),
+	}
+
+	// injecting the context of the producer span and the baggage into the message headers
+	otel.GetTextMapPropagator().Inject(ctx, messaging.NewAMQPHeaderCarrier(&msg.Headers))
+
+	if err := ch.PublishWithContext(ctx, "", queue, false, false, msg); err != nil {
+		// recording the error in the span and marking the span as failed
+		tracing.RecordFailure(span, err,
+			attribute.String("produce-error", "Failed to produce the greeting"))
+		return err
+	}
+
+	return nil
+}
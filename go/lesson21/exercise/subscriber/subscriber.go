@@ -0,0 +1,39 @@
+package main
+
+import (
+	"context"
+	"log"
+
+	"github.com/legosandorigami/opentelemetry-tutorial/go/lib/config"
+	"github.com/redis/go-redis/v9"
+)
+
+// CHANNEL is the Redis channel carrying the greetings
+const CHANNEL = "greetings"
+
+func main() {
+	// loading the service addresses from the command-line flags and environment variables
+	cfg := config.Load()
+	redisAddr := cfg.RedisAddr
+	if redisAddr == "" {
+		redisAddr = config.DEFAULT_REDIS_ADDR
+	}
+
+	ctx := context.Background()
+
+	// creating a Redis client, and subscribing to the greetings channel
+	rdb := redis.NewClient(&redis.Options{Addr: redisAddr})
+	defer rdb.Close()
+
+	pubsub := rdb.Subscribe(ctx, CHANNEL)
+	defer pubsub.Close()
+
+	// waiting for the confirmation of the subscription, which fails when Redis is unreachable
+	if _, err := pubsub.Receive(ctx); err != nil {
+		log.Fatalf("failed to subscribe: %v", err)
+	}
+
+	for msg := range pubsub.Channel() {
+		println(msg.Payload)
+	}
+}
@@ -0,0 +1,116 @@
+package main
+
+import (
+	"context"
+	"encoding/json"
+	"log"
+	"net/http"
+
+	"github.com/legosandorigami/opentelemetry-tutorial/go/lib/config"
+	"github.com/legosandorigami/opentelemetry-tutorial/go/lib/messaging"
+	"github.com/legosandorigami/opentelemetry-tutorial/go/lib/tracing"
+	"github.com/redis/go-redis/extra/redisotel/v9"
+	"github.com/redis/go-redis/v9"
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/propagation"
+	semconv "go.opentelemetry.io/otel/semconv/v1.4.0"
+	"go.opentelemetry.io/otel/trace"
+)
+
+// CHANNEL is the Redis channel carrying the greetings
+const CHANNEL = "greetings"
+
+func main() {
+	// loading the service addresses from the command-line flags and environment variables
+	cfg := config.Load()
+	redisAddr := cfg.RedisAddr
+	if redisAddr == "" {
+		redisAddr = config.DEFAULT_REDIS_ADDR
+	}
+
+	// initialize the OpenTelemetry TracerProvider with the service name "publisher"
+	tracerPovider, err := tracing.InitTracerProviderWithBackend("publisher", cfg.OTLPEndpoint, tracing.WithPropagation(cfg.Propagation))
+	if err != nil {
+		log.Fatalf("failed to create otel exporter: %v", err)
+	}
+
+	// creating a context and defering the shutdown of the TracerProvider to ensure proper cleanup
+	ctx := context.Background()
+	defer func() {
+		if err := tracerPovider.Shutdown(ctx); err != nil {
+			log.Fatalf("failed to shutdown TracerProvider: %v", err)
+		}
+	}()
+
+	// retrieving or creating a tracer with name "publisher-tracer"
+	tracer := tracerPovider.Tracer("publisher-tracer")
+
+	// creating a Redis client, instrumented so that every Redis command becomes a child span of the span in its context
+	rdb := redis.NewClient(&redis.Options{Addr: redisAddr})
+	defer rdb.Close()
+	if err := redisotel.InstrumentTracing(rdb); err != nil {
+		log.Fatalf("failed to instrument the redis client: %v", err)
+	}
+
+	http.HandleFunc("/publish", func(w http.ResponseWriter, r *http.Request) {
+		// retrieving the global propagator and extracting the span context from the request headers
+		ctx := otel.GetTextMapPropagator().Extract(context.Background(), propagation.HeaderCarrier(r.Header))
+
+		// starting a new span named "publish" as a child of the extracted span context
+		ctx, span := tracer.Start(ctx, "publish", trace.WithSpanKind(trace.SpanKindServer))
+		defer span.End()
+
+		helloStr := r.FormValue("helloStr")
+
+		// handing the greeting over to the Redis channel, the subscribers print it
+		if err := produce(ctx, tracer, rdb, helloStr); err != nil {
+			http.Error(w, err.Error(), http.StatusInternalServerError)
+			return
+		}
+
+		// printing the span details
+		tracing.PrintSpanContents(span)
+	})
+
+	log.Fatal(http.ListenAndServe(config.ListenAddr(cfg.PublisherAddr), nil))
+}
+
+// produce publishes the greeting to the Redis channel inside a producer span. Redis messages have no headers, so the
+// context of the span is injected into an envelope wrapping the greeting.
+func produce(ctx context.Context, tracer trace.Tracer, rdb *redis.Client, helloStr string) error {
+	// starting a span of kind producer, named after the destination and the operation as the semantic conventions ask
+	ctx, span := tracer.Start(ctx, CHANNEL+" send",
+		trace.WithAttributes(
+			semconv.MessagingSystemKey.String("redis"),
+			semconv.MessagingDestinationKey.String(CHANNEL),
+			semconv.MessagingDestinationKindTopic,
+			semconv.MessagingMessagePayloadSizeBytesKey.Int(len(helloStr)),
+		),
+		trace.WithSpanKind(trace.SpanKindProducer),
+	)
+	defer span.End()
+
+	// injecting the context of the producer span and the baggage into the envelope
+	envelope := messaging.Envelope{Body: helloStr}
+	otel.GetTextMapPropagator().Inject(ctx, envelope.Carrier())
+
+	payload, err := json.Marshal(envelope)
+	if err != nil {
+		return err
+	}
+
+	// publishing the envelope, the PUBLISH command is recorded in a child span by the instrumentation of the client
+	receivers, err := rdb.Publish(ctx, CHANNEL, payload).Result()
+	if err != nil {
+		// recording the error in the span and marking the span as failed
+		tracing.RecordFailure(span, err,
+			attribute.String("produce-error", "Failed to produce the greeting"))
+		return err
+	}
+
+	// recording how many subscribers received the greeting, Redis drops the messages nobody listens to
+	span.SetAttributes(attribute.Int64("messaging.redis.receivers", receivers))
+
+	return nil
+}
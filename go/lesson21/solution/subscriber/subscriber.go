@@ -0,0 +1,90 @@
+package main
+
+import (
+	"context"
+	"encoding/json"
+	"log"
+
+	"github.com/legosandorigami/opentelemetry-tutorial/go/lib/config"
+	"github.com/legosandorigami/opentelemetry-tutorial/go/lib/messaging"
+	"github.com/legosandorigami/opentelemetry-tutorial/go/lib/tracing"
+	"github.com/redis/go-redis/extra/redisotel/v9"
+	"github.com/redis/go-redis/v9"
+	"go.opentelemetry.io/otel"
+	semconv "go.opentelemetry.io/otel/semconv/v1.4.0"
+	"go.opentelemetry.io/otel/trace"
+)
+
+// CHANNEL is the Redis channel carrying the greetings
+const CHANNEL = "greetings"
+
+func main() {
+	// loading the service addresses from the command-line flags and environment variables
+	cfg := config.Load()
+	redisAddr := cfg.RedisAddr
+	if redisAddr == "" {
+		redisAddr = config.DEFAULT_REDIS_ADDR
+	}
+
+	// initialize the OpenTelemetry TracerProvider with the service name "subscriber"
+	tracerPovider, err := tracing.InitTracerProviderWithBackend("subscriber", cfg.OTLPEndpoint, tracing.WithPropagation(cfg.Propagation))
+	if err != nil {
+		log.Fatalf("failed to create otel exporter: %v", err)
+	}
+
+	// creating a context and defering the shutdown of the TracerProvider to ensure proper cleanup
+	ctx := context.Background()
+	defer func() {
+		if err := tracerPovider.Shutdown(ctx); err != nil {
+			log.Fatalf("failed to shutdown TracerProvider: %v", err)
+		}
+	}()
+
+	// retrieving or creating a tracer with name "subscriber-tracer"
+	tracer := tracerPovider.Tracer("subscriber-tracer")
+
+	// creating an instrumented Redis client, and subscribing to the greetings channel
+	rdb := redis.NewClient(&redis.Options{Addr: redisAddr})
+	defer rdb.Close()
+	if err := redisotel.InstrumentTracing(rdb); err != nil {
+		log.Fatalf("failed to instrument the redis client: %v", err)
+	}
+
+	pubsub := rdb.Subscribe(ctx, CHANNEL)
+	defer pubsub.Close()
+
+	// waiting for the confirmation of the subscription, which fails when Redis is unreachable
+	if _, err := pubsub.Receive(ctx); err != nil {
+		log.Fatalf("failed to subscribe: %v", err)
+	}
+
+	for msg := range pubsub.Channel() {
+		// decoding the envelope, a payload which is not an envelope is processed in a trace of its own
+		var envelope messaging.Envelope
+		if err := json.Unmarshal([]byte(msg.Payload), &envelope); err != nil {
+			envelope = messaging.Envelope{Body: msg.Payload}
+		}
+
+		// extracting the span context injected by the publisher from the envelope
+		msgCtx := otel.GetTextMapPropagator().Extract(ctx, envelope.Carrier())
+
+		// starting a span of kind consumer continuing the trace of the request that published the message
+		_, span := tracer.Start(msgCtx, msg.Channel+" process",
+			trace.WithAttributes(
+				semconv.MessagingSystemKey.String("redis"),
+				semconv.MessagingDestinationKey.String(msg.Channel),
+				semconv.MessagingDestinationKindTopic,
+				semconv.MessagingOperationProcess,
+				semconv.MessagingMessagePayloadSizeBytesKey.Int(len(envelope.Body)),
+			),
+			trace.WithSpanKind(trace.SpanKindConsumer),
+		)
+
+		println(envelope.Body)
+
+		// printing the span details
+		tracing.PrintSpanContents(span)
+
+		span.End()
+	}
+}
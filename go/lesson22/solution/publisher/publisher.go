@@ -0,0 +1,114 @@
+package main
+
+import (
+	"context"
+	"flag"
+	"log"
+	"net/http"
+	"time"
+
+	"github.com/legosandorigami/opentelemetry-tutorial/go/lib/config"
+	"github.com/legosandorigami/opentelemetry-tutorial/go/lib/tracing"
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/options"
+	"go.opentelemetry.io/contrib/instrumentation/go.mongodb.org/mongo-driver/mongo/otelmongo"
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/propagation"
+	"go.opentelemetry.io/otel/trace"
+)
+
+const (
+	// DATABASE is the MongoDB database storing the greetings
+	DATABASE = "tutorial"
+	// COLLECTION is the MongoDB collection storing the greetings
+	COLLECTION = "greetings"
+)
+
+func main() {
+	recordCommands := flag.Bool("record-commands", false, "record the full MongoDB commands, documents included, in the db.statement attribute")
+
+	// loading the service addresses from the command-line flags and environment variables
+	cfg := config.Load()
+
+	// initialize the OpenTelemetry TracerProvider with the service name "publisher"
+	tracerPovider, err := tracing.InitTracerProviderWithBackend("publisher", cfg.OTLPEndpoint, tracing.WithPropagation(cfg.Propagation))
+	if err != nil {
+		log.Fatalf("failed to create otel exporter: %v", err)
+	}
+
+	// creating a context and defering the shutdown of the TracerProvider to ensure proper cleanup
+	ctx := context.Background()
+	defer func() {
+		if err := tracerPovider.Shutdown(ctx); err != nil {
+			log.Fatalf("failed to shutdown TracerProvider: %v", err)
+		}
+	}()
+
+	// retrieving or creating a tracer with name "publisher-tracer"
+	tracer := tracerPovider.Tracer("publisher-tracer")
+
+	// creating the command monitor of otelmongo, which records every command sent to MongoDB as a client span.
+	// The commands, and the documents they carry, are left out of the spans unless asked for
+	monitor := otelmongo.NewMonitor(
+		otelmongo.WithTracerProvider(tracerPovider),
+		otelmongo.WithCommandAttributeDisabled(!*recordCommands),
+	)
+
+	// connecting to MongoDB with the monitor attached to the client
+	client, err := mongo.Connect(ctx, options.Client().ApplyURI(cfg.MongoURI).SetMonitor(monitor))
+	if err != nil {
+		log.Fatalf("failed to connect to MongoDB: %v", err)
+	}
+	defer client.Disconnect(ctx)
+
+	greetings := client.Database(DATABASE).Collection(COLLECTION)
+
+	http.HandleFunc("/publish", func(w http.ResponseWriter, r *http.Request) {
+		// retrieving the global propagator and extracting the span context from the request headers
+		ctx := otel.GetTextMapPropagator().Extract(context.Background(), propagation.HeaderCarrier(r.Header))
+
+		// starting a new span named "publish" as a child of the extracted span context
+		ctx, span := tracer.Start(ctx, "publish", trace.WithSpanKind(trace.SpanKindServer))
+		defer span.End()
+
+		helloStr := r.FormValue("helloStr")
+
+		// storing the greeting, the insert command is traced as a child span of the "publish" span
+		id, err := store(ctx, greetings, helloStr)
+		if err != nil {
+			// recording the error in the span and marking the span as failed
+			tracing.RecordFailure(span, err, attribute.String("store-error", "Failed to store the greeting"))
+			http.Error(w, err.Error(), http.StatusInternalServerError)
+			return
+		}
+
+		// recording the ID of the document rather than the document itself, enough to look it up when needed
+		span.SetAttributes(attribute.String("greeting.id", id))
+
+		println(helloStr)
+
+		// printing the span details
+		tracing.PrintSpanContents(span)
+	})
+
+	log.Fatal(http.ListenAndServe(config.ListenAddr(cfg.PublisherAddr), nil))
+}
+
+// store inserts the greeting into the collection and returns the ID of the new document. The context must carry the
+// span the insert command is a child of.
+func store(ctx context.Context, greetings *mongo.Collection, helloStr string) (string, error) {
+	res, err := greetings.InsertOne(ctx, bson.D{
+		{Key: "greeting", Value: helloStr},
+		{Key: "created_at", Value: time.Now()},
+	})
+	if err != nil {
+		return "", err
+	}
+
+	// the driver generates an ObjectID for the documents inserted without an _id
+	id, _ := res.InsertedID.(primitive.ObjectID)
+	return id.Hex(), nil
+}
@@ -0,0 +1,173 @@
+package main
+
+import (
+	"context"
+	"errors"
+	"flag"
+	"log"
+	"math/rand"
+	"net/http"
+	"sync"
+	"time"
+
+	"github.com/legosandorigami/opentelemetry-tutorial/go/lib/config"
+	"github.com/legosandorigami/opentelemetry-tutorial/go/lib/tracing"
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/propagation"
+	"go.opentelemetry.io/otel/trace"
+)
+
+// JOB_NAME is the name of the cleanup job, recorded on the span of every run
+const JOB_NAME = "cleanup-greetings"
+
+// greeting is a published greeting, along with the span context of the request that published it
+type greeting struct {
+	helloStr    string
+	publishedAt time.Time
+	spanCtx     trace.SpanContext
+}
+
+// store holds the published greetings, oldest first, until the cleanup job deletes them
+type store struct {
+	mu        sync.Mutex
+	greetings []greeting
+}
+
+func (s *store) add(g greeting) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.greetings = append(s.greetings, g)
+}
+
+// expired returns the greetings published before the cutoff
+func (s *store) expired(cutoff time.Time) []greeting {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	n := 0
+	for n < len(s.greetings) && s.greetings[n].publishedAt.Before(cutoff) {
+		n++
+	}
+	return append([]greeting(nil), s.greetings[:n]...)
+}
+
+// remove deletes the n oldest greetings and returns the number of greetings left
+func (s *store) remove(n int) int {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.greetings = s.greetings[n:]
+	return len(s.greetings)
+}
+
+func main() {
+	// registering the flags of the cleanup job
+	interval := flag.Duration("cleanup-interval", 10*time.Second, "time between two runs of the cleanup job")
+	retention := flag.Duration("retention", 30*time.Second, "time the published greetings are kept")
+
+	// loading the service addresses from the command-line flags and environment variables
+	cfg := config.Load()
+
+	// initialize the OpenTelemetry TracerProvider with the service name "publisher"
+	tracerPovider, err := tracing.InitTracerProviderWithBackend("publisher", cfg.OTLPEndpoint, tracing.WithPropagation(cfg.Propagation))
+	if err != nil {
+		log.Fatalf("failed to create otel exporter: %v", err)
+	}
+
+	// creating a context and defering the shutdown of the TracerProvider to ensure proper cleanup
+	ctx := context.Background()
+	defer func() {
+		if err := tracerPovider.Shutdown(ctx); err != nil {
+			log.Fatalf("failed to shutdown TracerProvider: %v", err)
+		}
+	}()
+
+	// retrieving or creating a tracer with name "publisher-tracer"
+	tracer := tracerPovider.Tracer("publisher-tracer")
+
+	s := &store{}
+
+	// cleanup deletes the expired greetings, failing a fraction of the runs on purpose
+	cleanup := func(ctx context.Context, trigger string) error {
+		return runCleanup(ctx, tracer, s, *retention, trigger, cfg.ChaosRate)
+	}
+
+	// running the cleanup job on schedule, inside a span covering all the runs
+	go func() {
+		ctx, span := tracer.Start(context.Background(), "cleanup-scheduler")
+		defer span.End()
+
+		ticker := time.NewTicker(*interval)
+		defer ticker.Stop()
+		for range ticker.C {
+			if err := cleanup(ctx, "schedule"); err != nil {
+				log.Printf("cleanup failed: %v", err)
+			}
+		}
+	}()
+
+	http.HandleFunc("/publish", func(w http.ResponseWriter, r *http.Request) {
+		// retrieving the global propagator and extracting the span context from the request headers
+		ctx := otel.GetTextMapPropagator().Extract(context.Background(), propagation.HeaderCarrier(r.Header))
+
+		// starting a new span named "publish" as a child of the extracted span context
+		_, span := tracer.Start(ctx, "publish", trace.WithSpanKind(trace.SpanKindServer))
+		defer span.End()
+
+		helloStr := r.FormValue("helloStr")
+		println(helloStr)
+
+		// storing the greeting with the context of the span
+		s.add(greeting{helloStr: helloStr, publishedAt: time.Now(), spanCtx: span.SpanContext()})
+
+		// printing the span details
+		tracing.PrintSpanContents(span)
+	})
+
+	// registering the endpoint running the cleanup job on demand
+	http.HandleFunc("/cleanup", func(w http.ResponseWriter, r *http.Request) {
+		// retrieving the global propagator and extracting the span context from the request headers
+		ctx := otel.GetTextMapPropagator().Extract(context.Background(), propagation.HeaderCarrier(r.Header))
+
+		// starting a new span named "cleanup" as a child of the extracted span context
+		ctx, span := tracer.Start(ctx, "cleanup", trace.WithSpanKind(trace.SpanKindServer))
+		defer span.End()
+
+		// running the job with the context of the request
+		if err := cleanup(ctx, "manual"); err != nil {
+			http.Error(w, err.Error(), http.StatusInternalServerError)
+		}
+	})
+
+	log.Fatal(http.ListenAndServe(config.ListenAddr(cfg.PublisherAddr), nil))
+}
+
+// runCleanup deletes the greetings published more than retention ago inside a span, child of the span in ctx.
+func runCleanup(ctx context.Context, tracer trace.Tracer, s *store, retention time.Duration, trigger string, failureRate float64) error {
+	expired := s.expired(time.Now().Add(-retention))
+
+	// starting a span for the run
+	_, span := tracer.Start(ctx, JOB_NAME,
+		trace.WithAttributes(
+			attribute.String("job.name", JOB_NAME),
+			attribute.String("job.trigger", trigger),
+			attribute.String("cleanup.retention", retention.String()),
+		),
+	)
+	defer span.End()
+
+	// failing the run on purpose, as a real job would when the store is unavailable
+	if rand.Float64() < failureRate {
+		return errors.New("store unavailable")
+	}
+
+	left := s.remove(len(expired))
+	span.SetAttributes(
+		attribute.Int("cleanup.deleted", len(expired)),
+		attribute.Int("cleanup.remaining", left),
+	)
+
+	// printing the span details
+	tracing.PrintSpanContents(span)
+
+	return nil
+}
@@ -0,0 +1,200 @@
+package main
+
+import (
+	"context"
+	"errors"
+	"flag"
+	"fmt"
+	"log"
+	"math/rand"
+	"net/http"
+	"sync"
+	"time"
+
+	"github.com/legosandorigami/opentelemetry-tutorial/go/lib/config"
+	"github.com/legosandorigami/opentelemetry-tutorial/go/lib/tracing"
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/codes"
+	"go.opentelemetry.io/otel/propagation"
+	"go.opentelemetry.io/otel/trace"
+)
+
+// JOB_NAME is the name of the cleanup job, recorded on the span of every run
+const JOB_NAME = "cleanup-greetings"
+
+// greeting is a published greeting, along with the span context of the request that published it
+type greeting struct {
+	helloStr    string
+	publishedAt time.Time
+	spanCtx     trace.SpanContext
+}
+
+// store holds the published greetings, oldest first, until the cleanup job deletes them
+type store struct {
+	mu        sync.Mutex
+	greetings []greeting
+}
+
+func (s *store) add(g greeting) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.greetings = append(s.greetings, g)
+}
+
+// expired returns the greetings published before the cutoff
+func (s *store) expired(cutoff time.Time) []greeting {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	n := 0
+	for n < len(s.greetings) && s.greetings[n].publishedAt.Before(cutoff) {
+		n++
+	}
+	return append([]greeting(nil), s.greetings[:n]...)
+}
+
+// remove deletes the n oldest greetings and returns the number of greetings left
+func (s *store) remove(n int) int {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.greetings = s.greetings[n:]
+	return len(s.greetings)
+}
+
+func main() {
+	// registering the flags of the cleanup job
+	interval := flag.Duration("cleanup-interval", 10*time.Second, "time between two runs of the cleanup job")
+	retention := flag.Duration("retention", 30*time.Second, "time the published greetings are kept")
+
+	// loading the service addresses from the command-line flags and environment variables
+	cfg := config.Load()
+
+	// initialize the OpenTelemetry TracerProvider with the service name "publisher"
+	tracerPovider, err := tracing.InitTracerProviderWithBackend("publisher", cfg.OTLPEndpoint, tracing.WithPropagation(cfg.Propagation))
+	if err != nil {
+		log.Fatalf("failed to create otel exporter: %v", err)
+	}
+
+	// creating a context and defering the shutdown of the TracerProvider to ensure proper cleanup
+	ctx := context.Background()
+	defer func() {
+		if err := tracerPovider.Shutdown(ctx); err != nil {
+			log.Fatalf("failed to shutdown TracerProvider: %v", err)
+		}
+	}()
+
+	// retrieving or creating a tracer with name "publisher-tracer"
+	tracer := tracerPovider.Tracer("publisher-tracer")
+
+	s := &store{}
+
+	// cleanup deletes the expired greetings in a trace of its own, failing a fraction of the runs on purpose
+	cleanup := func(ctx context.Context, trigger string) error {
+		return runCleanup(ctx, tracer, s, *retention, trigger, cfg.ChaosRate)
+	}
+
+	// running the cleanup job on schedule, every run starting from an empty context
+	go func() {
+		ticker := time.NewTicker(*interval)
+		defer ticker.Stop()
+		for range ticker.C {
+			if err := cleanup(context.Background(), "schedule"); err != nil {
+				log.Printf("cleanup failed: %v", err)
+			}
+		}
+	}()
+
+	http.HandleFunc("/publish", func(w http.ResponseWriter, r *http.Request) {
+		// retrieving the global propagator and extracting the span context from the request headers
+		ctx := otel.GetTextMapPropagator().Extract(context.Background(), propagation.HeaderCarrier(r.Header))
+
+		// starting a new span named "publish" as a child of the extracted span context
+		_, span := tracer.Start(ctx, "publish", trace.WithSpanKind(trace.SpanKindServer))
+		defer span.End()
+
+		helloStr := r.FormValue("helloStr")
+		println(helloStr)
+
+		// storing the greeting with the context of the span, the cleanup job links to it when deleting the greeting
+		s.add(greeting{helloStr: helloStr, publishedAt: time.Now(), spanCtx: span.SpanContext()})
+
+		// printing the span details
+		tracing.PrintSpanContents(span)
+	})
+
+	// registering the endpoint running the cleanup job on demand
+	http.HandleFunc("/cleanup", func(w http.ResponseWriter, r *http.Request) {
+		// retrieving the global propagator and extracting the span context from the request headers
+		ctx := otel.GetTextMapPropagator().Extract(context.Background(), propagation.HeaderCarrier(r.Header))
+
+		// starting a new span named "cleanup" as a child of the extracted span context
+		ctx, span := tracer.Start(ctx, "cleanup", trace.WithSpanKind(trace.SpanKindServer))
+		defer span.End()
+
+		// running the job with the context of the request, the run still starts a trace of its own
+		if err := cleanup(ctx, "manual"); err != nil {
+			span.SetStatus(codes.Error, err.Error())
+			http.Error(w, err.Error(), http.StatusInternalServerError)
+		}
+	})
+
+	log.Fatal(http.ListenAndServe(config.ListenAddr(cfg.PublisherAddr), nil))
+}
+
+// runCleanup deletes the greetings published more than retention ago. Every run is a trace of its own, whose root span
+// links to the span of the request that published each deleted greeting, and to the span in ctx if any, which
+// triggered the run. The outcome of the run is recorded as the status of its span.
+func runCleanup(ctx context.Context, tracer trace.Tracer, s *store, retention time.Duration, trigger string, failureRate float64) error {
+	expired := s.expired(time.Now().Add(-retention))
+
+	// creating a link to the span that triggered the run, when run on demand
+	var links []trace.Link
+	if sc := trace.SpanContextFromContext(ctx); sc.IsValid() {
+		links = append(links, trace.Link{
+			SpanContext: sc,
+			Attributes:  []attribute.KeyValue{attribute.String("link.reason", "triggered")},
+		})
+	}
+
+	// creating a link to the span of each request that published an expired greeting
+	for _, g := range expired {
+		links = append(links, trace.Link{
+			SpanContext: g.spanCtx,
+			Attributes:  []attribute.KeyValue{attribute.String("link.reason", "deleted")},
+		})
+	}
+
+	// starting a new root span for the run, even when ctx holds the span of the request that triggered it
+	_, span := tracer.Start(ctx, JOB_NAME,
+		trace.WithNewRoot(),
+		trace.WithLinks(links...),
+		trace.WithAttributes(
+			attribute.String("job.name", JOB_NAME),
+			attribute.String("job.trigger", trigger),
+			attribute.String("cleanup.retention", retention.String()),
+		),
+	)
+	defer span.End()
+
+	// failing the run on purpose, as a real job would when the store is unavailable
+	if rand.Float64() < failureRate {
+		err := errors.New("store unavailable")
+		span.RecordError(err)
+		span.SetStatus(codes.Error, fmt.Sprintf("cleanup failed: %v", err))
+		return err
+	}
+
+	left := s.remove(len(expired))
+	span.SetAttributes(
+		attribute.Int("cleanup.deleted", len(expired)),
+		attribute.Int("cleanup.remaining", left),
+	)
+
+	// marking the run as successful, which an empty run is too
+	span.SetStatus(codes.Ok, "")
+
+	// printing the span details
+	tracing.PrintSpanContents(span)
+
+	return nil
+}
@@ -0,0 +1,112 @@
+//go:build integration
+
+package collector
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"testing"
+	"time"
+
+	"github.com/legosandorigami/opentelemetry-tutorial/go/lib/config"
+	"github.com/legosandorigami/opentelemetry-tutorial/go/lib/tracing"
+	"go.opentelemetry.io/otel/codes"
+	"go.opentelemetry.io/otel/trace"
+)
+
+// DECISION_TIMEOUT is how long the test waits for the collector to make its decisions, longer than the decision_wait
+// of the tail_sampling processor and the timeout of the batch processor together
+const DECISION_TIMEOUT = 30 * time.Second
+
+// TestTailSampling sends a fast, a slow and a failed trace to the collector started with the configuration of the
+// solution, and checks that Jaeger only received the slow and the failed ones. Run it with the collector and Jaeger of
+// docker-compose.yaml up:
+//
+//	go test -tags integration ./lesson24/solution/collector
+func TestTailSampling(t *testing.T) {
+	endpoint := config.Getenv("OTLP_ENDPOINT", config.DEFAULT_OTLP_ENDPOINT)
+	jaegerURL := config.Getenv("TRACE_UI_URL", "http://localhost:16686")
+
+	tracerPovider, err := tracing.InitTracerProviderWithBackend("tail-sampling-test", endpoint)
+	if err != nil {
+		t.Fatalf("failed to create otel exporter: %v", err)
+	}
+	tracer := tracerPovider.Tracer("tail-sampling-test")
+
+	// each trace is a single span, whose duration is set through its timestamps rather than waited for
+	start := time.Now()
+	tests := []struct {
+		name     string
+		duration time.Duration
+		failed   bool
+		kept     bool
+	}{
+		{name: "fast", duration: 10 * time.Millisecond, kept: false},
+		{name: "slow", duration: 500 * time.Millisecond, kept: true},
+		{name: "failed", duration: 10 * time.Millisecond, failed: true, kept: true},
+	}
+
+	traceIDs := make(map[string]trace.TraceID)
+	for _, tt := range tests {
+		_, span := tracer.Start(context.Background(), tt.name, trace.WithTimestamp(start))
+		if tt.failed {
+			span.SetStatus(codes.Error, "failed on purpose")
+		}
+		span.End(trace.WithTimestamp(start.Add(tt.duration)))
+		traceIDs[tt.name] = span.SpanContext().TraceID()
+	}
+
+	// sending the spans to the collector
+	if err := tracerPovider.Shutdown(context.Background()); err != nil {
+		t.Fatalf("failed to shutdown TracerProvider: %v", err)
+	}
+
+	// waiting for the kept traces to reach Jaeger, the decisions about all the traces are made together
+	for _, tt := range tests {
+		if !tt.kept {
+			continue
+		}
+		t.Run(tt.name, func(t *testing.T) {
+			deadline := time.Now().Add(DECISION_TIMEOUT)
+			for !found(t, jaegerURL, traceIDs[tt.name]) {
+				if time.Now().After(deadline) {
+					t.Fatalf("trace %s was not kept", traceIDs[tt.name])
+				}
+				time.Sleep(time.Second)
+			}
+		})
+	}
+
+	for _, tt := range tests {
+		if tt.kept {
+			continue
+		}
+		t.Run(tt.name, func(t *testing.T) {
+			if found(t, jaegerURL, traceIDs[tt.name]) {
+				t.Errorf("trace %s was kept", traceIDs[tt.name])
+			}
+		})
+	}
+}
+
+// found reports whether Jaeger stores the trace, looking it up with the HTTP API of its UI
+func found(t *testing.T, jaegerURL string, traceID trace.TraceID) bool {
+	t.Helper()
+
+	resp, err := http.Get(fmt.Sprintf("%s/api/traces/%s", jaegerURL, traceID))
+	if err != nil {
+		t.Fatalf("failed to query Jaeger: %v", err)
+	}
+	defer resp.Body.Close()
+
+	switch resp.StatusCode {
+	case http.StatusOK:
+		return true
+	case http.StatusNotFound:
+		return false
+	default:
+		t.Fatalf("unexpected status from Jaeger: %s", resp.Status)
+		return false
+	}
+}
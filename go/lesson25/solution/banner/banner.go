@@ -0,0 +1,55 @@
+package main
+
+import (
+	"context"
+	"flag"
+	"fmt"
+	"log"
+	"strings"
+	"unicode/utf8"
+
+	"github.com/legosandorigami/opentelemetry-tutorial/go/lib/config"
+	"github.com/legosandorigami/opentelemetry-tutorial/go/lib/tracing"
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/attribute"
+)
+
+func main() {
+	// loading the OTLP endpoint from the command-line flags and environment variables
+	cfg := config.Load()
+
+	// checking if the number of positional arguments is exactly 1
+	if flag.NArg() != 1 {
+		log.Fatal("ERROR: Expecting one argument")
+	}
+
+	// initializing the OpenTelemetry TracerProvider with the service name "banner"
+	tracerPovider, err := tracing.InitTracerProviderWithBackend("banner", cfg.OTLPEndpoint, tracing.WithPropagation(cfg.Propagation))
+	if err != nil {
+		log.Fatalf("failed to create otel exporter: %v", err)
+	}
+
+	// defering the shutdown of the TracerProvider, which exports the spans before the short-lived process exits
+	defer func() {
+		if err := tracerPovider.Shutdown(context.Background()); err != nil {
+			log.Fatalf("failed to shutdown TracerProvider: %v", err)
+		}
+	}()
+
+	// extracting the span context and the baggage handed over by the parent process in the environment variables
+	ctx := otel.GetTextMapPropagator().Extract(context.Background(), tracing.NewEnvCarrier())
+
+	// starting a new span named "render" as a child of the extracted span context
+	_, span := tracerPovider.Tracer("banner-tracer").Start(ctx, "render")
+	defer span.End()
+
+	helloStr := flag.Arg(0)
+	span.SetAttributes(attribute.Int("banner.width", utf8.RuneCountInString(helloStr)+4))
+
+	// rendering the greeting in a box on the standard output, read by the parent process
+	line := strings.Repeat("*", utf8.RuneCountInString(helloStr)+4)
+	fmt.Printf("%s\n* %s *\n%s\n", line, helloStr, line)
+
+	// printing the span details on the standard error, which the parent process forwards
+	tracing.PrintSpanContents(span)
+}
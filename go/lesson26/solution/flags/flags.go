@@ -0,0 +1,42 @@
+package flags
+
+import (
+	"context"
+
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/baggage"
+	"go.opentelemetry.io/otel/trace"
+)
+
+const (
+	// FANCY_GREETING is the feature flag switching the formatter to the fancy greetings
+	FANCY_GREETING = "feature.fancy-greeting"
+
+	// ON and OFF are the only variants of the flags, any other value in the baggage is ignored
+	ON  = "on"
+	OFF = "off"
+
+	// PROVIDER_NAME is the name of the source of the flags, recorded with every evaluation
+	PROVIDER_NAME = "baggage"
+)
+
+// Enabled evaluates the feature flag key from the baggage of ctx, and records the evaluation in the span of ctx: as an
+// attribute named after the flag, to search the traces by variant, and as a "feature_flag" event following the
+// semantic conventions. The flag is off unless the baggage holds the key with the value "on": the baggage is set by the
+// callers, so a missing or unexpected value must fall back to the default behavior.
+func Enabled(ctx context.Context, key string) bool {
+	variant := OFF
+	if baggage.FromContext(ctx).Member(key).Value() == ON {
+		variant = ON
+	}
+
+	span := trace.SpanFromContext(ctx)
+	span.SetAttributes(attribute.String(key, variant))
+	span.AddEvent("feature_flag", trace.WithAttributes(
+		attribute.String("feature_flag.key", key),
+		attribute.String("feature_flag.variant", variant),
+		attribute.String("feature_flag.provider_name", PROVIDER_NAME),
+	))
+
+	return variant == ON
+}
@@ -0,0 +1,89 @@
+package main
+
+import (
+	"context"
+	"fmt"
+	"log"
+	"net"
+	"net/http"
+
+	"github.com/legosandorigami/opentelemetry-tutorial/go/lib/config"
+	"github.com/legosandorigami/opentelemetry-tutorial/go/lib/tracing"
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/propagation"
+	semconv "go.opentelemetry.io/otel/semconv/v1.4.0"
+	"go.opentelemetry.io/otel/trace"
+)
+
+func main() {
+	// loading the service addresses from the command-line flags and environment variables
+	cfg := config.Load()
+
+	// creating the sampler deciding which spans of the application are recorded
+	sampler, err := tracing.ParseSampler(cfg.Sampler)
+	if err != nil {
+		log.Fatal(err)
+	}
+
+	// initialize the OpenTelemetry TracerProvider of the application traces with the service name "formatter"
+	appProvider, err := tracing.InitTracerProviderWithSampler("formatter", cfg.OTLPEndpoint, sampler, tracing.WithPropagation(cfg.Propagation))
+	if err != nil {
+		log.Fatalf("failed to create otel exporter: %v", err)
+	}
+
+	// initialize the OpenTelemetry TracerProvider of the audit trail with the service name "formatter-audit"
+	// the exercise: give the audit trail a pipeline of its own, and stop relying on the global TracerProvider
+	auditProvider, err := tracing.InitTracerProviderWithBackend("formatter-audit", cfg.OTLPEndpoint, tracing.WithPropagation(cfg.Propagation))
+	if err != nil {
+		log.Fatalf("failed to create the audit exporter: %v", err)
+	}
+
+	// creating a context and defering the shutdown of both providers to ensure proper cleanup
+	ctx := context.Background()
+	defer func() {
+		if err := auditProvider.Shutdown(ctx); err != nil {
+			log.Fatalf("failed to shutdown the audit TracerProvider: %v", err)
+		}
+		if err := appProvider.Shutdown(ctx); err != nil {
+			log.Fatalf("failed to shutdown TracerProvider: %v", err)
+		}
+	}()
+
+	http.Handle("/format", formatHandler())
+
+	log.Fatal(http.ListenAndServe(config.ListenAddr(cfg.FormatterAddr), nil))
+}
+
+// formatHandler formats the greetings, tracing the requests and recording every greeting issued in the audit trail.
+func formatHandler() http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		// retrieving the global propagator and extracting the span context from the request headers
+		ctx := otel.GetTextMapPropagator().Extract(context.Background(), propagation.HeaderCarrier(r.Header))
+
+		// starting a new span named "format" as a child of the extracted span context
+		ctx, span := otel.Tracer("formatter-tracer").Start(ctx, "format", trace.WithSpanKind(trace.SpanKindServer))
+		defer span.End()
+
+		helloTo := r.FormValue("helloTo")
+		helloStr := fmt.Sprintf("Hello, %s!", helloTo)
+
+		// adding an event to the span indicating that the string was properly formatted
+		span.AddEvent("event", trace.WithAttributes(
+			attribute.String("string-format", helloStr),
+		))
+
+		// recording the greeting in the audit trail
+		clientIP, _, _ := net.SplitHostPort(r.RemoteAddr)
+		_, auditSpan := otel.Tracer("formatter-audit").Start(ctx, "greeting.issued", trace.WithAttributes(
+			attribute.String("hello-to", helloTo),
+			semconv.HTTPClientIPKey.String(clientIP),
+		))
+		auditSpan.End()
+
+		// printing the span details
+		tracing.PrintSpanContents(span)
+
+		w.Write([]byte(helloStr))
+	}
+}
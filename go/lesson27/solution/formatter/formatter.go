@@ -0,0 +1,139 @@
+package main
+
+import (
+	"context"
+	"flag"
+	"fmt"
+	"io"
+	"log"
+	"net"
+	"net/http"
+	"os"
+
+	"github.com/legosandorigami/opentelemetry-tutorial/go/lib/config"
+	"github.com/legosandorigami/opentelemetry-tutorial/go/lib/tracing"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/exporters/stdout/stdouttrace"
+	"go.opentelemetry.io/otel/propagation"
+	traceSdk "go.opentelemetry.io/otel/sdk/trace"
+	semconv "go.opentelemetry.io/otel/semconv/v1.4.0"
+	"go.opentelemetry.io/otel/trace"
+)
+
+func main() {
+	// registering the flag of the file the audit trail is written to
+	auditFile := flag.String("audit-file", "", "file the audit spans are appended to, as JSON, empty for the standard output")
+
+	// loading the service addresses from the command-line flags and environment variables
+	cfg := config.Load()
+
+	// creating the sampler deciding which spans of the application are recorded
+	sampler, err := tracing.ParseSampler(cfg.Sampler)
+	if err != nil {
+		log.Fatal(err)
+	}
+
+	// creating the TracerProvider of the application traces, exported to the tracing backend. Unlike
+	// InitTracerProviderWithSampler, NewTracerProvider does not make it the global TracerProvider
+	appRes, err := tracing.NewResource("formatter")
+	if err != nil {
+		log.Fatalf("failed to create the resource: %v", err)
+	}
+	appProvider, err := tracing.NewTracerProvider(appRes, cfg.OTLPEndpoint, sampler)
+	if err != nil {
+		log.Fatalf("failed to create otel exporter: %v", err)
+	}
+
+	// creating the TracerProvider of the audit trail, with a pipeline of its own
+	auditProvider, err := newAuditProvider(*auditFile)
+	if err != nil {
+		log.Fatalf("failed to create the audit exporter: %v", err)
+	}
+
+	// creating a context and defering the shutdown of both providers to ensure proper cleanup
+	ctx := context.Background()
+	defer func() {
+		if err := auditProvider.Shutdown(ctx); err != nil {
+			log.Fatalf("failed to shutdown the audit TracerProvider: %v", err)
+		}
+		if err := appProvider.Shutdown(ctx); err != nil {
+			log.Fatalf("failed to shutdown TracerProvider: %v", err)
+		}
+	}()
+
+	// retrieving a tracer from each provider, the handler is given both instead of looking them up globally
+	tracer := appProvider.Tracer("formatter-tracer")
+	auditTracer := auditProvider.Tracer("formatter-audit")
+
+	// creating the propagator extracting the span context and the baggage, rather than relying on the global one
+	propagator := propagation.NewCompositeTextMapPropagator(propagation.TraceContext{}, propagation.Baggage{})
+
+	http.Handle("/format", formatHandler(tracer, auditTracer, propagator))
+
+	log.Fatal(http.ListenAndServe(config.ListenAddr(cfg.FormatterAddr), nil))
+}
+
+// newAuditProvider creates the TracerProvider of the audit trail, appending every span to path as JSON, or writing it to
+// the standard output. The audit trail must be complete, so it records every span whatever the sampling decision of
+// the application, and exports each span as soon as it ends.
+func newAuditProvider(path string) (*traceSdk.TracerProvider, error) {
+	var w io.Writer = os.Stdout
+	if path != "" {
+		f, err := os.OpenFile(path, os.O_CREATE|os.O_APPEND|os.O_WRONLY, 0o600)
+		if err != nil {
+			return nil, err
+		}
+		w = f
+	}
+
+	exporter, err := stdouttrace.New(stdouttrace.WithWriter(w))
+	if err != nil {
+		return nil, err
+	}
+
+	res, err := tracing.NewResource("formatter-audit")
+	if err != nil {
+		return nil, err
+	}
+
+	return traceSdk.NewTracerProvider(
+		traceSdk.WithSyncer(exporter),
+		traceSdk.WithResource(res),
+		traceSdk.WithSampler(traceSdk.AlwaysSample()),
+	), nil
+}
+
+// formatHandler formats the greetings, tracing the requests with tracer and recording every greeting issued with
+// auditTracer.
+func formatHandler(tracer, auditTracer trace.Tracer, propagator propagation.TextMapPropagator) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		// extracting the span context from the request headers
+		ctx := propagator.Extract(context.Background(), propagation.HeaderCarrier(r.Header))
+
+		// starting a new span named "format" as a child of the extracted span context
+		ctx, span := tracer.Start(ctx, "format", trace.WithSpanKind(trace.SpanKindServer))
+		defer span.End()
+
+		helloTo := r.FormValue("helloTo")
+		helloStr := fmt.Sprintf("Hello, %s!", helloTo)
+
+		// adding an event to the span indicating that the string was properly formatted
+		span.AddEvent("event", trace.WithAttributes(
+			attribute.String("string-format", helloStr),
+		))
+
+		// recording the greeting in the audit trail, in the same trace as the "format" span so that both can be joined
+		// on the trace ID, but through the pipeline of the audit provider
+		clientIP, _, _ := net.SplitHostPort(r.RemoteAddr)
+		_, auditSpan := auditTracer.Start(ctx, "greeting.issued", trace.WithAttributes(
+			attribute.String("hello-to", helloTo),
+			semconv.HTTPClientIPKey.String(clientIP),
+		))
+		auditSpan.End()
+
+		// printing the span details
+		tracing.PrintSpanContents(span)
+
+		w.Write([]byte(helloStr))
+	}
+}
@@ -0,0 +1,54 @@
+// Package archive keeps the published greetings. It stands for a legacy dependency instrumented with OpenCensus, which
+// the services use as is: it is the same in the exercise and in the solution.
+package archive
+
+import (
+	"context"
+	"sync"
+	"time"
+
+	octrace "go.opencensus.io/trace"
+)
+
+// COMPRESSION_LATENCY is the time the archive pretends to spend compressing a greeting
+const COMPRESSION_LATENCY = 5 * time.Millisecond
+
+// Archive is an in-memory archive of greetings, safe for concurrent use.
+type Archive struct {
+	mu        sync.Mutex
+	greetings []string
+}
+
+// New returns an empty archive.
+func New() *Archive {
+	return &Archive{}
+}
+
+// Save archives the greeting. The work is traced with the OpenCensus API, as a child of the span in ctx.
+func (a *Archive) Save(ctx context.Context, greeting string) error {
+	ctx, span := octrace.StartSpan(ctx, "archive.Save")
+	defer span.End()
+
+	compressed := a.compress(ctx, greeting)
+
+	a.mu.Lock()
+	a.greetings = append(a.greetings, compressed)
+	size := len(a.greetings)
+	a.mu.Unlock()
+
+	span.AddAttributes(octrace.Int64Attribute("archive.size", int64(size)))
+	span.Annotate(nil, "greeting archived")
+
+	return nil
+}
+
+// compress pretends to compress the greeting
+func (a *Archive) compress(ctx context.Context, greeting string) string {
+	_, span := octrace.StartSpan(ctx, "archive.compress")
+	defer span.End()
+
+	time.Sleep(COMPRESSION_LATENCY)
+	span.AddAttributes(octrace.Int64Attribute("archive.bytes", int64(len(greeting))))
+
+	return greeting
+}
@@ -0,0 +1,25 @@
+package profiling
+
+import (
+	"context"
+)
+
+const (
+	// TRACE_ID_LABEL and SPAN_ID_LABEL are the pprof labels identifying the span a profile sample was taken in
+	TRACE_ID_LABEL = "trace_id"
+	SPAN_ID_LABEL  = "span_id"
+
+	// PROFILE_ID_KEY is the span attribute pointing to the samples of the span, following the convention of Grafana
+	// Pyroscope, whose profile ID is the span ID
+	PROFILE_ID_KEY = "pyroscope.profile.id"
+)
+
+// Do runs f with pprof labels holding the trace ID and the span ID of the span in ctx, so that the CPU samples taken
+// while f runs, including in the goroutines it starts, can be attributed to the span. The span records the ID of its
+// samples in the PROFILE_ID_KEY attribute. The spans which are not sampled are not labelled: their traces are not
+// recorded anyway, and every distinct label set grows the profiles.
+func Do(ctx context.Context, f func(context.Context)) {
+	// the exercise: label the CPU samples taken while f runs with the trace ID and the span ID of the span in ctx, and
+	// record the profile ID in the span
+	f(ctx)
+}
@@ -0,0 +1,35 @@
+package profiling
+
+import (
+	"context"
+	"runtime/pprof"
+
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/trace"
+)
+
+const (
+	// TRACE_ID_LABEL and SPAN_ID_LABEL are the pprof labels identifying the span a profile sample was taken in
+	TRACE_ID_LABEL = "trace_id"
+	SPAN_ID_LABEL  = "span_id"
+
+	// PROFILE_ID_KEY is the span attribute pointing to the samples of the span, following the convention of Grafana
+	// Pyroscope, whose profile ID is the span ID
+	PROFILE_ID_KEY = "pyroscope.profile.id"
+)
+
+// Do runs f with pprof labels holding the trace ID and the span ID of the span in ctx, so that the CPU samples taken
+// while f runs, including in the goroutines it starts, can be attributed to the span. The span records the ID of its
+// samples in the PROFILE_ID_KEY attribute. The spans which are not sampled are not labelled: their traces are not
+// recorded anyway, and every distinct label set grows the profiles.
+func Do(ctx context.Context, f func(context.Context)) {
+	span := trace.SpanFromContext(ctx)
+	sc := span.SpanContext()
+	if !sc.IsSampled() {
+		f(ctx)
+		return
+	}
+
+	span.SetAttributes(attribute.String(PROFILE_ID_KEY, sc.SpanID().String()))
+	pprof.Do(ctx, pprof.Labels(TRACE_ID_LABEL, sc.TraceID().String(), SPAN_ID_LABEL, sc.SpanID().String()), f)
+}
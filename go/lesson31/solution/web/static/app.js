@@ -0,0 +1,85 @@
+// A minimal tracer for the page, exporting its spans as OTLP/JSON. A real application would use the OpenTelemetry
+// JavaScript SDK, which does the same with a lot more care.
+
+const SERVICE_NAME = "browser";
+const TRACER_NAME = "browser-tracer";
+const SPAN_KIND_CLIENT = 3;
+const STATUS_CODE_ERROR = 2;
+
+// randomHex returns n random bytes, hex-encoded, as the trace and span IDs are
+function randomHex(n) {
+  const bytes = new Uint8Array(n);
+  crypto.getRandomValues(bytes);
+  return Array.from(bytes, (b) => b.toString(16).padStart(2, "0")).join("");
+}
+
+// nowUnixNano returns the current time in nanoseconds since the epoch, as a string since it overflows a number
+function nowUnixNano() {
+  const millis = performance.timeOrigin + performance.now();
+  return (BigInt(Math.round(millis * 1000)) * 1000n).toString();
+}
+
+// startSpan starts a root span: the page is where the trace begins
+function startSpan(name, attributes) {
+  return {
+    traceId: randomHex(16),
+    spanId: randomHex(8),
+    name: name,
+    kind: SPAN_KIND_CLIENT,
+    startTimeUnixNano: nowUnixNano(),
+    attributes: Object.entries(attributes).map(([key, value]) => ({ key, value: { stringValue: String(value) } })),
+    status: {},
+  };
+}
+
+// traceparent returns the W3C traceparent header of the span, always sampled
+function traceparent(span) {
+  return `00-${span.traceId}-${span.spanId}-01`;
+}
+
+// endSpan ends the span and sends it to the OTLP/HTTP endpoint the page was served with
+function endSpan(span) {
+  span.endTimeUnixNano = nowUnixNano();
+  const body = {
+    resourceSpans: [{
+      resource: { attributes: [{ key: "service.name", value: { stringValue: SERVICE_NAME } }] },
+      scopeSpans: [{ scope: { name: TRACER_NAME }, spans: [span] }],
+    }],
+  };
+  return fetch("/v1/traces", {
+    method: "POST",
+    headers: { "Content-Type": "application/json" },
+    body: JSON.stringify(body),
+  });
+}
+
+async function sayHello(helloTo) {
+  const url = document.querySelector('meta[name="formatter-url"]').content + "?" + new URLSearchParams({ helloTo });
+
+  // starting a span named "say-hello" around the call to the formatter
+  const span = startSpan("say-hello", { "hello-to": helloTo, "http.url": url, "http.method": "GET" });
+  try {
+    // injecting the span context into the request headers, which makes the browser send a preflight request first
+    const resp = await fetch(url, { headers: { traceparent: traceparent(span) } });
+    span.attributes.push({ key: "http.status_code", value: { intValue: resp.status } });
+    return { greeting: await resp.text(), traceId: span.traceId };
+  } catch (err) {
+    // a request refused by the CORS policy of the formatter ends up here, the reason only being shown in the console
+    span.status = { code: STATUS_CODE_ERROR, message: String(err) };
+    throw err;
+  } finally {
+    await endSpan(span);
+  }
+}
+
+document.getElementById("hello").addEventListener("submit", async (event) => {
+  event.preventDefault();
+  const helloTo = document.getElementById("helloTo").value;
+  try {
+    const { greeting, traceId } = await sayHello(helloTo);
+    document.getElementById("greeting").textContent = greeting;
+    document.getElementById("trace").textContent = `trace ID: ${traceId}`;
+  } catch (err) {
+    document.getElementById("greeting").textContent = `failed to call the formatter: ${err}`;
+  }
+});
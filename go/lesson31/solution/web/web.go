@@ -0,0 +1,53 @@
+package main
+
+import (
+	"embed"
+	"flag"
+	"html/template"
+	"io/fs"
+	"log"
+	"net/http"
+	"net/http/httputil"
+	"net/url"
+
+	"github.com/legosandorigami/opentelemetry-tutorial/go/lib/config"
+)
+
+// DEFAULT_WEB_ADDR is the host:port the page is served on, the origin the formatter must accept
+const DEFAULT_WEB_ADDR = "localhost:8083"
+
+//go:embed static
+var static embed.FS
+
+// page holds the values the index page is rendered with
+type page struct {
+	// FormatterURL is the URL of the formatter called by the page
+	FormatterURL string
+}
+
+func main() {
+	webAddr := flag.String("web-addr", config.Getenv("WEB_ADDR", DEFAULT_WEB_ADDR), "host:port the page is served on")
+
+	// loading the service addresses from the command-line flags and environment variables
+	cfg := config.Load()
+
+	files, err := fs.Sub(static, "static")
+	if err != nil {
+		log.Fatal(err)
+	}
+	index := template.Must(template.ParseFS(files, "index.html"))
+
+	// serving the page, which calls the formatter from the browser
+	http.HandleFunc("/{$}", func(w http.ResponseWriter, r *http.Request) {
+		if err := index.Execute(w, page{FormatterURL: "http://" + cfg.FormatterAddr + "/format"}); err != nil {
+			log.Printf("failed to render the page: %v", err)
+		}
+	})
+	http.Handle("/static/", http.StripPrefix("/static/", http.FileServerFS(files)))
+
+	// forwarding the spans of the page to the OTLP backend, so that the browser exports to its own origin
+	http.Handle("POST /v1/traces", httputil.NewSingleHostReverseProxy(&url.URL{Scheme: "http", Host: cfg.OTLPEndpoint}))
+
+	log.Printf("open http://%s in a browser", *webAddr)
+	log.Fatal(http.ListenAndServe(config.ListenAddr(*webAddr), nil))
+}
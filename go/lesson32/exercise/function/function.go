@@ -0,0 +1,65 @@
+package main
+
+import (
+	"context"
+	"fmt"
+	"log"
+
+	"github.com/aws/aws-lambda-go/events"
+	"github.com/aws/aws-lambda-go/lambda"
+	"github.com/legosandorigami/opentelemetry-tutorial/go/lib/config"
+	"github.com/legosandorigami/opentelemetry-tutorial/go/lib/tracing"
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/propagation"
+	"go.opentelemetry.io/otel/trace"
+)
+
+func main() {
+	// loading the service addresses from the command-line flags and environment variables
+	cfg := config.Load()
+
+	// initialize the OpenTelemetry TracerProvider with the service name "formatter"
+	tracerPovider, err := tracing.InitTracerProviderWithBackend("formatter", cfg.OTLPEndpoint, tracing.WithPropagation(cfg.Propagation))
+	if err != nil {
+		log.Fatalf("failed to create otel exporter: %v", err)
+	}
+
+	// creating a context and defering the shutdown of the TracerProvider to ensure proper cleanup
+	ctx := context.Background()
+	defer func() {
+		if err := tracerPovider.Shutdown(ctx); err != nil {
+			log.Fatalf("failed to shutdown TracerProvider: %v", err)
+		}
+	}()
+
+	// retrieving or creating a tracer with name "formatter-tracer"
+	tracer := tracerPovider.Tracer("formatter-tracer")
+
+	lambda.Start(func(ctx context.Context, req events.APIGatewayV2HTTPRequest) (events.APIGatewayV2HTTPResponse, error) {
+		return format(ctx, tracer, req)
+	})
+}
+
+// format handles an invocation with the event of an HTTP request sent by API Gateway
+func format(ctx context.Context, tracer trace.Tracer, req events.APIGatewayV2HTTPRequest) (events.APIGatewayV2HTTPResponse, error) {
+	// extracting the span context from the headers of the HTTP request, which API Gateway lowercases
+	ctx = otel.GetTextMapPropagator().Extract(ctx, propagation.MapCarrier(req.Headers))
+
+	// the exercise: describe the invocation with the FaaS semantic conventions, including the cold start, and make sure
+	// its spans are exported before the execution environment is frozen
+
+	// starting a new span named "format" as a child of the extracted span context
+	_, span := tracer.Start(ctx, "format", trace.WithSpanKind(trace.SpanKindServer))
+	defer span.End()
+
+	helloTo := req.QueryStringParameters["helloTo"]
+	helloStr := fmt.Sprintf("Hello, %s!", helloTo)
+
+	// adding an event to the span indicating that the string was properly formatted
+	span.AddEvent("event", trace.WithAttributes(
+		attribute.String("string-format", helloStr),
+	))
+
+	return events.APIGatewayV2HTTPResponse{StatusCode: 200, Body: helloStr}, nil
+}
@@ -0,0 +1,213 @@
+// The gateway stands for AWS API Gateway and the Lambda service, so that the function can be run locally. It serves
+// the HTTP requests of the client on the address of the formatter, turns them into API Gateway events (payload format
+// 2.0), and hands them to the function through a minimal implementation of the Lambda Runtime API, which the function
+// polls for its next invocation. It is the same in the exercise and in the solution, and is not traced, as neither
+// API Gateway nor Lambda send a trace context header of their own.
+package main
+
+import (
+	"encoding/json"
+	"flag"
+	"fmt"
+	"io"
+	"log"
+	"net/http"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/aws/aws-lambda-go/events"
+	"github.com/google/uuid"
+	"github.com/legosandorigami/opentelemetry-tutorial/go/lib/config"
+)
+
+const (
+	// DEFAULT_RUNTIME_API_ADDR is the host:port of the Runtime API, the value of AWS_LAMBDA_RUNTIME_API for the function
+	DEFAULT_RUNTIME_API_ADDR = "localhost:9001"
+	// FUNCTION_ARN is the ARN the function is invoked with
+	FUNCTION_ARN = "arn:aws:lambda:local:000000000000:function:formatter"
+)
+
+// invocation is an event waiting for the function, and the channel its result is sent to
+type invocation struct {
+	id       string
+	payload  []byte
+	deadline time.Time
+	result   chan result
+}
+
+// result is the response of the function to an invocation, or the error it reported
+type result struct {
+	payload []byte
+	err     error
+}
+
+// runtime implements the endpoints of the Lambda Runtime API used by the aws-lambda-go runtime
+type runtime struct {
+	queue chan *invocation
+
+	mu      sync.Mutex
+	pending map[string]*invocation
+}
+
+func main() {
+	runtimeAPIAddr := flag.String("runtime-api-addr", config.Getenv("AWS_LAMBDA_RUNTIME_API", DEFAULT_RUNTIME_API_ADDR), "host:port of the Lambda Runtime API polled by the function")
+	timeout := flag.Duration("timeout", 3*time.Second, "how long an invocation may run, the timeout of the function")
+
+	// loading the service addresses from the command-line flags and environment variables
+	cfg := config.Load()
+
+	rt := &runtime{queue: make(chan *invocation), pending: make(map[string]*invocation)}
+
+	runtimeMux := http.NewServeMux()
+	runtimeMux.HandleFunc("GET /2018-06-01/runtime/invocation/next", rt.next)
+	runtimeMux.HandleFunc("POST /2018-06-01/runtime/invocation/{id}/response", rt.respond)
+	runtimeMux.HandleFunc("POST /2018-06-01/runtime/invocation/{id}/error", rt.fail)
+	go func() {
+		log.Fatal(http.ListenAndServe(config.ListenAddr(*runtimeAPIAddr), runtimeMux))
+	}()
+
+	// serving the requests of the client on the address of the formatter
+	gatewayMux := http.NewServeMux()
+	gatewayMux.HandleFunc("/", func(w http.ResponseWriter, r *http.Request) {
+		serve(w, r, rt, *timeout)
+	})
+
+	log.Printf("waiting for the function on AWS_LAMBDA_RUNTIME_API=%s", *runtimeAPIAddr)
+	log.Fatal(http.ListenAndServe(config.ListenAddr(cfg.FormatterAddr), gatewayMux))
+}
+
+// serve turns the request into an API Gateway event, invokes the function with it, and writes its response
+func serve(w http.ResponseWriter, r *http.Request, rt *runtime, timeout time.Duration) {
+	event := newEvent(r)
+	payload, err := json.Marshal(event)
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+		return
+	}
+
+	resp, err := rt.invoke(payload, time.Now().Add(timeout))
+	if err != nil {
+		log.Printf("invocation %s failed: %v", event.RequestContext.RequestID, err)
+		http.Error(w, "Internal Server Error", http.StatusBadGateway)
+		return
+	}
+
+	var out events.APIGatewayV2HTTPResponse
+	if err := json.Unmarshal(resp, &out); err != nil {
+		http.Error(w, "malformed response of the function", http.StatusBadGateway)
+		return
+	}
+	for k, v := range out.Headers {
+		w.Header().Set(k, v)
+	}
+	w.WriteHeader(out.StatusCode)
+	w.Write([]byte(out.Body))
+}
+
+// newEvent returns the API Gateway event of the request. As API Gateway does with the payload format 2.0, the names
+// of the headers are lowercased, and the values of a repeated header or query parameter are joined with commas.
+func newEvent(r *http.Request) events.APIGatewayV2HTTPRequest {
+	headers := make(map[string]string, len(r.Header))
+	for k, v := range r.Header {
+		headers[strings.ToLower(k)] = strings.Join(v, ",")
+	}
+	query := make(map[string]string)
+	for k, v := range r.URL.Query() {
+		query[k] = strings.Join(v, ",")
+	}
+
+	return events.APIGatewayV2HTTPRequest{
+		Version:               "2.0",
+		RouteKey:              "$default",
+		RawPath:               r.URL.Path,
+		RawQueryString:        r.URL.RawQuery,
+		Headers:               headers,
+		QueryStringParameters: query,
+		RequestContext: events.APIGatewayV2HTTPRequestContext{
+			RequestID: uuid.NewString(),
+			TimeEpoch: time.Now().UnixMilli(),
+			HTTP: events.APIGatewayV2HTTPRequestContextHTTPDescription{
+				Method:    r.Method,
+				Path:      r.URL.Path,
+				Protocol:  r.Proto,
+				SourceIP:  r.RemoteAddr,
+				UserAgent: r.UserAgent(),
+			},
+		},
+	}
+}
+
+// invoke queues the payload for the function, and waits for its result until the deadline
+func (rt *runtime) invoke(payload []byte, deadline time.Time) ([]byte, error) {
+	inv := &invocation{id: uuid.NewString(), payload: payload, deadline: deadline, result: make(chan result, 1)}
+
+	timer := time.NewTimer(time.Until(deadline))
+	defer timer.Stop()
+
+	select {
+	case rt.queue <- inv:
+	case <-timer.C:
+		return nil, fmt.Errorf("no function polled for the invocation")
+	}
+
+	select {
+	case res := <-inv.result:
+		return res.payload, res.err
+	case <-timer.C:
+		return nil, fmt.Errorf("task timed out")
+	}
+}
+
+// next hands the next invocation to the function, blocking until there is one
+func (rt *runtime) next(w http.ResponseWriter, r *http.Request) {
+	var inv *invocation
+	select {
+	case inv = <-rt.queue:
+	case <-r.Context().Done():
+		return
+	}
+
+	rt.mu.Lock()
+	rt.pending[inv.id] = inv
+	rt.mu.Unlock()
+
+	w.Header().Set("Lambda-Runtime-Aws-Request-Id", inv.id)
+	w.Header().Set("Lambda-Runtime-Deadline-Ms", strconv.FormatInt(inv.deadline.UnixMilli(), 10))
+	w.Header().Set("Lambda-Runtime-Invoked-Function-Arn", FUNCTION_ARN)
+	w.Header().Set("Content-Type", "application/json")
+	w.Write(inv.payload)
+}
+
+// respond passes the response of the function to the waiting invocation
+func (rt *runtime) respond(w http.ResponseWriter, r *http.Request) {
+	rt.complete(w, r, func(body []byte) result { return result{payload: body} })
+}
+
+// fail passes the error reported by the function to the waiting invocation
+func (rt *runtime) fail(w http.ResponseWriter, r *http.Request) {
+	rt.complete(w, r, func(body []byte) result { return result{err: fmt.Errorf("function error: %s", body)} })
+}
+
+// complete reads the body of the request, and sends the result it makes to the invocation of the request ID
+func (rt *runtime) complete(w http.ResponseWriter, r *http.Request, makeResult func([]byte) result) {
+	id := r.PathValue("id")
+	rt.mu.Lock()
+	inv, ok := rt.pending[id]
+	delete(rt.pending, id)
+	rt.mu.Unlock()
+	if !ok {
+		http.Error(w, "unknown request ID", http.StatusBadRequest)
+		return
+	}
+
+	body, err := io.ReadAll(r.Body)
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+
+	inv.result <- makeResult(body)
+	w.WriteHeader(http.StatusAccepted)
+}
@@ -0,0 +1,158 @@
+package main
+
+import (
+	"context"
+	"fmt"
+	"log"
+
+	"github.com/aws/aws-lambda-go/events"
+	"github.com/aws/aws-lambda-go/lambda"
+	"github.com/aws/aws-lambda-go/lambdacontext"
+	"github.com/legosandorigami/opentelemetry-tutorial/go/lib/config"
+	"github.com/legosandorigami/opentelemetry-tutorial/go/lib/latency"
+	"github.com/legosandorigami/opentelemetry-tutorial/go/lib/tracing"
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/propagation"
+	"go.opentelemetry.io/otel/sdk/resource"
+	traceSdk "go.opentelemetry.io/otel/sdk/trace"
+	semconv "go.opentelemetry.io/otel/semconv/v1.4.0"
+	"go.opentelemetry.io/otel/trace"
+)
+
+const (
+	// DEFAULT_FUNCTION_NAME is the name of the function when AWS_LAMBDA_FUNCTION_NAME is not set
+	DEFAULT_FUNCTION_NAME = "formatter"
+	// INIT_LATENCY is the time the function pretends to spend initializing, once per execution environment
+	INIT_LATENCY = "fixed:300ms"
+)
+
+// coldStart is true until the first invocation of the execution environment. Lambda sends an execution environment
+// one invocation at a time, so it needs no lock.
+var coldStart = true
+
+func main() {
+	// loading the service addresses from the command-line flags and environment variables
+	cfg := config.Load()
+
+	// describing the function with the attributes of the FaaS semantic conventions
+	res, err := newResource()
+	if err != nil {
+		log.Fatalf("failed to create the resource: %v", err)
+	}
+
+	// initialize the OpenTelemetry TracerProvider with the resource of the function
+	tracerPovider, err := tracing.InitTracerProviderWithResource(res, cfg.OTLPEndpoint, traceSdk.ParentBased(traceSdk.AlwaysSample()), tracing.WithPropagation(cfg.Propagation))
+	if err != nil {
+		log.Fatalf("failed to create otel exporter: %v", err)
+	}
+
+	// there is no deferred shutdown of the TracerProvider: lambda.Start never returns, the execution environment is
+	// frozen between the invocations, and killed without notice once Lambda no longer needs it
+
+	// retrieving or creating a tracer with name "formatter-tracer"
+	tracer := tracerPovider.Tracer("formatter-tracer")
+
+	// recording the initialization of the execution environment, the cold start, in a span of its own
+	initCtx, initSpan := tracer.Start(context.Background(), "init")
+	initLatency, err := latency.Parse(INIT_LATENCY)
+	if err != nil {
+		log.Fatal(err)
+	}
+	latency.Simulate(initCtx, tracer, initLatency)
+	initSpan.End()
+
+	// the first invocation links to the initialization, which belongs to no trace of a request
+	initLink := trace.LinkFromContext(initCtx, attribute.String("link.reason", "cold start"))
+
+	lambda.Start(func(ctx context.Context, req events.APIGatewayV2HTTPRequest) (events.APIGatewayV2HTTPResponse, error) {
+		// flushing the spans before returning: once the response is sent, the execution environment may be frozen
+		// with the spans still in the batch processor, and never be thawed again
+		defer func() {
+			if err := tracerPovider.ForceFlush(ctx); err != nil {
+				log.Printf("failed to flush the spans: %v", err)
+			}
+		}()
+
+		return format(ctx, tracer, initLink, req)
+	})
+}
+
+// format handles an invocation with the event of an HTTP request sent by API Gateway
+func format(ctx context.Context, tracer trace.Tracer, initLink trace.Link, req events.APIGatewayV2HTTPRequest) (events.APIGatewayV2HTTPResponse, error) {
+	// extracting the span context from the headers of the HTTP request, which API Gateway lowercases
+	ctx = otel.GetTextMapPropagator().Extract(ctx, propagation.MapCarrier(req.Headers))
+
+	// describing the invocation with the attributes of the FaaS semantic conventions
+	lc, _ := lambdacontext.FromContext(ctx)
+	opts := []trace.SpanStartOption{
+		trace.WithSpanKind(trace.SpanKindServer),
+		trace.WithAttributes(
+			semconv.FaaSTriggerHTTP,
+			semconv.FaaSExecutionKey.String(lc.AwsRequestID),
+			semconv.FaaSColdstartKey.Bool(coldStart),
+			semconv.HTTPMethodKey.String(req.RequestContext.HTTP.Method),
+			semconv.HTTPTargetKey.String(req.RawPath),
+		),
+	}
+	if coldStart {
+		opts = append(opts, trace.WithLinks(initLink))
+	}
+	coldStart = false
+
+	// starting a new span named after the function as a child of the extracted span context
+	_, span := tracer.Start(ctx, functionName(), opts...)
+	defer span.End()
+
+	helloTo := req.QueryStringParameters["helloTo"]
+	helloStr := fmt.Sprintf("Hello, %s!", helloTo)
+
+	// adding an event to the span indicating that the string was properly formatted
+	span.AddEvent("event", trace.WithAttributes(
+		attribute.String("string-format", helloStr),
+	))
+	span.SetAttributes(semconv.HTTPStatusCodeKey.Int(200))
+
+	return events.APIGatewayV2HTTPResponse{StatusCode: 200, Body: helloStr}, nil
+}
+
+// newResource returns the resource of the function: the attributes of tracing.NewResource, and the cloud and FaaS
+// attributes read from the environment Lambda sets for the function
+func newResource() (*resource.Resource, error) {
+	base, err := tracing.NewResource(functionName())
+	if err != nil {
+		return nil, err
+	}
+
+	attrs := []attribute.KeyValue{
+		semconv.CloudProviderAWS,
+		semconv.CloudPlatformAWSLambda,
+		semconv.FaaSNameKey.String(functionName()),
+	}
+	if region := config.Getenv("AWS_REGION", ""); region != "" {
+		attrs = append(attrs, semconv.CloudRegionKey.String(region))
+	}
+	if lambdacontext.FunctionVersion != "" {
+		attrs = append(attrs, semconv.FaaSVersionKey.String(lambdacontext.FunctionVersion))
+	}
+	if lambdacontext.LogStreamName != "" {
+		attrs = append(attrs, semconv.FaaSInstanceKey.String(lambdacontext.LogStreamName))
+	}
+	if lambdacontext.MemoryLimitInMB != 0 {
+		attrs = append(attrs, semconv.FaaSMaxMemoryKey.Int(lambdacontext.MemoryLimitInMB))
+	}
+
+	faas, err := resource.New(context.Background(), resource.WithAttributes(attrs...))
+	if err != nil {
+		return nil, err
+	}
+	return resource.Merge(base, faas)
+}
+
+// functionName returns the name of the function, set by Lambda in AWS_LAMBDA_FUNCTION_NAME
+func functionName() string {
+	if lambdacontext.FunctionName != "" {
+		return lambdacontext.FunctionName
+	}
+	return DEFAULT_FUNCTION_NAME
+}
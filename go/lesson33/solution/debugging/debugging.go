@@ -0,0 +1,39 @@
+package debugging
+
+import (
+	"context"
+	"net/http"
+	"strings"
+
+	"github.com/legosandorigami/opentelemetry-tutorial/go/lib/tracing"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/trace"
+)
+
+const (
+	// VENDOR is the key of the member of the trace state owned by the tutorial, as a tracing vendor owns its own
+	VENDOR = "tutorial"
+
+	// DEBUG is the value of the member asking the services to record the details of the requests of the trace
+	DEBUG = "debug"
+)
+
+// Enabled reports whether the trace of the span of ctx is a debug trace, that is whether its trace state holds the
+// member VENDOR with the value DEBUG. Any other value, or no member, leaves the usual level of detail.
+func Enabled(ctx context.Context) bool {
+	return tracing.TraceStateMember(ctx, VENDOR) == DEBUG
+}
+
+// RecordRequest records the details of the request in the span of ctx, its URL and every header, when the trace is a
+// debug trace. They are too costly to record for every request, but handy to have for the one being investigated.
+func RecordRequest(ctx context.Context, r *http.Request) {
+	if !Enabled(ctx) {
+		return
+	}
+
+	attrs := []attribute.KeyValue{attribute.String("http.url", r.URL.String())}
+	for name, values := range r.Header {
+		attrs = append(attrs, attribute.StringSlice("http.request.header."+strings.ToLower(name), values))
+	}
+	trace.SpanFromContext(ctx).AddEvent("request", trace.WithAttributes(attrs...))
+}
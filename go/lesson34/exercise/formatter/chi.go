@@ -0,0 +1,17 @@
+package main
+
+import (
+	"net/http"
+
+	"github.com/go-chi/chi/v5"
+)
+
+// newChiRouter serves the formatter with chi, the route being declared as /format/{helloTo}
+func newChiRouter() http.Handler {
+	r := chi.NewRouter()
+
+	r.Get("/format/{helloTo}", func(w http.ResponseWriter, r *http.Request) {
+		w.Write([]byte(format(r.Context(), chi.URLParam(r, "helloTo"))))
+	})
+	return r
+}
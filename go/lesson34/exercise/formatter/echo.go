@@ -0,0 +1,19 @@
+package main
+
+import (
+	"net/http"
+
+	"github.com/labstack/echo/v4"
+)
+
+// newEchoRouter serves the formatter with echo, the route being declared as /format/:helloTo
+func newEchoRouter() http.Handler {
+	e := echo.New()
+	e.HideBanner = true
+	e.HidePort = true
+
+	e.GET("/format/:helloTo", func(c echo.Context) error {
+		return c.String(http.StatusOK, format(c.Request().Context(), c.Param("helloTo")))
+	})
+	return e
+}
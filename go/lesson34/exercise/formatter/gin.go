@@ -0,0 +1,20 @@
+package main
+
+import (
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+)
+
+// newGinRouter serves the formatter with gin, the route being declared as /format/:helloTo
+func newGinRouter() http.Handler {
+	// leaving the debug output of gin, listing the routes, out of the logs of the tutorial
+	gin.SetMode(gin.ReleaseMode)
+
+	r := gin.New()
+
+	r.GET("/format/:helloTo", func(c *gin.Context) {
+		c.String(http.StatusOK, format(c.Request.Context(), c.Param("helloTo")))
+	})
+	return r
+}
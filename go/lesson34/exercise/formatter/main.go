@@ -0,0 +1,54 @@
+package main
+
+import (
+	"context"
+	"flag"
+	"fmt"
+	"log"
+	"net/http"
+
+	"github.com/legosandorigami/opentelemetry-tutorial/go/lib/config"
+	"github.com/legosandorigami/opentelemetry-tutorial/go/lib/tracing"
+)
+
+// routers builds the formatter with each of the router frameworks, all of them serving GET /format/<helloTo>
+var routers = map[string]func() http.Handler{
+	"chi":  newChiRouter,
+	"gin":  newGinRouter,
+	"echo": newEchoRouter,
+}
+
+// format formats the greeting, in no span yet
+func format(ctx context.Context, helloTo string) string {
+	return fmt.Sprintf("Hello, %s!", helloTo)
+}
+
+func main() {
+	// registering the flag selecting the router framework
+	router := flag.String("router", "chi", "router framework serving the requests: chi, gin or echo")
+
+	// loading the service addresses from the command-line flags and environment variables
+	cfg := config.Load()
+
+	newRouter, ok := routers[*router]
+	if !ok {
+		log.Fatalf("unknown router %q, expecting chi, gin or echo", *router)
+	}
+
+	// initialize the OpenTelemetry TracerProvider with the service name "formatter"
+	tracerPovider, err := tracing.InitTracerProviderWithBackend("formatter", cfg.OTLPEndpoint, tracing.WithPropagation(cfg.Propagation))
+	if err != nil {
+		log.Fatalf("failed to create otel exporter: %v", err)
+	}
+
+	// creating a context and defering the shutdown of the TracerProvider to ensure proper cleanup
+	ctx := context.Background()
+	defer func() {
+		if err := tracerPovider.Shutdown(ctx); err != nil {
+			log.Fatalf("failed to shutdown TracerProvider: %v", err)
+		}
+	}()
+
+	log.Printf("serving the formatter with %s", *router)
+	log.Fatal(http.ListenAndServe(config.ListenAddr(cfg.FormatterAddr), newRouter()))
+}
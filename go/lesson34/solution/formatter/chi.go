@@ -0,0 +1,59 @@
+package main
+
+import (
+	"net/http"
+
+	"github.com/go-chi/chi/v5"
+	"github.com/go-chi/chi/v5/middleware"
+	"github.com/legosandorigami/opentelemetry-tutorial/go/lib/attrs"
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/codes"
+	"go.opentelemetry.io/otel/propagation"
+	semconv "go.opentelemetry.io/otel/semconv/v1.4.0"
+	"go.opentelemetry.io/otel/trace"
+)
+
+// newChiRouter serves the formatter with chi. The route is declared as /format/{helloTo}, and the span is named after
+// it by chiTracing, e.g. "GET /format/{helloTo}".
+func newChiRouter(tracer trace.Tracer) http.Handler {
+	r := chi.NewRouter()
+	r.Use(chiTracing(tracer))
+
+	r.Get("/format/{helloTo}", func(w http.ResponseWriter, r *http.Request) {
+		w.Write([]byte(format(r.Context(), chi.URLParam(r, "helloTo"))))
+	})
+	return r
+}
+
+// chiTracing returns a chi middleware serving every request in a server span, chi having no instrumentation library
+// of the OpenTelemetry project. The middlewares of a chi router run before the routing: the route matching the request
+// is only known once next returns, so the span starts with the method alone as its name, and is renamed after the
+// route pattern then. A request matching no route keeps the method alone, which keeps the number of span names low.
+func chiTracing(tracer trace.Tracer) func(http.Handler) http.Handler {
+	return func(next http.Handler) http.Handler {
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			// starting the server span as a child of the span context extracted from the request headers
+			ctx := otel.GetTextMapPropagator().Extract(r.Context(), propagation.HeaderCarrier(r.Header))
+			ctx, span := tracer.Start(ctx, r.Method,
+				trace.WithSpanKind(trace.SpanKindServer),
+				trace.WithAttributes(attrs.HTTPServerAttrs(r)...),
+			)
+			defer span.End()
+
+			ww := middleware.NewWrapResponseWriter(w, r.ProtoMajor)
+			next.ServeHTTP(ww, r.WithContext(ctx))
+
+			// naming the span after the route chi matched, which it recorded in the routing context of the request
+			if pattern := chi.RouteContext(r.Context()).RoutePattern(); pattern != "" {
+				span.SetName(r.Method + " " + pattern)
+				span.SetAttributes(semconv.HTTPRouteKey.String(pattern))
+			}
+
+			status := ww.Status()
+			span.SetAttributes(semconv.HTTPAttributesFromHTTPStatusCode(status)...)
+			if code, description := semconv.SpanStatusFromHTTPStatusCodeAndSpanKind(status, trace.SpanKindServer); code == codes.Error {
+				span.SetStatus(code, description)
+			}
+		})
+	}
+}
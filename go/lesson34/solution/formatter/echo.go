@@ -0,0 +1,26 @@
+package main
+
+import (
+	"net/http"
+
+	"github.com/labstack/echo/v4"
+	"go.opentelemetry.io/contrib/instrumentation/github.com/labstack/echo/otelecho"
+	"go.opentelemetry.io/otel/trace"
+)
+
+// newEchoRouter serves the formatter with echo. The route is declared as /format/:helloTo, and otelecho names the span
+// after it with the method, "GET /format/:helloTo".
+func newEchoRouter(trace.Tracer) http.Handler {
+	e := echo.New()
+	e.HideBanner = true
+	e.HidePort = true
+
+	// echo routes the request before running the middlewares registered with Use, so otelecho knows the route when it
+	// starts the span
+	e.Use(otelecho.Middleware("formatter"))
+
+	e.GET("/format/:helloTo", func(c echo.Context) error {
+		return c.String(http.StatusOK, format(c.Request().Context(), c.Param("helloTo")))
+	})
+	return e
+}
@@ -0,0 +1,25 @@
+package main
+
+import (
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+	"go.opentelemetry.io/contrib/instrumentation/github.com/gin-gonic/gin/otelgin"
+	"go.opentelemetry.io/otel/trace"
+)
+
+// newGinRouter serves the formatter with gin. The route is declared as /format/:helloTo, and otelgin names the span
+// after it without the method, "/format/:helloTo".
+func newGinRouter(trace.Tracer) http.Handler {
+	// leaving the debug output of gin, listing the routes, out of the logs of the tutorial
+	gin.SetMode(gin.ReleaseMode)
+
+	r := gin.New()
+	// gin routes the request before running the middlewares, so otelgin knows the route when it starts the span
+	r.Use(otelgin.Middleware("formatter"))
+
+	r.GET("/format/:helloTo", func(c *gin.Context) {
+		c.String(http.StatusOK, format(c.Request.Context(), c.Param("helloTo")))
+	})
+	return r
+}
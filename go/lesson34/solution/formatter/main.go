@@ -0,0 +1,71 @@
+package main
+
+import (
+	"context"
+	"flag"
+	"fmt"
+	"log"
+	"net/http"
+
+	"github.com/legosandorigami/opentelemetry-tutorial/go/lib/config"
+	"github.com/legosandorigami/opentelemetry-tutorial/go/lib/tracing"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/trace"
+)
+
+// routers builds the formatter with each of the router frameworks, all of them serving GET /format/<helloTo>
+var routers = map[string]func(tracer trace.Tracer) http.Handler{
+	"chi":  newChiRouter,
+	"gin":  newGinRouter,
+	"echo": newEchoRouter,
+}
+
+// format formats the greeting in the server span started by the middleware of the router, which it finds in ctx
+func format(ctx context.Context, helloTo string) string {
+	span := trace.SpanFromContext(ctx)
+
+	helloStr := fmt.Sprintf("Hello, %s!", helloTo)
+
+	// adding an event to the span indicating that the string was properly formatted
+	span.AddEvent("event", trace.WithAttributes(
+		attribute.String("string-format", helloStr),
+	))
+
+	// printing the span details
+	tracing.PrintSpanContents(span)
+
+	return helloStr
+}
+
+func main() {
+	// registering the flag selecting the router framework
+	router := flag.String("router", "chi", "router framework serving the requests: chi, gin or echo")
+
+	// loading the service addresses from the command-line flags and environment variables
+	cfg := config.Load()
+
+	newRouter, ok := routers[*router]
+	if !ok {
+		log.Fatalf("unknown router %q, expecting chi, gin or echo", *router)
+	}
+
+	// initialize the OpenTelemetry TracerProvider with the service name "formatter"
+	tracerPovider, err := tracing.InitTracerProviderWithBackend("formatter", cfg.OTLPEndpoint, tracing.WithPropagation(cfg.Propagation))
+	if err != nil {
+		log.Fatalf("failed to create otel exporter: %v", err)
+	}
+
+	// creating a context and defering the shutdown of the TracerProvider to ensure proper cleanup
+	ctx := context.Background()
+	defer func() {
+		if err := tracerPovider.Shutdown(ctx); err != nil {
+			log.Fatalf("failed to shutdown TracerProvider: %v", err)
+		}
+	}()
+
+	// retrieving or creating a tracer with name "formatter-tracer", for the routers without an instrumentation library
+	tracer := tracerPovider.Tracer("formatter-tracer")
+
+	log.Printf("serving the formatter with %s", *router)
+	log.Fatal(http.ListenAndServe(config.ListenAddr(cfg.FormatterAddr), newRouter(tracer)))
+}
@@ -0,0 +1,69 @@
+package main
+
+import (
+	"context"
+	"flag"
+	"log"
+	"net/http"
+	"strings"
+
+	"github.com/legosandorigami/opentelemetry-tutorial/go/lib/config"
+	xhttp "github.com/legosandorigami/opentelemetry-tutorial/go/lib/http"
+	"github.com/legosandorigami/opentelemetry-tutorial/go/lib/latency"
+	"github.com/legosandorigami/opentelemetry-tutorial/go/lib/tracing"
+	"go.opentelemetry.io/otel/attribute"
+)
+
+func main() {
+	// registering the flags setting the time each template takes to render, and the template failing on purpose
+	renderLatency := flag.String("render-latency", "uniform:20ms:80ms", "time taken to render each template: fixed:<d>, uniform:<min>:<max> or pareto:<scale>:<shape>")
+	failing := flag.String("fail-template", "", "name of the template failing to render, e.g. french")
+
+	// loading the service addresses from the command-line flags and environment variables
+	cfg := config.Load()
+
+	dist, err := latency.Parse(*renderLatency)
+	if err != nil {
+		log.Fatalf("invalid render latency: %v", err)
+	}
+
+	// initialize the OpenTelemetry TracerProvider with the service name "formatter"
+	tracerPovider, err := tracing.InitTracerProviderWithBackend("formatter", cfg.OTLPEndpoint, tracing.WithPropagation(cfg.Propagation))
+	if err != nil {
+		log.Fatalf("failed to create otel exporter: %v", err)
+	}
+
+	// creating a context and defering the shutdown of the TracerProvider to ensure proper cleanup
+	ctx := context.Background()
+	defer func() {
+		if err := tracerPovider.Shutdown(ctx); err != nil {
+			log.Fatalf("failed to shutdown TracerProvider: %v", err)
+		}
+	}()
+
+	// retrieving or creating a tracer with name "formatter-tracer"
+	tracer := tracerPovider.Tracer("formatter-tracer")
+
+	rd := &renderer{tracer: tracer, dist: dist, failing: *failing}
+
+	http.HandleFunc("/format", func(w http.ResponseWriter, r *http.Request) {
+		// starting a new span named "format" as a child of the span context extracted from the request headers
+		ctx, span, end := xhttp.StartSpanFromRequest(tracer, r, "format")
+		defer end()
+
+		helloTo := r.FormValue("helloTo")
+		span.SetAttributes(attribute.Int("template.count", len(TEMPLATES)))
+
+		// rendering the templates
+		greetings, err := rd.renderAll(ctx, helloTo)
+		if err != nil {
+			tracing.RecordFailure(span, err)
+			http.Error(w, err.Error(), http.StatusInternalServerError)
+			return
+		}
+
+		w.Write([]byte(strings.Join(greetings, "\n")))
+	})
+
+	log.Fatal(http.ListenAndServe(config.ListenAddr(cfg.FormatterAddr), nil))
+}
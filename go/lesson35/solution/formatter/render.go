@@ -0,0 +1,97 @@
+package main
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/legosandorigami/opentelemetry-tutorial/go/lib/latency"
+	"github.com/legosandorigami/opentelemetry-tutorial/go/lib/tracing"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/trace"
+	"golang.org/x/sync/errgroup"
+)
+
+// template is a way of greeting someone, rendered by a renderer of its own
+type template struct {
+	name   string
+	format string
+}
+
+// TEMPLATES are the greetings the formatter renders for every name, concurrently
+var TEMPLATES = []template{
+	{name: "english", format: "Hello, %s!"},
+	{name: "french", format: "Bonjour, %s !"},
+	{name: "spanish", format: "¡Hola, %s!"},
+	{name: "german", format: "Hallo, %s!"},
+}
+
+// renderer renders the templates, taking a duration sampled from dist for each of them, and failing on the template
+// named failing, if any
+type renderer struct {
+	tracer  trace.Tracer
+	dist    latency.Distribution
+	failing string
+}
+
+// renderAll renders every template concurrently, one goroutine each, and returns the greetings in the order of
+// TEMPLATES. Each goroutine receives the context of the errgroup, which is derived from ctx: it carries the span of the
+// caller, so that the render spans are its children, and it is canceled as soon as a renderer fails, so that the
+// other renderers stop rather than doing work nobody will use.
+func (rd *renderer) renderAll(ctx context.Context, helloTo string) ([]string, error) {
+	greetings := make([]string, len(TEMPLATES))
+
+	g, gctx := errgroup.WithContext(ctx)
+	for i, tmpl := range TEMPLATES {
+		g.Go(func() error {
+			greeting, err := rd.render(gctx, tmpl, helloTo)
+			if err != nil {
+				return err
+			}
+			greetings[i] = greeting
+			return nil
+		})
+	}
+
+	if err := g.Wait(); err != nil {
+		return nil, err
+	}
+	return greetings, nil
+}
+
+// render renders a single template in a "render" span, started from ctx
+func (rd *renderer) render(ctx context.Context, tmpl template, helloTo string) (string, error) {
+	_, span := rd.tracer.Start(ctx, "render", trace.WithAttributes(
+		attribute.String("template.name", tmpl.name),
+	))
+	defer span.End()
+
+	// failing right away, as for a template which does not parse
+	if tmpl.name == rd.failing {
+		err := fmt.Errorf("failed to parse the %s template", tmpl.name)
+		tracing.RecordFailure(span, err)
+		return "", err
+	}
+
+	// simulating the work of rendering, and giving up as soon as another renderer failed
+	var d time.Duration
+	if rd.dist != nil {
+		d = rd.dist.Sample()
+	}
+	select {
+	case <-time.After(d):
+	case <-ctx.Done():
+		// recording the cause of the cancellation, the error of the renderer which failed, without marking this span as
+		// failed: the failure belongs to the span of that renderer alone
+		span.AddEvent("canceled", trace.WithAttributes(
+			attribute.String("cause", context.Cause(ctx).Error()),
+		))
+		return "", ctx.Err()
+	}
+
+	greeting := fmt.Sprintf(tmpl.format, helloTo)
+	span.AddEvent("rendered", trace.WithAttributes(
+		attribute.String("greeting", greeting),
+	))
+	return greeting, nil
+}
@@ -0,0 +1,95 @@
+// Command stats prints statistics about the traces written into a SQLite file by the spanstore.Processor, computed
+// with plain SQL queries.
+package main
+
+import (
+	"database/sql"
+	"flag"
+	"fmt"
+	"log"
+	"os"
+	"text/tabwriter"
+
+	_ "modernc.org/sqlite"
+)
+
+// OPERATIONS summarizes the spans of every operation, an operation being a span name in a service
+const OPERATIONS = `
+SELECT service, name, COUNT(*), SUM(status = 'Error'), AVG(duration_ms), MAX(duration_ms)
+FROM spans
+GROUP BY service, name
+ORDER BY service, name`
+
+// SLOWEST_TRACES lists the slowest traces, the duration of a trace spanning from its first span to its last one
+const SLOWEST_TRACES = `
+SELECT trace_id,
+       (SELECT name FROM spans AS root WHERE root.trace_id = spans.trace_id AND root.parent_span_id IS NULL),
+       COUNT(*),
+       COUNT(DISTINCT service),
+       (MAX(end_unix_nano) - MIN(start_unix_nano)) / 1e6 AS duration_ms,
+       SUM(status = 'Error')
+FROM spans
+GROUP BY trace_id
+ORDER BY duration_ms DESC
+LIMIT ?`
+
+func main() {
+	spansDB := flag.String("spans-db", "spans.db", "SQLite file the spans were written to")
+	limit := flag.Int("limit", 5, "number of slowest traces listed")
+	flag.Parse()
+
+	// checking that the file exists, which opening it read-only would report as an obscure error
+	if _, err := os.Stat(*spansDB); err != nil {
+		log.Fatalf("no spans database: %v", err)
+	}
+
+	// opening the database read-only, the services may still be writing into it
+	db, err := sql.Open("sqlite", "file:"+*spansDB+"?mode=ro&_pragma=busy_timeout(5000)")
+	if err != nil {
+		log.Fatalf("failed to open the spans database: %v", err)
+	}
+	defer db.Close()
+
+	w := tabwriter.NewWriter(os.Stdout, 0, 0, 2, ' ', 0)
+
+	fmt.Fprintln(w, "SERVICE\tSPAN\tCOUNT\tERRORS\tAVG MS\tMAX MS")
+	rows, err := db.Query(OPERATIONS)
+	if err != nil {
+		log.Fatalf("failed to query the operations: %v", err)
+	}
+	for rows.Next() {
+		var service, name string
+		var count, errors int
+		var avg, max float64
+		if err := rows.Scan(&service, &name, &count, &errors, &avg, &max); err != nil {
+			log.Fatal(err)
+		}
+		fmt.Fprintf(w, "%s\t%s\t%d\t%d\t%.1f\t%.1f\n", service, name, count, errors, avg, max)
+	}
+	if err := rows.Err(); err != nil {
+		log.Fatal(err)
+	}
+	rows.Close()
+
+	fmt.Fprintln(w, "\nTRACE\tROOT\tSPANS\tSERVICES\tMS\tERRORS")
+	rows, err = db.Query(SLOWEST_TRACES, *limit)
+	if err != nil {
+		log.Fatalf("failed to query the slowest traces: %v", err)
+	}
+	for rows.Next() {
+		var traceID string
+		var root sql.NullString
+		var spans, services, errors int
+		var duration float64
+		if err := rows.Scan(&traceID, &root, &spans, &services, &duration, &errors); err != nil {
+			log.Fatal(err)
+		}
+		fmt.Fprintf(w, "%s\t%s\t%d\t%d\t%.1f\t%d\n", traceID, root.String, spans, services, duration, errors)
+	}
+	if err := rows.Err(); err != nil {
+		log.Fatal(err)
+	}
+	rows.Close()
+
+	w.Flush()
+}
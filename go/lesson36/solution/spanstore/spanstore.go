@@ -0,0 +1,240 @@
+// Package spanstore holds a SpanProcessor writing the finished spans into a SQLite database, to be queried with SQL.
+package spanstore
+
+import (
+	"context"
+	"database/sql"
+	"encoding/json"
+	"fmt"
+	"log"
+	"sync"
+	"sync/atomic"
+
+	"go.opentelemetry.io/otel/attribute"
+	traceSdk "go.opentelemetry.io/otel/sdk/trace"
+	semconv "go.opentelemetry.io/otel/semconv/v1.4.0"
+	_ "modernc.org/sqlite"
+)
+
+// QUEUE_SIZE is the number of finished spans waiting to be written, beyond which the spans are dropped
+const QUEUE_SIZE = 2048
+
+// SCHEMA creates the table of the spans, one row per finished span, with its attributes as a JSON object
+const SCHEMA = `
+CREATE TABLE IF NOT EXISTS spans (
+	trace_id        TEXT NOT NULL,
+	span_id         TEXT PRIMARY KEY,
+	parent_span_id  TEXT,
+	service         TEXT NOT NULL,
+	name            TEXT NOT NULL,
+	kind            TEXT NOT NULL,
+	start_unix_nano INTEGER NOT NULL,
+	end_unix_nano   INTEGER NOT NULL,
+	duration_ms     REAL NOT NULL,
+	status          TEXT NOT NULL,
+	status_message  TEXT NOT NULL,
+	attributes      TEXT NOT NULL
+);
+CREATE INDEX IF NOT EXISTS spans_trace_id ON spans (trace_id);
+`
+
+// INSERT writes a span, the same span being written only once whatever the number of calls to ForceFlush
+const INSERT = `INSERT OR IGNORE INTO spans VALUES (?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?)`
+
+// Processor is a SpanProcessor writing the finished spans into a SQLite database. OnEnd runs in the goroutine ending
+// the span, in the middle of the work of the application, so it must not wait for the disk: it only hands the span
+// over to a goroutine of the Processor, which writes the spans in transactions, as many at once as are waiting.
+type Processor struct {
+	db      *sql.DB
+	spans   chan traceSdk.ReadOnlySpan
+	flushes chan chan struct{}
+	stop    chan struct{}
+	done    chan struct{}
+	stopped sync.Once
+	dropped atomic.Int64
+}
+
+// Open opens the SQLite database at path, creating it and its table if needed, and returns a Processor writing into
+// it. Several processes may write into the same file, each one waiting for the others to be done.
+func Open(path string) (*Processor, error) {
+	db, err := sql.Open("sqlite", "file:"+path+"?_pragma=busy_timeout(5000)&_pragma=journal_mode(WAL)")
+	if err != nil {
+		return nil, err
+	}
+	if _, err := db.Exec(SCHEMA); err != nil {
+		db.Close()
+		return nil, fmt.Errorf("failed to create the spans table: %w", err)
+	}
+
+	p := &Processor{
+		db:      db,
+		spans:   make(chan traceSdk.ReadOnlySpan, QUEUE_SIZE),
+		flushes: make(chan chan struct{}),
+		stop:    make(chan struct{}),
+		done:    make(chan struct{}),
+	}
+	go p.run()
+	return p, nil
+}
+
+// OnStart does nothing: a span is only written once it has ended, with all its attributes.
+func (p *Processor) OnStart(parent context.Context, s traceSdk.ReadWriteSpan) {}
+
+// OnEnd queues the span to be written, or drops it, and counts it, if the queue is full or the Processor shut down.
+func (p *Processor) OnEnd(s traceSdk.ReadOnlySpan) {
+	// skipping the spans which were not sampled, as the exporters do
+	if !s.SpanContext().IsSampled() {
+		return
+	}
+
+	select {
+	case <-p.stop:
+		p.dropped.Add(1)
+		return
+	default:
+	}
+
+	select {
+	case p.spans <- s:
+	default:
+		p.dropped.Add(1)
+	}
+}
+
+// ForceFlush waits until the spans ended so far are written.
+func (p *Processor) ForceFlush(ctx context.Context) error {
+	flushed := make(chan struct{})
+	select {
+	case p.flushes <- flushed:
+	case <-p.done:
+		return nil
+	case <-ctx.Done():
+		return ctx.Err()
+	}
+
+	select {
+	case <-flushed:
+		return nil
+	case <-p.done:
+		return nil
+	case <-ctx.Done():
+		return ctx.Err()
+	}
+}
+
+// Shutdown writes the spans still queued and closes the database. The spans ending afterwards are dropped.
+func (p *Processor) Shutdown(ctx context.Context) error {
+	p.stopped.Do(func() { close(p.stop) })
+
+	select {
+	case <-p.done:
+	case <-ctx.Done():
+		return ctx.Err()
+	}
+
+	if dropped := p.dropped.Load(); dropped > 0 {
+		log.Printf("spanstore: dropped %d spans", dropped)
+	}
+	return p.db.Close()
+}
+
+// run writes the queued spans until the Processor shuts down
+func (p *Processor) run() {
+	defer close(p.done)
+	for {
+		select {
+		case s := <-p.spans:
+			p.write(append([]traceSdk.ReadOnlySpan{s}, p.drain()...))
+		case flushed := <-p.flushes:
+			p.write(p.drain())
+			close(flushed)
+		case <-p.stop:
+			p.write(p.drain())
+			return
+		}
+	}
+}
+
+// drain returns the spans waiting in the queue, without waiting for more
+func (p *Processor) drain() []traceSdk.ReadOnlySpan {
+	var spans []traceSdk.ReadOnlySpan
+	for {
+		select {
+		case s := <-p.spans:
+			spans = append(spans, s)
+		default:
+			return spans
+		}
+	}
+}
+
+// write inserts the spans in a single transaction, which costs a single write to the disk however many spans there are
+func (p *Processor) write(spans []traceSdk.ReadOnlySpan) {
+	if len(spans) == 0 {
+		return
+	}
+
+	if err := p.insert(spans); err != nil {
+		p.dropped.Add(int64(len(spans)))
+		log.Printf("spanstore: failed to write %d spans: %v", len(spans), err)
+	}
+}
+
+func (p *Processor) insert(spans []traceSdk.ReadOnlySpan) error {
+	tx, err := p.db.Begin()
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback()
+
+	stmt, err := tx.Prepare(INSERT)
+	if err != nil {
+		return err
+	}
+	defer stmt.Close()
+
+	for _, s := range spans {
+		if _, err := stmt.Exec(row(s)...); err != nil {
+			return err
+		}
+	}
+	return tx.Commit()
+}
+
+// row returns the values of the columns of the spans table for s
+func row(s traceSdk.ReadOnlySpan) []any {
+	var parent any
+	if s.Parent().SpanID().IsValid() {
+		parent = s.Parent().SpanID().String()
+	}
+
+	service, _ := s.Resource().Set().Value(semconv.ServiceNameKey)
+
+	return []any{
+		s.SpanContext().TraceID().String(),
+		s.SpanContext().SpanID().String(),
+		parent,
+		service.AsString(),
+		s.Name(),
+		s.SpanKind().String(),
+		s.StartTime().UnixNano(),
+		s.EndTime().UnixNano(),
+		float64(s.EndTime().Sub(s.StartTime()).Microseconds()) / 1000,
+		s.Status().Code.String(),
+		s.Status().Description,
+		attributesJSON(s.Attributes()),
+	}
+}
+
+// attributesJSON encodes the attributes as a JSON object, which the json_extract function of SQLite reads back
+func attributesJSON(attrs []attribute.KeyValue) string {
+	m := make(map[string]any, len(attrs))
+	for _, kv := range attrs {
+		m[string(kv.Key)] = kv.Value.AsInterface()
+	}
+	data, err := json.Marshal(m)
+	if err != nil {
+		return "{}"
+	}
+	return string(data)
+}
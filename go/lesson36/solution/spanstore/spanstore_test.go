@@ -0,0 +1,75 @@
+package spanstore
+
+import (
+	"context"
+	"database/sql"
+	"path/filepath"
+	"testing"
+
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/codes"
+	"go.opentelemetry.io/otel/sdk/resource"
+	traceSdk "go.opentelemetry.io/otel/sdk/trace"
+	semconv "go.opentelemetry.io/otel/semconv/v1.4.0"
+	"go.opentelemetry.io/otel/trace"
+)
+
+func TestProcessorWritesSpans(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "spans.db")
+	p, err := Open(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	tp := traceSdk.NewTracerProvider(
+		traceSdk.WithSpanProcessor(p),
+		traceSdk.WithResource(resource.NewSchemaless(semconv.ServiceNameKey.String("formatter"))),
+	)
+	tracer := tp.Tracer("test")
+
+	ctx, parent := tracer.Start(context.Background(), "format", trace.WithSpanKind(trace.SpanKindServer))
+	_, child := tracer.Start(ctx, "work", trace.WithAttributes(attribute.String("hello-to", "Brian")))
+	child.SetStatus(codes.Error, "too slow")
+	child.End()
+	parent.End()
+
+	// the spans are written once flushed, and written only once however many times they are flushed
+	if err := tp.ForceFlush(context.Background()); err != nil {
+		t.Fatal(err)
+	}
+	if err := tp.ForceFlush(context.Background()); err != nil {
+		t.Fatal(err)
+	}
+	if err := tp.Shutdown(context.Background()); err != nil {
+		t.Fatal(err)
+	}
+
+	db, err := sql.Open("sqlite", path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer db.Close()
+
+	var count int
+	if err := db.QueryRow(`SELECT COUNT(*) FROM spans`).Scan(&count); err != nil {
+		t.Fatal(err)
+	}
+	if count != 2 {
+		t.Fatalf("%d spans written, want 2", count)
+	}
+
+	var service, kind, status, helloTo string
+	var parentID sql.NullString
+	err = db.QueryRow(`
+		SELECT service, kind, status, json_extract(attributes, '$."hello-to"'), parent_span_id
+		FROM spans WHERE name = 'work'`).Scan(&service, &kind, &status, &helloTo, &parentID)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if service != "formatter" || kind != "internal" || status != "Error" || helloTo != "Brian" {
+		t.Errorf("work span = (%s, %s, %s, %s), want (formatter, internal, Error, Brian)", service, kind, status, helloTo)
+	}
+	if parentID.String != parent.SpanContext().SpanID().String() {
+		t.Errorf("parent_span_id = %q, want the ID of the format span %s", parentID.String, parent.SpanContext().SpanID())
+	}
+}
@@ -0,0 +1,141 @@
+package main
+
+import (
+	"context"
+	"flag"
+	"fmt"
+	"log"
+	"log/slog"
+	"net/http"
+	"net/url"
+	"time"
+
+	"github.com/legosandorigami/opentelemetry-tutorial/go/lib/attrs"
+	"github.com/legosandorigami/opentelemetry-tutorial/go/lib/config"
+	xhttp "github.com/legosandorigami/opentelemetry-tutorial/go/lib/http"
+	xlog "github.com/legosandorigami/opentelemetry-tutorial/go/lib/log"
+	"github.com/legosandorigami/opentelemetry-tutorial/go/lib/tracing"
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/propagation"
+	"go.opentelemetry.io/otel/trace"
+)
+
+// retrieving the tracer named "say-hello-tracer" once, from the global TracerProvider
+var tracer = otel.Tracer("say-hello-tracer")
+
+func main() {
+	// registering the flags of the load generator
+	rate := flag.Float64("rate", 10, "greetings per second, each one in its own trace")
+	duration := flag.Duration("duration", 30*time.Second, "how long to send greetings")
+
+	// loading the service addresses from the command-line flags and environment variables
+	cfg := config.Load()
+
+	// checking if at least one name was given, the greetings cycle through the names
+	if flag.NArg() < 1 || *rate <= 0 {
+		panic("ERROR: Expecting at least one argument and a positive rate")
+	}
+
+	// initializing the OpenTelemetry TracerProvider and LoggerProvider with the service name "hello-world"
+	tracerPovider, err := tracing.InitTracerProviderWithBackend("hello-world", cfg.OTLPEndpoint, tracing.WithPropagation(cfg.Propagation))
+	if err != nil {
+		log.Fatalf("failed to create otel exporter: %v", err)
+	}
+	loggerProvider, err := xlog.InitLoggerProviderWithBackend("hello-world", cfg.OTLPEndpoint)
+	if err != nil {
+		log.Fatalf("failed to create otel log exporter: %v", err)
+	}
+
+	// creating a context and defering the shutdown of the providers to ensure proper cleanup, the TracerProvider last
+	ctx := context.Background()
+	shutdown := config.NewShutdownCoordinator()
+	shutdown.Add(config.TRACES, 0, tracerPovider.Shutdown)
+	shutdown.Add(config.LOGS, 0, loggerProvider.Shutdown)
+	defer func() {
+		if err := shutdown.Shutdown(ctx); err != nil {
+			log.Fatalf("failed to shutdown the providers: %v", err)
+		}
+	}()
+
+	// creating a logger emitting its records through the OpenTelemetry log bridge
+	logger := xlog.NewOTel("say-hello-logger")
+
+	// sending the greetings at the requested rate until the duration elapses
+	ticker := time.NewTicker(time.Duration(float64(time.Second) / *rate))
+	defer ticker.Stop()
+	deadline := time.After(*duration)
+
+	var sent, failed int
+	for {
+		select {
+		case <-deadline:
+			log.Printf("%d greetings sent, %d failed", sent, failed)
+			return
+		case <-ticker.C:
+			helloTo := flag.Arg(sent % flag.NArg())
+			sent++
+			if !sayHello(ctx, cfg, logger, helloTo) {
+				failed++
+			}
+		}
+	}
+}
+
+// sayHello greets helloTo in a new trace, and reports whether it succeeded. The trace ID of a failed greeting is
+// printed, as the starting point of the investigation.
+func sayHello(ctx context.Context, cfg *config.Config, logger *slog.Logger, helloTo string) bool {
+	// starting a new root span named "say-hello"
+	ctx, span := tracer.Start(ctx, "say-hello", trace.WithAttributes(attribute.String("hello-to", helloTo)))
+	defer span.End()
+
+	// calling `formatString` function with the context ctx.
+	if _, err := formatString(ctx, cfg.FormatterAddr, helloTo); err != nil {
+		tracing.RecordFailure(span, err)
+		logger.ErrorContext(ctx, "failed to greet", slog.String("hello-to", helloTo), slog.String("error", err.Error()))
+		log.Printf("failed to greet %s in trace %s", helloTo, span.SpanContext().TraceID())
+		return false
+	}
+	return true
+}
+
+func formatString(ctx context.Context, formatterAddr, helloTo string) (string, error) {
+	// preparing to send an http get request to the "formatter" service
+	v := url.Values{}
+	v.Set("helloTo", helloTo)
+	url := "http://" + formatterAddr + "/format?" + v.Encode()
+
+	// creating a client span indicating that it is an RPC
+	ctx, span := tracer.Start(ctx, "formatString", trace.WithSpanKind(trace.SpanKindClient))
+	defer span.End()
+
+	// creating a new HTTP request to formatter microservice
+	req, err := http.NewRequestWithContext(ctx, "GET", url, nil)
+	if err != nil {
+		return "", err
+	}
+
+	// recording the attributes of the request following the semantic conventions
+	span.SetAttributes(append(attrs.HTTPClientAttrs(req), attrs.PeerService("formatter"))...)
+
+	// injecting the span context into the request headers
+	otel.GetTextMapPropagator().Inject(ctx, propagation.HeaderCarrier(req.Header))
+
+	//sending a get request
+	resp, err := xhttp.Do(req)
+	if err != nil {
+		// recording the error in the span and marking the span as failed
+		tracing.RecordFailure(span, err,
+			attribute.String("format-response-error", fmt.Sprintf("Failed to format the string %s", helloTo)))
+		return "", err
+	}
+
+	helloStr := string(resp)
+
+	// adding an event to the span indicating a successful response was received
+	span.AddEvent("format-event-response", trace.WithAttributes(
+		attribute.String("format-response", fmt.Sprintf("string-format: %s", helloStr)),
+	))
+
+	return helloStr, nil
+}
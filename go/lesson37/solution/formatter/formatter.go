@@ -0,0 +1,129 @@
+package main
+
+import (
+	"context"
+	"errors"
+	"flag"
+	"fmt"
+	"log"
+	"log/slog"
+	"math/rand/v2"
+	"net/http"
+	"os"
+	"os/signal"
+	"syscall"
+
+	"github.com/legosandorigami/opentelemetry-tutorial/go/lib/config"
+	xhttp "github.com/legosandorigami/opentelemetry-tutorial/go/lib/http"
+	"github.com/legosandorigami/opentelemetry-tutorial/go/lib/latency"
+	xlog "github.com/legosandorigami/opentelemetry-tutorial/go/lib/log"
+	"github.com/legosandorigami/opentelemetry-tutorial/go/lib/metrics"
+	"github.com/legosandorigami/opentelemetry-tutorial/go/lib/tracing"
+)
+
+// DEFAULT_LATENCY is a heavy-tailed latency, most requests taking about 20ms and a few of them much longer
+const DEFAULT_LATENCY = "pareto:20ms:1.5"
+
+func main() {
+	// registering the flag setting the fraction of the greetings failing on purpose
+	errorRate := flag.Float64("error-rate", 0.05, "fraction of the greetings failing with a 500")
+
+	// loading the service addresses from the command-line flags and environment variables
+	cfg := config.Load()
+	if cfg.Latency == "" {
+		cfg.Latency = DEFAULT_LATENCY
+	}
+
+	// initialize the OpenTelemetry TracerProvider, MeterProvider and LoggerProvider with the service name "formatter",
+	// all three exporting to the same backend
+	tracerPovider, err := tracing.InitTracerProviderWithBackend("formatter", cfg.OTLPEndpoint, tracing.WithPropagation(cfg.Propagation))
+	if err != nil {
+		log.Fatalf("failed to create otel exporter: %v", err)
+	}
+	meterProvider, err := metrics.InitMeterProviderWithBackend("formatter", cfg.OTLPEndpoint)
+	if err != nil {
+		log.Fatalf("failed to create otel metric exporter: %v", err)
+	}
+	loggerProvider, err := xlog.InitLoggerProviderWithBackend("formatter", cfg.OTLPEndpoint)
+	if err != nil {
+		log.Fatalf("failed to create otel log exporter: %v", err)
+	}
+
+	// creating a context canceled on an interrupt or a termination, which stops the server at the end of main rather than
+	// the process, and registering the providers to shut down then, in a defined order and with a timeout each, the
+	// TracerProvider last
+	ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt, syscall.SIGTERM)
+	defer stop()
+	shutdown := config.NewShutdownCoordinator()
+	shutdown.Add(config.TRACES, 0, tracerPovider.Shutdown)
+	shutdown.Add(config.METRICS, 0, meterProvider.Shutdown)
+	shutdown.Add(config.LOGS, 0, loggerProvider.Shutdown)
+
+	// parsing the distribution of the simulated work latency
+	workLatency, err := latency.Parse(cfg.Latency)
+	if err != nil {
+		log.Fatal(err)
+	}
+
+	// retrieving or creating a tracer with name "formatter-tracer"
+	tracer := tracerPovider.Tracer("formatter-tracer")
+
+	// creating a logger emitting its records through the OpenTelemetry log bridge
+	logger := xlog.NewOTel("formatter-logger")
+
+	// creating the middleware recording the RED metrics of the requests: their rate and errors, counted by status
+	// code, and their duration
+	red, err := xhttp.Metrics(meterProvider.Meter("formatter-meter"))
+	if err != nil {
+		log.Fatalf("failed to create instruments: %v", err)
+	}
+
+	format := func(w http.ResponseWriter, r *http.Request) {
+		// the context of the request holds the server span started by Traced
+		ctx := r.Context()
+		helloTo := r.FormValue("helloTo")
+
+		// simulating some work inside a child span named "work"
+		latency.Simulate(ctx, tracer, workLatency)
+
+		// failing a fraction of the greetings, which Traced records in the status of the span
+		if rand.Float64() < *errorRate {
+			logger.ErrorContext(ctx, "failed to format the greeting", slog.String("hello-to", helloTo))
+			http.Error(w, "failed to format the greeting", http.StatusInternalServerError)
+			return
+		}
+
+		helloStr := fmt.Sprintf("Hello, %s!", helloTo)
+
+		// logging with the context, the bridge copying the trace ID and span ID of the server span into the record
+		logger.InfoContext(ctx, "formatted the greeting", slog.String("hello-to", helloTo))
+
+		w.Write([]byte(helloStr))
+	}
+
+	// starting the server span before the middlewares run, so that the measurements and the access log of the request
+	// are recorded within it: the exemplars and the log records then point at the server span
+	http.Handle("/format", xhttp.Traced("format", xhttp.Chain(http.HandlerFunc(format), red, xhttp.Logging(logger)).ServeHTTP))
+
+	// serving in a goroutine rather than with log.Fatal, which would exit the program without shutting the providers
+	// down
+	server := &http.Server{Addr: config.ListenAddr(cfg.FormatterAddr)}
+	go func() {
+		if err := server.ListenAndServe(); !errors.Is(err, http.ErrServerClosed) {
+			log.Fatal(err)
+		}
+	}()
+
+	// waiting for an interrupt or a termination, then letting the requests in flight end before shutting the providers
+	// down, so that the telemetry of those requests is exported as well
+	<-ctx.Done()
+	log.Printf("shutting down")
+	serverCtx, cancel := context.WithTimeout(context.WithoutCancel(ctx), config.DEFAULT_SHUTDOWN_TIMEOUT)
+	defer cancel()
+	if err := server.Shutdown(serverCtx); err != nil {
+		log.Printf("failed to shutdown the server: %v", err)
+	}
+	if err := shutdown.Shutdown(ctx); err != nil {
+		log.Fatalf("failed to shutdown the providers: %v", err)
+	}
+}
@@ -0,0 +1,71 @@
+// Package attrs builds the attributes of the spans following the semantic conventions, so that the lessons do not
+// assemble them by hand. The version of the conventions is the one used throughout the tutorial, set here once.
+package attrs
+
+import (
+	"net/http"
+	"strconv"
+	"strings"
+
+	"go.opentelemetry.io/otel/attribute"
+	semconv "go.opentelemetry.io/otel/semconv/v1.4.0"
+)
+
+// HTTPClientAttrs returns the attributes of the client span sending req: its method, full URL and flavor, and the
+// name and port of the server it is sent to.
+func HTTPClientAttrs(req *http.Request) []attribute.KeyValue {
+	kvs := semconv.HTTPClientAttributesFromHTTPRequest(req)
+	if host := req.URL.Hostname(); host != "" {
+		kvs = append(kvs, semconv.NetPeerNameKey.String(host))
+	}
+	if port := port(req.URL.Port(), req.URL.Scheme); port > 0 {
+		kvs = append(kvs, semconv.NetPeerPortKey.Int(port))
+	}
+	return kvs
+}
+
+// HTTPServerAttrs returns the attributes of the server span handling r: its method, target, scheme, host and flavor,
+// the route when r was routed by an http.ServeMux, and the address of the client.
+func HTTPServerAttrs(r *http.Request) []attribute.KeyValue {
+	kvs := semconv.HTTPServerAttributesFromHTTPRequest("", Route(r), r)
+	return append(kvs, semconv.NetAttributesFromHTTPRequest("tcp", r)...)
+}
+
+// PeerService returns the attribute naming the service called by a client span, as the other service names itself,
+// which the backends use to draw the calls to the services which are not traced.
+func PeerService(name string) attribute.KeyValue {
+	return semconv.PeerServiceKey.String(name)
+}
+
+// Route returns the path of the pattern of the http.ServeMux which routed r, e.g. "/format" for "GET /format", or an
+// empty string when r was not routed by a mux. Unlike the path of the URL, it takes a bounded number of values, as
+// the attributes of the metrics must.
+func Route(r *http.Request) string {
+	pattern := r.Pattern
+	if _, path, ok := strings.Cut(pattern, " "); ok {
+		pattern = path
+	}
+	if i := strings.Index(pattern, "/"); i > 0 {
+		// dropping the host of the pattern, e.g. "example.com/format"
+		pattern = pattern[i:]
+	}
+	return pattern
+}
+
+// port returns the port of a URL, the default port of its scheme when it has none, or 0 when unknown
+func port(p, scheme string) int {
+	if p == "" {
+		switch scheme {
+		case "http":
+			return 80
+		case "https":
+			return 443
+		}
+		return 0
+	}
+	n, err := strconv.Atoi(p)
+	if err != nil {
+		return 0
+	}
+	return n
+}
@@ -0,0 +1,71 @@
+package attrs
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"go.opentelemetry.io/otel/attribute"
+)
+
+// values returns the attributes as a map, for the comparisons
+func values(kvs []attribute.KeyValue) map[string]string {
+	m := make(map[string]string)
+	for _, kv := range kvs {
+		m[string(kv.Key)] = kv.Value.Emit()
+	}
+	return m
+}
+
+func TestHTTPClientAttrs(t *testing.T) {
+	req, err := http.NewRequest("GET", "http://localhost:8081/format?helloTo=Bryan", nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	got := values(HTTPClientAttrs(req))
+	for key, want := range map[string]string{
+		"http.method":   "GET",
+		"http.url":      "http://localhost:8081/format?helloTo=Bryan",
+		"net.peer.name": "localhost",
+		"net.peer.port": "8081",
+	} {
+		if got[key] != want {
+			t.Errorf("%s = %q, want %q", key, got[key], want)
+		}
+	}
+}
+
+func TestHTTPServerAttrs(t *testing.T) {
+	var got map[string]string
+	mux := http.NewServeMux()
+	mux.HandleFunc("GET /format", func(w http.ResponseWriter, r *http.Request) {
+		got = values(HTTPServerAttrs(r))
+	})
+	r := httptest.NewRequest("GET", "/format?helloTo=Bryan", nil)
+	r.Host = "localhost:8081"
+	mux.ServeHTTP(httptest.NewRecorder(), r)
+
+	for key, want := range map[string]string{
+		"http.method": "GET",
+		"http.target": "/format?helloTo=Bryan",
+		"http.route":  "/format",
+		"http.host":   "localhost:8081",
+		"net.peer.ip": "192.0.2.1",
+	} {
+		if got[key] != want {
+			t.Errorf("%s = %q, want %q", key, got[key], want)
+		}
+	}
+
+	// the route is unknown without a mux
+	if got := values(HTTPServerAttrs(httptest.NewRequest("GET", "/format", nil))); got["http.route"] != "" {
+		t.Errorf("http.route = %q without a mux, want none", got["http.route"])
+	}
+}
+
+func TestPeerService(t *testing.T) {
+	if got := PeerService("formatter"); got != attribute.String("peer.service", "formatter") {
+		t.Errorf("PeerService() = %v", got)
+	}
+}
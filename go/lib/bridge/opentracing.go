@@ -0,0 +1,35 @@
+package bridge
+
+import (
+	"log"
+
+	"github.com/opentracing/opentracing-go"
+	"go.opentelemetry.io/otel"
+	otbridge "go.opentelemetry.io/otel/bridge/opentracing"
+	"go.opentelemetry.io/otel/trace"
+)
+
+// InitOpenTracing runs the code written against the OpenTracing API on top of the OpenTelemetry TracerProvider tp.
+// It creates an OpenTracing tracer forwarding its spans to a tracer of tp named name, and installs it as the global
+// OpenTracing tracer returned by opentracing.GlobalTracer.
+//
+// The global OpenTelemetry TracerProvider is replaced by a wrapper of the bridge, so that the spans started with either
+// API from a context holding a span of the other API become its children. The OpenTracing Inject and Extract use the
+// global OpenTelemetry propagator, which must be set beforehand, e.g. by tracing.InitTracerProviderWithBackend.
+func InitOpenTracing(tp trace.TracerProvider, name string) opentracing.Tracer {
+	// creating the pair of tracers sharing the same spans, one for each API
+	bridgeTracer, wrapperProvider := otbridge.NewTracerPair(tp.Tracer(name))
+
+	// propagating the OpenTracing spans with the W3C headers, like the OpenTelemetry ones
+	bridgeTracer.SetTextMapPropagator(otel.GetTextMapPropagator())
+
+	// logging the misuses of the APIs detected by the bridge, such as a span context of the wrong implementation
+	bridgeTracer.SetWarningHandler(func(msg string) {
+		log.Printf("opentracing bridge: %s", msg)
+	})
+
+	opentracing.SetGlobalTracer(bridgeTracer)
+	otel.SetTracerProvider(wrapperProvider)
+
+	return bridgeTracer
+}
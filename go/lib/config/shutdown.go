@@ -0,0 +1,86 @@
+package config
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sort"
+	"time"
+)
+
+// DEFAULT_SHUTDOWN_TIMEOUT is how long a provider is given to export its telemetry and shut down, unless told otherwise
+const DEFAULT_SHUTDOWN_TIMEOUT = 5 * time.Second
+
+// Signal is the kind of telemetry handled by a provider, which decides when the provider is shut down
+type Signal int
+
+const (
+	// LOGS, METRICS and TRACES are the signals in the order their providers are shut down: the traces last, so that the
+	// spans ended while the other providers export their telemetry are exported as well
+	LOGS Signal = iota
+	METRICS
+	TRACES
+)
+
+// String returns the name of the provider of the signal, e.g. "TracerProvider".
+func (s Signal) String() string {
+	switch s {
+	case LOGS:
+		return "LoggerProvider"
+	case METRICS:
+		return "MeterProvider"
+	case TRACES:
+		return "TracerProvider"
+	}
+	return fmt.Sprintf("Signal(%d)", int(s))
+}
+
+// shutdownStep is a provider registered with a ShutdownCoordinator
+type shutdownStep struct {
+	signal   Signal
+	timeout  time.Duration
+	shutdown func(ctx context.Context) error
+}
+
+// ShutdownCoordinator shuts down the providers of the signals of a program in a defined order, whatever the order they
+// were created in: the LoggerProvider first, then the MeterProvider, and the TracerProvider last. Each provider is given
+// a timeout of its own, so that a backend which is down does not eat the time of the others, and all the providers are
+// shut down even when one of them fails.
+type ShutdownCoordinator struct {
+	steps []shutdownStep
+}
+
+// NewShutdownCoordinator returns a ShutdownCoordinator without any provider.
+func NewShutdownCoordinator() *ShutdownCoordinator {
+	return &ShutdownCoordinator{}
+}
+
+// Add registers the Shutdown method of the provider of signal, e.g. tracerPovider.Shutdown, given timeout to return, or
+// DEFAULT_SHUTDOWN_TIMEOUT when zero. The providers of the same signal are shut down in the order they were added.
+func (c *ShutdownCoordinator) Add(signal Signal, timeout time.Duration, shutdown func(ctx context.Context) error) {
+	if timeout <= 0 {
+		timeout = DEFAULT_SHUTDOWN_TIMEOUT
+	}
+	c.steps = append(c.steps, shutdownStep{signal: signal, timeout: timeout, shutdown: shutdown})
+}
+
+// Shutdown shuts down the providers, and returns their errors joined, each one prefixed with the name of its provider.
+// The providers are shut down even when ctx is canceled, e.g. by the interrupt ending the program, with the values of
+// ctx and their own timeouts.
+func (c *ShutdownCoordinator) Shutdown(ctx context.Context) error {
+	steps := append([]shutdownStep(nil), c.steps...)
+	sort.SliceStable(steps, func(i, j int) bool { return steps[i].signal < steps[j].signal })
+
+	// shutting down the providers even when the program is being interrupted, which is when it matters the most
+	ctx = context.WithoutCancel(ctx)
+
+	var errs []error
+	for _, step := range steps {
+		stepCtx, cancel := context.WithTimeout(ctx, step.timeout)
+		if err := step.shutdown(stepCtx); err != nil {
+			errs = append(errs, fmt.Errorf("failed to shutdown %s: %w", step.signal, err))
+		}
+		cancel()
+	}
+	return errors.Join(errs...)
+}
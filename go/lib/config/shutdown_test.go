@@ -0,0 +1,72 @@
+package config
+
+import (
+	"context"
+	"errors"
+	"reflect"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestShutdownCoordinator(t *testing.T) {
+	var order []string
+	record := func(name string, err error) func(ctx context.Context) error {
+		return func(ctx context.Context) error {
+			order = append(order, name)
+			return err
+		}
+	}
+
+	// the providers are added in the order a program creates them, the tracer provider first
+	c := NewShutdownCoordinator()
+	c.Add(TRACES, 0, record("traces", nil))
+	c.Add(METRICS, 0, record("metrics", errors.New("connection refused")))
+	c.Add(TRACES, 0, record("audit traces", nil))
+	c.Add(LOGS, 0, record("logs", errors.New("timeout")))
+
+	err := c.Shutdown(context.Background())
+
+	// every provider is shut down despite the failures, the traces last
+	if want := []string{"logs", "metrics", "traces", "audit traces"}; !reflect.DeepEqual(order, want) {
+		t.Errorf("shut down %v, want %v", order, want)
+	}
+	if err == nil {
+		t.Fatal("Shutdown() = nil, want the errors of the providers")
+	}
+	for _, want := range []string{"failed to shutdown LoggerProvider: timeout", "failed to shutdown MeterProvider: connection refused"} {
+		if !strings.Contains(err.Error(), want) {
+			t.Errorf("Shutdown() = %q, want it to contain %q", err, want)
+		}
+	}
+}
+
+func TestShutdownCoordinatorTimeouts(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+
+	// a provider stuck on a backend which is down only eats its own timeout
+	c := NewShutdownCoordinator()
+	c.Add(METRICS, 10*time.Millisecond, func(ctx context.Context) error {
+		<-ctx.Done()
+		return ctx.Err()
+	})
+	var remaining time.Duration
+	c.Add(TRACES, time.Minute, func(ctx context.Context) error {
+		// the canceled context of the caller is not passed on
+		if err := ctx.Err(); err != nil {
+			return err
+		}
+		deadline, _ := ctx.Deadline()
+		remaining = time.Until(deadline)
+		return nil
+	})
+
+	err := c.Shutdown(ctx)
+	if !errors.Is(err, context.DeadlineExceeded) || strings.Contains(err.Error(), "TracerProvider") {
+		t.Errorf("Shutdown() = %v, want the deadline of the MeterProvider only", err)
+	}
+	if remaining < 50*time.Second {
+		t.Errorf("TracerProvider given %v, want its own timeout", remaining)
+	}
+}
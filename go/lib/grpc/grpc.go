@@ -0,0 +1,99 @@
+package xgrpc
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/legosandorigami/opentelemetry-tutorial/go/lib/tracing"
+	"go.opentelemetry.io/contrib/instrumentation/google.golang.org/grpc/otelgrpc"
+	"go.opentelemetry.io/otel/trace"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/credentials/insecure"
+	"google.golang.org/grpc/stats"
+	"google.golang.org/grpc/status"
+)
+
+// RETRY_POLICY is the service config of the connections created by Dial: the RPCs failing with UNAVAILABLE, the code
+// of a server which is down or restarting, are attempted up to 4 times, waiting 100ms then up to twice as long before
+// each new attempt. Every attempt is traced by a client span of its own.
+const RETRY_POLICY = `{
+	"methodConfig": [{
+		"name": [{}],
+		"retryPolicy": {
+			"maxAttempts": 4,
+			"initialBackoff": "0.1s",
+			"maxBackoff": "1s",
+			"backoffMultiplier": 2,
+			"retryableStatusCodes": ["UNAVAILABLE"]
+		}
+	}]
+}`
+
+// messageEvents makes the stats handlers record every message sent and received as an event of the RPC span, which
+// shows the activity inside streaming RPCs.
+var messageEvents = otelgrpc.WithMessageEvents(otelgrpc.ReceivedEvents, otelgrpc.SentEvents)
+
+// ServerHandler returns the otelgrpc stats handler tracing the incoming RPCs, recording the messages as events. The
+// options are applied after those, e.g. otelgrpc.WithTracerProvider(tracerPovider).
+func ServerHandler(opts ...otelgrpc.Option) stats.Handler {
+	return otelgrpc.NewServerHandler(append([]otelgrpc.Option{messageEvents}, opts...)...)
+}
+
+// ClientHandler returns the otelgrpc stats handler tracing the outgoing RPCs, recording the messages as events. The
+// options are applied after those.
+func ClientHandler(opts ...otelgrpc.Option) stats.Handler {
+	return otelgrpc.NewClientHandler(append([]otelgrpc.Option{messageEvents}, opts...)...)
+}
+
+// NewServer creates a gRPC server whose incoming RPCs are traced by the otelgrpc stats handler.
+// The handler extracts the span context and baggage from the request metadata using the global propagator.
+// A panic of a method is recovered: it is recorded in the span, and the caller gets an INTERNAL error.
+func NewServer(opts ...grpc.ServerOption) *grpc.Server {
+	opts = append([]grpc.ServerOption{
+		grpc.StatsHandler(ServerHandler()),
+		grpc.ChainUnaryInterceptor(recoverUnary),
+		grpc.ChainStreamInterceptor(recoverStream),
+	}, opts...)
+	return grpc.NewServer(opts...)
+}
+
+// Dial creates a plaintext client connection to target whose outgoing RPCs are traced by the otelgrpc stats handler,
+// and retried following RETRY_POLICY. The handler injects the span context and baggage into the request metadata
+// using the global propagator. The options are applied after those, e.g. grpc.WithDefaultServiceConfig to replace
+// the retry policy.
+func Dial(target string, opts ...grpc.DialOption) (*grpc.ClientConn, error) {
+	opts = append([]grpc.DialOption{
+		grpc.WithTransportCredentials(insecure.NewCredentials()),
+		grpc.WithStatsHandler(ClientHandler()),
+		grpc.WithDefaultServiceConfig(RETRY_POLICY),
+	}, opts...)
+	return grpc.NewClient(target, opts...)
+}
+
+// recoverUnary recovers a panic of a unary method, turning it into an INTERNAL error
+func recoverUnary(ctx context.Context, req any, info *grpc.UnaryServerInfo, handler grpc.UnaryHandler) (resp any, err error) {
+	defer func() {
+		if p := recover(); p != nil {
+			err = recovered(ctx, p)
+		}
+	}()
+	return handler(ctx, req)
+}
+
+// recoverStream recovers a panic of a streaming method, turning it into an INTERNAL error
+func recoverStream(srv any, ss grpc.ServerStream, info *grpc.StreamServerInfo, handler grpc.StreamHandler) (err error) {
+	defer func() {
+		if p := recover(); p != nil {
+			err = recovered(ss.Context(), p)
+		}
+	}()
+	return handler(srv, ss)
+}
+
+// recovered records the panic p in the span of ctx, the one of the stats handler, and returns the error sent to the
+// caller in its place, which does not reveal the panic
+func recovered(ctx context.Context, p any) error {
+	tracing.RecordFailure(trace.SpanFromContext(ctx), fmt.Errorf("panic: %v", p))
+	return status.Error(codes.Internal, "internal error")
+}
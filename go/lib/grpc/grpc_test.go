@@ -0,0 +1,126 @@
+package xgrpc
+
+import (
+	"context"
+	"net"
+	"testing"
+
+	"github.com/legosandorigami/opentelemetry-tutorial/go/lib/tracingtest"
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/codes"
+	"go.opentelemetry.io/otel/propagation"
+	"go.opentelemetry.io/otel/trace"
+	grpcCodes "google.golang.org/grpc/codes"
+	"google.golang.org/grpc/health/grpc_health_v1"
+	"google.golang.org/grpc/status"
+)
+
+// healthServer fails the first checks with UNAVAILABLE, then panics for the service "panic" and answers SERVING for
+// the others
+type healthServer struct {
+	grpc_health_v1.UnimplementedHealthServer
+	unavailable int
+}
+
+func (h *healthServer) Check(ctx context.Context, req *grpc_health_v1.HealthCheckRequest) (*grpc_health_v1.HealthCheckResponse, error) {
+	if h.unavailable > 0 {
+		h.unavailable--
+		return nil, status.Error(grpcCodes.Unavailable, "restarting")
+	}
+	if req.Service == "panic" {
+		panic("boom")
+	}
+	return &grpc_health_v1.HealthCheckResponse{Status: grpc_health_v1.HealthCheckResponse_SERVING}, nil
+}
+
+// serve starts a server created by NewServer, and returns a connection to it created by Dial
+func serve(t *testing.T, h *healthServer) grpc_health_v1.HealthClient {
+	l, err := net.Listen("tcp", "localhost:0")
+	if err != nil {
+		t.Fatal(err)
+	}
+	server := NewServer()
+	grpc_health_v1.RegisterHealthServer(server, h)
+	go server.Serve(l)
+	t.Cleanup(server.Stop)
+
+	conn, err := Dial(l.Addr().String())
+	if err != nil {
+		t.Fatal(err)
+	}
+	t.Cleanup(func() { conn.Close() })
+	return grpc_health_v1.NewHealthClient(conn)
+}
+
+// CHECK_SPAN is the name of the client and server spans of a health check
+const CHECK_SPAN = "grpc.health.v1.Health/Check"
+
+// checkSpan describes the client span of a health check attempt, whose child is the server span, the trace context
+// being propagated to the server
+func checkSpan(code codes.Code, serverMatchers ...tracingtest.Matcher) tracingtest.Span {
+	return tracingtest.Span{
+		Name:     CHECK_SPAN,
+		Matchers: []tracingtest.Matcher{tracingtest.HasKind(trace.SpanKindClient), tracingtest.HasStatus(code)},
+		Children: []tracingtest.Span{{
+			Name:     CHECK_SPAN,
+			Matchers: append([]tracingtest.Matcher{tracingtest.HasKind(trace.SpanKindServer), tracingtest.HasRemoteParent()}, serverMatchers...),
+		}},
+	}
+}
+
+func TestDialRetriesUnavailable(t *testing.T) {
+	defer otel.SetTextMapPropagator(otel.GetTextMapPropagator())
+	otel.SetTextMapPropagator(propagation.TraceContext{})
+	tp, sr := tracingtest.NewRecorder()
+	defer otel.SetTracerProvider(otel.GetTracerProvider())
+	otel.SetTracerProvider(tp)
+
+	client := serve(t, &healthServer{unavailable: 2})
+	// grouping the attempts under a span of the caller
+	ctx, span := otel.Tracer("test").Start(context.Background(), "check")
+	resp, err := client.Check(ctx, &grpc_health_v1.HealthCheckRequest{})
+	span.End()
+	if err != nil {
+		t.Fatalf("the RPC failed despite the retries: %v", err)
+	}
+	if resp.Status != grpc_health_v1.HealthCheckResponse_SERVING {
+		t.Errorf("status = %v, want SERVING", resp.Status)
+	}
+
+	// one client span per attempt, the first two failed with UNAVAILABLE
+	tracingtest.AssertSpanTree(t, sr, tracingtest.Span{
+		Name: "check",
+		Children: []tracingtest.Span{
+			checkSpan(codes.Error, tracingtest.HasStatus(codes.Error)),
+			checkSpan(codes.Error, tracingtest.HasStatus(codes.Error)),
+			checkSpan(codes.Unset, tracingtest.HasStatus(codes.Unset)),
+		},
+	})
+}
+
+func TestNewServerRecoversPanics(t *testing.T) {
+	defer otel.SetTextMapPropagator(otel.GetTextMapPropagator())
+	otel.SetTextMapPropagator(propagation.TraceContext{})
+	tp, sr := tracingtest.NewRecorder()
+	defer otel.SetTracerProvider(otel.GetTracerProvider())
+	otel.SetTracerProvider(tp)
+
+	client := serve(t, &healthServer{})
+	_, err := client.Check(context.Background(), &grpc_health_v1.HealthCheckRequest{Service: "panic"})
+	if status.Code(err) != grpcCodes.Internal {
+		t.Fatalf("err = %v, want an INTERNAL error", err)
+	}
+	// the server span records the panic
+	tracingtest.AssertSpanTree(t, sr, checkSpan(codes.Error,
+		tracingtest.HasStatus(codes.Error),
+		tracingtest.HasEvent("exception", attribute.String("exception.message", "panic: boom")),
+	))
+
+	// the server keeps serving
+	sr.Reset()
+	if _, err := client.Check(context.Background(), &grpc_health_v1.HealthCheckRequest{}); err != nil {
+		t.Fatalf("the server failed after the panic: %v", err)
+	}
+	tracingtest.AssertSpanTree(t, sr, checkSpan(codes.Unset, tracingtest.HasStatus(codes.Unset)))
+}
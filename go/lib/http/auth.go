@@ -0,0 +1,92 @@
+package xhttp
+
+import (
+	"context"
+	"crypto/subtle"
+	"net/http"
+
+	"go.opentelemetry.io/otel/baggage"
+	"go.opentelemetry.io/otel/propagation"
+)
+
+const (
+	// API_KEY_HEADER is the request header carrying the API key
+	API_KEY_HEADER = "X-API-Key"
+	// USER_ID_BAGGAGE_KEY is the baggage member carrying the identity of the caller once authenticated
+	USER_ID_BAGGAGE_KEY = "user.id"
+)
+
+// userIDKey is the context key of the user ID authenticated by Auth
+type userIDKey struct{}
+
+// UserID returns the user ID authenticated by the Auth middleware for the request of ctx, and false when the request
+// went through no Auth middleware. Unlike the "user.id" member of the baggage, which any caller can send, it can be
+// recorded as the identity of the caller.
+func UserID(ctx context.Context) (string, bool) {
+	userID, ok := ctx.Value(userIDKey{}).(string)
+	return userID, ok
+}
+
+// Auth returns a middleware rejecting the requests without a valid API key, keys mapping the API keys to user IDs.
+// The user ID of an authenticated request is made available to the handler by UserID, and written into the "user.id"
+// member of its baggage header, replacing any value sent by the caller, so that the services called with the
+// propagated baggage know the identity of the caller.
+func Auth(keys map[string]string) Middleware {
+	return func(next http.Handler) http.Handler {
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			userID, ok := lookupKey(keys, r.Header.Get(API_KEY_HEADER))
+			if !ok {
+				http.Error(w, "invalid API key", http.StatusUnauthorized)
+				return
+			}
+
+			member, err := baggage.NewMember(USER_ID_BAGGAGE_KEY, userID)
+			if err != nil {
+				http.Error(w, "invalid user ID", http.StatusInternalServerError)
+				return
+			}
+
+			// rewriting the baggage header of the request with the authenticated user ID
+			carrier := propagation.HeaderCarrier(r.Header)
+			b, err := baggage.FromContext(propagation.Baggage{}.Extract(r.Context(), carrier)).SetMember(member)
+			if err != nil {
+				http.Error(w, "invalid baggage", http.StatusBadRequest)
+				return
+			}
+			propagation.Baggage{}.Inject(baggage.ContextWithBaggage(r.Context(), b), carrier)
+
+			next.ServeHTTP(w, r.WithContext(context.WithValue(r.Context(), userIDKey{}, userID)))
+		})
+	}
+}
+
+// lookupKey returns the user ID of the API key, comparing the keys in constant time
+func lookupKey(keys map[string]string, key string) (string, bool) {
+	if key == "" {
+		return "", false
+	}
+	for k, userID := range keys {
+		if subtle.ConstantTimeCompare([]byte(k), []byte(key)) == 1 {
+			return userID, true
+		}
+	}
+	return "", false
+}
+
+// apiKeyTransport is an http.RoundTripper adding the API key header to every request
+type apiKeyTransport struct {
+	key  string
+	next http.RoundTripper
+}
+
+// NewAPIKeyTransport returns an http.RoundTripper sending key in the X-API-Key header of every request made through next.
+func NewAPIKeyTransport(key string, next http.RoundTripper) http.RoundTripper {
+	return &apiKeyTransport{key: key, next: next}
+}
+
+func (t *apiKeyTransport) RoundTrip(req *http.Request) (*http.Response, error) {
+	// a RoundTripper must not modify the request it was given
+	req = req.Clone(req.Context())
+	req.Header.Set(API_KEY_HEADER, t.key)
+	return t.next.RoundTrip(req)
+}
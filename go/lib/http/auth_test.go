@@ -0,0 +1,79 @@
+package xhttp
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+)
+
+func TestAuthUserID(t *testing.T) {
+	var userID string
+	var authenticated bool
+	handler := func(w http.ResponseWriter, r *http.Request) {
+		userID, authenticated = UserID(r.Context())
+	}
+
+	for _, tt := range []struct {
+		name       string
+		handler    http.Handler
+		key        string
+		wantStatus int
+		wantUserID string
+		wantOK     bool
+	}{
+		{"valid key", Chain(http.HandlerFunc(handler), Auth(map[string]string{"s3cr3t": "alice"})), "s3cr3t", http.StatusOK, "alice", true},
+		{"invalid key", Chain(http.HandlerFunc(handler), Auth(map[string]string{"s3cr3t": "alice"})), "guess", http.StatusUnauthorized, "", false},
+		// without the middleware, the user.id member sent by the caller authenticates nobody
+		{"no middleware", http.HandlerFunc(handler), "", http.StatusOK, "", false},
+	} {
+		t.Run(tt.name, func(t *testing.T) {
+			userID, authenticated = "", false
+			req := httptest.NewRequest("GET", "/format?helloTo=Brian", nil)
+			req.Header.Set("Baggage", USER_ID_BAGGAGE_KEY+"=mallory")
+			if tt.key != "" {
+				req.Header.Set(API_KEY_HEADER, tt.key)
+			}
+			rec := httptest.NewRecorder()
+			tt.handler.ServeHTTP(rec, req)
+
+			if rec.Code != tt.wantStatus {
+				t.Errorf("status = %d, want %d", rec.Code, tt.wantStatus)
+			}
+			if userID != tt.wantUserID || authenticated != tt.wantOK {
+				t.Errorf("UserID = %q, %v, want %q, %v", userID, authenticated, tt.wantUserID, tt.wantOK)
+			}
+		})
+	}
+}
+
+func TestAPIKeyTransportAuthenticatesTheCallingService(t *testing.T) {
+	// the publisher, accepting the key of the formatter only
+	var userID string
+	publisher := httptest.NewServer(Chain(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		userID, _ = UserID(r.Context())
+	}), Auth(map[string]string{"f0rm4tt3r": "formatter"})))
+	defer publisher.Close()
+
+	for _, tt := range []struct {
+		name       string
+		transport  http.RoundTripper
+		wantStatus int
+		wantUserID string
+	}{
+		{"with the key of the service", NewAPIKeyTransport("f0rm4tt3r", http.DefaultTransport), http.StatusOK, "formatter"},
+		{"without key", http.DefaultTransport, http.StatusUnauthorized, ""},
+	} {
+		t.Run(tt.name, func(t *testing.T) {
+			userID = ""
+			client := &http.Client{Transport: tt.transport}
+			res, err := client.Get(publisher.URL + "/publish?helloStr=Hello")
+			if err != nil {
+				t.Fatal(err)
+			}
+			res.Body.Close()
+			if res.StatusCode != tt.wantStatus || userID != tt.wantUserID {
+				t.Errorf("status = %d, user.id = %q, want %d, %q", res.StatusCode, userID, tt.wantStatus, tt.wantUserID)
+			}
+		})
+	}
+}
@@ -0,0 +1,76 @@
+package xhttp
+
+import (
+	"math"
+	"math/rand/v2"
+	"net/http"
+	"strconv"
+	"time"
+
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/baggage"
+	"go.opentelemetry.io/otel/propagation"
+)
+
+// CHAOS_BAGGAGE_KEY is the baggage member a caller can set to override the failure rate of the services downstream,
+// when they allow it with ChaosOptions.BaggageOverride.
+const CHAOS_BAGGAGE_KEY = "chaos.rate"
+
+// ChaosOptions configures the failures injected by the Chaos middleware.
+type ChaosOptions struct {
+	// Rate is the probability, between 0 and 1, that a request is affected
+	Rate float64
+	// Delay is how long a delayed request is held before being served
+	Delay time.Duration
+	// BaggageOverride lets the callers override Rate with the "chaos.rate" baggage member. Any caller can then fail the
+	// requests, so it is meant for test environments only.
+	BaggageOverride bool
+}
+
+// Chaos returns a middleware that randomly fails a fraction of the requests, either by answering
+// with a 500, by delaying them, or by dropping the connection without a response.
+// When opts.BaggageOverride is set, the rate can be overridden per request with the "chaos.rate" baggage member.
+func Chaos(opts ChaosOptions) Middleware {
+	return func(next http.Handler) http.Handler {
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			rate := opts.Rate
+
+			// the baggage is extracted here as the handler has not seen the request yet
+			if opts.BaggageOverride {
+				ctx := otel.GetTextMapPropagator().Extract(r.Context(), propagation.HeaderCarrier(r.Header))
+				if v := baggage.FromContext(ctx).Member(CHAOS_BAGGAGE_KEY).Value(); v != "" {
+					if f, err := strconv.ParseFloat(v, 64); err == nil && !math.IsNaN(f) {
+						rate = f
+					}
+				}
+			}
+
+			// clamping the rate to [0, 1], a NaN rate of the options, which would fail every comparison, counting as 0
+			if math.IsNaN(rate) {
+				rate = 0
+			}
+			rate = max(0, min(rate, 1))
+
+			if rate == 0 || rand.Float64() >= rate {
+				next.ServeHTTP(w, r)
+				return
+			}
+
+			switch rand.IntN(3) {
+			case 0:
+				http.Error(w, "chaos: injected failure", http.StatusInternalServerError)
+			case 1:
+				time.Sleep(opts.Delay)
+				next.ServeHTTP(w, r)
+			default:
+				// dropping the request by closing the underlying connection without answering
+				conn, _, err := http.NewResponseController(w).Hijack()
+				if err != nil {
+					http.Error(w, "chaos: injected failure", http.StatusInternalServerError)
+					return
+				}
+				conn.Close()
+			}
+		})
+	}
+}
@@ -0,0 +1,56 @@
+package xhttp
+
+import (
+	"math"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/propagation"
+)
+
+func TestChaosRate(t *testing.T) {
+	defer otel.SetTextMapPropagator(otel.GetTextMapPropagator())
+	otel.SetTextMapPropagator(propagation.Baggage{})
+
+	for _, tt := range []struct {
+		name    string
+		opts    ChaosOptions
+		baggage string
+		// wantFailures is whether some of the requests fail; the delayed ones are still served, so not all of them do
+		wantFailures bool
+	}{
+		{"no chaos", ChaosOptions{}, "", false},
+		{"rate of 1", ChaosOptions{Rate: 1}, "", true},
+		{"rate above 1", ChaosOptions{Rate: 5}, "", true},
+		{"negative rate", ChaosOptions{Rate: -1}, "", false},
+		{"NaN rate", ChaosOptions{Rate: math.NaN()}, "", false},
+		{"override not allowed", ChaosOptions{}, CHAOS_BAGGAGE_KEY + "=1", false},
+		{"override", ChaosOptions{BaggageOverride: true}, CHAOS_BAGGAGE_KEY + "=1", true},
+		{"override to 0", ChaosOptions{Rate: 1, BaggageOverride: true}, CHAOS_BAGGAGE_KEY + "=0", false},
+		{"NaN override", ChaosOptions{BaggageOverride: true}, CHAOS_BAGGAGE_KEY + "=NaN", false},
+	} {
+		t.Run(tt.name, func(t *testing.T) {
+			handler := Chaos(tt.opts)(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {}))
+
+			failures := 0
+			for range 50 {
+				req := httptest.NewRequest("GET", "/", nil)
+				if tt.baggage != "" {
+					req.Header.Set("Baggage", tt.baggage)
+				}
+				// the recorder cannot be hijacked, so the dropped requests are answered with a 500 as well
+				rec := httptest.NewRecorder()
+				handler.ServeHTTP(rec, req)
+				if rec.Code != http.StatusOK {
+					failures++
+				}
+			}
+
+			if got := failures > 0; got != tt.wantFailures {
+				t.Errorf("%d failures out of 50 requests, want failures: %v", failures, tt.wantFailures)
+			}
+		})
+	}
+}
@@ -4,10 +4,31 @@ import (
 	"fmt"
 	"io"
 	"net/http"
+
+	"go.opentelemetry.io/otel/attribute"
+	semconv "go.opentelemetry.io/otel/semconv/v1.4.0"
+	"go.opentelemetry.io/otel/trace"
 )
 
+// StatusError is the error returned by Do for a response whose status code is not 200.
+type StatusError struct {
+	StatusCode int
+	Body       []byte
+}
+
+func (e *StatusError) Error() string {
+	return fmt.Sprintf("StatusCode: %d, Body: %s", e.StatusCode, e.Body)
+}
+
+// Retryable reports whether the same request may succeed later: a 4xx status code is a mistake of the caller, which
+// fails again, except for 429 Too Many Requests.
+func (e *StatusError) Retryable() bool {
+	return e.StatusCode >= 500 || e.StatusCode == http.StatusTooManyRequests
+}
+
 // Do executes an HTTP request and returns the response body.
-// Any errors or non-200 status code result in an error.
+// Any errors or non-200 status code result in an error, a *StatusError for the latter.
+// The protocol negotiated for the request is recorded in the http.flavor attribute of the span in the request context.
 func Do(req *http.Request) ([]byte, error) {
 	resp, err := http.DefaultClient.Do(req)
 	if err != nil {
@@ -15,14 +36,28 @@ func Do(req *http.Request) ([]byte, error) {
 	}
 	defer resp.Body.Close()
 
+	trace.SpanFromContext(req.Context()).SetAttributes(Flavor(resp.ProtoMajor, resp.ProtoMinor))
+
 	body, err := io.ReadAll(resp.Body)
 	if err != nil {
 		return nil, err
 	}
 
 	if resp.StatusCode != 200 {
-		return nil, fmt.Errorf("StatusCode: %d, Body: %s", resp.StatusCode, body)
+		return nil, &StatusError{StatusCode: resp.StatusCode, Body: body}
 	}
 
 	return body, nil
 }
+
+// Flavor returns the http.flavor attribute matching the version of the HTTP protocol.
+func Flavor(major, minor int) attribute.KeyValue {
+	switch {
+	case major == 2:
+		return semconv.HTTPFlavorHTTP20
+	case major == 1 && minor == 0:
+		return semconv.HTTPFlavorHTTP10
+	default:
+		return semconv.HTTPFlavorHTTP11
+	}
+}
@@ -0,0 +1,44 @@
+package xhttp
+
+import "net/http"
+
+// CORS_ALLOWED_HEADERS are the request headers a page of another origin may send: the trace context and the baggage
+const CORS_ALLOWED_HEADERS = "traceparent, tracestate, baggage"
+
+// CORS returns a middleware letting the pages served from the given origins, e.g. "http://localhost:8083", call the
+// handler from the browser with the trace context headers. Sending a traceparent header makes the request non-simple,
+// so the browser first sends a preflight OPTIONS request, answered here without calling the handler. The requests of
+// the other origins are served without the CORS headers, and the browser refuses to hand their response to the page.
+func CORS(origins ...string) Middleware {
+	allowed := make(map[string]bool, len(origins))
+	for _, origin := range origins {
+		allowed[origin] = true
+	}
+
+	return func(next http.Handler) http.Handler {
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			// varying on the origin even when refusing it, so that a cache does not hand the response of one origin to another
+			h := w.Header()
+			h.Add("Vary", "Origin")
+
+			origin := r.Header.Get("Origin")
+			if !allowed[origin] {
+				next.ServeHTTP(w, r)
+				return
+			}
+
+			h.Set("Access-Control-Allow-Origin", origin)
+
+			// answering the preflight request, which carries no trace context as the browser sends no custom header with it
+			if r.Method == http.MethodOptions && r.Header.Get("Access-Control-Request-Method") != "" {
+				h.Set("Access-Control-Allow-Methods", "GET, POST")
+				h.Set("Access-Control-Allow-Headers", CORS_ALLOWED_HEADERS)
+				h.Set("Access-Control-Max-Age", "600")
+				w.WriteHeader(http.StatusNoContent)
+				return
+			}
+
+			next.ServeHTTP(w, r)
+		})
+	}
+}
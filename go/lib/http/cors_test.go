@@ -0,0 +1,48 @@
+package xhttp
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+)
+
+func TestCORS(t *testing.T) {
+	handler := CORS("http://localhost:8083")(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Write([]byte("Hello, Bryan!"))
+	}))
+
+	for _, tc := range []struct {
+		name, method, origin, preflight string
+		wantCode                        int
+		wantAllowOrigin                 string
+	}{
+		{"allowed origin", "GET", "http://localhost:8083", "", http.StatusOK, "http://localhost:8083"},
+		{"preflight", "OPTIONS", "http://localhost:8083", "GET", http.StatusNoContent, "http://localhost:8083"},
+		{"other origin", "GET", "http://evil.example", "", http.StatusOK, ""},
+		{"other origin preflight", "OPTIONS", "http://evil.example", "GET", http.StatusOK, ""},
+		{"no origin", "GET", "", "", http.StatusOK, ""},
+	} {
+		t.Run(tc.name, func(t *testing.T) {
+			r := httptest.NewRequest(tc.method, "/format", nil)
+			if tc.origin != "" {
+				r.Header.Set("Origin", tc.origin)
+			}
+			if tc.preflight != "" {
+				r.Header.Set("Access-Control-Request-Method", tc.preflight)
+			}
+			w := httptest.NewRecorder()
+			handler.ServeHTTP(w, r)
+
+			if w.Code != tc.wantCode {
+				t.Errorf("status = %d, want %d", w.Code, tc.wantCode)
+			}
+			if got := w.Header().Get("Access-Control-Allow-Origin"); got != tc.wantAllowOrigin {
+				t.Errorf("Access-Control-Allow-Origin = %q, want %q", got, tc.wantAllowOrigin)
+			}
+			// every response varies on the origin, or a cache could serve the one of an origin to another
+			if got := w.Header().Values("Vary"); len(got) != 1 || got[0] != "Origin" {
+				t.Errorf("Vary = %q, want [Origin]", got)
+			}
+		})
+	}
+}
@@ -0,0 +1,55 @@
+package xhttp
+
+import (
+	"context"
+	"net/http"
+	"strconv"
+	"time"
+
+	"go.opentelemetry.io/otel/attribute"
+	semconv "go.opentelemetry.io/otel/semconv/v1.4.0"
+	"go.opentelemetry.io/otel/trace"
+)
+
+// TIMEOUT_HEADER carries the time the caller has left for the request, in milliseconds. Like the grpc-timeout header of
+// gRPC, it holds a duration rather than a point in time, which the clocks of the two hosts could disagree on.
+const TIMEOUT_HEADER = "X-Request-Timeout-Ms"
+
+// InjectDeadline records the time left before the deadline of ctx in the TIMEOUT_HEADER of the request headers, next
+// to the trace context, if ctx has a deadline.
+func InjectDeadline(ctx context.Context, header http.Header) {
+	deadline, ok := ctx.Deadline()
+	if !ok {
+		return
+	}
+	header.Set(TIMEOUT_HEADER, strconv.FormatInt(time.Until(deadline).Milliseconds(), 10))
+}
+
+// ExtractDeadline returns a copy of ctx ending when the time left by the caller in the TIMEOUT_HEADER of the request
+// runs out, and records that time in the deadline.remaining_ms attribute of the span in ctx. The context is already
+// done when the caller had no time left, which DeadlineExceeded tells. Without the header, ctx is returned unchanged.
+// The returned function must be called once the request is handled, as with context.WithTimeout.
+func ExtractDeadline(ctx context.Context, r *http.Request) (context.Context, context.CancelFunc) {
+	ms, err := strconv.ParseInt(r.Header.Get(TIMEOUT_HEADER), 10, 64)
+	if err != nil {
+		return ctx, func() {}
+	}
+
+	trace.SpanFromContext(ctx).SetAttributes(attribute.Int64("deadline.remaining_ms", ms))
+	return context.WithTimeout(ctx, time.Duration(ms)*time.Millisecond)
+}
+
+// DeadlineExceeded answers the request with a 504 Gateway Timeout when the deadline of ctx has passed, recording a
+// "deadline exceeded" event and the status code in the span in ctx, and reports whether it did: the caller has given up
+// on the request, so the handler had better stop working on it.
+func DeadlineExceeded(ctx context.Context, w http.ResponseWriter) bool {
+	if ctx.Err() != context.DeadlineExceeded {
+		return false
+	}
+
+	span := trace.SpanFromContext(ctx)
+	span.AddEvent("deadline exceeded")
+	span.SetAttributes(semconv.HTTPStatusCodeKey.Int(http.StatusGatewayTimeout))
+	http.Error(w, "deadline exceeded", http.StatusGatewayTimeout)
+	return true
+}
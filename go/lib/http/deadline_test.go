@@ -0,0 +1,68 @@
+package xhttp
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"strconv"
+	"testing"
+	"time"
+
+	"github.com/legosandorigami/opentelemetry-tutorial/go/lib/tracingtest"
+	"go.opentelemetry.io/otel/attribute"
+)
+
+func TestDeadlinePropagation(t *testing.T) {
+	// the caller has two seconds left
+	ctx, cancel := context.WithTimeout(context.Background(), 2*time.Second)
+	defer cancel()
+	header := http.Header{}
+	InjectDeadline(ctx, header)
+	ms, err := strconv.Atoi(header.Get(TIMEOUT_HEADER))
+	if err != nil || ms <= 1900 || ms > 2000 {
+		t.Fatalf("%s = %q, want about 2000", TIMEOUT_HEADER, header.Get(TIMEOUT_HEADER))
+	}
+
+	// the server ends the request when the time of the caller runs out, and records it in its span
+	tp, sr := tracingtest.NewRecorder()
+	tracer := tp.Tracer("test")
+	r := httptest.NewRequest("GET", "/format", nil)
+	r.Header = header
+	serverCtx, span := tracer.Start(context.Background(), "format")
+	serverCtx, cancelServer := ExtractDeadline(serverCtx, r)
+	defer cancelServer()
+	span.End()
+
+	deadline, ok := serverCtx.Deadline()
+	if left := time.Until(deadline); !ok || left <= 1800*time.Millisecond || left > 2*time.Second {
+		t.Errorf("deadline in %v, want about 2s", left)
+	}
+	tracingtest.AssertSpanTree(t, sr, tracingtest.Span{
+		Name:     "format",
+		Matchers: []tracingtest.Matcher{tracingtest.HasAttributes(attribute.Int("deadline.remaining_ms", ms))},
+	})
+
+	// a caller out of time gets a context which is already done
+	r.Header.Set(TIMEOUT_HEADER, "-5")
+	expiredCtx, cancelExpired := ExtractDeadline(context.Background(), r)
+	defer cancelExpired()
+	w := httptest.NewRecorder()
+	if !DeadlineExceeded(expiredCtx, w) || w.Code != http.StatusGatewayTimeout {
+		t.Errorf("DeadlineExceeded() answered %d, want 504 for a caller out of time", w.Code)
+	}
+
+	// without a deadline, nothing is injected nor extracted
+	header = http.Header{}
+	InjectDeadline(context.Background(), header)
+	if len(header) != 0 {
+		t.Errorf("injected %v, want nothing", header)
+	}
+	plainCtx, cancelPlain := ExtractDeadline(context.Background(), httptest.NewRequest("GET", "/format", nil))
+	defer cancelPlain()
+	if _, ok := plainCtx.Deadline(); ok {
+		t.Error("a deadline was extracted from a request without one")
+	}
+	if DeadlineExceeded(plainCtx, httptest.NewRecorder()) {
+		t.Error("DeadlineExceeded() = true without a deadline")
+	}
+}
@@ -0,0 +1,126 @@
+package xhttp
+
+import (
+	"io"
+	"log/slog"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/baggage"
+	"go.opentelemetry.io/otel/propagation"
+	"go.opentelemetry.io/otel/trace"
+)
+
+// The fuzz targets feed hostile trace context and baggage headers to the middlewares extracting them. Without -fuzz,
+// go test runs the seeds only; to look for new failing inputs, run one target at a time, e.g.
+//
+//	go test ./lib/http -run XXX -fuzz FuzzAuth -fuzztime 30s
+
+// hostileBaggage are baggage headers a caller may send: malformed, oversized, or trying to set the members the
+// services trust
+var hostileBaggage = []string{
+	"",
+	"user.id=mallory",
+	"user.id=mallory,user.id=eve;p=1",
+	"chaos.rate=1",
+	"chaos.rate=NaN,chaos.rate=-Inf",
+	"%zz=%zz",
+	"=,=;=",
+	"key=" + strings.Repeat("v", 9000),
+	strings.Repeat("k=v,", 200),
+	"k=v;" + strings.Repeat(";p", 500),
+}
+
+// newHeaderRequest returns a request with the given header values, unvalidated as they may arrive from the wire
+func newHeaderRequest(headers map[string]string) *http.Request {
+	req := httptest.NewRequest("GET", "/", nil)
+	for k, v := range headers {
+		req.Header[k] = []string{v}
+	}
+	return req
+}
+
+func FuzzAuth(f *testing.F) {
+	for _, b := range hostileBaggage {
+		f.Add("s3cr3t", b)
+		f.Add("wrong", b)
+	}
+
+	f.Fuzz(func(t *testing.T, key, baggageHeader string) {
+		called := false
+		var forwarded baggage.Baggage
+		handler := Auth(map[string]string{"s3cr3t": "alice"})(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			called = true
+			forwarded = baggage.FromContext(propagation.Baggage{}.Extract(r.Context(), propagation.HeaderCarrier(r.Header)))
+		}))
+
+		handler.ServeHTTP(httptest.NewRecorder(), newHeaderRequest(map[string]string{"X-Api-Key": key, "Baggage": baggageHeader}))
+
+		if called && key != "s3cr3t" {
+			t.Fatalf("request with key %q served", key)
+		}
+		// the caller must never be able to choose the identity seen by the handler
+		if called && forwarded.Member(USER_ID_BAGGAGE_KEY).Value() != "alice" {
+			t.Fatalf("handler sees %s=%q, want alice", USER_ID_BAGGAGE_KEY, forwarded.Member(USER_ID_BAGGAGE_KEY).Value())
+		}
+	})
+}
+
+func FuzzChaos(f *testing.F) {
+	otel.SetTextMapPropagator(propagation.NewCompositeTextMapPropagator(propagation.TraceContext{}, propagation.Baggage{}))
+	for _, b := range hostileBaggage {
+		f.Add(b)
+	}
+
+	f.Fuzz(func(t *testing.T, baggageHeader string) {
+		called := false
+		handler := Chaos(ChaosOptions{})(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			called = true
+		}))
+
+		handler.ServeHTTP(httptest.NewRecorder(), newHeaderRequest(map[string]string{"Baggage": baggageHeader}))
+
+		// without BaggageOverride, the chaos.rate member is ignored, and the rate is the one of the options, which never fails
+		if !called {
+			t.Fatalf("request failed with %q although the options do not allow the baggage override", baggageHeader)
+		}
+	})
+}
+
+func FuzzLogging(f *testing.F) {
+	otel.SetTextMapPropagator(propagation.NewCompositeTextMapPropagator(propagation.TraceContext{}, propagation.Baggage{}))
+	for _, tp := range []string{
+		"00-0af7651916cd43dd8448eb211c80319c-b7ad6b7169203331-01",
+		"00-00000000000000000000000000000000-0000000000000000-01",
+		"ff-0af7651916cd43dd8448eb211c80319c-b7ad6b7169203331-01",
+		"01-0af7651916cd43dd8448eb211c80319c-b7ad6b7169203331-01-future-fields",
+		"00-0AF7651916CD43DD8448EB211C80319C-B7AD6B7169203331-01",
+		"00-0af7651916cd43dd8448eb211c80319c-b7ad6b7169203331",
+		"garbage",
+	} {
+		f.Add(tp, "vendor=value,other="+strings.Repeat("x", 300), hostileBaggage[len(hostileBaggage)-1])
+	}
+
+	logger := slog.New(slog.NewTextHandler(io.Discard, nil))
+	f.Fuzz(func(t *testing.T, traceparent, tracestate, baggageHeader string) {
+		called := false
+		handler := Logging(logger)(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			called = true
+		}))
+
+		req := newHeaderRequest(map[string]string{"Traceparent": traceparent, "Tracestate": tracestate, "Baggage": baggageHeader})
+		handler.ServeHTTP(httptest.NewRecorder(), req)
+		if !called {
+			t.Fatal("handler not called")
+		}
+
+		// a span context extracted from the headers is either invalid, or holds non-zero IDs
+		sc := trace.SpanContextFromContext(otel.GetTextMapPropagator().Extract(req.Context(), propagation.HeaderCarrier(req.Header)))
+		if sc.IsValid() && (!sc.TraceID().IsValid() || !sc.SpanID().IsValid()) {
+			t.Fatalf("extracted invalid IDs %s-%s from %q", sc.TraceID(), sc.SpanID(), traceparent)
+		}
+	})
+}
@@ -0,0 +1,35 @@
+package xhttp
+
+import (
+	"context"
+	"crypto/tls"
+	"net"
+	"net/http"
+
+	"golang.org/x/net/http2"
+	"golang.org/x/net/http2/h2c"
+)
+
+// NewH2CTransport returns an http.RoundTripper speaking HTTP/2 over cleartext TCP connections (h2c) with prior knowledge,
+// i.e. without upgrading from HTTP/1.1 first. The servers must accept h2c, see H2CHandler. As NewUnixTransport does, it
+// dials the unix domain sockets among addrs for the hosts returned by BaseURL, and every other host over TCP.
+func NewH2CTransport(addrs ...string) http.RoundTripper {
+	sockets := socketMap(addrs)
+
+	return &http2.Transport{
+		AllowHTTP: true,
+		// dialing a plain connection where a TLS one is expected, as the scheme of the requests is http
+		DialTLSContext: func(ctx context.Context, network, addr string, _ *tls.Config) (net.Conn, error) {
+			var d net.Dialer
+			if path, ok := sockets[addr]; ok {
+				return d.DialContext(ctx, "unix", path)
+			}
+			return d.DialContext(ctx, network, addr)
+		},
+	}
+}
+
+// H2CHandler wraps h so that it serves HTTP/2 over cleartext connections in addition to HTTP/1.1.
+func H2CHandler(h http.Handler) http.Handler {
+	return h2c.NewHandler(h, &http2.Server{})
+}
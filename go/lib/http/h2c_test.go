@@ -0,0 +1,31 @@
+package xhttp
+
+import (
+	"net/http"
+	"path/filepath"
+	"testing"
+)
+
+func TestH2CTransportDialsUnixSockets(t *testing.T) {
+	addr := UNIX_PREFIX + filepath.Join(t.TempDir(), "formatter.sock")
+	lis, err := Listen(addr)
+	if err != nil {
+		t.Fatal(err)
+	}
+	srv := &http.Server{Handler: H2CHandler(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("X-Proto", r.Proto)
+	}))}
+	go srv.Serve(lis)
+	defer srv.Close()
+
+	// the host of the URL is the name of the socket file, which the transport maps back to the socket
+	client := &http.Client{Transport: NewH2CTransport(addr)}
+	res, err := client.Get(BaseURL(addr) + "/format")
+	if err != nil {
+		t.Fatal(err)
+	}
+	res.Body.Close()
+	if got := res.Header.Get("X-Proto"); got != "HTTP/2.0" {
+		t.Errorf("served over %s, want HTTP/2.0", got)
+	}
+}
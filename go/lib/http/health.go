@@ -0,0 +1,64 @@
+package xhttp
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"net/http"
+	"time"
+)
+
+// HealthHandler reports that the process is up and able to serve requests.
+func HealthHandler(w http.ResponseWriter, r *http.Request) {
+	w.WriteHeader(http.StatusOK)
+	w.Write([]byte("ok"))
+}
+
+// probeClient sends the probes of ReadyHandler, whatever the transport of http.DefaultClient, which the services may
+// route to a unix socket or over h2c
+var probeClient = &http.Client{Timeout: time.Second}
+
+// ProbeOTLP checks that the OTLP/HTTP backend at the given address accepts the spans, by exporting an empty request:
+// unlike a TCP connection, it fails when the backend answers with an error, e.g. a collector whose own backend is down.
+// The empty request is encoded in protobuf as zero bytes, and holds no span to store.
+func ProbeOTLP(ctx context.Context, backend string) error {
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, "http://"+backend+"/v1/traces", http.NoBody)
+	if err != nil {
+		return err
+	}
+	req.Header.Set("Content-Type", "application/x-protobuf")
+
+	resp, err := probeClient.Do(req)
+	if err != nil {
+		return fmt.Errorf("otlp backend %s unreachable: %v", backend, err)
+	}
+	defer resp.Body.Close()
+	io.Copy(io.Discard, io.LimitReader(resp.Body, 1024))
+
+	if resp.StatusCode < 200 || resp.StatusCode > 299 {
+		return fmt.Errorf("otlp backend %s refused an empty export with %s", backend, resp.Status)
+	}
+	return nil
+}
+
+// ReadyHandler returns a handler reporting whether the OTLP backend at the given address accepts the spans, probed with
+// ProbeOTLP on every request. The services are only considered ready once their telemetry can actually be delivered.
+func ReadyHandler(backend string) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		start := time.Now()
+		if err := ProbeOTLP(r.Context(), backend); err != nil {
+			http.Error(w, err.Error(), http.StatusServiceUnavailable)
+			return
+		}
+
+		w.WriteHeader(http.StatusOK)
+		fmt.Fprintf(w, "ok: otlp backend %s accepted an empty export in %v\n", backend, time.Since(start).Round(time.Millisecond))
+	}
+}
+
+// RegisterHealthHandlers registers the /healthz and /readyz probes on the given mux. They are served in the spans named
+// "healthz" and "readyz", which the TracerProviders of lib/tracing drop before export with tracing.DropHealthChecks.
+func RegisterHealthHandlers(mux *http.ServeMux, backend string) {
+	mux.Handle("/healthz", Traced("healthz", HealthHandler))
+	mux.Handle("/readyz", Traced("readyz", ReadyHandler(backend)))
+}
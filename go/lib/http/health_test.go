@@ -0,0 +1,89 @@
+package xhttp
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"slices"
+	"strings"
+	"testing"
+
+	"github.com/legosandorigami/opentelemetry-tutorial/go/lib/testutil"
+	"github.com/legosandorigami/opentelemetry-tutorial/go/lib/tracing"
+	"go.opentelemetry.io/otel"
+	traceSdk "go.opentelemetry.io/otel/sdk/trace"
+)
+
+func TestReadyHandler(t *testing.T) {
+	status := http.StatusOK
+	var contentType string
+	backend := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Method != http.MethodPost || r.URL.Path != "/v1/traces" {
+			t.Errorf("probed with %s %s, want POST /v1/traces", r.Method, r.URL.Path)
+		}
+		contentType = r.Header.Get("Content-Type")
+		w.WriteHeader(status)
+	}))
+	defer backend.Close()
+	addr := strings.TrimPrefix(backend.URL, "http://")
+
+	for _, tt := range []struct {
+		name    string
+		status  int
+		backend string
+		want    int
+		body    string
+	}{
+		{"accepting", http.StatusOK, addr, http.StatusOK, "ok: otlp backend " + addr + " accepted an empty export"},
+		{"refusing", http.StatusServiceUnavailable, addr, http.StatusServiceUnavailable, "refused an empty export with 503 Service Unavailable"},
+		{"unreachable", http.StatusOK, "localhost:1", http.StatusServiceUnavailable, "otlp backend localhost:1 unreachable"},
+	} {
+		status = tt.status
+		w := httptest.NewRecorder()
+		ReadyHandler(tt.backend)(w, httptest.NewRequest("GET", "/readyz", nil))
+
+		if w.Code != tt.want || !strings.Contains(w.Body.String(), tt.body) {
+			t.Errorf("%s backend: %d %q, want %d %q", tt.name, w.Code, w.Body.String(), tt.want, tt.body)
+		}
+	}
+	if contentType != "application/x-protobuf" {
+		t.Errorf("Content-Type = %q, want application/x-protobuf", contentType)
+	}
+}
+
+func TestHealthChecksAreNotExported(t *testing.T) {
+	receiver := testutil.NewOTLPReceiver(t)
+	res, err := tracing.NewResource("formatter")
+	if err != nil {
+		t.Fatal(err)
+	}
+	tp, err := tracing.NewTracerProvider(res, receiver.Endpoint(), traceSdk.AlwaysSample())
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer otel.SetTracerProvider(otel.GetTracerProvider())
+	otel.SetTracerProvider(tp)
+
+	// the probes and a request of the service, all traced through the global TracerProvider
+	mux := http.NewServeMux()
+	RegisterHealthHandlers(mux, receiver.Endpoint())
+	mux.Handle("/format", Traced("format", func(w http.ResponseWriter, r *http.Request) {}))
+	for _, path := range []string{"/healthz", "/readyz", "/format"} {
+		w := httptest.NewRecorder()
+		mux.ServeHTTP(w, httptest.NewRequest("GET", path, nil))
+		if w.Code != http.StatusOK {
+			t.Fatalf("GET %s: %d %s", path, w.Code, w.Body.String())
+		}
+	}
+	if err := tp.Shutdown(context.Background()); err != nil {
+		t.Fatal(err)
+	}
+
+	var names []string
+	for _, s := range receiver.Spans() {
+		names = append(names, s.GetName())
+	}
+	if !slices.Equal(names, []string{"format"}) {
+		t.Errorf("exported %v, want the format span only", names)
+	}
+}
@@ -0,0 +1,54 @@
+package xhttp
+
+import (
+	"log/slog"
+	"net/http"
+	"time"
+)
+
+// statusRecorder is an http.ResponseWriter remembering the status code written by the handler, and whether the
+// handler wrote anything
+type statusRecorder struct {
+	http.ResponseWriter
+	status  int
+	written bool
+}
+
+func (r *statusRecorder) WriteHeader(status int) {
+	r.status = status
+	r.written = true
+	r.ResponseWriter.WriteHeader(status)
+}
+
+func (r *statusRecorder) Write(b []byte) (int, error) {
+	r.written = true
+	return r.ResponseWriter.Write(b)
+}
+
+// Unwrap gives http.ResponseController access to the underlying writer, e.g. for hijacking the connection
+func (r *statusRecorder) Unwrap() http.ResponseWriter {
+	return r.ResponseWriter
+}
+
+// Logging returns a middleware emitting one structured log line per request, with its method, path, status and duration.
+// The log line is written with the context propagated by the caller, so a trace-aware logger adds the trace_id
+// of the request and the span_id of the client span that sent it, or the span_id of the server span when the
+// middleware runs inside Traced.
+func Logging(logger *slog.Logger) Middleware {
+	return func(next http.Handler) http.Handler {
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			start := time.Now()
+			rec := &statusRecorder{ResponseWriter: w, status: http.StatusOK}
+
+			next.ServeHTTP(rec, r)
+
+			ctx := requestContext(r)
+			logger.InfoContext(ctx, "request",
+				slog.String("method", r.Method),
+				slog.String("path", r.URL.Path),
+				slog.Int("status", rec.status),
+				slog.Duration("duration", time.Since(start)),
+			)
+		})
+	}
+}
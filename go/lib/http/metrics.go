@@ -0,0 +1,76 @@
+package xhttp
+
+import (
+	"context"
+	"net/http"
+	"time"
+
+	"github.com/legosandorigami/opentelemetry-tutorial/go/lib/attrs"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/metric"
+	semconv "go.opentelemetry.io/otel/semconv/v1.4.0"
+)
+
+// UNKNOWN_ROUTE is the http.route of the measurements of the requests not routed by an http.ServeMux, rather than
+// their path, which would make a time series of every URL
+const UNKNOWN_ROUTE = "unknown"
+
+// metricAttributesKey is the context key of the attributes added to the measurements of a request by its handler
+type metricAttributesKey struct{}
+
+// AddMetricAttributes adds attributes to the measurements of the request r recorded by the Metrics middleware, from a
+// handler it wraps, as otelhttp.Labeler does. Mind their values: each one makes a time series of its own.
+func AddMetricAttributes(r *http.Request, kvs ...attribute.KeyValue) {
+	if extra, ok := r.Context().Value(metricAttributesKey{}).(*[]attribute.KeyValue); ok {
+		*extra = append(*extra, kvs...)
+	}
+}
+
+// Metrics returns a middleware counting the requests and recording their latency with instruments created from meter.
+// The measurements are recorded with the context propagated by the caller, so that they carry the trace ID as an exemplar,
+// or with the server span when the middleware runs inside Traced.
+func Metrics(meter metric.Meter) (Middleware, error) {
+	requests, err := meter.Int64Counter("http.server.requests",
+		metric.WithDescription("Number of HTTP requests served"),
+		metric.WithUnit("{request}"),
+	)
+	if err != nil {
+		return nil, err
+	}
+
+	duration, err := meter.Float64Histogram("http.server.duration",
+		metric.WithDescription("Duration of the HTTP requests served"),
+		metric.WithUnit("s"),
+	)
+	if err != nil {
+		return nil, err
+	}
+
+	return func(next http.Handler) http.Handler {
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			start := time.Now()
+			rec := &statusRecorder{ResponseWriter: w, status: http.StatusOK}
+
+			// collecting the attributes added by the handler, the mux routing this copy of the request
+			var extra []attribute.KeyValue
+			r = r.WithContext(context.WithValue(r.Context(), metricAttributesKey{}, &extra))
+
+			next.ServeHTTP(rec, r)
+
+			// the pattern of the mux is known once it has routed the request
+			route := attrs.Route(r)
+			if route == "" {
+				route = UNKNOWN_ROUTE
+			}
+
+			ctx := requestContext(r)
+			set := metric.WithAttributes(append([]attribute.KeyValue{
+				semconv.HTTPMethodKey.String(r.Method),
+				semconv.HTTPRouteKey.String(route),
+				attribute.Int(string(semconv.HTTPStatusCodeKey), rec.status),
+			}, extra...)...)
+			requests.Add(ctx, 1, set)
+			duration.Record(ctx, time.Since(start).Seconds(), set)
+		})
+	}, nil
+}
@@ -0,0 +1,83 @@
+package xhttp
+
+import (
+	"context"
+	"maps"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"go.opentelemetry.io/otel/attribute"
+	metricSdk "go.opentelemetry.io/otel/sdk/metric"
+	"go.opentelemetry.io/otel/sdk/metric/metricdata"
+)
+
+func TestMetricsRecordsTheRouteTemplate(t *testing.T) {
+	reader := metricSdk.NewManualReader()
+	red, err := Metrics(metricSdk.NewMeterProvider(metricSdk.WithReader(reader)).Meter("test"))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	ok := func(w http.ResponseWriter, r *http.Request) {}
+	mux := http.NewServeMux()
+	mux.HandleFunc("GET /format/{helloTo}", ok)
+
+	// two requests routed by the mux, whatever their path, and one served without a mux
+	handler := Chain(mux, red)
+	handler.ServeHTTP(httptest.NewRecorder(), httptest.NewRequest("GET", "/format/Brian", nil))
+	handler.ServeHTTP(httptest.NewRecorder(), httptest.NewRequest("GET", "/format/Alice", nil))
+	Chain(http.HandlerFunc(ok), red).ServeHTTP(httptest.NewRecorder(), httptest.NewRequest("GET", "/publish?helloTo=Brian", nil))
+
+	var rm metricdata.ResourceMetrics
+	if err := reader.Collect(context.Background(), &rm); err != nil {
+		t.Fatal(err)
+	}
+	counts := map[string]int64{}
+	for _, m := range rm.ScopeMetrics[0].Metrics {
+		if m.Name != "http.server.requests" {
+			continue
+		}
+		for _, dp := range m.Data.(metricdata.Sum[int64]).DataPoints {
+			route, _ := dp.Attributes.Value("http.route")
+			counts[route.AsString()] += dp.Value
+		}
+	}
+	want := map[string]int64{"/format/{helloTo}": 2, UNKNOWN_ROUTE: 1}
+	if !maps.Equal(counts, want) {
+		t.Errorf("requests by http.route = %v, want %v", counts, want)
+	}
+}
+
+func TestAddMetricAttributes(t *testing.T) {
+	reader := metricSdk.NewManualReader()
+	red, err := Metrics(metricSdk.NewMeterProvider(metricSdk.WithReader(reader)).Meter("test"))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	// the handler adds its attribute from inside Traced, which derives the context of the request
+	handler := Chain(Traced("format", func(w http.ResponseWriter, r *http.Request) {
+		AddMetricAttributes(r, attribute.String("hello-to", "Brian"))
+	}), red)
+	handler.ServeHTTP(httptest.NewRecorder(), httptest.NewRequest("GET", "/format?helloTo=Brian", nil))
+
+	var rm metricdata.ResourceMetrics
+	if err := reader.Collect(context.Background(), &rm); err != nil {
+		t.Fatal(err)
+	}
+	for _, m := range rm.ScopeMetrics[0].Metrics {
+		if m.Name != "http.server.duration" {
+			continue
+		}
+		dps := m.Data.(metricdata.Histogram[float64]).DataPoints
+		if len(dps) != 1 {
+			t.Fatalf("%d data points, want 1", len(dps))
+		}
+		if got, _ := dps[0].Attributes.Value("hello-to"); got.AsString() != "Brian" {
+			t.Errorf("hello-to = %q, want the attribute added by the handler", got.AsString())
+		}
+		return
+	}
+	t.Fatal("no http.server.duration histogram recorded")
+}
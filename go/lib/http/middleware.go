@@ -0,0 +1,31 @@
+package xhttp
+
+import (
+	"context"
+	"net/http"
+
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/propagation"
+	"go.opentelemetry.io/otel/trace"
+)
+
+// Middleware decorates an http.Handler with additional behaviour.
+type Middleware func(http.Handler) http.Handler
+
+// Chain wraps h with the given middlewares, the first one being the outermost.
+func Chain(h http.Handler, middlewares ...Middleware) http.Handler {
+	for i := len(middlewares) - 1; i >= 0; i-- {
+		h = middlewares[i](h)
+	}
+	return h
+}
+
+// requestContext returns the context the middlewares record their measurements and logs with: the context of the
+// request when it holds a span already, i.e. when the middleware runs inside Traced, so that they point at the server
+// span, or else the context propagated by the caller, which points at the client span.
+func requestContext(r *http.Request) context.Context {
+	if trace.SpanContextFromContext(r.Context()).IsValid() {
+		return r.Context()
+	}
+	return otel.GetTextMapPropagator().Extract(r.Context(), propagation.HeaderCarrier(r.Header))
+}
@@ -0,0 +1,67 @@
+package xhttp
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"log/slog"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/legosandorigami/opentelemetry-tutorial/go/lib/tracingtest"
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/propagation"
+	traceSdk "go.opentelemetry.io/otel/sdk/trace"
+	"go.opentelemetry.io/otel/trace"
+)
+
+// spanIDHandler is a slog.Handler remembering the span ID found in the context of the last record
+type spanIDHandler struct {
+	slog.Handler
+	spanID trace.SpanID
+}
+
+func (h *spanIDHandler) Handle(ctx context.Context, r slog.Record) error {
+	h.spanID = trace.SpanContextFromContext(ctx).SpanID()
+	return nil
+}
+
+func TestLoggingPointsAtTheInnermostSpan(t *testing.T) {
+	defer otel.SetTextMapPropagator(otel.GetTextMapPropagator())
+	otel.SetTextMapPropagator(propagation.TraceContext{})
+	tp, sr := tracingtest.NewRecorder()
+	defer otel.SetTracerProvider(otel.GetTracerProvider())
+	otel.SetTracerProvider(tp)
+
+	newRequest := func() *http.Request {
+		r := httptest.NewRequest("GET", "/format", nil)
+		r.Header.Set("traceparent", "00-0af7651916cd43dd8448eb211c80319c-b7ad6b7169203331-01")
+		return r
+	}
+	ok := func(w http.ResponseWriter, r *http.Request) {}
+
+	// outside Traced, the log line points at the client span of the caller
+	h := &spanIDHandler{Handler: slog.NewTextHandler(io.Discard, nil)}
+	Chain(http.HandlerFunc(ok), Logging(slog.New(h))).ServeHTTP(httptest.NewRecorder(), newRequest())
+	if h.spanID.String() != "b7ad6b7169203331" {
+		t.Errorf("span_id = %s outside Traced, want the client span b7ad6b7169203331", h.spanID)
+	}
+
+	// inside Traced, it points at the server span
+	Traced("format", Chain(http.HandlerFunc(ok), Logging(slog.New(h))).ServeHTTP).ServeHTTP(httptest.NewRecorder(), newRequest())
+	tracingtest.AssertSpanTree(t, sr, tracingtest.Span{
+		Name:     "format",
+		Matchers: []tracingtest.Matcher{tracingtest.HasRemoteParent(), hasSpanID(h.spanID)},
+	})
+}
+
+// hasSpanID matches the span with the given span ID
+func hasSpanID(spanID trace.SpanID) tracingtest.Matcher {
+	return func(s traceSdk.ReadOnlySpan) error {
+		if s.SpanContext().SpanID() != spanID {
+			return fmt.Errorf("span_id = %s, want %s", s.SpanContext().SpanID(), spanID)
+		}
+		return nil
+	}
+}
@@ -0,0 +1,75 @@
+package xhttp
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+
+	"github.com/legosandorigami/opentelemetry-tutorial/go/lib/attrs"
+	"github.com/legosandorigami/opentelemetry-tutorial/go/lib/tracing"
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/codes"
+	"go.opentelemetry.io/otel/propagation"
+	semconv "go.opentelemetry.io/otel/semconv/v1.4.0"
+	"go.opentelemetry.io/otel/trace"
+)
+
+// TRACER_NAME is the name of the tracer of the spans started by Traced
+const TRACER_NAME = "xhttp"
+
+// tracer is the tracer of the spans started by Traced
+var tracer = tracing.NewGlobalTracer(TRACER_NAME)
+
+// StartSpanFromRequest starts the server span named name for the request r, as every handler of the tutorial does:
+// it extracts the span context sent by the caller with the global propagator, starts the span as its child with
+// tracer, and records the attributes of the request following the HTTP semantic conventions. It returns the context
+// holding the span, the span, and a function to be deferred, which prints the span details and ends the span.
+func StartSpanFromRequest(tracer trace.Tracer, r *http.Request, name string) (context.Context, trace.Span, func()) {
+	ctx := otel.GetTextMapPropagator().Extract(r.Context(), propagation.HeaderCarrier(r.Header))
+
+	ctx, span := tracer.Start(ctx, name,
+		trace.WithSpanKind(trace.SpanKindServer),
+		trace.WithAttributes(attrs.HTTPServerAttrs(r)...),
+	)
+
+	end := func() {
+		tracing.PrintSpanContents(span)
+		span.End()
+	}
+	return ctx, span, end
+}
+
+// Traced wraps handler so that every request is served in a server span named name, started from the global
+// TracerProvider as a child of the span context sent by the caller, e.g.
+//
+//	http.Handle("/format", xhttp.Traced("format", handler))
+//
+// The handler finds the span in the context of the request. The status code of the response is recorded in the span,
+// and marks it as failed when it is a 5xx. A panic of the handler is recovered: it is recorded in the span, and the
+// caller gets a 500 if nothing was written yet.
+func Traced(name string, handler http.HandlerFunc) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		ctx, span, end := StartSpanFromRequest(tracer.Tracer(), r, name)
+		defer end()
+
+		rec := &statusRecorder{ResponseWriter: w, status: http.StatusOK}
+		defer func() {
+			// the panic fails the span whatever the status code, which was possibly written before it
+			if p := recover(); p != nil {
+				tracing.RecordFailure(span, fmt.Errorf("panic: %v", p))
+				if !rec.written {
+					http.Error(rec, http.StatusText(http.StatusInternalServerError), http.StatusInternalServerError)
+				}
+				span.SetAttributes(semconv.HTTPAttributesFromHTTPStatusCode(rec.status)...)
+				return
+			}
+
+			span.SetAttributes(semconv.HTTPAttributesFromHTTPStatusCode(rec.status)...)
+			if code, description := semconv.SpanStatusFromHTTPStatusCodeAndSpanKind(rec.status, trace.SpanKindServer); code == codes.Error {
+				span.SetStatus(code, description)
+			}
+		}()
+
+		handler(rec, r.WithContext(ctx))
+	})
+}
@@ -0,0 +1,120 @@
+package xhttp
+
+import (
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/legosandorigami/opentelemetry-tutorial/go/lib/tracingtest"
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/codes"
+	"go.opentelemetry.io/otel/propagation"
+	traceSdk "go.opentelemetry.io/otel/sdk/trace"
+	"go.opentelemetry.io/otel/trace"
+)
+
+func TestStartSpanFromRequest(t *testing.T) {
+	defer otel.SetTextMapPropagator(otel.GetTextMapPropagator())
+	otel.SetTextMapPropagator(propagation.TraceContext{})
+	tp, sr := tracingtest.NewRecorder()
+	tracer := tp.Tracer("test")
+
+	r := httptest.NewRequest("GET", "/format?helloTo=Brian", nil)
+	r.Host = "localhost:8081"
+	r.Header.Set("traceparent", "00-0af7651916cd43dd8448eb211c80319c-b7ad6b7169203331-01")
+
+	ctx, span, end := StartSpanFromRequest(tracer, r, "format")
+	if trace.SpanFromContext(ctx) != span {
+		t.Error("the context does not hold the span")
+	}
+	end()
+
+	tracingtest.AssertSpanTree(t, sr, tracingtest.Span{
+		Name: "format",
+		Matchers: []tracingtest.Matcher{
+			tracingtest.HasKind(trace.SpanKindServer),
+			tracingtest.HasRemoteParent(),
+			hasParent("0af7651916cd43dd8448eb211c80319c", "b7ad6b7169203331"),
+			tracingtest.HasAttributes(
+				attribute.String("http.method", "GET"),
+				attribute.String("http.target", "/format?helloTo=Brian"),
+				attribute.String("http.host", "localhost:8081"),
+			),
+		},
+	})
+}
+
+// hasParent matches the spans whose parent has the given trace and span IDs
+func hasParent(traceID, spanID string) tracingtest.Matcher {
+	return func(s traceSdk.ReadOnlySpan) error {
+		if got := s.Parent(); got.TraceID().String() != traceID || got.SpanID().String() != spanID {
+			return fmt.Errorf("parent = %s/%s, want %s/%s", got.TraceID(), got.SpanID(), traceID, spanID)
+		}
+		return nil
+	}
+}
+
+func TestTraced(t *testing.T) {
+	tp, sr := tracingtest.NewRecorder()
+	defer otel.SetTracerProvider(otel.GetTracerProvider())
+	otel.SetTracerProvider(tp)
+
+	mux := http.NewServeMux()
+	mux.Handle("/format", Traced("format", func(w http.ResponseWriter, r *http.Request) {
+		// the span is in the context of the request
+		trace.SpanFromContext(r.Context()).SetAttributes(attribute.String("hello-to", r.FormValue("helloTo")))
+		if r.FormValue("helloTo") == "" {
+			http.Error(w, "missing helloTo", http.StatusBadRequest)
+			return
+		}
+		w.Write([]byte("Hello, " + r.FormValue("helloTo") + "!"))
+	}))
+	mux.Handle("/fail", Traced("fail", func(w http.ResponseWriter, r *http.Request) {
+		http.Error(w, "publisher down", http.StatusBadGateway)
+	}))
+	mux.Handle("/panic", Traced("panic", func(w http.ResponseWriter, r *http.Request) {
+		panic("boom")
+	}))
+
+	tests := []struct {
+		target      string
+		wantSpan    string
+		wantStatus  int
+		wantCode    codes.Code
+		wantSpanErr string
+	}{
+		{"/format?helloTo=Brian", "format", http.StatusOK, codes.Unset, ""},
+		{"/format", "format", http.StatusBadRequest, codes.Unset, ""},
+		{"/fail", "fail", http.StatusBadGateway, codes.Error, ""},
+		{"/panic", "panic", http.StatusInternalServerError, codes.Error, "panic: boom"},
+	}
+	for _, tt := range tests {
+		sr.Reset()
+		w := httptest.NewRecorder()
+		mux.ServeHTTP(w, httptest.NewRequest("GET", tt.target, nil))
+		if w.Code != tt.wantStatus {
+			t.Errorf("%s: status = %d, want %d", tt.target, w.Code, tt.wantStatus)
+		}
+
+		matchers := []tracingtest.Matcher{
+			tracingtest.HasAttributes(attribute.Int("http.status_code", tt.wantStatus)),
+			tracingtest.HasStatus(tt.wantCode),
+		}
+		if tt.wantSpanErr != "" {
+			matchers = append(matchers, hasStatusDescription(tt.wantSpanErr))
+		}
+		tracingtest.AssertSpanTree(t, sr, tracingtest.Span{Name: tt.wantSpan, Matchers: matchers})
+	}
+}
+
+// hasStatusDescription matches the spans whose status has the given description
+func hasStatusDescription(description string) tracingtest.Matcher {
+	return func(s traceSdk.ReadOnlySpan) error {
+		if s.Status().Description != description {
+			return fmt.Errorf("status description = %q, want %q", s.Status().Description, description)
+		}
+		return nil
+	}
+}
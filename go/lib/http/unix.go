@@ -0,0 +1,95 @@
+package xhttp
+
+import (
+	"context"
+	"net"
+	"net/http"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+
+	"go.opentelemetry.io/otel/attribute"
+	semconv "go.opentelemetry.io/otel/semconv/v1.4.0"
+)
+
+// UNIX_PREFIX marks the service addresses that are unix domain socket paths, e.g. "unix:/tmp/formatter.sock"
+const UNIX_PREFIX = "unix:"
+
+// socketPath returns the path of the unix domain socket of addr, and whether addr is a unix domain socket at all
+func socketPath(addr string) (string, bool) {
+	if !strings.HasPrefix(addr, UNIX_PREFIX) {
+		return "", false
+	}
+	return strings.TrimPrefix(addr, UNIX_PREFIX), true
+}
+
+// Listen listens on addr, either a TCP address or a unix domain socket path prefixed with "unix:".
+// A socket file left over by a previous run is removed first.
+func Listen(addr string) (net.Listener, error) {
+	if path, ok := socketPath(addr); ok {
+		if err := os.Remove(path); err != nil && !os.IsNotExist(err) {
+			return nil, err
+		}
+		return net.Listen("unix", path)
+	}
+	return net.Listen("tcp", addr)
+}
+
+// BaseURL returns the base URL of the service listening on addr.
+// URLs cannot hold a socket path, so for a unix domain socket the host is the name of the socket file, which the
+// transport returned by NewUnixTransport maps back to the socket.
+func BaseURL(addr string) string {
+	if path, ok := socketPath(addr); ok {
+		return "http://" + filepath.Base(path)
+	}
+	return "http://" + addr
+}
+
+// NewUnixTransport returns a transport dialing the unix domain sockets among addrs for the hosts returned by BaseURL,
+// and every other host over TCP.
+func NewUnixTransport(addrs ...string) *http.Transport {
+	sockets := socketMap(addrs)
+
+	transport := http.DefaultTransport.(*http.Transport).Clone()
+	dial := transport.DialContext
+	transport.DialContext = func(ctx context.Context, network, addr string) (net.Conn, error) {
+		if path, ok := sockets[addr]; ok {
+			var d net.Dialer
+			return d.DialContext(ctx, "unix", path)
+		}
+		return dial(ctx, network, addr)
+	}
+	return transport
+}
+
+// socketMap returns the socket paths of the unix domain sockets among addrs, by the host:port the transports dial for
+// the URLs returned by BaseURL
+func socketMap(addrs []string) map[string]string {
+	sockets := make(map[string]string)
+	for _, addr := range addrs {
+		if path, ok := socketPath(addr); ok {
+			// the transports dial host:port, the port defaulting to 80 for http URLs
+			sockets[net.JoinHostPort(filepath.Base(path), "80")] = path
+		}
+	}
+	return sockets
+}
+
+// PeerAttributes returns the net.* attributes describing the service listening on addr: the transport, and either the
+// socket path or the host and port.
+func PeerAttributes(addr string) []attribute.KeyValue {
+	if path, ok := socketPath(addr); ok {
+		return []attribute.KeyValue{semconv.NetTransportUnix, semconv.NetPeerNameKey.String(path)}
+	}
+
+	host, port, err := net.SplitHostPort(addr)
+	if err != nil {
+		return []attribute.KeyValue{semconv.NetTransportTCP, semconv.NetPeerNameKey.String(addr)}
+	}
+	attrs := []attribute.KeyValue{semconv.NetTransportTCP, semconv.NetPeerNameKey.String(host)}
+	if p, err := strconv.Atoi(port); err == nil {
+		attrs = append(attrs, semconv.NetPeerPortKey.Int(p))
+	}
+	return attrs
+}
@@ -0,0 +1,47 @@
+package i18n
+
+import (
+	"golang.org/x/text/language"
+)
+
+// greetings maps the supported locales to their greeting, the first one being the default
+var greetings = []struct {
+	tag      language.Tag
+	greeting string
+}{
+	{language.English, "Hello"},
+	{language.French, "Bonjour"},
+	{language.Spanish, "Hola"},
+	{language.German, "Hallo"},
+	{language.Italian, "Ciao"},
+	{language.Portuguese, "Olá"},
+}
+
+var matcher = func() language.Matcher {
+	tags := make([]language.Tag, len(greetings))
+	for i, g := range greetings {
+		tags[i] = g.tag
+	}
+	return language.NewMatcher(tags)
+}()
+
+// Greeting returns the greeting for the best match among the supported locales and the locale that was selected.
+// The preferences are given as BCP 47 tags (e.g. "fr-CA") or as an Accept-Language header value, the first
+// one that parses wins. English is used when none of them matches.
+func Greeting(preferences ...string) (greeting string, locale string) {
+	for _, pref := range preferences {
+		if pref == "" {
+			continue
+		}
+		tags, _, err := language.ParseAcceptLanguage(pref)
+		if err != nil || len(tags) == 0 {
+			continue
+		}
+		_, index, confidence := matcher.Match(tags...)
+		if confidence == language.No {
+			continue
+		}
+		return greetings[index].greeting, greetings[index].tag.String()
+	}
+	return greetings[0].greeting, greetings[0].tag.String()
+}
@@ -0,0 +1,154 @@
+package latency
+
+import (
+	"context"
+	"crypto/sha256"
+	"fmt"
+	"math"
+	"math/rand/v2"
+	"strconv"
+	"strings"
+	"time"
+
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/trace"
+)
+
+// Distribution produces the durations of the simulated work.
+type Distribution interface {
+	Sample() time.Duration
+	String() string
+}
+
+// Fixed always returns the same duration.
+type Fixed struct {
+	D time.Duration
+}
+
+func (f Fixed) Sample() time.Duration { return f.D }
+func (f Fixed) String() string        { return fmt.Sprintf("fixed:%s", f.D) }
+
+// Uniform returns durations spread evenly between Min and Max.
+type Uniform struct {
+	Min, Max time.Duration
+}
+
+func (u Uniform) Sample() time.Duration {
+	return u.Min + time.Duration(rand.Int64N(int64(u.Max-u.Min)+1))
+}
+func (u Uniform) String() string { return fmt.Sprintf("uniform:%s:%s", u.Min, u.Max) }
+
+// Pareto returns heavy-tailed durations of at least Scale, the smaller the Shape the longer the tail.
+// Samples are capped at 100 times the scale so a single request cannot hang forever.
+type Pareto struct {
+	Scale time.Duration
+	Shape float64
+}
+
+func (p Pareto) Sample() time.Duration {
+	// inverse transform sampling, 1-U is used so that U == 0 cannot divide by zero
+	d := float64(p.Scale) / math.Pow(1-rand.Float64(), 1/p.Shape)
+	return time.Duration(math.Min(d, 100*float64(p.Scale)))
+}
+func (p Pareto) String() string { return fmt.Sprintf("pareto:%s:%g", p.Scale, p.Shape) }
+
+// Parse parses a distribution spec of the form "fixed:100ms", "uniform:50ms:200ms" or "pareto:20ms:1.5".
+// An empty spec returns a nil Distribution, meaning no latency is simulated.
+func Parse(spec string) (Distribution, error) {
+	if spec == "" {
+		return nil, nil
+	}
+
+	parts := strings.Split(spec, ":")
+	switch {
+	case parts[0] == "fixed" && len(parts) == 2:
+		d, err := parseDuration(parts[1])
+		if err != nil {
+			return nil, fmt.Errorf("invalid latency %q: %v", spec, err)
+		}
+		return Fixed{D: d}, nil
+	case parts[0] == "uniform" && len(parts) == 3:
+		min, err := parseDuration(parts[1])
+		if err != nil {
+			return nil, fmt.Errorf("invalid latency %q: %v", spec, err)
+		}
+		max, err := parseDuration(parts[2])
+		if err != nil {
+			return nil, fmt.Errorf("invalid latency %q: %v", spec, err)
+		}
+		if max < min {
+			return nil, fmt.Errorf("invalid latency %q: max is lower than min", spec)
+		}
+		return Uniform{Min: min, Max: max}, nil
+	case parts[0] == "pareto" && len(parts) == 3:
+		scale, err := parseDuration(parts[1])
+		if err != nil {
+			return nil, fmt.Errorf("invalid latency %q: %v", spec, err)
+		}
+		shape, err := strconv.ParseFloat(parts[2], 64)
+		if err != nil || shape <= 0 {
+			return nil, fmt.Errorf("invalid latency %q: shape must be a positive number", spec)
+		}
+		return Pareto{Scale: scale, Shape: shape}, nil
+	}
+
+	return nil, fmt.Errorf("invalid latency %q: expecting fixed:<d>, uniform:<min>:<max> or pareto:<scale>:<shape>", spec)
+}
+
+// parseDuration parses a duration of the spec, refusing the negative ones as no work takes less than nothing
+func parseDuration(s string) (time.Duration, error) {
+	d, err := time.ParseDuration(s)
+	if err != nil {
+		return 0, err
+	}
+	if d < 0 {
+		return 0, fmt.Errorf("duration %s is negative", s)
+	}
+	return d, nil
+}
+
+// Simulate sleeps for a duration sampled from dist inside a child span named "work".
+// It does nothing when dist is nil.
+func Simulate(ctx context.Context, tracer trace.Tracer, dist Distribution) {
+	if dist == nil {
+		return
+	}
+
+	_, span := tracer.Start(ctx, "work")
+	defer span.End()
+
+	d := dist.Sample()
+	span.SetAttributes(
+		attribute.String("latency.distribution", dist.String()),
+		attribute.Int64("latency.ms", d.Milliseconds()),
+	)
+
+	select {
+	case <-time.After(d):
+	case <-ctx.Done():
+	}
+}
+
+// Burn keeps the CPU busy for a duration sampled from dist inside a child span named "work", rather than sleeping as
+// Simulate does, so that the work shows in the CPU profiles. It does nothing when dist is nil.
+func Burn(ctx context.Context, tracer trace.Tracer, dist Distribution) {
+	if dist == nil {
+		return
+	}
+
+	_, span := tracer.Start(ctx, "work")
+	defer span.End()
+
+	d := dist.Sample()
+	span.SetAttributes(
+		attribute.String("latency.distribution", dist.String()),
+		attribute.Int64("latency.ms", d.Milliseconds()),
+	)
+
+	// hashing a block over and over until the duration elapsed or the context is done
+	block := make([]byte, 4096)
+	for start := time.Now(); time.Since(start) < d && ctx.Err() == nil; {
+		sum := sha256.Sum256(block)
+		copy(block, sum[:])
+	}
+}
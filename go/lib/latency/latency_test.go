@@ -0,0 +1,55 @@
+package latency
+
+import (
+	"testing"
+	"time"
+)
+
+func TestParse(t *testing.T) {
+	for _, tc := range []struct {
+		spec string
+		want Distribution
+	}{
+		{"", nil},
+		{"fixed:100ms", Fixed{D: 100 * time.Millisecond}},
+		{"fixed:0", Fixed{}},
+		{"uniform:50ms:200ms", Uniform{Min: 50 * time.Millisecond, Max: 200 * time.Millisecond}},
+		{"uniform:100ms:100ms", Uniform{Min: 100 * time.Millisecond, Max: 100 * time.Millisecond}},
+		{"pareto:20ms:1.5", Pareto{Scale: 20 * time.Millisecond, Shape: 1.5}},
+	} {
+		got, err := Parse(tc.spec)
+		if err != nil {
+			t.Errorf("Parse(%q) failed: %v", tc.spec, err)
+		} else if got != tc.want {
+			t.Errorf("Parse(%q) = %v, want %v", tc.spec, got, tc.want)
+		}
+	}
+}
+
+func TestParseMalformed(t *testing.T) {
+	for _, tc := range []struct {
+		name, spec string
+	}{
+		{"missing unit", "fixed:100"},
+		{"missing duration", "fixed:"},
+		{"negative value", "fixed:-100ms"},
+		{"negative min", "uniform:-50ms:200ms"},
+		{"negative scale", "pareto:-20ms:1.5"},
+		{"unknown distribution", "normal:100ms:10ms"},
+		{"uppercase distribution", "Fixed:100ms"},
+		{"no distribution", "100ms"},
+		{"too many parts", "fixed:100ms:200ms"},
+		{"too few parts", "uniform:50ms"},
+		{"max lower than min", "uniform:200ms:50ms"},
+		{"malformed max", "uniform:50ms:fast"},
+		{"zero shape", "pareto:20ms:0"},
+		{"negative shape", "pareto:20ms:-1.5"},
+		{"malformed shape", "pareto:20ms:heavy"},
+	} {
+		t.Run(tc.name, func(t *testing.T) {
+			if d, err := Parse(tc.spec); err == nil {
+				t.Errorf("Parse(%q) = %v, want an error", tc.spec, d)
+			}
+		})
+	}
+}
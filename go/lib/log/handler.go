@@ -0,0 +1,48 @@
+package xlog
+
+import (
+	"context"
+	"log/slog"
+	"os"
+
+	"go.opentelemetry.io/otel/trace"
+)
+
+// Handler is a slog.Handler adding the trace_id and span_id of the span found in the context to every record,
+// so that the log lines can be correlated with the traces in the backend.
+type Handler struct {
+	next slog.Handler
+}
+
+// NewHandler wraps next in a trace-aware Handler.
+func NewHandler(next slog.Handler) *Handler {
+	return &Handler{next: next}
+}
+
+func (h *Handler) Enabled(ctx context.Context, level slog.Level) bool {
+	return h.next.Enabled(ctx, level)
+}
+
+func (h *Handler) Handle(ctx context.Context, r slog.Record) error {
+	if sc := trace.SpanContextFromContext(ctx); sc.IsValid() {
+		r.AddAttrs(
+			slog.String("trace_id", sc.TraceID().String()),
+			slog.String("span_id", sc.SpanID().String()),
+		)
+	}
+	return h.next.Handle(ctx, r)
+}
+
+func (h *Handler) WithAttrs(attrs []slog.Attr) slog.Handler {
+	return &Handler{next: h.next.WithAttrs(attrs)}
+}
+
+func (h *Handler) WithGroup(name string) slog.Handler {
+	return &Handler{next: h.next.WithGroup(name)}
+}
+
+// New creates a logger writing JSON records to stderr through a trace-aware Handler, tagged with the service name.
+// The records only carry the trace and span IDs when logged with a context, e.g. with logger.InfoContext(ctx, ...).
+func New(service string) *slog.Logger {
+	return slog.New(NewHandler(slog.NewJSONHandler(os.Stderr, nil))).With(slog.String("service", service))
+}
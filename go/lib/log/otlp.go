@@ -0,0 +1,48 @@
+package xlog
+
+import (
+	"context"
+	"log/slog"
+
+	"github.com/legosandorigami/opentelemetry-tutorial/go/lib/tracing"
+	"go.opentelemetry.io/contrib/bridges/otelslog"
+	"go.opentelemetry.io/otel/exporters/otlp/otlplog/otlploghttp"
+	"go.opentelemetry.io/otel/log/global"
+	logSdk "go.opentelemetry.io/otel/sdk/log"
+)
+
+// InitLoggerProviderWithBackend initializes the OpenTelemetry LoggerProvider with the specified service name, sending the
+// log records to the specified backend over OTLP.
+func InitLoggerProviderWithBackend(service, backend string) (*logSdk.LoggerProvider, error) {
+	ctx := context.Background()
+
+	// creating an OTLP log exporter to send the log records to the specified backend
+	exporter, err := otlploghttp.New(ctx, otlploghttp.WithEndpoint(backend), otlploghttp.WithInsecure())
+	if err != nil {
+		return nil, err
+	}
+
+	// describing the service with the same resource as its traces
+	res, err := tracing.NewResource(service)
+	if err != nil {
+		return nil, err
+	}
+
+	// creating a LoggerProvider exporting the log records in batches, like the spans
+	lp := logSdk.NewLoggerProvider(
+		logSdk.WithProcessor(logSdk.NewBatchProcessor(exporter)),
+		logSdk.WithResource(res),
+	)
+
+	// setting up the global logger provider
+	global.SetLoggerProvider(lp)
+
+	return lp, nil
+}
+
+// NewOTel creates a logger named name emitting its records through the OpenTelemetry log bridge of the global
+// LoggerProvider. The bridge fills in the trace and span IDs of the records logged with a context holding a span, e.g. with
+// logger.InfoContext(ctx, ...).
+func NewOTel(name string) *slog.Logger {
+	return otelslog.NewLogger(name)
+}
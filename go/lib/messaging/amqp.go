@@ -0,0 +1,43 @@
+package messaging
+
+import (
+	amqp "github.com/rabbitmq/amqp091-go"
+	"go.opentelemetry.io/otel/propagation"
+)
+
+// AMQPHeaderCarrier adapts the headers of an AMQP message to the propagation.TextMapCarrier interface,
+// so that the trace context and baggage can be injected into and extracted from messages.
+type AMQPHeaderCarrier struct {
+	Headers amqp.Table
+}
+
+var _ propagation.TextMapCarrier = AMQPHeaderCarrier{}
+
+// NewAMQPHeaderCarrier returns a carrier reading and writing headers, the Headers field of an amqp.Publishing or of an
+// amqp.Delivery. The table is created if it is nil.
+func NewAMQPHeaderCarrier(headers *amqp.Table) AMQPHeaderCarrier {
+	if *headers == nil {
+		*headers = amqp.Table{}
+	}
+	return AMQPHeaderCarrier{Headers: *headers}
+}
+
+// Get returns the value of the header with the given key, or an empty string if it is missing or not a string.
+func (c AMQPHeaderCarrier) Get(key string) string {
+	v, _ := c.Headers[key].(string)
+	return v
+}
+
+// Set sets the header with the given key.
+func (c AMQPHeaderCarrier) Set(key, value string) {
+	c.Headers[key] = value
+}
+
+// Keys returns the keys of all the headers.
+func (c AMQPHeaderCarrier) Keys() []string {
+	keys := make([]string, 0, len(c.Headers))
+	for k := range c.Headers {
+		keys = append(keys, k)
+	}
+	return keys
+}
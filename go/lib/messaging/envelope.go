@@ -0,0 +1,20 @@
+package messaging
+
+import (
+	"go.opentelemetry.io/otel/propagation"
+)
+
+// Envelope wraps the body of a message along with the headers carrying the trace context and the baggage, for the
+// transports whose messages have no headers of their own, such as Redis pub/sub. It is sent encoded in JSON.
+type Envelope struct {
+	Headers map[string]string `json:"headers,omitempty"`
+	Body    string            `json:"body"`
+}
+
+// Carrier returns a carrier reading and writing the headers of the envelope, which are created if they are nil.
+func (e *Envelope) Carrier() propagation.MapCarrier {
+	if e.Headers == nil {
+		e.Headers = map[string]string{}
+	}
+	return propagation.MapCarrier(e.Headers)
+}
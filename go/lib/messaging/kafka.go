@@ -0,0 +1,49 @@
+package messaging
+
+import (
+	"github.com/segmentio/kafka-go"
+	"go.opentelemetry.io/otel/propagation"
+)
+
+// KafkaHeaderCarrier adapts the headers of a Kafka message to the propagation.TextMapCarrier interface,
+// so that the trace context and baggage can be injected into and extracted from messages.
+type KafkaHeaderCarrier struct {
+	Headers *[]kafka.Header
+}
+
+var _ propagation.TextMapCarrier = KafkaHeaderCarrier{}
+
+// NewKafkaHeaderCarrier returns a carrier reading and writing the headers of msg.
+func NewKafkaHeaderCarrier(msg *kafka.Message) KafkaHeaderCarrier {
+	return KafkaHeaderCarrier{Headers: &msg.Headers}
+}
+
+// Get returns the value of the first header with the given key.
+func (c KafkaHeaderCarrier) Get(key string) string {
+	for _, h := range *c.Headers {
+		if h.Key == key {
+			return string(h.Value)
+		}
+	}
+	return ""
+}
+
+// Set overwrites the header with the given key, or appends it if it is not present yet.
+func (c KafkaHeaderCarrier) Set(key, value string) {
+	for i, h := range *c.Headers {
+		if h.Key == key {
+			(*c.Headers)[i].Value = []byte(value)
+			return
+		}
+	}
+	*c.Headers = append(*c.Headers, kafka.Header{Key: key, Value: []byte(value)})
+}
+
+// Keys returns the keys of all the headers.
+func (c KafkaHeaderCarrier) Keys() []string {
+	keys := make([]string, 0, len(*c.Headers))
+	for _, h := range *c.Headers {
+		keys = append(keys, h.Key)
+	}
+	return keys
+}
@@ -0,0 +1,46 @@
+package messaging
+
+import (
+	"github.com/nats-io/nats.go"
+	"go.opentelemetry.io/otel/propagation"
+)
+
+// NATSHeaderCarrier adapts the headers of a NATS message to the propagation.TextMapCarrier interface, so that the
+// trace context and baggage can be injected into and extracted from messages. NATS headers look like HTTP headers, but
+// their keys are case-sensitive: the carrier keeps them as they are, where propagation.HeaderCarrier would turn
+// "traceparent" into "Traceparent" and miss the header set by a publisher written in another language.
+type NATSHeaderCarrier struct {
+	Header nats.Header
+}
+
+var _ propagation.TextMapCarrier = NATSHeaderCarrier{}
+
+// NewNATSHeaderCarrier returns a carrier reading and writing the headers of msg.
+func NewNATSHeaderCarrier(msg *nats.Msg) NATSHeaderCarrier {
+	if msg.Header == nil {
+		msg.Header = nats.Header{}
+	}
+	return NATSHeaderCarrier{Header: msg.Header}
+}
+
+// Get returns the first value of the header with exactly the given key.
+func (c NATSHeaderCarrier) Get(key string) string {
+	if values := c.Header[key]; len(values) > 0 {
+		return values[0]
+	}
+	return ""
+}
+
+// Set replaces the values of the header with exactly the given key.
+func (c NATSHeaderCarrier) Set(key, value string) {
+	c.Header[key] = []string{value}
+}
+
+// Keys returns the keys of all the headers.
+func (c NATSHeaderCarrier) Keys() []string {
+	keys := make([]string, 0, len(c.Header))
+	for key := range c.Header {
+		keys = append(keys, key)
+	}
+	return keys
+}
@@ -0,0 +1,29 @@
+package messaging
+
+import (
+	"context"
+	"testing"
+
+	"github.com/nats-io/nats.go"
+	"go.opentelemetry.io/otel/propagation"
+	"go.opentelemetry.io/otel/trace"
+)
+
+func TestNATSHeaderCarrierKeepsTheCase(t *testing.T) {
+	// a message published by a client setting the W3C header in lowercase, as the specification writes it
+	msg := &nats.Msg{Header: nats.Header{
+		"traceparent": {"00-0af7651916cd43dd8448eb211c80319c-b7ad6b7169203331-01"},
+	}}
+
+	sc := trace.SpanContextFromContext(propagation.TraceContext{}.Extract(context.Background(), NewNATSHeaderCarrier(msg)))
+	if sc.TraceID().String() != "0af7651916cd43dd8448eb211c80319c" || sc.SpanID().String() != "b7ad6b7169203331" || !sc.IsRemote() {
+		t.Errorf("extracted %v, want the span context of the lowercase traceparent header", sc)
+	}
+
+	// the header injected keeps the key of the specification as well
+	out := &nats.Msg{}
+	propagation.TraceContext{}.Inject(trace.ContextWithSpanContext(context.Background(), sc), NewNATSHeaderCarrier(out))
+	if got := out.Header["traceparent"]; len(got) != 1 || got[0] != "00-0af7651916cd43dd8448eb211c80319c-b7ad6b7169203331-01" {
+		t.Errorf("injected headers %v, want a lowercase traceparent header", out.Header)
+	}
+}
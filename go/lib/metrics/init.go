@@ -0,0 +1,57 @@
+package metrics
+
+import (
+	"net/http"
+
+	"github.com/legosandorigami/opentelemetry-tutorial/go/lib/tracing"
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/promhttp"
+	"go.opentelemetry.io/otel"
+	otelprom "go.opentelemetry.io/otel/exporters/prometheus"
+	metricSdk "go.opentelemetry.io/otel/sdk/metric"
+	"go.opentelemetry.io/otel/sdk/metric/exemplar"
+)
+
+// InitPrometheusMeterProvider initializes the OpenTelemetry MeterProvider with the specified service name, exposing the
+// metrics in the Prometheus format through the returned handler.
+// Measurements recorded with a context holding a sampled span carry the trace ID as an exemplar, which Prometheus
+// scrapes in the OpenMetrics format.
+func InitPrometheusMeterProvider(service string) (*metricSdk.MeterProvider, http.Handler, error) {
+	return InitPrometheusMeterProviderWithExemplarFilter(service, exemplar.TraceBasedFilter)
+}
+
+// InitPrometheusMeterProviderWithExemplarFilter initializes the OpenTelemetry MeterProvider like
+// InitPrometheusMeterProvider, filter deciding which measurements are kept as exemplars.
+func InitPrometheusMeterProviderWithExemplarFilter(service string, filter exemplar.Filter) (*metricSdk.MeterProvider, http.Handler, error) {
+	return InitPrometheusMeterProviderWithOptions(service, metricSdk.WithExemplarFilter(filter))
+}
+
+// InitPrometheusMeterProviderWithOptions initializes the OpenTelemetry MeterProvider like InitPrometheusMeterProvider,
+// applying the given options, e.g. views, after the reader and the resource.
+func InitPrometheusMeterProviderWithOptions(service string, opts ...metricSdk.Option) (*metricSdk.MeterProvider, http.Handler, error) {
+	// creating a dedicated registry, so that only the OpenTelemetry metrics are exposed
+	registry := prometheus.NewRegistry()
+	exporter, err := otelprom.New(otelprom.WithRegisterer(registry))
+	if err != nil {
+		return nil, nil, err
+	}
+
+	// describing the service with the same resource as its traces
+	res, err := tracing.NewResource(service)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	// creating a MeterProvider with the specified options, the exemplars are kept for the measurements made inside
+	// sampled spans unless an option says otherwise
+	mp := metricSdk.NewMeterProvider(append([]metricSdk.Option{
+		metricSdk.WithReader(exporter),
+		metricSdk.WithResource(res),
+		metricSdk.WithExemplarFilter(exemplar.TraceBasedFilter),
+	}, opts...)...)
+
+	// setting up the global meter provider
+	otel.SetMeterProvider(mp)
+
+	return mp, promhttp.HandlerFor(registry, promhttp.HandlerOpts{EnableOpenMetrics: true}), nil
+}
@@ -0,0 +1,56 @@
+package metrics
+
+import (
+	"context"
+	"time"
+
+	"github.com/legosandorigami/opentelemetry-tutorial/go/lib/tracing"
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/exporters/otlp/otlpmetric/otlpmetrichttp"
+	metricSdk "go.opentelemetry.io/otel/sdk/metric"
+	"go.opentelemetry.io/otel/sdk/metric/exemplar"
+)
+
+const (
+	METRICS_BACKEND = "localhost:4318"
+
+	// EXPORT_INTERVAL is how often the metrics are pushed to the backend, shorter than the SDK default of one minute
+	// so that the measurements of a short run show up quickly
+	EXPORT_INTERVAL = 10 * time.Second
+)
+
+// InitMeterProvider initializes the OpenTelemetry MeterProvider with the specified service name and default backend.
+func InitMeterProvider(service string) (*metricSdk.MeterProvider, error) {
+	return InitMeterProviderWithBackend(service, METRICS_BACKEND)
+}
+
+// InitMeterProviderWithBackend initializes the OpenTelemetry MeterProvider with the specified service name, pushing the
+// metrics to the specified backend over OTLP.
+func InitMeterProviderWithBackend(service, backend string) (*metricSdk.MeterProvider, error) {
+	ctx := context.Background()
+
+	// creating an OTLP metric exporter to send the metrics to the specified backend
+	exporter, err := otlpmetrichttp.New(ctx, otlpmetrichttp.WithEndpoint(backend), otlpmetrichttp.WithInsecure())
+	if err != nil {
+		return nil, err
+	}
+
+	// describing the service with the same resource as its traces
+	res, err := tracing.NewResource(service)
+	if err != nil {
+		return nil, err
+	}
+
+	// creating a MeterProvider collecting and exporting the metrics periodically, and keeping exemplars for the
+	// measurements made inside sampled spans
+	mp := metricSdk.NewMeterProvider(
+		metricSdk.WithReader(metricSdk.NewPeriodicReader(exporter, metricSdk.WithInterval(EXPORT_INTERVAL))),
+		metricSdk.WithResource(res),
+		metricSdk.WithExemplarFilter(exemplar.TraceBasedFilter),
+	)
+
+	// setting up the global meter provider
+	otel.SetMeterProvider(mp)
+
+	return mp, nil
+}
@@ -0,0 +1,61 @@
+package testutil
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/legosandorigami/opentelemetry-tutorial/go/lib/tracing"
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/propagation"
+	traceSdk "go.opentelemetry.io/otel/sdk/trace"
+	"go.opentelemetry.io/otel/sdk/trace/tracetest"
+	"go.opentelemetry.io/otel/trace"
+)
+
+// HelloServices builds the three programs of a lesson out of the code of the lesson, each one tracing with the tracer
+// it is given.
+type HelloServices struct {
+	// Formatter returns the handler of the formatter
+	Formatter func(tracer trace.Tracer) http.Handler
+	// Publisher returns the handler of the publisher
+	Publisher func(tracer trace.Tracer) http.Handler
+	// Client greets helloTo through the formatter and the publisher at the given URLs
+	Client func(ctx context.Context, tracer trace.Tracer, formatterURL, publisherURL, helloTo string) error
+}
+
+// RunHello runs a whole greeting in process: the formatter and the publisher on httptest servers, on ephemeral
+// ports, and the client as a function call. Each program gets a TracerProvider of its own, with the resource of its
+// service, "hello-world", "formatter" or "publisher", and all three record their spans in the returned SpanRecorder.
+// The error is the one returned by the client.
+//
+// The requests go through real HTTP connections, with the trace context and the baggage propagated in their headers
+// by the global propagator, which RunHello sets for the duration of the test. The tests using it must not run in
+// parallel.
+func RunHello(t testing.TB, services HelloServices, helloTo string) (*tracetest.SpanRecorder, error) {
+	t.Helper()
+
+	previous := otel.GetTextMapPropagator()
+	otel.SetTextMapPropagator(propagation.NewCompositeTextMapPropagator(propagation.TraceContext{}, propagation.Baggage{}))
+	t.Cleanup(func() { otel.SetTextMapPropagator(previous) })
+
+	sr := tracetest.NewSpanRecorder()
+	newTracer := func(service string) trace.Tracer {
+		res, err := tracing.NewResource(service)
+		if err != nil {
+			t.Fatalf("failed to create the resource of %s: %v", service, err)
+		}
+		tp := traceSdk.NewTracerProvider(traceSdk.WithSpanProcessor(sr), traceSdk.WithResource(res))
+		t.Cleanup(func() { tp.Shutdown(context.Background()) })
+		return tp.Tracer(service + "-tracer")
+	}
+
+	formatter := httptest.NewServer(services.Formatter(newTracer("formatter")))
+	defer formatter.Close()
+	publisher := httptest.NewServer(services.Publisher(newTracer("publisher")))
+	defer publisher.Close()
+
+	err := services.Client(context.Background(), newTracer("hello-world"), formatter.URL, publisher.URL, helloTo)
+	return sr, err
+}
@@ -0,0 +1,244 @@
+// Package testutil helps testing the services of the tutorial in process, through the same code paths they use in
+// production.
+package testutil
+
+import (
+	"compress/gzip"
+	"encoding/base64"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	coltracepb "go.opentelemetry.io/proto/otlp/collector/trace/v1"
+	commonpb "go.opentelemetry.io/proto/otlp/common/v1"
+	tracepb "go.opentelemetry.io/proto/otlp/trace/v1"
+	"google.golang.org/protobuf/encoding/protojson"
+	"google.golang.org/protobuf/proto"
+)
+
+// ReceivedSpan is a span received over OTLP, with the resource and the instrumentation scope it was sent with.
+type ReceivedSpan struct {
+	*tracepb.Span
+	// Resource holds the attributes of the resource of the span
+	Resource []*commonpb.KeyValue
+	// Scope is the name of the tracer which created the span
+	Scope string
+}
+
+// Service returns the service.name attribute of the resource of the span.
+func (s ReceivedSpan) Service() string {
+	for _, kv := range s.Resource {
+		if kv.Key == "service.name" {
+			return kv.Value.GetStringValue()
+		}
+	}
+	return ""
+}
+
+// TraceID returns the hex-encoded trace ID of the span.
+func (s ReceivedSpan) TraceID() string {
+	return hex.EncodeToString(s.Span.TraceId)
+}
+
+// SpanID returns the hex-encoded ID of the span.
+func (s ReceivedSpan) SpanID() string {
+	return hex.EncodeToString(s.Span.SpanId)
+}
+
+// ParentSpanID returns the hex-encoded ID of the parent of the span, empty for a root span.
+func (s ReceivedSpan) ParentSpanID() string {
+	return hex.EncodeToString(s.Span.ParentSpanId)
+}
+
+// OTLPReceiver is an OTLP/HTTP trace receiver running in process, on an ephemeral port. The services export to it
+// through their real exporter, so that a test covers the exporter and the propagation between the services, and not
+// only the SDK.
+type OTLPReceiver struct {
+	srv *httptest.Server
+
+	mu    sync.Mutex
+	spans []ReceivedSpan
+}
+
+// NewOTLPReceiver starts a receiver on an ephemeral port, closed when the test ends.
+func NewOTLPReceiver(t testing.TB) *OTLPReceiver {
+	r, err := StartOTLPReceiver("127.0.0.1:0")
+	if err != nil {
+		t.Fatalf("failed to start the OTLP receiver: %v", err)
+	}
+	t.Cleanup(r.Close)
+	return r
+}
+
+// StartOTLPReceiver starts a receiver listening on addr, e.g. "localhost:4318" for the programs exporting to the
+// default endpoint, outside of a test. It must be closed once done.
+func StartOTLPReceiver(addr string) (*OTLPReceiver, error) {
+	l, err := net.Listen("tcp", addr)
+	if err != nil {
+		return nil, err
+	}
+
+	r := &OTLPReceiver{}
+	mux := http.NewServeMux()
+	mux.HandleFunc("POST /v1/traces", r.export)
+	r.srv = httptest.NewUnstartedServer(mux)
+	r.srv.Listener.Close()
+	r.srv.Listener = l
+	r.srv.Start()
+	return r, nil
+}
+
+// Close stops the receiver.
+func (r *OTLPReceiver) Close() {
+	r.srv.Close()
+}
+
+// Endpoint returns the host:port of the receiver, to be used as the OTLP endpoint of the services.
+func (r *OTLPReceiver) Endpoint() string {
+	return strings.TrimPrefix(r.srv.URL, "http://")
+}
+
+// Spans returns the spans received so far, in the order they were received.
+func (r *OTLPReceiver) Spans() []ReceivedSpan {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	return append([]ReceivedSpan(nil), r.spans...)
+}
+
+// Reset forgets the spans received so far.
+func (r *OTLPReceiver) Reset() {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.spans = nil
+}
+
+// WaitForSpans waits until the receiver holds at least n spans, and returns them. It fails the test after timeout,
+// e.g. when the services were not flushed: the batch span processor only exports every 5 seconds on its own.
+func (r *OTLPReceiver) WaitForSpans(t testing.TB, n int, timeout time.Duration) []ReceivedSpan {
+	t.Helper()
+	deadline := time.Now().Add(timeout)
+	for {
+		spans := r.Spans()
+		if len(spans) >= n {
+			return spans
+		}
+		if time.Now().After(deadline) {
+			t.Fatalf("received %d spans after %s, want %d", len(spans), timeout, n)
+		}
+		time.Sleep(10 * time.Millisecond)
+	}
+}
+
+// export handles an export request, encoded in protobuf as the exporters of the SDK send them, or in JSON
+func (r *OTLPReceiver) export(w http.ResponseWriter, req *http.Request) {
+	body := io.Reader(req.Body)
+	if req.Header.Get("Content-Encoding") == "gzip" {
+		gz, err := gzip.NewReader(req.Body)
+		if err != nil {
+			http.Error(w, err.Error(), http.StatusBadRequest)
+			return
+		}
+		defer gz.Close()
+		body = gz
+	}
+	b, err := io.ReadAll(body)
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+
+	var export coltracepb.ExportTraceServiceRequest
+	contentType := req.Header.Get("Content-Type")
+	switch contentType {
+	case "application/x-protobuf":
+		err = proto.Unmarshal(b, &export)
+	case "application/json":
+		err = unmarshalJSON(b, &export)
+	default:
+		http.Error(w, fmt.Sprintf("unsupported content type %q", contentType), http.StatusUnsupportedMediaType)
+		return
+	}
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+
+	r.add(&export)
+
+	resp, err := proto.Marshal(&coltracepb.ExportTraceServiceResponse{})
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+		return
+	}
+	w.Header().Set("Content-Type", "application/x-protobuf")
+	w.Write(resp)
+}
+
+// add flattens the spans of the export request
+func (r *OTLPReceiver) add(export *coltracepb.ExportTraceServiceRequest) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	for _, rs := range export.ResourceSpans {
+		for _, ss := range rs.ScopeSpans {
+			for _, s := range ss.Spans {
+				r.spans = append(r.spans, ReceivedSpan{
+					Span:     s,
+					Resource: rs.GetResource().GetAttributes(),
+					Scope:    ss.GetScope().GetName(),
+				})
+			}
+		}
+	}
+}
+
+// unmarshalJSON decodes an OTLP/JSON export request. Unlike the standard JSON mapping of protobuf, OTLP/JSON encodes
+// the trace and span IDs in hex rather than base64, so they are converted before decoding.
+func unmarshalJSON(b []byte, export *coltracepb.ExportTraceServiceRequest) error {
+	var v any
+	if err := json.Unmarshal(b, &v); err != nil {
+		return err
+	}
+	if err := hexToBase64(v); err != nil {
+		return err
+	}
+	b, err := json.Marshal(v)
+	if err != nil {
+		return err
+	}
+	return protojson.UnmarshalOptions{DiscardUnknown: true}.Unmarshal(b, export)
+}
+
+// hexToBase64 converts the hex-encoded IDs found in the decoded JSON value v to base64, in place
+func hexToBase64(v any) error {
+	switch v := v.(type) {
+	case map[string]any:
+		for k, child := range v {
+			if id, ok := child.(string); ok && (k == "traceId" || k == "spanId" || k == "parentSpanId") {
+				raw, err := hex.DecodeString(id)
+				if err != nil {
+					return fmt.Errorf("invalid %s %q: %v", k, id, err)
+				}
+				v[k] = base64.StdEncoding.EncodeToString(raw)
+				continue
+			}
+			if err := hexToBase64(child); err != nil {
+				return err
+			}
+		}
+	case []any:
+		for _, child := range v {
+			if err := hexToBase64(child); err != nil {
+				return err
+			}
+		}
+	}
+	return nil
+}
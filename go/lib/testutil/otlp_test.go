@@ -0,0 +1,110 @@
+package testutil
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/legosandorigami/opentelemetry-tutorial/go/lib/tracing"
+	"go.opentelemetry.io/otel/propagation"
+	traceSdk "go.opentelemetry.io/otel/sdk/trace"
+	"go.opentelemetry.io/otel/trace"
+)
+
+func TestOTLPReceiver(t *testing.T) {
+	receiver := NewOTLPReceiver(t)
+
+	// exporting through the exporter and the batch processor of the services
+	res, err := tracing.NewResource("testutil")
+	if err != nil {
+		t.Fatal(err)
+	}
+	tp, err := tracing.NewTracerProvider(res, receiver.Endpoint(), traceSdk.AlwaysSample())
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer tp.Shutdown(context.Background())
+	tracer := tp.Tracer("testutil-tracer")
+	propagator := propagation.TraceContext{}
+
+	// propagating the span context of a client span to a server span through the headers of a request
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		ctx := propagator.Extract(r.Context(), propagation.HeaderCarrier(r.Header))
+		_, span := tracer.Start(ctx, "server", trace.WithSpanKind(trace.SpanKindServer))
+		span.End()
+	}))
+	defer srv.Close()
+
+	ctx, span := tracer.Start(context.Background(), "client", trace.WithSpanKind(trace.SpanKindClient))
+	req, err := http.NewRequestWithContext(ctx, "GET", srv.URL, nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	propagator.Inject(ctx, propagation.HeaderCarrier(req.Header))
+	resp, err := http.DefaultClient.Do(req)
+	if err != nil {
+		t.Fatal(err)
+	}
+	resp.Body.Close()
+	span.End()
+
+	if err := tp.ForceFlush(context.Background()); err != nil {
+		t.Fatalf("ForceFlush() = %v", err)
+	}
+	spans := receiver.WaitForSpans(t, 2, 5*time.Second)
+
+	byName := make(map[string]ReceivedSpan)
+	for _, s := range spans {
+		byName[s.Name] = s
+	}
+	client, server := byName["client"], byName["server"]
+	if client.Span == nil || server.Span == nil {
+		t.Fatalf("received %v, want the client and server spans", spans)
+	}
+	if server.TraceID() != client.TraceID() || server.ParentSpanID() != client.SpanID() {
+		t.Errorf("server span %s/%s is not a child of the client span %s/%s", server.TraceID(), server.ParentSpanID(), client.TraceID(), client.SpanID())
+	}
+	if client.ParentSpanID() != "" {
+		t.Errorf("client span has parent %s, want none", client.ParentSpanID())
+	}
+	if got := client.Service(); got != "testutil" {
+		t.Errorf("service = %q, want %q", got, "testutil")
+	}
+	if client.Scope != "testutil-tracer" {
+		t.Errorf("scope = %q, want %q", client.Scope, "testutil-tracer")
+	}
+}
+
+func TestOTLPReceiverJSON(t *testing.T) {
+	receiver := NewOTLPReceiver(t)
+
+	// a span as the browser of Lesson 31 sends it, the IDs being hex-encoded in OTLP/JSON
+	body := `{"resourceSpans":[{"resource":{"attributes":[{"key":"service.name","value":{"stringValue":"browser"}}]},
+		"scopeSpans":[{"scope":{"name":"browser-tracer"},"spans":[{"traceId":"0af7651916cd43dd8448eb211c80319c",
+		"spanId":"b7ad6b7169203331","name":"say-hello","kind":3,"startTimeUnixNano":"1","endTimeUnixNano":"2"}]}]}]}`
+	resp, err := http.Post("http://"+receiver.Endpoint()+"/v1/traces", "application/json", strings.NewReader(body))
+	if err != nil {
+		t.Fatal(err)
+	}
+	resp.Body.Close()
+	if resp.StatusCode != http.StatusOK {
+		t.Fatalf("status = %d, want %d", resp.StatusCode, http.StatusOK)
+	}
+
+	spans := receiver.Spans()
+	if len(spans) != 1 {
+		t.Fatalf("received %d spans, want 1", len(spans))
+	}
+	if got, want := spans[0].TraceID(), "0af7651916cd43dd8448eb211c80319c"; got != want {
+		t.Errorf("trace ID = %s, want %s", got, want)
+	}
+	if got, want := spans[0].SpanID(), "b7ad6b7169203331"; got != want {
+		t.Errorf("span ID = %s, want %s", got, want)
+	}
+	if got := spans[0].Service(); got != "browser" {
+		t.Errorf("service = %q, want %q", got, "browser")
+	}
+}
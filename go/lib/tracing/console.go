@@ -0,0 +1,196 @@
+package tracing
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"os"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/codes"
+	traceSdk "go.opentelemetry.io/otel/sdk/trace"
+	semconv "go.opentelemetry.io/otel/semconv/v1.4.0"
+	"go.opentelemetry.io/otel/trace"
+)
+
+// CONSOLE_SPANS_ENV is the environment variable which, set to true, makes NewTracerProvider print the finished traces
+// to the terminal with a ConsoleExporter, instead of the span contexts printed by PrintSpanContents.
+const CONSOLE_SPANS_ENV = "CONSOLE_SPANS"
+
+// the ANSI escape codes coloring the output
+const (
+	ansiReset = "\x1b[0m"
+	ansiBold  = "\x1b[1m"
+	ansiDim   = "\x1b[2m"
+	ansiRed   = "\x1b[31m"
+	ansiCyan  = "\x1b[36m"
+	ansiYell  = "\x1b[33m"
+)
+
+// ConsoleExporter is a SpanExporter printing the spans as a tree, each span under its parent with its duration,
+// attributes and events. A span is held until the local root of its trace, the span of the process whose parent is
+// remote or missing, is exported: register it with a simple span processor, so that the spans reach it as soon as
+// they end, children first.
+type ConsoleExporter struct {
+	w     io.Writer
+	color bool
+
+	mu      sync.Mutex
+	pending map[trace.TraceID][]traceSdk.ReadOnlySpan
+}
+
+// NewConsoleExporter returns an exporter printing to w, colored when w is a terminal and the NO_COLOR environment
+// variable is not set.
+func NewConsoleExporter(w io.Writer) *ConsoleExporter {
+	return &ConsoleExporter{
+		w:       w,
+		color:   isTerminal(w) && os.Getenv("NO_COLOR") == "",
+		pending: make(map[trace.TraceID][]traceSdk.ReadOnlySpan),
+	}
+}
+
+// isTerminal reports whether w is a terminal
+func isTerminal(w io.Writer) bool {
+	f, ok := w.(*os.File)
+	if !ok {
+		return false
+	}
+	fi, err := f.Stat()
+	return err == nil && fi.Mode()&os.ModeCharDevice != 0
+}
+
+// consoleSpansEnabled reports whether CONSOLE_SPANS_ENV is set to true
+func consoleSpansEnabled() bool {
+	v, _ := strconv.ParseBool(os.Getenv(CONSOLE_SPANS_ENV))
+	return v
+}
+
+// ExportSpans prints the trees of the traces whose local root is among spans, and holds the other spans.
+func (e *ConsoleExporter) ExportSpans(ctx context.Context, spans []traceSdk.ReadOnlySpan) error {
+	e.mu.Lock()
+	defer e.mu.Unlock()
+
+	for _, s := range spans {
+		traceID := s.SpanContext().TraceID()
+		e.pending[traceID] = append(e.pending[traceID], s)
+		if parent := s.Parent(); !parent.IsValid() || parent.IsRemote() {
+			e.print(e.pending[traceID])
+			delete(e.pending, traceID)
+		}
+	}
+	return nil
+}
+
+// Shutdown prints the spans still held, whose local root never ended.
+func (e *ConsoleExporter) Shutdown(ctx context.Context) error {
+	e.mu.Lock()
+	defer e.mu.Unlock()
+
+	for traceID, spans := range e.pending {
+		e.print(spans)
+		delete(e.pending, traceID)
+	}
+	return nil
+}
+
+// print writes the spans of a trace as trees, one per span whose parent is not among spans
+func (e *ConsoleExporter) print(spans []traceSdk.ReadOnlySpan) {
+	ids := make(map[trace.SpanID]bool)
+	children := make(map[trace.SpanID][]traceSdk.ReadOnlySpan)
+	for _, s := range spans {
+		ids[s.SpanContext().SpanID()] = true
+		children[s.Parent().SpanID()] = append(children[s.Parent().SpanID()], s)
+	}
+	for _, c := range children {
+		sort.SliceStable(c, func(i, j int) bool { return c[i].StartTime().Before(c[j].StartTime()) })
+	}
+
+	var b strings.Builder
+	for _, s := range spans {
+		if !ids[s.Parent().SpanID()] {
+			e.printSpan(&b, s, children, "", "")
+		}
+	}
+	io.WriteString(e.w, b.String())
+}
+
+// printSpan writes the span, after the prefix of its first line, then its details and its children after the prefix
+// of the next lines
+func (e *ConsoleExporter) printSpan(b *strings.Builder, s traceSdk.ReadOnlySpan, children map[trace.SpanID][]traceSdk.ReadOnlySpan, first, next string) {
+	kids := children[s.SpanContext().SpanID()]
+
+	// the first line: the name of the span, its service and kind, and its duration
+	name := e.paint(ansiBold, s.Name())
+	if s.Status().Code == codes.Error {
+		name = e.paint(ansiBold+ansiRed, s.Name())
+	}
+	fmt.Fprintf(b, "%s%s %s %s", first, name,
+		e.paint(ansiCyan, fmt.Sprintf("(%s, %s)", service(s), s.SpanKind())),
+		e.paint(ansiYell, formatDuration(s.EndTime().Sub(s.StartTime()))))
+	if s.Parent().IsRemote() {
+		b.WriteString(e.paint(ansiDim, " remote parent "+s.Parent().SpanID().String()))
+	}
+	b.WriteString("\n")
+
+	// the details, under the name, along the line to the children
+	details := next + "   "
+	if len(kids) > 0 {
+		details = next + "│  "
+	}
+	if s.Status().Code == codes.Error {
+		fmt.Fprintf(b, "%s%s\n", details, e.paint(ansiRed, "error: "+s.Status().Description))
+	}
+	for _, kv := range s.Attributes() {
+		fmt.Fprintf(b, "%s%s\n", details, e.formatAttribute(kv))
+	}
+	for _, ev := range s.Events() {
+		line := fmt.Sprintf("• +%s %s", formatDuration(ev.Time.Sub(s.StartTime())), ev.Name)
+		for _, kv := range ev.Attributes {
+			line += " " + e.formatAttribute(kv)
+		}
+		fmt.Fprintf(b, "%s%s\n", details, line)
+	}
+
+	for i, c := range kids {
+		if i == len(kids)-1 {
+			e.printSpan(b, c, children, next+"└─ ", next+"   ")
+		} else {
+			e.printSpan(b, c, children, next+"├─ ", next+"│  ")
+		}
+	}
+}
+
+// formatAttribute formats an attribute as key=value, quoting the strings
+func (e *ConsoleExporter) formatAttribute(kv attribute.KeyValue) string {
+	value := kv.Value.Emit()
+	if kv.Value.Type() == attribute.STRING {
+		value = fmt.Sprintf("%q", value)
+	}
+	return e.paint(ansiDim, string(kv.Key)+"=") + value
+}
+
+// paint colors s with the escape codes, if the exporter is colored
+func (e *ConsoleExporter) paint(codes, s string) string {
+	if !e.color {
+		return s
+	}
+	return codes + s + ansiReset
+}
+
+// service returns the service name of the resource of the span
+func service(s traceSdk.ReadOnlySpan) string {
+	if v, ok := s.Resource().Set().Value(semconv.ServiceNameKey); ok {
+		return v.AsString()
+	}
+	return "unknown service"
+}
+
+// formatDuration formats d in milliseconds
+func formatDuration(d time.Duration) string {
+	return fmt.Sprintf("%.2fms", float64(d)/float64(time.Millisecond))
+}
@@ -0,0 +1,94 @@
+package tracing
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/codes"
+	"go.opentelemetry.io/otel/sdk/resource"
+	traceSdk "go.opentelemetry.io/otel/sdk/trace"
+	semconv "go.opentelemetry.io/otel/semconv/v1.4.0"
+	"go.opentelemetry.io/otel/trace"
+)
+
+// newConsoleTracer returns a tracer of the "hello-world" service whose spans are printed to the returned buffer, with
+// sequential IDs and timestamps 1ms apart
+func newConsoleTracer() (trace.Tracer, *traceSdk.TracerProvider, *bytes.Buffer) {
+	var out bytes.Buffer
+	tp := traceSdk.NewTracerProvider(
+		traceSdk.WithSpanProcessor(traceSdk.NewSimpleSpanProcessor(NewConsoleExporter(&out))),
+		traceSdk.WithIDGenerator(NewSequentialIDGenerator()),
+		traceSdk.WithResource(resource.NewSchemaless(semconv.ServiceNameKey.String("hello-world"))),
+	)
+	clock := NewStepClock(time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC), time.Millisecond)
+	return WithClock(tp, clock).Tracer("test"), tp, &out
+}
+
+func TestConsoleExporter(t *testing.T) {
+	tracer, _, out := newConsoleTracer()
+
+	ctx, root := tracer.Start(context.Background(), "say-hello", trace.WithAttributes(attribute.String("hello-to", "Bryan")))
+	fctx, format := tracer.Start(ctx, "formatString", trace.WithSpanKind(trace.SpanKindClient))
+	_, call := tracer.Start(fctx, "GET /format", trace.WithSpanKind(trace.SpanKindClient))
+	call.End()
+	format.AddEvent("formatted", trace.WithAttributes(attribute.String("greeting", "Hello, Bryan!")))
+	format.End()
+	_, print := tracer.Start(ctx, "printHello", trace.WithAttributes(attribute.Int("retries", 2)))
+	print.RecordError(errors.New("publisher down"))
+	print.SetStatus(codes.Error, "publisher down")
+	print.End()
+
+	// nothing is printed until the root span ends
+	if out.Len() != 0 {
+		t.Fatalf("printed before the end of the trace:\n%s", out)
+	}
+	root.End()
+
+	want := `say-hello (hello-world, internal) 9.00ms
+│  hello-to="Bryan"
+├─ formatString (hello-world, client) 4.00ms
+│  │  • +3.00ms formatted greeting="Hello, Bryan!"
+│  └─ GET /format (hello-world, client) 1.00ms
+└─ printHello (hello-world, internal) 2.00ms
+      error: publisher down
+      retries=2
+      • +1.00ms exception exception.type="*errors.errorString" exception.message="publisher down"
+`
+	if got := out.String(); got != want {
+		t.Errorf("printed:\n%s\nwant:\n%s", got, want)
+	}
+}
+
+func TestConsoleExporterRemoteParent(t *testing.T) {
+	tracer, tp, out := newConsoleTracer()
+
+	// the trace of a server starts with a span whose parent is in another process
+	parent := trace.NewSpanContext(trace.SpanContextConfig{
+		TraceID:    trace.TraceID{0x01},
+		SpanID:     trace.SpanID{0x02},
+		TraceFlags: trace.FlagsSampled,
+		Remote:     true,
+	})
+	ctx, span := tracer.Start(trace.ContextWithRemoteSpanContext(context.Background(), parent), "format", trace.WithSpanKind(trace.SpanKindServer))
+	_, orphan := tracer.Start(ctx, "late")
+	span.End()
+
+	want := "format (hello-world, server) 2.00ms remote parent 0200000000000000\n"
+	if got := out.String(); got != want {
+		t.Errorf("printed %q, want %q", got, want)
+	}
+
+	// a span ending after its local root is printed at shutdown
+	out.Reset()
+	orphan.End()
+	if err := tp.Shutdown(context.Background()); err != nil {
+		t.Fatal(err)
+	}
+	if got, want := out.String(), "late (hello-world, internal) 2.00ms\n"; got != want {
+		t.Errorf("printed %q at shutdown, want %q", got, want)
+	}
+}
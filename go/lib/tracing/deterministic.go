@@ -0,0 +1,130 @@
+package tracing
+
+import (
+	"context"
+	"encoding/binary"
+	"sync"
+	"time"
+
+	traceSdk "go.opentelemetry.io/otel/sdk/trace"
+	"go.opentelemetry.io/otel/trace"
+)
+
+// sequentialIDGenerator hands out the trace and span IDs 1, 2, 3...
+type sequentialIDGenerator struct {
+	mu     sync.Mutex
+	traces uint64
+	spans  uint64
+}
+
+// NewSequentialIDGenerator returns an IDGenerator numbering the traces and the spans from 1, for the tests and the
+// teaching material which need the same IDs on every run, e.g.
+//
+//	traceSdk.NewTracerProvider(traceSdk.WithIDGenerator(tracing.NewSequentialIDGenerator()))
+//
+// The IDs are only unique within the generator: never use it to export spans shared with other processes.
+func NewSequentialIDGenerator() traceSdk.IDGenerator {
+	return &sequentialIDGenerator{}
+}
+
+func (g *sequentialIDGenerator) NewIDs(ctx context.Context) (trace.TraceID, trace.SpanID) {
+	g.mu.Lock()
+	defer g.mu.Unlock()
+	g.traces++
+	g.spans++
+
+	var traceID trace.TraceID
+	binary.BigEndian.PutUint64(traceID[8:], g.traces)
+	var spanID trace.SpanID
+	binary.BigEndian.PutUint64(spanID[:], g.spans)
+	return traceID, spanID
+}
+
+func (g *sequentialIDGenerator) NewSpanID(ctx context.Context, traceID trace.TraceID) trace.SpanID {
+	g.mu.Lock()
+	defer g.mu.Unlock()
+	g.spans++
+
+	var spanID trace.SpanID
+	binary.BigEndian.PutUint64(spanID[:], g.spans)
+	return spanID
+}
+
+// Clock tells the time to the spans of a TracerProvider wrapped by WithClock.
+type Clock interface {
+	Now() time.Time
+}
+
+// StepClock is a Clock moving forward by a fixed step every time it is read, so that the timestamps, and the durations
+// of the spans, only depend on the order of the calls.
+type StepClock struct {
+	mu   sync.Mutex
+	now  time.Time
+	step time.Duration
+}
+
+// NewStepClock returns a clock whose first reading is start, each later one step after the previous one.
+func NewStepClock(start time.Time, step time.Duration) *StepClock {
+	return &StepClock{now: start, step: step}
+}
+
+// Now returns the current time of the clock, and moves it forward.
+func (c *StepClock) Now() time.Time {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	now := c.now
+	c.now = c.now.Add(c.step)
+	return now
+}
+
+// WithClock returns a TracerProvider timestamping the start and the end of the spans of tp, and their events, with
+// clock rather than with the system clock. The SDK has no option for it, so the timestamps are passed explicitly to
+// every call, a timestamp given by the caller still taking precedence.
+func WithClock(tp trace.TracerProvider, clock Clock) trace.TracerProvider {
+	return &clockTracerProvider{TracerProvider: tp, clock: clock}
+}
+
+type clockTracerProvider struct {
+	trace.TracerProvider
+	clock Clock
+}
+
+func (p *clockTracerProvider) Tracer(name string, opts ...trace.TracerOption) trace.Tracer {
+	return &clockTracer{Tracer: p.TracerProvider.Tracer(name, opts...), provider: p}
+}
+
+type clockTracer struct {
+	trace.Tracer
+	provider *clockTracerProvider
+}
+
+func (t *clockTracer) Start(ctx context.Context, name string, opts ...trace.SpanStartOption) (context.Context, trace.Span) {
+	// the options given last win, so the timestamp goes first
+	opts = append([]trace.SpanStartOption{trace.WithTimestamp(t.provider.clock.Now())}, opts...)
+	ctx, span := t.Tracer.Start(ctx, name, opts...)
+
+	// storing the wrapped span in the context, so that trace.SpanFromContext returns it as well
+	s := &clockSpan{Span: span, provider: t.provider}
+	return trace.ContextWithSpan(ctx, s), s
+}
+
+type clockSpan struct {
+	trace.Span
+	provider *clockTracerProvider
+}
+
+func (s *clockSpan) End(opts ...trace.SpanEndOption) {
+	s.Span.End(append([]trace.SpanEndOption{trace.WithTimestamp(s.provider.clock.Now())}, opts...)...)
+}
+
+func (s *clockSpan) AddEvent(name string, opts ...trace.EventOption) {
+	s.Span.AddEvent(name, append([]trace.EventOption{trace.WithTimestamp(s.provider.clock.Now())}, opts...)...)
+}
+
+func (s *clockSpan) RecordError(err error, opts ...trace.EventOption) {
+	s.Span.RecordError(err, append([]trace.EventOption{trace.WithTimestamp(s.provider.clock.Now())}, opts...)...)
+}
+
+func (s *clockSpan) TracerProvider() trace.TracerProvider {
+	return s.provider
+}
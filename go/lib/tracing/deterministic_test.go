@@ -0,0 +1,69 @@
+package tracing
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	traceSdk "go.opentelemetry.io/otel/sdk/trace"
+	"go.opentelemetry.io/otel/sdk/trace/tracetest"
+	"go.opentelemetry.io/otel/trace"
+)
+
+func TestDeterministicSpans(t *testing.T) {
+	start := time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC)
+	sr := tracetest.NewSpanRecorder()
+	tp := traceSdk.NewTracerProvider(
+		traceSdk.WithSpanProcessor(sr),
+		traceSdk.WithIDGenerator(NewSequentialIDGenerator()),
+	)
+	tracer := WithClock(tp, NewStepClock(start, 10*time.Millisecond)).Tracer("test")
+
+	// root starts at 0ms, child at 10ms, the event is at 20ms, child ends at 30ms and root at 40ms
+	ctx, root := tracer.Start(context.Background(), "root")
+	_, child := tracer.Start(ctx, "child")
+	child.RecordError(errors.New("boom"))
+	child.End()
+	root.End()
+
+	// the spans started from the span in the context, or from its provider, use the clock as well
+	if _, ok := trace.SpanFromContext(ctx).(*clockSpan); !ok {
+		t.Error("the span in the context does not use the clock")
+	}
+	if _, ok := root.TracerProvider().(*clockTracerProvider); !ok {
+		t.Error("the provider of the span does not use the clock")
+	}
+
+	spans := sr.Ended()
+	if len(spans) != 2 {
+		t.Fatalf("recorded %d spans, want 2", len(spans))
+	}
+	c, r := spans[0], spans[1]
+
+	for _, tc := range []struct {
+		name string
+		got  string
+		want string
+	}{
+		{"root trace ID", r.SpanContext().TraceID().String(), "00000000000000000000000000000001"},
+		{"root span ID", r.SpanContext().SpanID().String(), "0000000000000001"},
+		{"child trace ID", c.SpanContext().TraceID().String(), "00000000000000000000000000000001"},
+		{"child span ID", c.SpanContext().SpanID().String(), "0000000000000002"},
+		{"root duration", r.EndTime().Sub(r.StartTime()).String(), "40ms"},
+		{"child start", c.StartTime().Sub(start).String(), "10ms"},
+		{"child duration", c.EndTime().Sub(c.StartTime()).String(), "20ms"},
+		{"event time", c.Events()[0].Time.Sub(start).String(), "20ms"},
+	} {
+		if tc.got != tc.want {
+			t.Errorf("%s = %s, want %s", tc.name, tc.got, tc.want)
+		}
+	}
+
+	// a new root span starts a new trace
+	_, other := tracer.Start(context.Background(), "other")
+	other.End()
+	if got := other.SpanContext().TraceID().String(); got != "00000000000000000000000000000002" {
+		t.Errorf("other trace ID = %s, want 00000000000000000000000000000002", got)
+	}
+}
@@ -0,0 +1,161 @@
+package tracing
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+
+	"go.opentelemetry.io/otel/codes"
+	traceSdk "go.opentelemetry.io/otel/sdk/trace"
+	"go.opentelemetry.io/otel/trace"
+)
+
+// spanTree indexes the spans of a trace by parent, the children of every span sorted by start time, and returns the
+// spans whose parent is not among spans
+func spanTree(spans []traceSdk.ReadOnlySpan) ([]traceSdk.ReadOnlySpan, map[trace.SpanID][]traceSdk.ReadOnlySpan) {
+	ids := make(map[trace.SpanID]bool)
+	for _, s := range spans {
+		ids[s.SpanContext().SpanID()] = true
+	}
+	var roots []traceSdk.ReadOnlySpan
+	children := make(map[trace.SpanID][]traceSdk.ReadOnlySpan)
+	for _, s := range spans {
+		if ids[s.Parent().SpanID()] {
+			children[s.Parent().SpanID()] = append(children[s.Parent().SpanID()], s)
+		} else {
+			roots = append(roots, s)
+		}
+	}
+	byStart := func(spans []traceSdk.ReadOnlySpan) {
+		sort.SliceStable(spans, func(i, j int) bool { return spans[i].StartTime().Before(spans[j].StartTime()) })
+	}
+	byStart(roots)
+	for _, c := range children {
+		byStart(c)
+	}
+	return roots, children
+}
+
+// Mermaid returns a Mermaid sequence diagram of the calls between the services in a trace. Every span whose parent
+// belongs to another service is a call from the service of the parent, answered once the span ends; the spans of a
+// service called from the same service are notes over the service. The client spans are left out, the calls standing
+// for them.
+func Mermaid(spans []traceSdk.ReadOnlySpan) string {
+	roots, children := spanTree(spans)
+
+	var b strings.Builder
+	b.WriteString("sequenceDiagram\n")
+
+	// declaring the services in the order they join the trace
+	seen := make(map[string]bool)
+	var declare func(s traceSdk.ReadOnlySpan)
+	declare = func(s traceSdk.ReadOnlySpan) {
+		if name := service(s); !seen[name] {
+			seen[name] = true
+			fmt.Fprintf(&b, "    participant %s as %s\n", mermaidID(name), mermaidText(name))
+		}
+		for _, c := range children[s.SpanContext().SpanID()] {
+			declare(c)
+		}
+	}
+	for _, r := range roots {
+		declare(r)
+	}
+
+	var walk func(s traceSdk.ReadOnlySpan, caller string)
+	walk = func(s traceSdk.ReadOnlySpan, caller string) {
+		callee := service(s)
+		label := mermaidText(fmt.Sprintf("%s (%s)", s.Name(), formatDuration(s.EndTime().Sub(s.StartTime()))))
+		failed := s.Status().Code == codes.Error
+		switch {
+		case caller != "" && caller != callee:
+			fmt.Fprintf(&b, "    %s->>+%s: %s\n", mermaidID(caller), mermaidID(callee), mermaidText(s.Name()))
+		case s.SpanKind() != trace.SpanKindClient && s.SpanKind() != trace.SpanKindProducer:
+			fmt.Fprintf(&b, "    Note over %s: %s\n", mermaidID(callee), label)
+		}
+
+		for _, c := range children[s.SpanContext().SpanID()] {
+			walk(c, callee)
+		}
+
+		if caller != "" && caller != callee {
+			arrow := "-->>"
+			if failed {
+				arrow = "--x"
+				label += " failed"
+			}
+			fmt.Fprintf(&b, "    %s%s-%s: %s\n", mermaidID(callee), arrow, mermaidID(caller), label)
+		}
+	}
+	for _, r := range roots {
+		walk(r, "")
+	}
+	return b.String()
+}
+
+// mermaidID returns an identifier of the participant of a service, which Mermaid accepts
+func mermaidID(service string) string {
+	return strings.Map(func(r rune) rune {
+		if r >= 'a' && r <= 'z' || r >= 'A' && r <= 'Z' || r >= '0' && r <= '9' || r == '_' {
+			return r
+		}
+		return '_'
+	}, service)
+}
+
+// mermaidText escapes the characters ending a Mermaid statement
+func mermaidText(s string) string {
+	return strings.NewReplacer(";", "#59;", "#", "#35;", "\n", " ").Replace(s)
+}
+
+// Graphviz returns a Graphviz graph of a trace, in the DOT language, with an edge from every span to each of its
+// children and a cluster per service. The failed spans are red.
+func Graphviz(spans []traceSdk.ReadOnlySpan) string {
+	roots, children := spanTree(spans)
+
+	var b strings.Builder
+	b.WriteString("digraph trace {\n    rankdir=LR;\n    node [shape=box, style=rounded];\n")
+
+	// grouping the spans by service, in the order the services join the trace
+	var services []string
+	byService := make(map[string][]traceSdk.ReadOnlySpan)
+	var group func(s traceSdk.ReadOnlySpan)
+	group = func(s traceSdk.ReadOnlySpan) {
+		name := service(s)
+		if _, ok := byService[name]; !ok {
+			services = append(services, name)
+		}
+		byService[name] = append(byService[name], s)
+		for _, c := range children[s.SpanContext().SpanID()] {
+			group(c)
+		}
+	}
+	for _, r := range roots {
+		group(r)
+	}
+
+	for i, name := range services {
+		fmt.Fprintf(&b, "    subgraph cluster_%d {\n        label=%s;\n", i, dotString(name))
+		for _, s := range byService[name] {
+			attrs := fmt.Sprintf("label=%s", dotString(fmt.Sprintf("%s\n%s", s.Name(), formatDuration(s.EndTime().Sub(s.StartTime())))))
+			if s.Status().Code == codes.Error {
+				attrs += ", color=red, fontcolor=red"
+			}
+			fmt.Fprintf(&b, "        %s [%s];\n", dotString(s.SpanContext().SpanID().String()), attrs)
+		}
+		b.WriteString("    }\n")
+	}
+
+	for _, s := range spans {
+		for _, c := range children[s.SpanContext().SpanID()] {
+			fmt.Fprintf(&b, "    %s -> %s;\n", dotString(s.SpanContext().SpanID().String()), dotString(c.SpanContext().SpanID().String()))
+		}
+	}
+	b.WriteString("}\n")
+	return b.String()
+}
+
+// dotString quotes s as a DOT string
+func dotString(s string) string {
+	return `"` + strings.NewReplacer(`\`, `\\`, `"`, `\"`, "\n", `\n`).Replace(s) + `"`
+}
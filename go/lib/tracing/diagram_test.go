@@ -0,0 +1,106 @@
+package tracing
+
+import (
+	"context"
+	"slices"
+	"strings"
+	"testing"
+	"time"
+
+	"go.opentelemetry.io/otel/codes"
+	"go.opentelemetry.io/otel/propagation"
+	"go.opentelemetry.io/otel/sdk/resource"
+	traceSdk "go.opentelemetry.io/otel/sdk/trace"
+	"go.opentelemetry.io/otel/sdk/trace/tracetest"
+	semconv "go.opentelemetry.io/otel/semconv/v1.4.0"
+	"go.opentelemetry.io/otel/trace"
+)
+
+// recordHello records the trace of the hello-world client calling the formatter, which fails, and the publisher,
+// with timestamps 1ms apart
+func recordHello(t *testing.T) []traceSdk.ReadOnlySpan {
+	t.Helper()
+	sr := tracetest.NewSpanRecorder()
+	ids := NewSequentialIDGenerator()
+	clock := NewStepClock(time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC), time.Millisecond)
+	tracer := func(service string) trace.Tracer {
+		tp := traceSdk.NewTracerProvider(
+			traceSdk.WithSpanProcessor(sr),
+			traceSdk.WithIDGenerator(ids),
+			traceSdk.WithResource(resource.NewSchemaless(semconv.ServiceNameKey.String(service))),
+		)
+		return WithClock(tp, clock).Tracer("test")
+	}
+	client, formatter, publisher := tracer("hello-world"), tracer("formatter"), tracer("publisher")
+
+	// calling a service through the propagator, as over the wire
+	call := func(ctx context.Context, name string, server trace.Tracer, serverName string, failed bool) {
+		ctx, span := client.Start(ctx, name, trace.WithSpanKind(trace.SpanKindClient))
+		carrier := propagation.MapCarrier{}
+		propagation.TraceContext{}.Inject(ctx, carrier)
+		_, serverSpan := server.Start(propagation.TraceContext{}.Extract(context.Background(), carrier), serverName, trace.WithSpanKind(trace.SpanKindServer))
+		if failed {
+			serverSpan.SetStatus(codes.Error, "boom")
+		}
+		serverSpan.End()
+		span.End()
+	}
+
+	ctx, root := client.Start(context.Background(), "say-hello")
+	call(ctx, "formatString", formatter, "format", true)
+	call(ctx, "printHello", publisher, "publish", false)
+	root.End()
+
+	return sr.Ended()
+}
+
+func TestMermaid(t *testing.T) {
+	want := `sequenceDiagram
+    participant hello_world as hello-world
+    participant formatter as formatter
+    participant publisher as publisher
+    Note over hello_world: say-hello (9.00ms)
+    hello_world->>+formatter: format
+    formatter--x-hello_world: format (1.00ms) failed
+    hello_world->>+publisher: publish
+    publisher-->>-hello_world: publish (1.00ms)
+`
+	if got := Mermaid(recordHello(t)); got != want {
+		t.Errorf("Mermaid() =\n%s\nwant:\n%s", got, want)
+	}
+}
+
+func TestGraphviz(t *testing.T) {
+	want := `digraph trace {
+    rankdir=LR;
+    node [shape=box, style=rounded];
+    subgraph cluster_0 {
+        label="hello-world";
+        "0000000000000001" [label="say-hello\n9.00ms"];
+        "0000000000000002" [label="formatString\n3.00ms"];
+        "0000000000000004" [label="printHello\n3.00ms"];
+    }
+    subgraph cluster_1 {
+        label="formatter";
+        "0000000000000003" [label="format\n1.00ms", color=red, fontcolor=red];
+    }
+    subgraph cluster_2 {
+        label="publisher";
+        "0000000000000005" [label="publish\n1.00ms"];
+    }
+`
+	got := Graphviz(recordHello(t))
+	if len(got) < len(want) || got[:len(want)] != want {
+		t.Errorf("Graphviz() =\n%s\nwant it to start with:\n%s", got, want)
+	}
+	for _, edge := range []string{
+		`"0000000000000001" -> "0000000000000002";`,
+		`"0000000000000001" -> "0000000000000004";`,
+		`"0000000000000002" -> "0000000000000003";`,
+		`"0000000000000004" -> "0000000000000005";`,
+	} {
+		if !slices.Contains(strings.Split(got, "\n"), "    "+edge) {
+			t.Errorf("Graphviz() has no edge %s", edge)
+		}
+	}
+}
@@ -0,0 +1,64 @@
+package tracing
+
+import (
+	"os"
+	"sort"
+	"strings"
+)
+
+// EnvCarrier is a TextMapCarrier over environment variables, carrying the context to a child process.
+// The keys of the propagators are mapped to variable names by upper-casing them and replacing the dashes with
+// underscores, so that the W3C propagators use the TRACEPARENT, TRACESTATE and BAGGAGE variables.
+type EnvCarrier map[string]string
+
+// NewEnvCarrier returns a carrier reading the environment of the current process, to extract the context given by
+// the parent process.
+func NewEnvCarrier() EnvCarrier {
+	return EnvCarrierFromEnviron(os.Environ())
+}
+
+// EnvCarrierFromEnviron returns a carrier reading the given "NAME=value" variables, as returned by os.Environ.
+func EnvCarrierFromEnviron(environ []string) EnvCarrier {
+	c := EnvCarrier{}
+	for _, kv := range environ {
+		if name, value, ok := strings.Cut(kv, "="); ok {
+			c[name] = value
+		}
+	}
+	return c
+}
+
+// envName returns the name of the environment variable holding the value of key
+func envName(key string) string {
+	return strings.ToUpper(strings.ReplaceAll(key, "-", "_"))
+}
+
+// Get returns the value of the variable matching key.
+func (c EnvCarrier) Get(key string) string {
+	return c[envName(key)]
+}
+
+// Set stores the value of the variable matching key.
+func (c EnvCarrier) Set(key, value string) {
+	c[envName(key)] = value
+}
+
+// Keys lists the names of the variables in the carrier.
+func (c EnvCarrier) Keys() []string {
+	keys := make([]string, 0, len(c))
+	for k := range c {
+		keys = append(keys, k)
+	}
+	return keys
+}
+
+// Environ returns the variables in the carrier as sorted "NAME=value" strings, to be appended to the Env of an
+// exec.Cmd. Variables appearing later in Env take precedence, so the context injected replaces any inherited one.
+func (c EnvCarrier) Environ() []string {
+	environ := make([]string, 0, len(c))
+	for name, value := range c {
+		environ = append(environ, name+"="+value)
+	}
+	sort.Strings(environ)
+	return environ
+}
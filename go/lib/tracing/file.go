@@ -0,0 +1,332 @@
+package tracing
+
+import (
+	"bufio"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"os"
+	"strconv"
+	"sync"
+	"time"
+
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/codes"
+	"go.opentelemetry.io/otel/sdk/instrumentation"
+	"go.opentelemetry.io/otel/sdk/resource"
+	traceSdk "go.opentelemetry.io/otel/sdk/trace"
+	"go.opentelemetry.io/otel/sdk/trace/tracetest"
+	"go.opentelemetry.io/otel/trace"
+)
+
+// SPANS_FILE_ENV is the environment variable which, set to the path of a file, makes NewTracerProvider append the
+// finished spans to it with a FileExporter, along with exporting them to the backend.
+const SPANS_FILE_ENV = "SPANS_FILE"
+
+// spanRecord is a span as written to a file, one per line
+type spanRecord struct {
+	TraceID      string            `json:"traceId"`
+	SpanID       string            `json:"spanId"`
+	TraceFlags   string            `json:"traceFlags"`
+	TraceState   string            `json:"traceState,omitempty"`
+	ParentSpanID string            `json:"parentSpanId,omitempty"`
+	ParentRemote bool              `json:"parentRemote,omitempty"`
+	Name         string            `json:"name"`
+	Kind         string            `json:"kind"`
+	Start        time.Time         `json:"start"`
+	End          time.Time         `json:"end"`
+	Attributes   []attributeRecord `json:"attributes,omitempty"`
+	Events       []eventRecord     `json:"events,omitempty"`
+	Links        []linkRecord      `json:"links,omitempty"`
+	Status       statusRecord      `json:"status"`
+	Resource     []attributeRecord `json:"resource,omitempty"`
+	Scope        scopeRecord       `json:"scope"`
+}
+
+// attributeRecord is an attribute, with its type so that the integers are not read back as floats
+type attributeRecord struct {
+	Key   string          `json:"key"`
+	Type  string          `json:"type"`
+	Value json.RawMessage `json:"value"`
+}
+
+type eventRecord struct {
+	Name       string            `json:"name"`
+	Time       time.Time         `json:"time"`
+	Attributes []attributeRecord `json:"attributes,omitempty"`
+}
+
+type linkRecord struct {
+	TraceID    string            `json:"traceId"`
+	SpanID     string            `json:"spanId"`
+	Attributes []attributeRecord `json:"attributes,omitempty"`
+}
+
+type statusRecord struct {
+	Code        codes.Code `json:"code"`
+	Description string     `json:"description,omitempty"`
+}
+
+type scopeRecord struct {
+	Name    string `json:"name"`
+	Version string `json:"version,omitempty"`
+}
+
+// FileExporter is a SpanExporter appending the spans to a file as JSON lines, to be sent to a backend later on with
+// ReadSpans and the replay command. The file is opened in append mode and every span is written at once, so that
+// the programs of a lesson can share the same file.
+type FileExporter struct {
+	mu sync.Mutex
+	f  *os.File
+}
+
+// NewFileExporter returns an exporter appending to the file at path, created if needed.
+func NewFileExporter(path string) (*FileExporter, error) {
+	f, err := os.OpenFile(path, os.O_APPEND|os.O_CREATE|os.O_WRONLY, 0o644)
+	if err != nil {
+		return nil, err
+	}
+	return &FileExporter{f: f}, nil
+}
+
+// ExportSpans appends the spans to the file, one line each.
+func (e *FileExporter) ExportSpans(ctx context.Context, spans []traceSdk.ReadOnlySpan) error {
+	e.mu.Lock()
+	defer e.mu.Unlock()
+
+	for _, s := range spans {
+		line, err := json.Marshal(newSpanRecord(s))
+		if err != nil {
+			return err
+		}
+		if _, err := e.f.Write(append(line, '\n')); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// Shutdown closes the file.
+func (e *FileExporter) Shutdown(ctx context.Context) error {
+	e.mu.Lock()
+	defer e.mu.Unlock()
+	return e.f.Close()
+}
+
+// ReadSpans reads the spans written by a FileExporter. Their Snapshots can be exported by any SpanExporter.
+func ReadSpans(r io.Reader) (tracetest.SpanStubs, error) {
+	var stubs tracetest.SpanStubs
+	scanner := bufio.NewScanner(r)
+	scanner.Buffer(make([]byte, 64*1024), 16*1024*1024)
+	for n := 1; scanner.Scan(); n++ {
+		if len(scanner.Bytes()) == 0 {
+			continue
+		}
+		var record spanRecord
+		if err := json.Unmarshal(scanner.Bytes(), &record); err != nil {
+			return nil, fmt.Errorf("line %d: %v", n, err)
+		}
+		stub, err := record.stub()
+		if err != nil {
+			return nil, fmt.Errorf("line %d: %v", n, err)
+		}
+		stubs = append(stubs, stub)
+	}
+	return stubs, scanner.Err()
+}
+
+// newSpanRecord returns the record of the span
+func newSpanRecord(s traceSdk.ReadOnlySpan) spanRecord {
+	sc := s.SpanContext()
+	record := spanRecord{
+		TraceID:    sc.TraceID().String(),
+		SpanID:     sc.SpanID().String(),
+		TraceFlags: sc.TraceFlags().String(),
+		TraceState: sc.TraceState().String(),
+		Name:       s.Name(),
+		Kind:       s.SpanKind().String(),
+		Start:      s.StartTime(),
+		End:        s.EndTime(),
+		Attributes: attributeRecords(s.Attributes()),
+		Status:     statusRecord{Code: s.Status().Code, Description: s.Status().Description},
+		Scope:      scopeRecord{Name: s.InstrumentationScope().Name, Version: s.InstrumentationScope().Version},
+	}
+	if s.Parent().IsValid() {
+		record.ParentSpanID = s.Parent().SpanID().String()
+		record.ParentRemote = s.Parent().IsRemote()
+	}
+	for _, e := range s.Events() {
+		record.Events = append(record.Events, eventRecord{Name: e.Name, Time: e.Time, Attributes: attributeRecords(e.Attributes)})
+	}
+	for _, l := range s.Links() {
+		record.Links = append(record.Links, linkRecord{
+			TraceID:    l.SpanContext.TraceID().String(),
+			SpanID:     l.SpanContext.SpanID().String(),
+			Attributes: attributeRecords(l.Attributes),
+		})
+	}
+	if res := s.Resource(); res != nil {
+		record.Resource = attributeRecords(res.Attributes())
+	}
+	return record
+}
+
+// stub returns the span of the record
+func (r spanRecord) stub() (tracetest.SpanStub, error) {
+	traceID, err := trace.TraceIDFromHex(r.TraceID)
+	if err != nil {
+		return tracetest.SpanStub{}, err
+	}
+	spanID, err := trace.SpanIDFromHex(r.SpanID)
+	if err != nil {
+		return tracetest.SpanStub{}, err
+	}
+	flags, err := strconv.ParseUint(r.TraceFlags, 16, 8)
+	if err != nil {
+		return tracetest.SpanStub{}, fmt.Errorf("invalid trace flags %q: %v", r.TraceFlags, err)
+	}
+	state, err := trace.ParseTraceState(r.TraceState)
+	if err != nil {
+		return tracetest.SpanStub{}, err
+	}
+
+	stub := tracetest.SpanStub{
+		Name: r.Name,
+		SpanContext: trace.NewSpanContext(trace.SpanContextConfig{
+			TraceID:    traceID,
+			SpanID:     spanID,
+			TraceFlags: trace.TraceFlags(flags),
+			TraceState: state,
+		}),
+		SpanKind:             spanKind(r.Kind),
+		StartTime:            r.Start,
+		EndTime:              r.End,
+		Status:               traceSdk.Status{Code: r.Status.Code, Description: r.Status.Description},
+		InstrumentationScope: instrumentation.Scope{Name: r.Scope.Name, Version: r.Scope.Version},
+	}
+	if r.ParentSpanID != "" {
+		parentID, err := trace.SpanIDFromHex(r.ParentSpanID)
+		if err != nil {
+			return tracetest.SpanStub{}, err
+		}
+		stub.Parent = trace.NewSpanContext(trace.SpanContextConfig{
+			TraceID:    traceID,
+			SpanID:     parentID,
+			TraceFlags: trace.TraceFlags(flags),
+			Remote:     r.ParentRemote,
+		})
+	}
+
+	if stub.Attributes, err = keyValues(r.Attributes); err != nil {
+		return tracetest.SpanStub{}, err
+	}
+	for _, e := range r.Events {
+		attrs, err := keyValues(e.Attributes)
+		if err != nil {
+			return tracetest.SpanStub{}, err
+		}
+		stub.Events = append(stub.Events, traceSdk.Event{Name: e.Name, Time: e.Time, Attributes: attrs})
+	}
+	for _, l := range r.Links {
+		linkTraceID, err := trace.TraceIDFromHex(l.TraceID)
+		if err != nil {
+			return tracetest.SpanStub{}, err
+		}
+		linkSpanID, err := trace.SpanIDFromHex(l.SpanID)
+		if err != nil {
+			return tracetest.SpanStub{}, err
+		}
+		attrs, err := keyValues(l.Attributes)
+		if err != nil {
+			return tracetest.SpanStub{}, err
+		}
+		stub.Links = append(stub.Links, traceSdk.Link{
+			SpanContext: trace.NewSpanContext(trace.SpanContextConfig{TraceID: linkTraceID, SpanID: linkSpanID}),
+			Attributes:  attrs,
+		})
+	}
+	attrs, err := keyValues(r.Resource)
+	if err != nil {
+		return tracetest.SpanStub{}, err
+	}
+	stub.Resource = resource.NewSchemaless(attrs...)
+
+	return stub, nil
+}
+
+// spanKind parses the name of a span kind, as returned by SpanKind.String
+func spanKind(name string) trace.SpanKind {
+	for _, kind := range []trace.SpanKind{
+		trace.SpanKindInternal, trace.SpanKindServer, trace.SpanKindClient, trace.SpanKindProducer, trace.SpanKindConsumer,
+	} {
+		if kind.String() == name {
+			return kind
+		}
+	}
+	return trace.SpanKindUnspecified
+}
+
+// attributeRecords returns the records of the attributes
+func attributeRecords(kvs []attribute.KeyValue) []attributeRecord {
+	records := make([]attributeRecord, 0, len(kvs))
+	for _, kv := range kvs {
+		value, err := json.Marshal(kv.Value.AsInterface())
+		if err != nil {
+			// the values of the attributes are basic types and slices of them, always encoded
+			continue
+		}
+		records = append(records, attributeRecord{Key: string(kv.Key), Type: kv.Value.Type().String(), Value: value})
+	}
+	return records
+}
+
+// keyValues returns the attributes of the records
+func keyValues(records []attributeRecord) ([]attribute.KeyValue, error) {
+	var kvs []attribute.KeyValue
+	for _, r := range records {
+		var kv attribute.KeyValue
+		var err error
+		switch r.Type {
+		case attribute.BOOL.String():
+			var v bool
+			err = json.Unmarshal(r.Value, &v)
+			kv = attribute.Bool(r.Key, v)
+		case attribute.INT64.String():
+			var v int64
+			err = json.Unmarshal(r.Value, &v)
+			kv = attribute.Int64(r.Key, v)
+		case attribute.FLOAT64.String():
+			var v float64
+			err = json.Unmarshal(r.Value, &v)
+			kv = attribute.Float64(r.Key, v)
+		case attribute.STRING.String():
+			var v string
+			err = json.Unmarshal(r.Value, &v)
+			kv = attribute.String(r.Key, v)
+		case attribute.BOOLSLICE.String():
+			var v []bool
+			err = json.Unmarshal(r.Value, &v)
+			kv = attribute.BoolSlice(r.Key, v)
+		case attribute.INT64SLICE.String():
+			var v []int64
+			err = json.Unmarshal(r.Value, &v)
+			kv = attribute.Int64Slice(r.Key, v)
+		case attribute.FLOAT64SLICE.String():
+			var v []float64
+			err = json.Unmarshal(r.Value, &v)
+			kv = attribute.Float64Slice(r.Key, v)
+		case attribute.STRINGSLICE.String():
+			var v []string
+			err = json.Unmarshal(r.Value, &v)
+			kv = attribute.StringSlice(r.Key, v)
+		default:
+			err = fmt.Errorf("unknown type %q", r.Type)
+		}
+		if err != nil {
+			return nil, fmt.Errorf("attribute %q: %v", r.Key, err)
+		}
+		kvs = append(kvs, kv)
+	}
+	return kvs, nil
+}
@@ -0,0 +1,124 @@
+package tracing
+
+import (
+	"context"
+	"errors"
+	"os"
+	"path/filepath"
+	"reflect"
+	"strings"
+	"testing"
+
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/codes"
+	"go.opentelemetry.io/otel/sdk/resource"
+	traceSdk "go.opentelemetry.io/otel/sdk/trace"
+	"go.opentelemetry.io/otel/sdk/trace/tracetest"
+	semconv "go.opentelemetry.io/otel/semconv/v1.4.0"
+	"go.opentelemetry.io/otel/trace"
+)
+
+func TestFileExporter(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "spans.jsonl")
+	exporter, err := NewFileExporter(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	sr := tracetest.NewSpanRecorder()
+	tp := traceSdk.NewTracerProvider(
+		traceSdk.WithSpanProcessor(sr),
+		traceSdk.WithSyncer(exporter),
+		traceSdk.WithResource(resource.NewSchemaless(semconv.ServiceNameKey.String("formatter"))),
+	)
+	tracer := tp.Tracer("test", trace.WithInstrumentationVersion("1.0.0"))
+
+	// a server span, with a remote parent, every type of attribute, an event, a link and an error
+	parent := trace.NewSpanContext(trace.SpanContextConfig{
+		TraceID:    trace.TraceID{0x01},
+		SpanID:     trace.SpanID{0x02},
+		TraceFlags: trace.FlagsSampled,
+		Remote:     true,
+	})
+	link := trace.NewSpanContext(trace.SpanContextConfig{TraceID: trace.TraceID{0x03}, SpanID: trace.SpanID{0x04}})
+	ctx, span := tracer.Start(trace.ContextWithRemoteSpanContext(context.Background(), parent), "format",
+		trace.WithSpanKind(trace.SpanKindServer),
+		trace.WithLinks(trace.Link{SpanContext: link, Attributes: []attribute.KeyValue{attribute.String("link.reason", "retry")}}),
+		trace.WithAttributes(
+			attribute.String("hello-to", "Bryan"),
+			attribute.Int64("large", 1<<62+1),
+			attribute.Float64("ratio", 0.25),
+			attribute.Bool("cached", true),
+			attribute.StringSlice("names", []string{"Bryan", "Alice"}),
+			attribute.Int64Slice("sizes", []int64{1, 2}),
+			attribute.Float64Slice("ratios", []float64{0.5}),
+			attribute.BoolSlice("flags", []bool{true, false}),
+		),
+	)
+	_, child := tracer.Start(ctx, "cache.get")
+	child.End()
+	span.RecordError(errors.New("boom"))
+	span.SetStatus(codes.Error, "boom")
+	span.End()
+
+	if err := tp.Shutdown(context.Background()); err != nil {
+		t.Fatal(err)
+	}
+
+	f, err := os.Open(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer f.Close()
+	got, err := ReadSpans(f)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	want := tracetest.SpanStubsFromReadOnlySpans(sr.Ended())
+	if len(got) != len(want) {
+		t.Fatalf("read %d spans, want %d", len(got), len(want))
+	}
+	for i := range want {
+		g, w := got[i], want[i]
+		if g.Name != w.Name {
+			t.Errorf("span %d: name = %q, want %q", i, g.Name, w.Name)
+		}
+		if !g.SpanContext.Equal(w.SpanContext) {
+			t.Errorf("%s: span context = %v, want %v", w.Name, g.SpanContext, w.SpanContext)
+		}
+		if g.Parent.SpanID() != w.Parent.SpanID() || g.Parent.IsRemote() != w.Parent.IsRemote() {
+			t.Errorf("%s: parent = %v, want %v", w.Name, g.Parent, w.Parent)
+		}
+		if g.SpanKind != w.SpanKind {
+			t.Errorf("%s: kind = %v, want %v", w.Name, g.SpanKind, w.SpanKind)
+		}
+		if !g.StartTime.Equal(w.StartTime) || !g.EndTime.Equal(w.EndTime) {
+			t.Errorf("%s: times = %v-%v, want %v-%v", w.Name, g.StartTime, g.EndTime, w.StartTime, w.EndTime)
+		}
+		if !reflect.DeepEqual(g.Attributes, w.Attributes) {
+			t.Errorf("%s: attributes = %v, want %v", w.Name, g.Attributes, w.Attributes)
+		}
+		if len(g.Events) != len(w.Events) || len(w.Events) > 0 && (g.Events[0].Name != w.Events[0].Name || !reflect.DeepEqual(g.Events[0].Attributes, w.Events[0].Attributes)) {
+			t.Errorf("%s: events = %v, want %v", w.Name, g.Events, w.Events)
+		}
+		if len(g.Links) != len(w.Links) || len(w.Links) > 0 && (!g.Links[0].SpanContext.Equal(w.Links[0].SpanContext) || !reflect.DeepEqual(g.Links[0].Attributes, w.Links[0].Attributes)) {
+			t.Errorf("%s: links = %v, want %v", w.Name, g.Links, w.Links)
+		}
+		if g.Status != w.Status {
+			t.Errorf("%s: status = %v, want %v", w.Name, g.Status, w.Status)
+		}
+		if !g.Resource.Equal(w.Resource) {
+			t.Errorf("%s: resource = %v, want %v", w.Name, g.Resource, w.Resource)
+		}
+		if g.InstrumentationScope != w.InstrumentationScope {
+			t.Errorf("%s: scope = %v, want %v", w.Name, g.InstrumentationScope, w.InstrumentationScope)
+		}
+	}
+}
+
+func TestReadSpansInvalid(t *testing.T) {
+	_, err := ReadSpans(strings.NewReader(`{"traceId":"01","spanId":"0000000000000001","traceFlags":"01"}` + "\n"))
+	if err == nil || !strings.HasPrefix(err.Error(), "line 1:") {
+		t.Errorf("ReadSpans() error = %v, want an error on line 1", err)
+	}
+}
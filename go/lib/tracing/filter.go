@@ -0,0 +1,58 @@
+package tracing
+
+import (
+	"context"
+
+	traceSdk "go.opentelemetry.io/otel/sdk/trace"
+)
+
+// SpanFilter reports whether a finished span should be dropped instead of exported.
+type SpanFilter func(span traceSdk.ReadOnlySpan) bool
+
+// filteringProcessor wraps another SpanProcessor and drops the spans matched by its filters
+type filteringProcessor struct {
+	next    traceSdk.SpanProcessor
+	filters []SpanFilter
+}
+
+// NewFilteringProcessor returns a SpanProcessor that forwards finished spans to next unless one of the filters matches them.
+func NewFilteringProcessor(next traceSdk.SpanProcessor, filters ...SpanFilter) traceSdk.SpanProcessor {
+	return &filteringProcessor{next: next, filters: filters}
+}
+
+func (p *filteringProcessor) OnStart(parent context.Context, s traceSdk.ReadWriteSpan) {
+	p.next.OnStart(parent, s)
+}
+
+func (p *filteringProcessor) OnEnd(s traceSdk.ReadOnlySpan) {
+	for _, filter := range p.filters {
+		if filter(s) {
+			return
+		}
+	}
+	p.next.OnEnd(s)
+}
+
+func (p *filteringProcessor) Shutdown(ctx context.Context) error {
+	return p.next.Shutdown(ctx)
+}
+
+func (p *filteringProcessor) ForceFlush(ctx context.Context) error {
+	return p.next.ForceFlush(ctx)
+}
+
+// DropSpanNames returns a SpanFilter matching spans with any of the given names.
+func DropSpanNames(names ...string) SpanFilter {
+	set := make(map[string]struct{}, len(names))
+	for _, name := range names {
+		set[name] = struct{}{}
+	}
+	return func(span traceSdk.ReadOnlySpan) bool {
+		_, ok := set[span.Name()]
+		return ok
+	}
+}
+
+// DropHealthChecks matches the spans produced while serving the health and readiness probes, e.g. those registered by
+// xhttp.RegisterHealthHandlers, so that orchestrator polling does not flood the tracing backend.
+var DropHealthChecks = DropSpanNames("healthz", "readyz", "GET /healthz", "GET /readyz")
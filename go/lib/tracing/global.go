@@ -0,0 +1,40 @@
+package tracing
+
+import (
+	"sync/atomic"
+
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/trace"
+)
+
+// GlobalTracer is a named tracer of the global TracerProvider, retrieved once and reused by every call, such as the
+// tracers of the helpers of this library. Unlike a tracer kept in a package-level variable, it follows the global
+// TracerProvider when the provider is replaced, as the tests do, by retrieving the tracer again from the new one.
+type GlobalTracer struct {
+	name   string
+	cached atomic.Pointer[providerTracer]
+}
+
+// providerTracer is a tracer along with the TracerProvider it was retrieved from
+type providerTracer struct {
+	provider trace.TracerProvider
+	tracer   trace.Tracer
+}
+
+// NewGlobalTracer returns the GlobalTracer named name.
+func NewGlobalTracer(name string) *GlobalTracer {
+	return &GlobalTracer{name: name}
+}
+
+// Tracer returns the tracer of the current global TracerProvider, only looked up in the provider the first time it is
+// asked for after the provider was set.
+func (g *GlobalTracer) Tracer() trace.Tracer {
+	provider := otel.GetTracerProvider()
+	if c := g.cached.Load(); c != nil && c.provider == provider {
+		return c.tracer
+	}
+
+	tracer := provider.Tracer(g.name)
+	g.cached.Store(&providerTracer{provider: provider, tracer: tracer})
+	return tracer
+}
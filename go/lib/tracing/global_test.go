@@ -0,0 +1,53 @@
+package tracing
+
+import (
+	"context"
+	"testing"
+
+	"go.opentelemetry.io/otel"
+	traceSdk "go.opentelemetry.io/otel/sdk/trace"
+	"go.opentelemetry.io/otel/sdk/trace/tracetest"
+)
+
+func TestGlobalTracerFollowsTheProvider(t *testing.T) {
+	defer otel.SetTracerProvider(otel.GetTracerProvider())
+	g := NewGlobalTracer("test")
+
+	// the spans go to the provider set when they are started, whichever was set when the tracer was first asked for
+	for i := 0; i < 2; i++ {
+		sr := tracetest.NewSpanRecorder()
+		otel.SetTracerProvider(traceSdk.NewTracerProvider(traceSdk.WithSpanProcessor(sr)))
+
+		if g.Tracer() != g.Tracer() {
+			t.Errorf("provider %d: the tracer is retrieved again without the provider being replaced", i)
+		}
+		_, span := g.Tracer().Start(context.Background(), "span")
+		span.End()
+		if got := len(sr.Ended()); got != 1 {
+			t.Errorf("provider %d: recorded %d spans, want 1", i, got)
+		}
+	}
+}
+
+// BenchmarkTracer compares retrieving a tracer from the global TracerProvider with otel.Tracer, as the helpers of this
+// library did at every call, with a GlobalTracer, e.g.
+//
+//	go test ./lib/tracing -run XXX -bench Tracer -benchmem
+func BenchmarkTracer(b *testing.B) {
+	defer otel.SetTracerProvider(otel.GetTracerProvider())
+	otel.SetTracerProvider(traceSdk.NewTracerProvider())
+
+	b.Run("otel.Tracer", func(b *testing.B) {
+		b.ReportAllocs()
+		for i := 0; i < b.N; i++ {
+			otel.Tracer(TRACER_NAME)
+		}
+	})
+	b.Run("GlobalTracer", func(b *testing.B) {
+		g := NewGlobalTracer(TRACER_NAME)
+		b.ReportAllocs()
+		for i := 0; i < b.N; i++ {
+			g.Tracer()
+		}
+	})
+}
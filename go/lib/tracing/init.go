@@ -2,11 +2,13 @@ package tracing
 
 import (
 	"context"
+	"fmt"
 	"log"
+	"os"
+	"strconv"
 
 	"go.opentelemetry.io/otel"
 	"go.opentelemetry.io/otel/attribute"
-	"go.opentelemetry.io/otel/exporters/otlp/otlptrace/otlptracehttp"
 	"go.opentelemetry.io/otel/propagation"
 	"go.opentelemetry.io/otel/sdk/resource"
 	traceSdk "go.opentelemetry.io/otel/sdk/trace"
@@ -18,23 +20,64 @@ const (
 	TRACING_BACKEND = "localhost:4318"
 )
 
+// InitOption configures the TracerProvider set up by the Init functions
+type InitOption func(*initOptions)
+
+// initOptions holds what the InitOptions configure
+type initOptions struct {
+	propagation string
+}
+
+// WithPropagation makes the Init functions propagate the context in the formats accepted by NewPropagator, e.g. the
+// -propagation flag of config.Load, rather than in the W3C formats. Empty, it leaves the choice to the PROPAGATION and
+// OTEL_PROPAGATORS variables.
+func WithPropagation(formats string) InitOption {
+	return func(o *initOptions) {
+		o.propagation = formats
+	}
+}
+
 // InitTracerProvider initializes the OpenTelemetry TracerProvider with the specified service name and default backend.
-func InitTracerProvider(servicename string) (*traceSdk.TracerProvider, error) {
-	return InitTracerProviderWithBackend(servicename, TRACING_BACKEND)
+func InitTracerProvider(servicename string, opts ...InitOption) (*traceSdk.TracerProvider, error) {
+	return InitTracerProviderWithBackend(servicename, TRACING_BACKEND, opts...)
 }
 
 // InitTracerProviderWithBackend initializes the OpenTelemetry TracerProvider with the specified service name and backend.
-func InitTracerProviderWithBackend(service, backend string) (*traceSdk.TracerProvider, error) {
-	ctx := context.Background()
+// It samples with the standard OTEL_TRACES_SAMPLER and OTEL_TRACES_SAMPLER_ARG variables when set, and with the default
+// sampler of the SDK otherwise.
+func InitTracerProviderWithBackend(service, backend string, opts ...InitOption) (*traceSdk.TracerProvider, error) {
+	// the default sampler of the SDK, recording the root spans and following the decision of the parent otherwise
+	var sampler traceSdk.Sampler = traceSdk.ParentBased(traceSdk.AlwaysSample())
+
+	// sampling with the standard variables, if set, so that the programs are configured as any other OpenTelemetry
+	// one: like the SDK, only when the program doesn't choose its sampler itself
+	envSampler, err := otelSamplerFromEnv()
+	if err != nil {
+		return nil, err
+	}
+	if envSampler != nil {
+		log.Printf("sampling with %s, set by %s", envSampler.Description(), OTEL_TRACES_SAMPLER_ENV)
+		sampler = envSampler
+	}
 
-	// creating an OTLP trace exporter to send spans to the specified backend
-	exporter, err := otlptracehttp.New(ctx, otlptracehttp.WithEndpoint(backend), otlptracehttp.WithInsecure())
+	return InitTracerProviderWithSampler(service, backend, sampler, opts...)
+}
+
+// InitTracerProviderWithSampler initializes the OpenTelemetry TracerProvider with the specified service name, backend
+// and sampler.
+func InitTracerProviderWithSampler(service, backend string, sampler traceSdk.Sampler, opts ...InitOption) (*traceSdk.TracerProvider, error) {
+	res, err := NewResource(service)
 	if err != nil {
 		return nil, err
 	}
+	return InitTracerProviderWithResource(res, backend, sampler, opts...)
+}
 
+// NewResource returns the resource describing the service: its name, version and environment. lib/metrics and lib/log
+// describe the service with it as well, so that its traces, metrics and logs share their resource.
+func NewResource(service string) (*resource.Resource, error) {
 	// defining resource attributes for the service
-	res, err := resource.New(
+	return resource.New(
 		context.Background(),
 		resource.WithAttributes(
 			semconv.ServiceNameKey.String(service),        // service name
@@ -42,34 +85,143 @@ func InitTracerProviderWithBackend(service, backend string) (*traceSdk.TracerPro
 			attribute.String("environment", "production"), // environment
 		),
 	)
+}
+
+// InitTracerProviderWithResource initializes the OpenTelemetry TracerProvider with the specified resource, backend and
+// sampler. The resource must hold the service name.
+func InitTracerProviderWithResource(res *resource.Resource, backend string, sampler traceSdk.Sampler, opts ...InitOption) (*traceSdk.TracerProvider, error) {
+	var o initOptions
+	for _, opt := range opts {
+		opt(&o)
+	}
+
+	propagator, err := ResolvePropagator(o.propagation)
 	if err != nil {
 		return nil, err
 	}
 
-	// creating a TracerProvider with the specified exporter and resource attributes
-	tp := traceSdk.NewTracerProvider(
-		traceSdk.WithBatcher(exporter),
-		traceSdk.WithResource(res),
-	)
+	tp, stats, err := newTracerProvider(res, backend, sampler)
+	if err != nil {
+		return nil, err
+	}
 
-	// setting up the global tracer provider
+	// exposing the state of the export queue with the metrics of the service, if it has a MeterProvider
+	if err := RegisterQueueMetrics(otel.Meter(TRACER_NAME), stats); err != nil {
+		return nil, err
+	}
+
+	// setting up the global tracer provider, and remembering its resource for LoggerFromContext
 	otel.SetTracerProvider(tp)
+	globalResource.Store(res)
 
 	// setting up a propagator to handle trace context propagation across the services
 	// otel.SetTextMapPropagator(propagation.TraceContext{})
 
-	// Uncomment the code below to set up composite propagator
-	// setting up a composite propagator to handle context propagation (traces and baggage) across services
-	otel.SetTextMapPropagator(propagation.NewCompositeTextMapPropagator(
-		propagation.TraceContext{},
-		propagation.Baggage{},
-	))
+	// setting up the propagator resolved above, by default a composite propagator handling the context propagation
+	// (traces and baggage) across services
+	otel.SetTextMapPropagator(propagator)
 
 	return tp, nil
 }
 
-// prints the span contents
+// ResolvePropagator returns the propagator installed by the Init functions: the one of formats, given as to
+// NewPropagator, when not empty, of the PROPAGATION variable otherwise, or else of the standard OTEL_PROPAGATORS
+// variable, the W3C trace context and baggage propagators when none of them is set. The programs which install their
+// own propagator, such as the client of Lesson 4 in a dry run, honor the same variables with it.
+func ResolvePropagator(formats string) (propagation.TextMapPropagator, error) {
+	if formats == "" {
+		formats = os.Getenv(PROPAGATION_ENV)
+	}
+	if formats != "" {
+		return NewPropagator(formats)
+	}
+	if names := os.Getenv(OTEL_PROPAGATORS_ENV); names != "" {
+		return ParseOTelPropagators(names)
+	}
+
+	// the trace context and the baggage in the W3C formats
+	return propagation.NewCompositeTextMapPropagator(propagation.TraceContext{}, propagation.Baggage{}), nil
+}
+
+// NewTracerProvider creates a TracerProvider with the specified resource, backend and sampler, exporting to the backend
+// over OTLP/HTTP, in the encoding set by OTLP_ENCODING_ENV, through a queue of the size set by EXPORT_QUEUE_SIZE_ENV.
+// Unlike the Init functions, it leaves the global TracerProvider and propagator untouched, so that several providers
+// can live in the same process.
+func NewTracerProvider(res *resource.Resource, backend string, sampler traceSdk.Sampler) (*traceSdk.TracerProvider, error) {
+	tp, _, err := newTracerProvider(res, backend, sampler)
+	return tp, err
+}
+
+// newTracerProvider creates a TracerProvider like NewTracerProvider, returning the stats of its export queue as well
+func newTracerProvider(res *resource.Resource, backend string, sampler traceSdk.Sampler) (*traceSdk.TracerProvider, *QueueStats, error) {
+	// creating an OTLP trace exporter to send spans to the specified backend, in JSON rather than protobuf if asked to
+	encoding := OTLP_PROTOBUF
+	if e := os.Getenv(OTLP_ENCODING_ENV); e != "" {
+		encoding = e
+		log.Printf("exporting spans to %s over OTLP/HTTP, encoded in %s", backend, encoding)
+	}
+	exporter, err := NewOTLPExporter(context.Background(), backend, encoding)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	// batching the spans in a queue of the size asked for, counting the spans dropped when the backend is down
+	queueSize := DEFAULT_EXPORT_QUEUE_SIZE
+	if size := os.Getenv(EXPORT_QUEUE_SIZE_ENV); size != "" {
+		if queueSize, err = strconv.Atoi(size); err != nil || queueSize <= 0 {
+			return nil, nil, fmt.Errorf("invalid export queue size %q", size)
+		}
+	}
+	processor, stats := NewQueueProcessor(exporter, queueSize)
+
+	// creating a TracerProvider with the specified exporter, resource attributes and sampler, dropping the health check spans before they are exported
+	opts := []traceSdk.TracerProviderOption{
+		traceSdk.WithSpanProcessor(NewFilteringProcessor(processor, DropHealthChecks)),
+		traceSdk.WithResource(res),
+		traceSdk.WithSampler(sampler),
+	}
+
+	// generating the IDs with another generator, if asked to
+	if name := os.Getenv(ID_GENERATOR_ENV); name != "" {
+		generator, err := NewIDGenerator(name)
+		if err != nil {
+			return nil, nil, err
+		}
+		opts = append(opts, traceSdk.WithIDGenerator(generator))
+	}
+
+	// appending the finished spans to a file as well, if asked to
+	if path := os.Getenv(SPANS_FILE_ENV); path != "" {
+		fileExporter, err := NewFileExporter(path)
+		if err != nil {
+			return nil, nil, err
+		}
+		opts = append(opts, traceSdk.WithSpanProcessor(NewFilteringProcessor(traceSdk.NewBatchSpanProcessor(fileExporter), DropHealthChecks)))
+	}
+
+	// reporting the spans breaking the naming policy, if asked to
+	switch policy := os.Getenv(SPAN_NAME_POLICY_ENV); policy {
+	case "":
+	case "warn":
+		opts = append(opts, traceSdk.WithSpanProcessor(NewNamingPolicyProcessor(log.Default())))
+	default:
+		return nil, nil, fmt.Errorf("unknown span naming policy %q", policy)
+	}
+
+	// printing the finished traces to the terminal as well, if asked to
+	if consoleSpansEnabled() {
+		opts = append(opts, traceSdk.WithSpanProcessor(traceSdk.NewSimpleSpanProcessor(NewConsoleExporter(os.Stderr))))
+	}
+
+	return traceSdk.NewTracerProvider(opts...), stats, nil
+}
+
+// prints the span contents, unless the finished traces are printed by a ConsoleExporter
 func PrintSpanContents(span trace.Span) {
+	if consoleSpansEnabled() {
+		return
+	}
+
 	spanCtx := span.SpanContext()
 
 	data, err := spanCtx.MarshalJSON()
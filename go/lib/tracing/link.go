@@ -0,0 +1,17 @@
+package tracing
+
+import (
+	"strings"
+
+	"go.opentelemetry.io/otel/trace"
+)
+
+// TraceURL returns a link to the trace in the UI of the tracing backend.
+// If base contains the "{traceId}" placeholder it is replaced by the trace ID, which suits UIs such as Grafana,
+// otherwise "/trace/<trace ID>" is appended to base, which is the layout of the Jaeger UI.
+func TraceURL(base string, traceID trace.TraceID) string {
+	if strings.Contains(base, "{traceId}") {
+		return strings.ReplaceAll(base, "{traceId}", traceID.String())
+	}
+	return strings.TrimSuffix(base, "/") + "/trace/" + traceID.String()
+}
@@ -0,0 +1,61 @@
+package tracing
+
+import (
+	"context"
+	"log/slog"
+	"os"
+	"sync/atomic"
+
+	"go.opentelemetry.io/otel/sdk/resource"
+	traceSdk "go.opentelemetry.io/otel/sdk/trace"
+	semconv "go.opentelemetry.io/otel/semconv/v1.4.0"
+	"go.opentelemetry.io/otel/trace"
+)
+
+// globalResource is the resource of the TracerProvider set up by the Init functions, naming the service when the span of
+// the context does not
+var globalResource atomic.Pointer[resource.Resource]
+
+// LoggerFromContext returns a logger writing JSON records to stderr, already holding the fields correlating its lines
+// with the traces: the service name, and the trace_id and span_id of the span of ctx, named as by the trace-aware
+// handler of lib/log. Unlike that handler, it needs no context at logging time, so that logger.Info("...") is correlated
+// as well, e.g. in a helper which was only given the context:
+//
+//	tracing.LoggerFromContext(ctx).Warn("failed to read the cache", slog.Any("error", err))
+//
+// The service name is read from the resource of the span, or from the resource of the TracerProvider set up by the Init
+// functions when the span was not recorded.
+func LoggerFromContext(ctx context.Context) *slog.Logger {
+	return loggerFromContext(ctx, slog.NewJSONHandler(os.Stderr, nil))
+}
+
+// loggerFromContext returns a logger writing its records to handler, holding the fields of LoggerFromContext
+func loggerFromContext(ctx context.Context, handler slog.Handler) *slog.Logger {
+	logger := slog.New(handler)
+
+	span := trace.SpanFromContext(ctx)
+	if service := serviceName(span); service != "" {
+		logger = logger.With(slog.String("service", service))
+	}
+	if sc := span.SpanContext(); sc.IsValid() {
+		logger = logger.With(
+			slog.String("trace_id", sc.TraceID().String()),
+			slog.String("span_id", sc.SpanID().String()),
+		)
+	}
+	return logger
+}
+
+// serviceName returns the service name of the resource of span, or of the global resource, if any
+func serviceName(span trace.Span) string {
+	res := globalResource.Load()
+	if s, ok := span.(traceSdk.ReadOnlySpan); ok {
+		res = s.Resource()
+	}
+	if res == nil {
+		return ""
+	}
+	set := res.Set()
+	value, _ := set.Value(semconv.ServiceNameKey)
+	return value.AsString()
+}
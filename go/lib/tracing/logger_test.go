@@ -0,0 +1,76 @@
+package tracing
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"log/slog"
+	"testing"
+
+	traceSdk "go.opentelemetry.io/otel/sdk/trace"
+	"go.opentelemetry.io/otel/trace"
+)
+
+// logLine logs a line without any context with the logger of ctx, and returns its fields
+func logLine(t *testing.T, ctx context.Context) map[string]any {
+	t.Helper()
+
+	var buf bytes.Buffer
+	loggerFromContext(ctx, slog.NewJSONHandler(&buf, nil)).Info("formatted the greeting")
+
+	var fields map[string]any
+	if err := json.Unmarshal(buf.Bytes(), &fields); err != nil {
+		t.Fatalf("unmarshalling %q: %v", buf.String(), err)
+	}
+	return fields
+}
+
+func TestLoggerFromContext(t *testing.T) {
+	res, err := NewResource("formatter")
+	if err != nil {
+		t.Fatal(err)
+	}
+	tracer := traceSdk.NewTracerProvider(traceSdk.WithResource(res)).Tracer("test")
+
+	ctx, span := tracer.Start(context.Background(), "format")
+	defer span.End()
+
+	fields := logLine(t, ctx)
+	for key, want := range map[string]string{
+		"service":  "formatter",
+		"trace_id": span.SpanContext().TraceID().String(),
+		"span_id":  span.SpanContext().SpanID().String(),
+	} {
+		if fields[key] != want {
+			t.Errorf("%s = %v, want %q", key, fields[key], want)
+		}
+	}
+}
+
+func TestLoggerFromContextWithoutSpan(t *testing.T) {
+	// without a span, the line only holds the name of the service set up by the Init functions
+	res, err := NewResource("publisher")
+	if err != nil {
+		t.Fatal(err)
+	}
+	globalResource.Store(res)
+	defer globalResource.Store(nil)
+
+	fields := logLine(t, context.Background())
+	if fields["service"] != "publisher" {
+		t.Errorf("service = %v, want %q", fields["service"], "publisher")
+	}
+	if _, ok := fields["trace_id"]; ok {
+		t.Errorf("trace_id = %v, want none", fields["trace_id"])
+	}
+
+	// a span context propagated by the caller, without a recorded span, still correlates the line
+	sc := trace.NewSpanContext(trace.SpanContextConfig{
+		TraceID: trace.TraceID{0x6a, 0xb2},
+		SpanID:  trace.SpanID{0x16, 0x04},
+	})
+	fields = logLine(t, trace.ContextWithRemoteSpanContext(context.Background(), sc))
+	if fields["service"] != "publisher" || fields["trace_id"] != sc.TraceID().String() {
+		t.Errorf("logged %v, want the service and the trace ID of the caller", fields)
+	}
+}
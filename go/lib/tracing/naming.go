@@ -0,0 +1,120 @@
+package tracing
+
+import (
+	"context"
+	"fmt"
+	"log"
+	"regexp"
+	"strings"
+	"sync"
+
+	traceSdk "go.opentelemetry.io/otel/sdk/trace"
+)
+
+const (
+	// SPAN_NAME_POLICY_ENV is the environment variable which, set to "warn", makes NewTracerProvider log the spans
+	// whose names break the rules of ValidateSpanName
+	SPAN_NAME_POLICY_ENV = "SPAN_NAME_POLICY"
+
+	// MAX_SPAN_NAME_LENGTH is the length above which a span name is most likely made of data rather than of the name
+	// of an operation
+	MAX_SPAN_NAME_LENGTH = 64
+
+	// maxReportedNames bounds the names remembered by the naming policy, which would grow with the cardinality it
+	// reports otherwise
+	maxReportedNames = 100
+)
+
+// httpMethods are the methods of HTTP, any other method being named "HTTP" alone, e.g. a mistyped one
+var httpMethods = map[string]bool{
+	"GET": true, "HEAD": true, "POST": true, "PUT": true, "DELETE": true,
+	"CONNECT": true, "OPTIONS": true, "TRACE": true, "PATCH": true,
+}
+
+// HTTPSpanName returns the name of the span of an HTTP operation, e.g. "HTTP GET /format", from its method and its
+// route: the pattern matching the path, such as "/users/{id}", and never the path itself, which holds the IDs. Without
+// a route, the name is the method alone, e.g. "HTTP GET".
+func HTTPSpanName(method, route string) string {
+	name := "HTTP"
+	if method = strings.ToUpper(method); httpMethods[method] {
+		name += " " + method
+	}
+	if route != "" {
+		name += " " + route
+	}
+	return name
+}
+
+var (
+	// idSegment matches the parts of a name looking like an ID: numbers, UUIDs and long hexadecimal strings
+	idSegment = regexp.MustCompile(`^([0-9]+|[0-9a-fA-F]{8}-[0-9a-fA-F]{4}-[0-9a-fA-F]{4}-[0-9a-fA-F]{4}-[0-9a-fA-F]{12}|[0-9a-fA-F]{16,})$`)
+	// separators split a name into the parts checked by idSegment
+	separators = regexp.MustCompile(`[\s/.:,;=_-]+`)
+)
+
+// ValidateSpanName checks name against the cardinality policy of the tutorial: a span name names an operation, and
+// the backends group the spans by name, so it must not change from one request to the next. The names holding a query
+// string, a full URL, an email address or an ID, or too long to be anything but data, are rejected with the reason.
+// Such values belong in the attributes of the span.
+func ValidateSpanName(name string) error {
+	switch {
+	case strings.TrimSpace(name) == "":
+		return fmt.Errorf("span name is empty")
+	case len(name) > MAX_SPAN_NAME_LENGTH:
+		return fmt.Errorf("span name %q is longer than %d characters", name, MAX_SPAN_NAME_LENGTH)
+	case strings.Contains(name, "://"):
+		return fmt.Errorf("span name %q holds a URL, name the route instead", name)
+	case strings.Contains(name, "?"):
+		return fmt.Errorf("span name %q holds a query string", name)
+	case strings.Contains(name, "@"):
+		return fmt.Errorf("span name %q holds an email address", name)
+	}
+	for _, segment := range separators.Split(name, -1) {
+		if idSegment.MatchString(segment) {
+			return fmt.Errorf("span name %q holds the ID %q, name the route instead, e.g. /users/{id}", name, segment)
+		}
+	}
+	return nil
+}
+
+// namingPolicyProcessor logs the spans whose names break the rules of ValidateSpanName, once per name
+type namingPolicyProcessor struct {
+	logger *log.Logger
+
+	mu       sync.Mutex
+	reported map[string]bool
+}
+
+// NewNamingPolicyProcessor returns a SpanProcessor logging with logger the spans started with a name rejected by
+// ValidateSpanName, once per name, and for the first names only: the spans are exported as they are.
+func NewNamingPolicyProcessor(logger *log.Logger) traceSdk.SpanProcessor {
+	return &namingPolicyProcessor{logger: logger, reported: make(map[string]bool)}
+}
+
+func (p *namingPolicyProcessor) OnStart(parent context.Context, s traceSdk.ReadWriteSpan) {
+	err := ValidateSpanName(s.Name())
+	if err == nil {
+		return
+	}
+
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	if p.reported[s.Name()] || len(p.reported) >= maxReportedNames {
+		return
+	}
+	p.reported[s.Name()] = true
+	p.logger.Printf("span naming policy: %v", err)
+	if len(p.reported) == maxReportedNames {
+		p.logger.Printf("span naming policy: %d span names reported, not reporting any more", maxReportedNames)
+	}
+}
+
+func (p *namingPolicyProcessor) OnEnd(s traceSdk.ReadOnlySpan) {}
+
+func (p *namingPolicyProcessor) Shutdown(ctx context.Context) error {
+	return nil
+}
+
+func (p *namingPolicyProcessor) ForceFlush(ctx context.Context) error {
+	return nil
+}
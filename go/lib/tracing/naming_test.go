@@ -0,0 +1,70 @@
+package tracing
+
+import (
+	"bytes"
+	"context"
+	"log"
+	"strings"
+	"testing"
+
+	traceSdk "go.opentelemetry.io/otel/sdk/trace"
+)
+
+func TestHTTPSpanName(t *testing.T) {
+	tests := []struct {
+		method, route, want string
+	}{
+		{"GET", "/format", "HTTP GET /format"},
+		{"post", "/users/{id}", "HTTP POST /users/{id}"},
+		{"GET", "", "HTTP GET"},
+		{"FETCH", "/format", "HTTP /format"},
+	}
+	for _, tt := range tests {
+		if got := HTTPSpanName(tt.method, tt.route); got != tt.want {
+			t.Errorf("HTTPSpanName(%q, %q) = %q, want %q", tt.method, tt.route, got, tt.want)
+		}
+		if err := ValidateSpanName(tt.want); err != nil {
+			t.Errorf("ValidateSpanName(%q) = %v, want nil", tt.want, err)
+		}
+	}
+}
+
+func TestValidateSpanName(t *testing.T) {
+	for _, name := range []string{"say-hello", "formatString", "format", "HTTP GET /users/{id}", "archive.Save", "hello.Greeter/SayHello"} {
+		if err := ValidateSpanName(name); err != nil {
+			t.Errorf("ValidateSpanName(%q) = %v, want nil", name, err)
+		}
+	}
+
+	for _, name := range []string{
+		"",
+		"GET /users/42",
+		"GET /orders/0af76519-16cd-43dd-8448-eb211c80319c",
+		"trace 0af7651916cd43dd8448eb211c80319c",
+		"GET http://localhost:8081/format",
+		"GET /format?helloTo=Bryan",
+		"notify bryan@example.com",
+		"Hello, Bryan! Greetings and salutations, have a wonderful day, Bryan!",
+	} {
+		if err := ValidateSpanName(name); err == nil {
+			t.Errorf("ValidateSpanName(%q) = nil, want an error", name)
+		}
+	}
+}
+
+func TestNamingPolicyProcessor(t *testing.T) {
+	var buf bytes.Buffer
+	tp := traceSdk.NewTracerProvider(traceSdk.WithSpanProcessor(NewNamingPolicyProcessor(log.New(&buf, "", 0))))
+	tracer := tp.Tracer("test")
+
+	for _, name := range []string{"format", "GET /users/42", "GET /users/42", "GET /users/43"} {
+		_, span := tracer.Start(context.Background(), name)
+		span.End()
+	}
+
+	// every offending name is reported once
+	lines := strings.Split(strings.TrimSpace(buf.String()), "\n")
+	if len(lines) != 2 || !strings.Contains(lines[0], `"42"`) || !strings.Contains(lines[1], `"43"`) {
+		t.Errorf("logged %q, want one line per offending name", buf.String())
+	}
+}
@@ -0,0 +1,13 @@
+package tracing
+
+import (
+	ocbridge "go.opentelemetry.io/otel/bridge/opencensus"
+	"go.opentelemetry.io/otel/trace"
+)
+
+// InstallOpenCensusBridge makes the spans created with the OpenCensus API, typically by legacy libraries, flow into the
+// pipeline of tp. OpenCensus being configured through globals, the bridge replaces the global OpenCensus tracer, and
+// picks up the spans of every library of the process using OpenCensus.
+func InstallOpenCensusBridge(tp trace.TracerProvider) {
+	ocbridge.InstallTraceBridge(ocbridge.WithTracerProvider(tp))
+}
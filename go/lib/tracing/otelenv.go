@@ -0,0 +1,190 @@
+package tracing
+
+import (
+	"fmt"
+	"os"
+	"strconv"
+	"strings"
+
+	"go.opentelemetry.io/otel/propagation"
+	traceSdk "go.opentelemetry.io/otel/sdk/trace"
+)
+
+const (
+	// OTEL_TRACES_SAMPLER_ENV is the standard environment variable naming the sampler, e.g.
+	// OTEL_TRACES_SAMPLER=parentbased_traceidratio, which replaces the default sampler of InitTracerProviderWithBackend
+	OTEL_TRACES_SAMPLER_ENV = "OTEL_TRACES_SAMPLER"
+	// OTEL_TRACES_SAMPLER_ARG_ENV is the standard environment variable holding the argument of the sampler, e.g.
+	// OTEL_TRACES_SAMPLER_ARG=0.1 for the ratio of a traceidratio sampler
+	OTEL_TRACES_SAMPLER_ARG_ENV = "OTEL_TRACES_SAMPLER_ARG"
+	// OTEL_PROPAGATORS_ENV is the standard environment variable listing the propagators, e.g.
+	// OTEL_PROPAGATORS=tracecontext,baggage,b3, which replaces the W3C propagator installed by the Init functions
+	OTEL_PROPAGATORS_ENV = "OTEL_PROPAGATORS"
+)
+
+// otelSamplers are the values of OTEL_TRACES_SAMPLER, with whether they take OTEL_TRACES_SAMPLER_ARG
+var otelSamplers = []struct {
+	name string
+	arg  bool
+}{
+	{"always_on", false},
+	{"always_off", false},
+	{"traceidratio", true},
+	{"parentbased_always_on", false},
+	{"parentbased_always_off", false},
+	{"parentbased_traceidratio", true},
+}
+
+// otelPropagators are the values of OTEL_PROPAGATORS, with the format of NewPropagator they stand for
+var otelPropagators = []struct {
+	name   string
+	format string
+}{
+	{"tracecontext", "tracecontext"},
+	{"baggage", "baggage"},
+	{"b3", "b3"},
+	{"b3multi", "b3multi"},
+	{"jaeger", "jaeger"},
+	{"xray", "xray"},
+	{"none", ""},
+}
+
+// otelUnsupported are the values of the standard variables which are valid, but which the lib does not implement
+var otelUnsupported = map[string]string{
+	"jaeger_remote":             "it needs the remote sampling endpoint of a Jaeger agent",
+	"parentbased_jaeger_remote": "it needs the remote sampling endpoint of a Jaeger agent",
+	"xray":                      "use the X-Ray sampling rules of the AWS Distro for OpenTelemetry instead",
+	"ottrace":                   "the OpenTracing headers are not propagated, see Lesson 28 for the bridge",
+	"w3c":                       "list tracecontext,baggage instead, w3c is only understood by PROPAGATION",
+}
+
+// ParseOTelSampler returns the sampler described by the standard name and argument of OTEL_TRACES_SAMPLER and
+// OTEL_TRACES_SAMPLER_ARG: always_on, always_off or traceidratio, optionally prefixed with "parentbased_". The ratio of
+// the traceidratio samplers defaults to 1 and must lie between 0 and 1. Unlike the SDK, which falls back to the default
+// sampler, a name or argument it does not understand is an error, suggesting the closest name for a typo.
+func ParseOTelSampler(name, arg string) (traceSdk.Sampler, error) {
+	name = strings.ToLower(strings.TrimSpace(name))
+	arg = strings.TrimSpace(arg)
+
+	for _, s := range otelSamplers {
+		if s.name != name {
+			continue
+		}
+		if !s.arg {
+			if arg != "" {
+				return nil, fmt.Errorf("%s=%s takes no argument, unset %s=%s", OTEL_TRACES_SAMPLER_ENV, name, OTEL_TRACES_SAMPLER_ARG_ENV, arg)
+			}
+			return ParseSampler(name)
+		}
+
+		ratio := 1.0
+		if arg != "" {
+			r, err := strconv.ParseFloat(arg, 64)
+			if err != nil || r < 0 || r > 1 {
+				return nil, fmt.Errorf("invalid %s=%s for %s=%s: want a ratio between 0 and 1, e.g. 0.1", OTEL_TRACES_SAMPLER_ARG_ENV, arg, OTEL_TRACES_SAMPLER_ENV, name)
+			}
+			ratio = r
+		}
+		return ParseSampler(name + ":" + strconv.FormatFloat(ratio, 'g', -1, 64))
+	}
+
+	if reason, ok := otelUnsupported[name]; ok {
+		return nil, fmt.Errorf("unsupported %s=%s: %s", OTEL_TRACES_SAMPLER_ENV, name, reason)
+	}
+	names := make([]string, len(otelSamplers))
+	for i, s := range otelSamplers {
+		names[i] = s.name
+	}
+	return nil, unknownValueError(OTEL_TRACES_SAMPLER_ENV, name, names)
+}
+
+// ParseOTelPropagators returns the composite propagator described by the standard comma-separated list of
+// OTEL_PROPAGATORS: tracecontext, baggage, b3, b3multi, jaeger and xray, or none for no propagation at all. A name it
+// does not understand is an error, suggesting the closest name for a typo.
+func ParseOTelPropagators(value string) (propagation.TextMapPropagator, error) {
+	names := make([]string, len(otelPropagators))
+	for i, p := range otelPropagators {
+		names[i] = p.name
+	}
+
+	var formats []string
+	seen := map[string]bool{}
+	for _, name := range strings.Split(value, ",") {
+		name = strings.ToLower(strings.TrimSpace(name))
+		if seen[name] {
+			continue
+		}
+		seen[name] = true
+
+		format, ok := "", false
+		for _, p := range otelPropagators {
+			if p.name == name {
+				format, ok = p.format, true
+				break
+			}
+		}
+		switch {
+		case !ok && otelUnsupported[name] != "":
+			return nil, fmt.Errorf("unsupported propagator %q in %s: %s", name, OTEL_PROPAGATORS_ENV, otelUnsupported[name])
+		case !ok:
+			return nil, unknownValueError(OTEL_PROPAGATORS_ENV, name, names)
+		case format != "":
+			formats = append(formats, format)
+		}
+	}
+
+	// "none" alone, or with other names as the SDK allows it, leaves the propagators listed
+	if len(formats) == 0 {
+		return propagation.NewCompositeTextMapPropagator(), nil
+	}
+	return NewPropagator(strings.Join(formats, ","))
+}
+
+// otelSamplerFromEnv returns the sampler set by OTEL_TRACES_SAMPLER and OTEL_TRACES_SAMPLER_ARG, or nil if unset
+func otelSamplerFromEnv() (traceSdk.Sampler, error) {
+	name, arg := os.Getenv(OTEL_TRACES_SAMPLER_ENV), os.Getenv(OTEL_TRACES_SAMPLER_ARG_ENV)
+	if name == "" {
+		if arg != "" {
+			return nil, fmt.Errorf("%s=%s is set without %s, e.g. %s=parentbased_traceidratio", OTEL_TRACES_SAMPLER_ARG_ENV, arg, OTEL_TRACES_SAMPLER_ENV, OTEL_TRACES_SAMPLER_ENV)
+		}
+		return nil, nil
+	}
+	return ParseOTelSampler(name, arg)
+}
+
+// unknownValueError returns the error of an unknown value of the variable env, suggesting the closest of the valid
+// values when it looks like a typo
+func unknownValueError(env, value string, valid []string) error {
+	closest, distance := "", len(value)
+	for _, v := range valid {
+		if d := editDistance(value, v); d < distance {
+			closest, distance = v, d
+		}
+	}
+	if closest != "" && distance <= max(1, len(value)/4) {
+		return fmt.Errorf("unknown value %q in %s, did you mean %q?", value, env, closest)
+	}
+	return fmt.Errorf("unknown value %q in %s, want one of %s", value, env, strings.Join(valid, ", "))
+}
+
+// editDistance returns the Levenshtein distance between a and b: the number of characters to insert, delete or
+// substitute to turn one into the other
+func editDistance(a, b string) int {
+	prev := make([]int, len(b)+1)
+	for j := range prev {
+		prev[j] = j
+	}
+	for i := 1; i <= len(a); i++ {
+		cur := make([]int, len(b)+1)
+		cur[0] = i
+		for j := 1; j <= len(b); j++ {
+			cost := 1
+			if a[i-1] == b[j-1] {
+				cost = 0
+			}
+			cur[j] = min(prev[j]+1, cur[j-1]+1, prev[j-1]+cost)
+		}
+		prev = cur
+	}
+	return prev[len(b)]
+}
@@ -0,0 +1,133 @@
+package tracing
+
+import (
+	"context"
+	"net/http"
+	"sort"
+	"strings"
+	"testing"
+
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/propagation"
+	traceSdk "go.opentelemetry.io/otel/sdk/trace"
+	"go.opentelemetry.io/otel/trace"
+)
+
+func TestParseOTelSampler(t *testing.T) {
+	for _, tt := range []struct {
+		name, arg string
+		want      string
+	}{
+		{"always_on", "", "AlwaysOnSampler"},
+		{"parentbased_always_off", "", "ParentBased{root:AlwaysOffSampler"},
+		{"traceidratio", "0.25", "TraceIDRatioBased{0.25}"},
+		{"parentbased_traceidratio", "", "ParentBased{root:AlwaysOnSampler"},
+		{" ParentBased_TraceIDRatio ", " 0.1 ", "ParentBased{root:TraceIDRatioBased{0.1}"},
+	} {
+		sampler, err := ParseOTelSampler(tt.name, tt.arg)
+		if err != nil {
+			t.Errorf("ParseOTelSampler(%q, %q) returned %v", tt.name, tt.arg, err)
+			continue
+		}
+		if got := sampler.Description(); !strings.HasPrefix(got, tt.want) {
+			t.Errorf("ParseOTelSampler(%q, %q) = %s, want %s", tt.name, tt.arg, got, tt.want)
+		}
+	}
+}
+
+func TestParseOTelSamplerErrors(t *testing.T) {
+	for _, tt := range []struct {
+		name, arg string
+		want      string
+	}{
+		{"parentbased_traceidration", "0.1", `did you mean "parentbased_traceidratio"?`},
+		{"alwayson", "", `did you mean "always_on"?`},
+		{"probabilistic", "", "want one of always_on, always_off"},
+		{"traceidratio", "10%", "want a ratio between 0 and 1"},
+		{"traceidratio", "1.5", "want a ratio between 0 and 1"},
+		{"always_on", "0.1", "takes no argument"},
+		{"parentbased_jaeger_remote", "", "unsupported"},
+	} {
+		_, err := ParseOTelSampler(tt.name, tt.arg)
+		if err == nil || !strings.Contains(err.Error(), tt.want) {
+			t.Errorf("ParseOTelSampler(%q, %q) = %v, want an error containing %q", tt.name, tt.arg, err, tt.want)
+		}
+	}
+}
+
+func TestOTelSamplerFromEnv(t *testing.T) {
+	t.Setenv(OTEL_TRACES_SAMPLER_ENV, "")
+	t.Setenv(OTEL_TRACES_SAMPLER_ARG_ENV, "0.1")
+	if _, err := otelSamplerFromEnv(); err == nil || !strings.Contains(err.Error(), "set without OTEL_TRACES_SAMPLER") {
+		t.Errorf("otelSamplerFromEnv() = %v, want the missing sampler reported", err)
+	}
+
+	t.Setenv(OTEL_TRACES_SAMPLER_ARG_ENV, "")
+	if sampler, err := otelSamplerFromEnv(); sampler != nil || err != nil {
+		t.Errorf("otelSamplerFromEnv() = %v, %v, want no sampler", sampler, err)
+	}
+}
+
+func TestExplicitSamplerWinsOverEnv(t *testing.T) {
+	t.Setenv(OTEL_TRACES_SAMPLER_ENV, "always_off")
+	t.Setenv(OTEL_TRACES_SAMPLER_ARG_ENV, "")
+	defer otel.SetTracerProvider(otel.GetTracerProvider())
+	defer otel.SetTextMapPropagator(otel.GetTextMapPropagator())
+
+	// the spans are started but never ended, so that nothing is exported to the backend
+	sampled := func(tp *traceSdk.TracerProvider) bool {
+		_, span := tp.Tracer("test").Start(context.Background(), "say-hello")
+		return span.SpanContext().IsSampled()
+	}
+
+	explicit, err := InitTracerProviderWithSampler("hello-world", TRACING_BACKEND, traceSdk.AlwaysSample())
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !sampled(explicit) {
+		t.Error("the explicit AlwaysSample sampler was replaced by OTEL_TRACES_SAMPLER=always_off")
+	}
+
+	byDefault, err := InitTracerProviderWithBackend("hello-world", TRACING_BACKEND)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if sampled(byDefault) {
+		t.Error("OTEL_TRACES_SAMPLER=always_off did not replace the default sampler")
+	}
+}
+
+func TestParseOTelPropagators(t *testing.T) {
+	propagator, err := ParseOTelPropagators("tracecontext, baggage,b3,tracecontext")
+	if err != nil {
+		t.Fatal(err)
+	}
+	fields := propagator.Fields()
+	sort.Strings(fields)
+	if got, want := strings.Join(fields, ","), "b3,baggage,traceparent,tracestate"; got != want {
+		t.Errorf("fields = %s, want %s", got, want)
+	}
+
+	// no propagation at all: nothing is injected
+	propagator, err = ParseOTelPropagators("none")
+	if err != nil {
+		t.Fatal(err)
+	}
+	sc := trace.NewSpanContext(trace.SpanContextConfig{TraceID: trace.TraceID{1}, SpanID: trace.SpanID{1}})
+	header := http.Header{}
+	propagator.Inject(trace.ContextWithSpanContext(context.Background(), sc), propagation.HeaderCarrier(header))
+	if len(header) != 0 {
+		t.Errorf("injected %v, want nothing", header)
+	}
+
+	for value, want := range map[string]string{
+		"tracecontext,bagage": `did you mean "baggage"?`,
+		"w3c":                 "list tracecontext,baggage instead",
+		"zipkin":              "want one of tracecontext, baggage, b3",
+		"ottrace":             "unsupported",
+	} {
+		if _, err := ParseOTelPropagators(value); err == nil || !strings.Contains(err.Error(), want) {
+			t.Errorf("ParseOTelPropagators(%q) = %v, want an error containing %q", value, err, want)
+		}
+	}
+}
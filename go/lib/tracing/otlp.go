@@ -0,0 +1,127 @@
+package tracing
+
+import (
+	"bytes"
+	"context"
+	"encoding/base64"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+
+	"go.opentelemetry.io/otel/exporters/otlp/otlptrace"
+	"go.opentelemetry.io/otel/exporters/otlp/otlptrace/otlptracehttp"
+	coltracepb "go.opentelemetry.io/proto/otlp/collector/trace/v1"
+	tracepb "go.opentelemetry.io/proto/otlp/trace/v1"
+	"google.golang.org/protobuf/encoding/protojson"
+)
+
+const (
+	// OTLP_ENCODING_ENV is the environment variable selecting the encoding of the OTLP/HTTP payloads sent by
+	// NewTracerProvider, e.g. OTLP_ENCODING=json to read them in a capture of the traffic
+	OTLP_ENCODING_ENV = "OTLP_ENCODING"
+
+	// OTLP_PROTOBUF and OTLP_JSON are the encodings accepted by NewOTLPExporter
+	OTLP_PROTOBUF = "protobuf"
+	OTLP_JSON     = "json"
+)
+
+// NewOTLPExporter creates an exporter sending the spans to the backend over OTLP/HTTP, with the given encoding:
+// OTLP_PROTOBUF, the default of the SDK, or OTLP_JSON, which is larger and slower but human-readable.
+func NewOTLPExporter(ctx context.Context, backend, encoding string) (*otlptrace.Exporter, error) {
+	switch encoding {
+	case OTLP_PROTOBUF:
+		return otlptracehttp.New(ctx, otlptracehttp.WithEndpoint(backend), otlptracehttp.WithInsecure())
+	case OTLP_JSON:
+		// the exporter of the SDK only speaks protobuf, so the JSON payloads are sent by a client of our own
+		return otlptrace.New(ctx, &jsonClient{url: "http://" + backend + "/v1/traces", client: &http.Client{}})
+	default:
+		return nil, fmt.Errorf("unknown OTLP encoding %q", encoding)
+	}
+}
+
+// jsonClient uploads the spans over OTLP/HTTP, encoded in JSON
+type jsonClient struct {
+	url    string
+	client *http.Client
+}
+
+func (c *jsonClient) Start(ctx context.Context) error {
+	return nil
+}
+
+func (c *jsonClient) Stop(ctx context.Context) error {
+	c.client.CloseIdleConnections()
+	return nil
+}
+
+func (c *jsonClient) UploadTraces(ctx context.Context, spans []*tracepb.ResourceSpans) error {
+	body, err := marshalJSON(&coltracepb.ExportTraceServiceRequest{ResourceSpans: spans})
+	if err != nil {
+		return err
+	}
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, c.url, bytes.NewReader(body))
+	if err != nil {
+		return err
+	}
+	req.Header.Set("Content-Type", "application/json")
+
+	resp, err := c.client.Do(req)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode < 200 || resp.StatusCode > 299 {
+		msg, _ := io.ReadAll(io.LimitReader(resp.Body, 1024))
+		return fmt.Errorf("OTLP/JSON export failed with %s: %s", resp.Status, bytes.TrimSpace(msg))
+	}
+	return nil
+}
+
+// marshalJSON encodes an export request in OTLP/JSON. Unlike the standard JSON mapping of protobuf, OTLP/JSON encodes
+// the trace and span IDs in hex rather than base64, and the enums as integers.
+func marshalJSON(export *coltracepb.ExportTraceServiceRequest) ([]byte, error) {
+	b, err := protojson.MarshalOptions{UseEnumNumbers: true}.Marshal(export)
+	if err != nil {
+		return nil, err
+	}
+
+	var v any
+	if err := json.Unmarshal(b, &v); err != nil {
+		return nil, err
+	}
+	if err := base64ToHex(v); err != nil {
+		return nil, err
+	}
+	return json.Marshal(v)
+}
+
+// base64ToHex converts the base64-encoded IDs found in the decoded JSON value v to hex, in place
+func base64ToHex(v any) error {
+	switch v := v.(type) {
+	case map[string]any:
+		for k, child := range v {
+			if id, ok := child.(string); ok && (k == "traceId" || k == "spanId" || k == "parentSpanId") {
+				raw, err := base64.StdEncoding.DecodeString(id)
+				if err != nil {
+					return fmt.Errorf("invalid %s %q: %v", k, id, err)
+				}
+				v[k] = hex.EncodeToString(raw)
+				continue
+			}
+			if err := base64ToHex(child); err != nil {
+				return err
+			}
+		}
+	case []any:
+		for _, child := range v {
+			if err := base64ToHex(child); err != nil {
+				return err
+			}
+		}
+	}
+	return nil
+}
@@ -0,0 +1,88 @@
+package tracing
+
+import (
+	"context"
+	"encoding/json"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	traceSdk "go.opentelemetry.io/otel/sdk/trace"
+)
+
+func TestNewOTLPExporterJSON(t *testing.T) {
+	var contentType string
+	var body []byte
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		contentType = r.Header.Get("Content-Type")
+		body, _ = io.ReadAll(r.Body)
+	}))
+	defer srv.Close()
+
+	exporter, err := NewOTLPExporter(context.Background(), strings.TrimPrefix(srv.URL, "http://"), OTLP_JSON)
+	if err != nil {
+		t.Fatal(err)
+	}
+	tp := traceSdk.NewTracerProvider(traceSdk.WithSyncer(exporter))
+	_, span := tp.Tracer("otlp-tracer").Start(context.Background(), "say-hello")
+	span.End()
+	if err := tp.Shutdown(context.Background()); err != nil {
+		t.Fatalf("Shutdown() = %v", err)
+	}
+
+	if contentType != "application/json" {
+		t.Errorf("Content-Type = %q, want %q", contentType, "application/json")
+	}
+
+	// the IDs are hex-encoded, as in the UIs of the backends, and the kind is a number
+	var export struct {
+		ResourceSpans []struct {
+			ScopeSpans []struct {
+				Spans []struct {
+					TraceID string `json:"traceId"`
+					SpanID  string `json:"spanId"`
+					Name    string `json:"name"`
+					Kind    int    `json:"kind"`
+				} `json:"spans"`
+			} `json:"scopeSpans"`
+		} `json:"resourceSpans"`
+	}
+	if err := json.Unmarshal(body, &export); err != nil {
+		t.Fatalf("invalid JSON payload %s: %v", body, err)
+	}
+	got := export.ResourceSpans[0].ScopeSpans[0].Spans[0]
+	sc := span.SpanContext()
+	if got.TraceID != sc.TraceID().String() || got.SpanID != sc.SpanID().String() {
+		t.Errorf("IDs = %s/%s, want %s/%s", got.TraceID, got.SpanID, sc.TraceID(), sc.SpanID())
+	}
+	if got.Name != "say-hello" || got.Kind != 1 {
+		t.Errorf("span = %+v, want an internal span named say-hello", got)
+	}
+}
+
+func TestNewOTLPExporterJSONError(t *testing.T) {
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		http.Error(w, "unsupported content type", http.StatusUnsupportedMediaType)
+	}))
+	defer srv.Close()
+
+	exporter, err := NewOTLPExporter(context.Background(), strings.TrimPrefix(srv.URL, "http://"), OTLP_JSON)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer exporter.Shutdown(context.Background())
+
+	tp := traceSdk.NewTracerProvider()
+	_, span := tp.Tracer("otlp-tracer").Start(context.Background(), "say-hello")
+	span.End()
+	err = exporter.ExportSpans(context.Background(), []traceSdk.ReadOnlySpan{span.(traceSdk.ReadOnlySpan)})
+	if err == nil || !strings.Contains(err.Error(), "415") {
+		t.Errorf("ExportSpans() = %v, want the 415 of the backend", err)
+	}
+
+	if _, err := NewOTLPExporter(context.Background(), "localhost:4318", "xml"); err == nil {
+		t.Error("NewOTLPExporter(\"xml\") returned no error")
+	}
+}
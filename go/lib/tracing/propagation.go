@@ -0,0 +1,76 @@
+package tracing
+
+import (
+	"fmt"
+	"strings"
+
+	gcppropagator "github.com/GoogleCloudPlatform/opentelemetry-operations-go/propagator"
+	"go.opentelemetry.io/contrib/propagators/aws/xray"
+	"go.opentelemetry.io/contrib/propagators/b3"
+	"go.opentelemetry.io/contrib/propagators/jaeger"
+	"go.opentelemetry.io/otel/propagation"
+	traceSdk "go.opentelemetry.io/otel/sdk/trace"
+)
+
+const (
+	// PROPAGATION_ENV is the environment variable which, set to a list of formats accepted by NewPropagator, replaces
+	// the W3C propagator installed by the Init functions, e.g. PROPAGATION=w3c,xray
+	PROPAGATION_ENV = "PROPAGATION"
+	// ID_GENERATOR_ENV is the environment variable which, set to a generator accepted by NewIDGenerator, makes
+	// NewTracerProvider generate the IDs with it, e.g. ID_GENERATOR=xray
+	ID_GENERATOR_ENV = "ID_GENERATOR"
+)
+
+// NewPropagator builds a composite propagator from a comma-separated list of formats.
+// The supported formats are "tracecontext", "baggage", and "w3c" which stands for both of them, and "xray" for the
+// X-Amzn-Trace-Id header of AWS X-Ray, added by the AWS load balancers and understood by the AWS services.
+// "cloudtrace" is for the X-Cloud-Trace-Context header of Google Cloud Trace, added by the Google load balancers, and
+// "cloudtrace-oneway" only extracts it, leaving the other formats of the list to propagate the context further.
+// "b3" and "b3multi" are for the single b3 header and the X-B3-* headers of Zipkin, used by Istio among others: both
+// extract either encoding, and inject their own. "jaeger" is for the uber-trace-id header of the Jaeger clients.
+// "composite" stands for "w3c,b3,jaeger": the context is injected in the three formats, and extracted from any of them,
+// which suits a system in the middle of a migration from one format to another.
+func NewPropagator(formats string) (propagation.TextMapPropagator, error) {
+	var propagators []propagation.TextMapPropagator
+	for _, format := range strings.Split(formats, ",") {
+		switch strings.TrimSpace(format) {
+		case "w3c":
+			propagators = append(propagators, propagation.TraceContext{}, propagation.Baggage{})
+		case "composite":
+			propagators = append(propagators, propagation.TraceContext{}, propagation.Baggage{},
+				b3.New(b3.WithInjectEncoding(b3.B3SingleHeader)), jaeger.Jaeger{})
+		case "tracecontext":
+			propagators = append(propagators, propagation.TraceContext{})
+		case "baggage":
+			propagators = append(propagators, propagation.Baggage{})
+		case "xray":
+			propagators = append(propagators, xray.Propagator{})
+		case "b3":
+			propagators = append(propagators, b3.New(b3.WithInjectEncoding(b3.B3SingleHeader)))
+		case "b3multi":
+			propagators = append(propagators, b3.New(b3.WithInjectEncoding(b3.B3MultipleHeader)))
+		case "jaeger":
+			propagators = append(propagators, jaeger.Jaeger{})
+		case "cloudtrace":
+			propagators = append(propagators, gcppropagator.CloudTraceFormatPropagator{})
+		case "cloudtrace-oneway":
+			propagators = append(propagators, gcppropagator.CloudTraceOneWayPropagator{})
+		default:
+			return nil, fmt.Errorf("unknown propagation format %q", format)
+		}
+	}
+	return propagation.NewCompositeTextMapPropagator(propagators...), nil
+}
+
+// NewIDGenerator returns the generator of the trace and span IDs named name: "random" for the random IDs of the SDK,
+// or "xray" for trace IDs starting with their timestamp, as AWS X-Ray requires to accept them.
+func NewIDGenerator(name string) (traceSdk.IDGenerator, error) {
+	switch name {
+	case "random":
+		return nil, nil
+	case "xray":
+		return xray.NewIDGenerator(), nil
+	default:
+		return nil, fmt.Errorf("unknown ID generator %q", name)
+	}
+}
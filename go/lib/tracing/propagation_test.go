@@ -0,0 +1,240 @@
+package tracing
+
+import (
+	"context"
+	"encoding/binary"
+	"net/http"
+	"slices"
+	"testing"
+	"time"
+
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/propagation"
+	"go.opentelemetry.io/otel/trace"
+)
+
+func TestNewPropagatorXRay(t *testing.T) {
+	propagator, err := NewPropagator("w3c,xray")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	sc := trace.NewSpanContext(trace.SpanContextConfig{
+		TraceID:    trace.TraceID{0x5f, 0x84, 0xc7, 0xa5, 0x01, 0x02, 0x03, 0x04, 0x05, 0x06, 0x07, 0x08, 0x09, 0x0a, 0x0b, 0x0c},
+		SpanID:     trace.SpanID{0x01, 0x02, 0x03, 0x04, 0x05, 0x06, 0x07, 0x08},
+		TraceFlags: trace.FlagsSampled,
+	})
+	header := http.Header{}
+	propagator.Inject(trace.ContextWithSpanContext(context.Background(), sc), propagation.HeaderCarrier(header))
+
+	if got, want := header.Get("X-Amzn-Trace-Id"), "Root=1-5f84c7a5-0102030405060708090a0b0c;Parent=0102030405060708;Sampled=1"; got != want {
+		t.Errorf("X-Amzn-Trace-Id = %q, want %q", got, want)
+	}
+	if header.Get("traceparent") == "" {
+		t.Error("traceparent not injected")
+	}
+
+	// a request from an AWS load balancer only carries the X-Ray header
+	header = http.Header{"X-Amzn-Trace-Id": {"Root=1-5f84c7a5-0102030405060708090a0b0c;Parent=0102030405060708;Sampled=1"}}
+	got := trace.SpanContextFromContext(propagator.Extract(context.Background(), propagation.HeaderCarrier(header)))
+	if got.TraceID() != sc.TraceID() || got.SpanID() != sc.SpanID() || !got.IsSampled() || !got.IsRemote() {
+		t.Errorf("extracted %v, want the remote span context %v", got, sc)
+	}
+}
+
+func TestNewIDGeneratorXRay(t *testing.T) {
+	generator, err := NewIDGenerator("xray")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	// X-Ray rejects the trace IDs not starting with the time of the trace, in seconds
+	before := time.Now().Unix()
+	traceID, _ := generator.NewIDs(context.Background())
+	if got := int64(binary.BigEndian.Uint32(traceID[:4])); got < before || got > time.Now().Unix() {
+		t.Errorf("trace ID %s starts with %d, want the current time %d", traceID, got, before)
+	}
+
+	if _, err := NewIDGenerator("sequential"); err == nil {
+		t.Error("NewIDGenerator(\"sequential\") returned no error")
+	}
+}
+
+func TestNewPropagatorCloudTrace(t *testing.T) {
+	propagator, err := NewPropagator("w3c,cloudtrace")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	sc := trace.NewSpanContext(trace.SpanContextConfig{
+		TraceID:    trace.TraceID{0x01, 0x02, 0x03, 0x04, 0x05, 0x06, 0x07, 0x08, 0x09, 0x0a, 0x0b, 0x0c, 0x0d, 0x0e, 0x0f, 0x10},
+		SpanID:     trace.SpanID{0, 0, 0, 0, 0, 0, 0x01, 0x00},
+		TraceFlags: trace.FlagsSampled,
+	})
+	header := http.Header{}
+	propagator.Inject(trace.ContextWithSpanContext(context.Background(), sc), propagation.HeaderCarrier(header))
+
+	// the span ID of Cloud Trace is a decimal number
+	if got, want := header.Get("X-Cloud-Trace-Context"), "0102030405060708090a0b0c0d0e0f10/256;o=1"; got != want {
+		t.Errorf("X-Cloud-Trace-Context = %q, want %q", got, want)
+	}
+
+	// a request from a Google load balancer carries the Cloud Trace header
+	header = http.Header{"X-Cloud-Trace-Context": {"0102030405060708090a0b0c0d0e0f10/256;o=1"}}
+	got := trace.SpanContextFromContext(propagator.Extract(context.Background(), propagation.HeaderCarrier(header)))
+	if got.TraceID() != sc.TraceID() || got.SpanID() != sc.SpanID() || !got.IsSampled() || !got.IsRemote() {
+		t.Errorf("extracted %v, want the remote span context %v", got, sc)
+	}
+
+	// the one-way propagator extracts the header without injecting it
+	propagator, err = NewPropagator("w3c,cloudtrace-oneway")
+	if err != nil {
+		t.Fatal(err)
+	}
+	ctx := propagator.Extract(context.Background(), propagation.HeaderCarrier(header))
+	if got := trace.SpanContextFromContext(ctx); got.TraceID() != sc.TraceID() {
+		t.Errorf("extracted trace ID %s, want %s", got.TraceID(), sc.TraceID())
+	}
+	header = http.Header{}
+	propagator.Inject(ctx, propagation.HeaderCarrier(header))
+	if got := header.Get("X-Cloud-Trace-Context"); got != "" {
+		t.Errorf("X-Cloud-Trace-Context = %q, want none", got)
+	}
+	if header.Get("traceparent") == "" {
+		t.Error("traceparent not injected")
+	}
+}
+
+func TestNewPropagatorB3(t *testing.T) {
+	sc := trace.NewSpanContext(trace.SpanContextConfig{
+		TraceID:    trace.TraceID{0x01, 0x02, 0x03, 0x04, 0x05, 0x06, 0x07, 0x08, 0x09, 0x0a, 0x0b, 0x0c, 0x0d, 0x0e, 0x0f, 0x10},
+		SpanID:     trace.SpanID{0x01, 0x02, 0x03, 0x04, 0x05, 0x06, 0x07, 0x08},
+		TraceFlags: trace.FlagsSampled,
+	})
+	ctx := trace.ContextWithSpanContext(context.Background(), sc)
+
+	tests := []struct {
+		formats string
+		want    http.Header
+	}{
+		{"b3", http.Header{
+			"B3": {"0102030405060708090a0b0c0d0e0f10-0102030405060708-1"},
+		}},
+		{"b3multi", http.Header{
+			"X-B3-Traceid": {"0102030405060708090a0b0c0d0e0f10"},
+			"X-B3-Spanid":  {"0102030405060708"},
+			"X-B3-Sampled": {"1"},
+		}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.formats, func(t *testing.T) {
+			propagator, err := NewPropagator(tt.formats)
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			header := http.Header{}
+			propagator.Inject(ctx, propagation.HeaderCarrier(header))
+			if len(header) != len(tt.want) {
+				t.Errorf("injected %v, want %v", header, tt.want)
+			}
+			for key, want := range tt.want {
+				if got := header.Get(key); got != want[0] {
+					t.Errorf("%s = %q, want %q", key, got, want[0])
+				}
+			}
+
+			got := trace.SpanContextFromContext(propagator.Extract(context.Background(), propagation.HeaderCarrier(header)))
+			if got.TraceID() != sc.TraceID() || got.SpanID() != sc.SpanID() || !got.IsSampled() || !got.IsRemote() {
+				t.Errorf("extracted %v, want the remote span context %v", got, sc)
+			}
+		})
+	}
+
+	// stacked, the propagator injects every encoding, for the services understanding only one of them
+	propagator, err := NewPropagator("w3c,b3,b3multi")
+	if err != nil {
+		t.Fatal(err)
+	}
+	header := http.Header{}
+	propagator.Inject(ctx, propagation.HeaderCarrier(header))
+	for _, key := range []string{"traceparent", "b3", "X-B3-TraceId"} {
+		if header.Get(key) == "" {
+			t.Errorf("%s not injected", key)
+		}
+	}
+}
+
+func TestNewPropagatorJaeger(t *testing.T) {
+	propagator, err := NewPropagator("w3c,jaeger")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	sc := trace.NewSpanContext(trace.SpanContextConfig{
+		TraceID:    trace.TraceID{0x01, 0x02, 0x03, 0x04, 0x05, 0x06, 0x07, 0x08, 0x09, 0x0a, 0x0b, 0x0c, 0x0d, 0x0e, 0x0f, 0x10},
+		SpanID:     trace.SpanID{0x01, 0x02, 0x03, 0x04, 0x05, 0x06, 0x07, 0x08},
+		TraceFlags: trace.FlagsSampled,
+	})
+	header := http.Header{}
+	propagator.Inject(trace.ContextWithSpanContext(context.Background(), sc), propagation.HeaderCarrier(header))
+
+	// the Jaeger clients do not record the parent span ID in the header anymore, hence the 0
+	if got, want := header.Get("uber-trace-id"), "0102030405060708090a0b0c0d0e0f10:0102030405060708:0:1"; got != want {
+		t.Errorf("uber-trace-id = %q, want %q", got, want)
+	}
+
+	// a request from a service still using a Jaeger client only carries the Jaeger header
+	header = http.Header{"Uber-Trace-Id": {"0102030405060708090a0b0c0d0e0f10:0102030405060708:0:1"}}
+	got := trace.SpanContextFromContext(propagator.Extract(context.Background(), propagation.HeaderCarrier(header)))
+	if got.TraceID() != sc.TraceID() || got.SpanID() != sc.SpanID() || !got.IsSampled() || !got.IsRemote() {
+		t.Errorf("extracted %v, want the remote span context %v", got, sc)
+	}
+}
+
+func TestNewPropagatorComposite(t *testing.T) {
+	propagator, err := NewPropagator("composite")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	sc := trace.NewSpanContext(trace.SpanContextConfig{
+		TraceID:    trace.TraceID{0x01, 0x02, 0x03, 0x04, 0x05, 0x06, 0x07, 0x08, 0x09, 0x0a, 0x0b, 0x0c, 0x0d, 0x0e, 0x0f, 0x10},
+		SpanID:     trace.SpanID{0x01, 0x02, 0x03, 0x04, 0x05, 0x06, 0x07, 0x08},
+		TraceFlags: trace.FlagsSampled,
+	})
+	header := http.Header{}
+	propagator.Inject(trace.ContextWithSpanContext(context.Background(), sc), propagation.HeaderCarrier(header))
+
+	// the same span context, encoded in the three formats
+	for key, want := range map[string]string{
+		"traceparent":   "00-0102030405060708090a0b0c0d0e0f10-0102030405060708-01",
+		"b3":            "0102030405060708090a0b0c0d0e0f10-0102030405060708-1",
+		"uber-trace-id": "0102030405060708090a0b0c0d0e0f10:0102030405060708:0:1",
+	} {
+		if got := header.Get(key); got != want {
+			t.Errorf("%s = %q, want %q", key, got, want)
+		}
+	}
+
+	// a request in any of the formats is understood
+	header = http.Header{"B3": {"0102030405060708090a0b0c0d0e0f10-0102030405060708-1"}}
+	got := trace.SpanContextFromContext(propagator.Extract(context.Background(), propagation.HeaderCarrier(header)))
+	if got.TraceID() != sc.TraceID() || got.SpanID() != sc.SpanID() || !got.IsRemote() {
+		t.Errorf("extracted %v, want the remote span context %v", got, sc)
+	}
+}
+
+func TestWithPropagation(t *testing.T) {
+	t.Setenv(PROPAGATION_ENV, "jaeger")
+	defer otel.SetTracerProvider(otel.GetTracerProvider())
+	defer otel.SetTextMapPropagator(otel.GetTextMapPropagator())
+
+	// the formats given explicitly win over the PROPAGATION variable
+	if _, err := InitTracerProviderWithBackend("hello-world", TRACING_BACKEND, WithPropagation("b3")); err != nil {
+		t.Fatal(err)
+	}
+	if fields := otel.GetTextMapPropagator().Fields(); !slices.Contains(fields, "b3") || slices.Contains(fields, "uber-trace-id") {
+		t.Errorf("propagating %v, want the b3 header only", fields)
+	}
+}
@@ -0,0 +1,162 @@
+package tracing
+
+import (
+	"context"
+	"log"
+	"sync/atomic"
+
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/metric"
+	traceSdk "go.opentelemetry.io/otel/sdk/trace"
+)
+
+const (
+	// EXPORT_QUEUE_SIZE_ENV is the environment variable setting how many spans wait for the backend at most, before
+	// the new ones are dropped, e.g. a few hundred to see the spans dropped within seconds of a backend outage
+	EXPORT_QUEUE_SIZE_ENV = "EXPORT_QUEUE_SIZE"
+
+	// DEFAULT_EXPORT_QUEUE_SIZE is the size of the queue of the batch span processor of the SDK
+	DEFAULT_EXPORT_QUEUE_SIZE = 2048
+)
+
+// QueueStats counts the spans going through a processor returned by NewQueueProcessor.
+type QueueStats struct {
+	capacity int64
+
+	pending  atomic.Int64
+	dropped  atomic.Int64
+	exported atomic.Int64
+	failed   atomic.Int64
+
+	// dropping is set from the first span dropped until the backend accepts the spans again
+	dropping atomic.Bool
+}
+
+// Capacity returns the number of spans which can wait for the backend at most.
+func (s *QueueStats) Capacity() int64 { return s.capacity }
+
+// Pending returns the number of spans waiting for the backend: queued, or being exported.
+func (s *QueueStats) Pending() int64 { return s.pending.Load() }
+
+// Dropped returns the number of spans dropped because the queue was full.
+func (s *QueueStats) Dropped() int64 { return s.dropped.Load() }
+
+// Exported returns the number of spans the backend accepted.
+func (s *QueueStats) Exported() int64 { return s.exported.Load() }
+
+// Failed returns the number of spans lost because their export failed, once the exporter gave up retrying.
+func (s *QueueStats) Failed() int64 { return s.failed.Load() }
+
+// queueProcessor bounds the number of spans handed to a batch span processor, whose own queue never fills, so that the
+// spans it would drop are counted
+type queueProcessor struct {
+	traceSdk.SpanProcessor
+	stats *QueueStats
+}
+
+// NewQueueProcessor returns a batch span processor exporting the spans with exporter, which counts the spans it queues,
+// drops and exports in the returned QueueStats: the batch span processor of the SDK drops the new spans when its queue
+// is full, e.g. while the backend is down, without telling how many. It behaves the same, at most queueSize spans
+// waiting for the backend, the ones in the batch being exported included. The options are passed to the batch span
+// processor of the SDK, but for its queue size.
+func NewQueueProcessor(exporter traceSdk.SpanExporter, queueSize int, opts ...traceSdk.BatchSpanProcessorOption) (traceSdk.SpanProcessor, *QueueStats) {
+	stats := &QueueStats{capacity: int64(queueSize)}
+
+	// the queue of the batch span processor holds as many spans as the processor lets wait, so that it never drops any
+	opts = append(opts, traceSdk.WithMaxQueueSize(queueSize))
+	bsp := traceSdk.NewBatchSpanProcessor(&countingExporter{SpanExporter: exporter, stats: stats}, opts...)
+
+	return &queueProcessor{SpanProcessor: bsp, stats: stats}, stats
+}
+
+func (p *queueProcessor) OnEnd(s traceSdk.ReadOnlySpan) {
+	// the batch span processor ignores the spans which are not sampled
+	if !s.SpanContext().IsSampled() {
+		return
+	}
+
+	// counting the span as pending unless the queue is full, in which case it is dropped
+	for {
+		pending := p.stats.pending.Load()
+		if pending >= p.stats.capacity {
+			p.stats.dropped.Add(1)
+			if p.stats.dropping.CompareAndSwap(false, true) {
+				log.Printf("the export queue is full, %d spans waiting for the backend: dropping the new spans", pending)
+			}
+			return
+		}
+		if p.stats.pending.CompareAndSwap(pending, pending+1) {
+			break
+		}
+	}
+	p.SpanProcessor.OnEnd(s)
+}
+
+// countingExporter counts the spans exported, successfully or not, in the stats of a queueProcessor
+type countingExporter struct {
+	traceSdk.SpanExporter
+	stats *QueueStats
+}
+
+func (e *countingExporter) ExportSpans(ctx context.Context, spans []traceSdk.ReadOnlySpan) error {
+	err := e.SpanExporter.ExportSpans(ctx, spans)
+	e.stats.pending.Add(-int64(len(spans)))
+	if err != nil {
+		e.stats.failed.Add(int64(len(spans)))
+		return err
+	}
+	e.stats.exported.Add(int64(len(spans)))
+	if e.stats.dropping.CompareAndSwap(true, false) {
+		log.Printf("the backend accepts the spans again, %d spans dropped so far", e.stats.Dropped())
+	}
+	return nil
+}
+
+// RegisterQueueMetrics records the counts of stats in observable instruments of meter, collected with the other
+// metrics of the service:
+//
+//   - tracing.export.queue.size: the spans waiting for the backend, tracing.export.queue.capacity at most
+//   - tracing.export.dropped: the spans dropped because the queue was full
+//   - tracing.export.spans: the spans exported, by outcome, "success" or "failure"
+func RegisterQueueMetrics(meter metric.Meter, stats *QueueStats) error {
+	size, err := meter.Int64ObservableGauge("tracing.export.queue.size",
+		metric.WithDescription("Spans waiting for the backend, queued or being exported"),
+		metric.WithUnit("{span}"),
+	)
+	if err != nil {
+		return err
+	}
+	capacity, err := meter.Int64ObservableGauge("tracing.export.queue.capacity",
+		metric.WithDescription("Spans which can wait for the backend at most"),
+		metric.WithUnit("{span}"),
+	)
+	if err != nil {
+		return err
+	}
+	dropped, err := meter.Int64ObservableCounter("tracing.export.dropped",
+		metric.WithDescription("Spans dropped because the export queue was full"),
+		metric.WithUnit("{span}"),
+	)
+	if err != nil {
+		return err
+	}
+	exported, err := meter.Int64ObservableCounter("tracing.export.spans",
+		metric.WithDescription("Spans exported, by outcome"),
+		metric.WithUnit("{span}"),
+	)
+	if err != nil {
+		return err
+	}
+
+	success := metric.WithAttributes(attribute.String("outcome", "success"))
+	failure := metric.WithAttributes(attribute.String("outcome", "failure"))
+	_, err = meter.RegisterCallback(func(ctx context.Context, o metric.Observer) error {
+		o.ObserveInt64(size, stats.Pending())
+		o.ObserveInt64(capacity, stats.Capacity())
+		o.ObserveInt64(dropped, stats.Dropped())
+		o.ObserveInt64(exported, stats.Exported(), success)
+		o.ObserveInt64(exported, stats.Failed(), failure)
+		return nil
+	}, size, capacity, dropped, exported)
+	return err
+}
@@ -0,0 +1,113 @@
+package tracing
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"go.opentelemetry.io/otel/attribute"
+	metricSdk "go.opentelemetry.io/otel/sdk/metric"
+	"go.opentelemetry.io/otel/sdk/metric/metricdata"
+	traceSdk "go.opentelemetry.io/otel/sdk/trace"
+)
+
+// blockingExporter exports the spans once released, failing them with err
+type blockingExporter struct {
+	release chan struct{}
+	err     error
+}
+
+func (e *blockingExporter) ExportSpans(ctx context.Context, spans []traceSdk.ReadOnlySpan) error {
+	select {
+	case <-e.release:
+		return e.err
+	case <-ctx.Done():
+		return ctx.Err()
+	}
+}
+
+func (e *blockingExporter) Shutdown(ctx context.Context) error { return nil }
+
+func TestQueueProcessor(t *testing.T) {
+	// the backend being down, nothing leaves the queue
+	exporter := &blockingExporter{release: make(chan struct{})}
+	processor, stats := NewQueueProcessor(exporter, 4, traceSdk.WithMaxExportBatchSize(2), traceSdk.WithBatchTimeout(time.Millisecond))
+	tp := traceSdk.NewTracerProvider(traceSdk.WithSpanProcessor(processor))
+	tracer := tp.Tracer("test")
+
+	for range 10 {
+		_, span := tracer.Start(context.Background(), "format")
+		span.End()
+	}
+	if stats.Pending() != 4 || stats.Dropped() != 6 {
+		t.Errorf("pending = %d, dropped = %d, want 4 and 6", stats.Pending(), stats.Dropped())
+	}
+
+	// the backend being back, the queue drains
+	close(exporter.release)
+	if err := tp.ForceFlush(context.Background()); err != nil {
+		t.Fatal(err)
+	}
+	if stats.Pending() != 0 || stats.Exported() != 4 || stats.Failed() != 0 {
+		t.Errorf("pending = %d, exported = %d, failed = %d, want 0, 4 and 0", stats.Pending(), stats.Exported(), stats.Failed())
+	}
+
+	// the spans of the exports failing for good are counted as failed
+	exporter.err = errors.New("connection refused")
+	_, span := tracer.Start(context.Background(), "format")
+	span.End()
+	tp.ForceFlush(context.Background())
+	if stats.Pending() != 0 || stats.Failed() != 1 {
+		t.Errorf("pending = %d, failed = %d, want 0 and 1", stats.Pending(), stats.Failed())
+	}
+}
+
+func TestRegisterQueueMetrics(t *testing.T) {
+	stats := &QueueStats{capacity: 2048}
+	stats.pending.Store(100)
+	stats.dropped.Store(7)
+	stats.exported.Store(500)
+	stats.failed.Store(3)
+
+	reader := metricSdk.NewManualReader()
+	mp := metricSdk.NewMeterProvider(metricSdk.WithReader(reader))
+	if err := RegisterQueueMetrics(mp.Meter("test"), stats); err != nil {
+		t.Fatal(err)
+	}
+
+	var rm metricdata.ResourceMetrics
+	if err := reader.Collect(context.Background(), &rm); err != nil {
+		t.Fatal(err)
+	}
+
+	// the values of every instrument, by outcome for the exported spans
+	got := map[string]int64{}
+	for _, m := range rm.ScopeMetrics[0].Metrics {
+		var points []metricdata.DataPoint[int64]
+		switch data := m.Data.(type) {
+		case metricdata.Gauge[int64]:
+			points = data.DataPoints
+		case metricdata.Sum[int64]:
+			points = data.DataPoints
+		}
+		for _, p := range points {
+			name := m.Name
+			if outcome, ok := p.Attributes.Value(attribute.Key("outcome")); ok {
+				name += "/" + outcome.AsString()
+			}
+			got[name] = p.Value
+		}
+	}
+	for name, want := range map[string]int64{
+		"tracing.export.queue.size":     100,
+		"tracing.export.queue.capacity": 2048,
+		"tracing.export.dropped":        7,
+		"tracing.export.spans/success":  500,
+		"tracing.export.spans/failure":  3,
+	} {
+		if got[name] != want {
+			t.Errorf("%s = %d, want %d", name, got[name], want)
+		}
+	}
+}
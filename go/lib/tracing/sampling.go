@@ -0,0 +1,85 @@
+package tracing
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+
+	traceSdk "go.opentelemetry.io/otel/sdk/trace"
+	"go.opentelemetry.io/otel/trace"
+	"golang.org/x/time/rate"
+)
+
+// PARENT_BASED_PREFIX turns the sampler of a spec into the root sampler of a parent-based sampler
+const PARENT_BASED_PREFIX = "parentbased_"
+
+// ParseSampler returns the sampler described by spec, one of:
+//   - always_on: records every trace
+//   - always_off: records no trace
+//   - traceidratio:<ratio>: records the given fraction of the traces, e.g. traceidratio:0.1
+//   - ratelimiting:<per second>: records at most the given number of traces per second, e.g. ratelimiting:5
+//
+// Prefixed with "parentbased_", the sampler only decides for the root spans, the other spans following the decision
+// of their parent, e.g. parentbased_traceidratio:0.1.
+func ParseSampler(spec string) (traceSdk.Sampler, error) {
+	if root, ok := strings.CutPrefix(spec, PARENT_BASED_PREFIX); ok {
+		sampler, err := ParseSampler(root)
+		if err != nil {
+			return nil, err
+		}
+		return traceSdk.ParentBased(sampler), nil
+	}
+
+	name, arg, _ := strings.Cut(spec, ":")
+	switch name {
+	case "always_on":
+		return traceSdk.AlwaysSample(), nil
+	case "always_off":
+		return traceSdk.NeverSample(), nil
+	case "traceidratio":
+		ratio, err := strconv.ParseFloat(arg, 64)
+		if err != nil {
+			return nil, fmt.Errorf("invalid ratio in sampler %q: %v", spec, err)
+		}
+		return traceSdk.TraceIDRatioBased(ratio), nil
+	case "ratelimiting":
+		perSecond, err := strconv.ParseFloat(arg, 64)
+		if err != nil {
+			return nil, fmt.Errorf("invalid rate in sampler %q: %v", spec, err)
+		}
+		return NewRateLimitingSampler(perSecond), nil
+	default:
+		return nil, fmt.Errorf("unknown sampler %q", spec)
+	}
+}
+
+// rateLimitingSampler records the traces as long as its token bucket has tokens left
+type rateLimitingSampler struct {
+	limiter   *rate.Limiter
+	perSecond float64
+}
+
+// NewRateLimitingSampler returns a sampler recording at most perSecond traces per second, with a burst of one second
+// worth of traces. Unlike a ratio, the limit keeps the volume of traces bounded however much the traffic grows.
+func NewRateLimitingSampler(perSecond float64) traceSdk.Sampler {
+	burst := int(perSecond)
+	if burst < 1 {
+		burst = 1
+	}
+	return &rateLimitingSampler{limiter: rate.NewLimiter(rate.Limit(perSecond), burst), perSecond: perSecond}
+}
+
+func (s *rateLimitingSampler) ShouldSample(p traceSdk.SamplingParameters) traceSdk.SamplingResult {
+	decision := traceSdk.Drop
+	if s.limiter.Allow() {
+		decision = traceSdk.RecordAndSample
+	}
+	return traceSdk.SamplingResult{
+		Decision:   decision,
+		Tracestate: trace.SpanContextFromContext(p.ParentContext).TraceState(),
+	}
+}
+
+func (s *rateLimitingSampler) Description() string {
+	return fmt.Sprintf("RateLimitingSampler{%g}", s.perSecond)
+}
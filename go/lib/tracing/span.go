@@ -0,0 +1,65 @@
+package tracing
+
+import (
+	"context"
+	"fmt"
+
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/codes"
+	"go.opentelemetry.io/otel/trace"
+)
+
+// TRACER_NAME is the name of the tracer of the spans started by Trace
+const TRACER_NAME = "tracing"
+
+// tracer is the tracer of the spans started by Trace
+var tracer = NewGlobalTracer(TRACER_NAME)
+
+// WithSpan runs fn in a span named name, started with tracer as a child of the span of ctx, and returns the error of
+// fn. fn receives the context holding the span, to add attributes and events to it, or to start child spans. When fn
+// fails, the error is recorded in an "exception" event and the span is marked as failed with its message, as Lesson 15
+// does by hand. The span ends when fn returns, or when it panics, in which case the panic is recorded as well before
+// going on. The options are passed to tracer.Start, e.g. trace.WithSpanKind(trace.SpanKindClient).
+func WithSpan(ctx context.Context, tracer trace.Tracer, name string, fn func(ctx context.Context) error, opts ...trace.SpanStartOption) error {
+	ctx, span := tracer.Start(ctx, name, opts...)
+	defer func() {
+		if r := recover(); r != nil {
+			RecordFailure(span, fmt.Errorf("panic: %v", r))
+			span.End()
+			panic(r)
+		}
+		span.End()
+	}()
+
+	err := fn(ctx)
+	if err != nil {
+		RecordFailure(span, err)
+	}
+	return err
+}
+
+// Trace runs fn in a span named name, as WithSpan does, and returns the value and the error of fn, e.g.
+//
+//	helloStr, err := tracing.Trace(ctx, "formatString", func(ctx context.Context) (string, error) {
+//		return format(ctx, helloTo)
+//	})
+//
+// The span is started with the tracer named TRACER_NAME of the global TracerProvider, as a child of the span of ctx, with
+// the given options.
+func Trace[T any](ctx context.Context, name string, fn func(ctx context.Context) (T, error), opts ...trace.SpanStartOption) (T, error) {
+	var result T
+	err := WithSpan(ctx, tracer.Tracer(), name, func(ctx context.Context) error {
+		var err error
+		result, err = fn(ctx)
+		return err
+	}, opts...)
+	return result, err
+}
+
+// RecordFailure records err in an "exception" event of the span, along with the stack trace and the given attributes,
+// and marks the span as failed with the message of err. RecordError alone only adds the event: the status of the span
+// is what the backends look at to flag the span, and the trace, as failed.
+func RecordFailure(span trace.Span, err error, attrs ...attribute.KeyValue) {
+	span.RecordError(err, trace.WithStackTrace(true), trace.WithAttributes(attrs...))
+	span.SetStatus(codes.Error, err.Error())
+}
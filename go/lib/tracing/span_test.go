@@ -0,0 +1,144 @@
+package tracing
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/codes"
+	traceSdk "go.opentelemetry.io/otel/sdk/trace"
+	"go.opentelemetry.io/otel/sdk/trace/tracetest"
+	"go.opentelemetry.io/otel/trace"
+)
+
+func TestWithSpan(t *testing.T) {
+	sr := tracetest.NewSpanRecorder()
+	tracer := traceSdk.NewTracerProvider(traceSdk.WithSpanProcessor(sr)).Tracer("test")
+
+	ctx, root := tracer.Start(context.Background(), "root")
+	err := WithSpan(ctx, tracer, "succeeds", func(ctx context.Context) error {
+		// the context holds the span, as a child of the span of the caller
+		if got := trace.SpanContextFromContext(ctx); got.SpanID() == root.SpanContext().SpanID() {
+			t.Error("fn runs in the span of the caller")
+		}
+		return nil
+	})
+	if err != nil {
+		t.Errorf("WithSpan() = %v, want nil", err)
+	}
+
+	boom := errors.New("boom")
+	if err := WithSpan(ctx, tracer, "fails", func(ctx context.Context) error { return boom }); err != boom {
+		t.Errorf("WithSpan() = %v, want %v", err, boom)
+	}
+	root.End()
+
+	spans := sr.Ended()
+	if len(spans) != 3 {
+		t.Fatalf("recorded %d spans, want 3", len(spans))
+	}
+	succeeds, fails := spans[0], spans[1]
+	if succeeds.Name() != "succeeds" || succeeds.Parent().SpanID() != root.SpanContext().SpanID() {
+		t.Errorf("span %q has parent %s, want a child of the root span", succeeds.Name(), succeeds.Parent().SpanID())
+	}
+	if succeeds.Status().Code != codes.Unset || len(succeeds.Events()) != 0 {
+		t.Errorf("succeeding span has status %v and %d events, want neither", succeeds.Status(), len(succeeds.Events()))
+	}
+	if fails.Status().Code != codes.Error || fails.Status().Description != "boom" {
+		t.Errorf("failing span has status %v, want an error", fails.Status())
+	}
+	if len(fails.Events()) != 1 || fails.Events()[0].Name != "exception" {
+		t.Errorf("failing span has events %v, want an exception", fails.Events())
+	}
+}
+
+func TestWithSpanPanic(t *testing.T) {
+	sr := tracetest.NewSpanRecorder()
+	tracer := traceSdk.NewTracerProvider(traceSdk.WithSpanProcessor(sr)).Tracer("test")
+
+	defer func() {
+		if r := recover(); r != "boom" {
+			t.Errorf("recovered %v, want the panic of fn", r)
+		}
+
+		// the span ended before the panic went on
+		spans := sr.Ended()
+		if len(spans) != 1 {
+			t.Fatalf("recorded %d spans, want 1", len(spans))
+		}
+		if got := spans[0].Status(); got.Code != codes.Error || got.Description != "panic: boom" {
+			t.Errorf("status = %v, want the panic", got)
+		}
+	}()
+	WithSpan(context.Background(), tracer, "panics", func(ctx context.Context) error { panic("boom") })
+}
+
+func TestRecordFailure(t *testing.T) {
+	sr := tracetest.NewSpanRecorder()
+	tracer := traceSdk.NewTracerProvider(traceSdk.WithSpanProcessor(sr)).Tracer("test")
+
+	_, span := tracer.Start(context.Background(), "formatString")
+	RecordFailure(span, errors.New("connection refused"), attribute.String("format-response-error", "Failed to format the string Bryan"))
+	span.End()
+
+	s := sr.Ended()[0]
+	if s.Status().Code != codes.Error || s.Status().Description != "connection refused" {
+		t.Errorf("status = %v, want an error with the message of the error", s.Status())
+	}
+	if len(s.Events()) != 1 {
+		t.Fatalf("recorded %d events, want 1", len(s.Events()))
+	}
+	attrs := attribute.NewSet(s.Events()[0].Attributes...)
+	for key, want := range map[attribute.Key]string{
+		"exception.message":     "connection refused",
+		"format-response-error": "Failed to format the string Bryan",
+	} {
+		if got, _ := attrs.Value(key); got.AsString() != want {
+			t.Errorf("%s = %q, want %q", key, got.AsString(), want)
+		}
+	}
+	if got, _ := attrs.Value("exception.stacktrace"); got.AsString() == "" {
+		t.Error("exception.stacktrace missing")
+	}
+}
+
+func TestTrace(t *testing.T) {
+	sr := tracetest.NewSpanRecorder()
+	previous := otel.GetTracerProvider()
+	otel.SetTracerProvider(traceSdk.NewTracerProvider(traceSdk.WithSpanProcessor(sr)))
+	defer otel.SetTracerProvider(previous)
+
+	helloStr, err := Trace(context.Background(), "formatString", func(ctx context.Context) (string, error) {
+		trace.SpanFromContext(ctx).SetAttributes(attribute.String("hello-to", "Bryan"))
+		return "Hello, Bryan!", nil
+	}, trace.WithSpanKind(trace.SpanKindClient))
+	if helloStr != "Hello, Bryan!" || err != nil {
+		t.Errorf("Trace() = %q, %v, want the result of fn", helloStr, err)
+	}
+
+	// the value returned along with the error is returned as well
+	boom := errors.New("boom")
+	n, err := Trace(context.Background(), "count", func(ctx context.Context) (int, error) { return 42, boom })
+	if n != 42 || err != boom {
+		t.Errorf("Trace() = %d, %v, want 42, %v", n, err, boom)
+	}
+
+	spans := sr.Ended()
+	if len(spans) != 2 {
+		t.Fatalf("recorded %d spans, want 2", len(spans))
+	}
+	if got := spans[0].InstrumentationScope().Name; got != TRACER_NAME {
+		t.Errorf("span started by tracer %q, want %q", got, TRACER_NAME)
+	}
+	if got := spans[0].SpanKind(); got != trace.SpanKindClient {
+		t.Errorf("span kind = %v, want the one given in the options", got)
+	}
+	if attrs := spans[0].Attributes(); len(attrs) != 1 || attrs[0].Value.AsString() != "Bryan" {
+		t.Errorf("succeeding span has attributes %v, want the ones set by fn", attrs)
+	}
+	if got := spans[1].Status(); got.Code != codes.Error || got.Description != "boom" {
+		t.Errorf("failing span has status %v, want an error", got)
+	}
+}
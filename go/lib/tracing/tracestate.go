@@ -0,0 +1,30 @@
+package tracing
+
+import (
+	"context"
+
+	"go.opentelemetry.io/otel/trace"
+)
+
+// TraceStateMember returns the value of the member key in the trace state of the span context of ctx, or an empty
+// string if there is none.
+func TraceStateMember(ctx context.Context, key string) string {
+	return trace.SpanContextFromContext(ctx).TraceState().Get(key)
+}
+
+// ContextWithTraceStateMember returns a copy of ctx whose span context carries the member key=value in its trace
+// state. The member is put first, replacing the previous value of key if any, as W3C asks of a vendor updating its
+// member. The key must be a lowercase vendor name, e.g. "tutorial" or "tenant@vendor", and the value must not hold
+// commas nor equal signs.
+//
+// The spans started from the returned context inherit the member, and the propagators inject it in the tracestate
+// header. The span of ctx itself, if any, is not changed, and is hidden behind the new span context: add the member
+// before starting the span which should carry it, e.g. the root span of the trace.
+func ContextWithTraceStateMember(ctx context.Context, key, value string) (context.Context, error) {
+	sc := trace.SpanContextFromContext(ctx)
+	ts, err := sc.TraceState().Insert(key, value)
+	if err != nil {
+		return ctx, err
+	}
+	return trace.ContextWithSpanContext(ctx, sc.WithTraceState(ts)), nil
+}
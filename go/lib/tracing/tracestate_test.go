@@ -0,0 +1,65 @@
+package tracing
+
+import (
+	"context"
+	"net/http"
+	"testing"
+
+	"go.opentelemetry.io/otel/propagation"
+	traceSdk "go.opentelemetry.io/otel/sdk/trace"
+	"go.opentelemetry.io/otel/sdk/trace/tracetest"
+)
+
+func TestContextWithTraceStateMember(t *testing.T) {
+	sr := tracetest.NewSpanRecorder()
+	tp := traceSdk.NewTracerProvider(traceSdk.WithSpanProcessor(sr))
+	tracer := tp.Tracer("test")
+
+	// the member added before the root span is inherited by the root span and its children
+	ctx, err := ContextWithTraceStateMember(context.Background(), "tutorial", "debug")
+	if err != nil {
+		t.Fatal(err)
+	}
+	ctx, root := tracer.Start(ctx, "root")
+	if got := TraceStateMember(ctx, "tutorial"); got != "debug" {
+		t.Errorf("TraceStateMember() = %q, want %q", got, "debug")
+	}
+
+	// updating a member moves it first
+	ctx, err = ContextWithTraceStateMember(ctx, "vendor", "1")
+	if err != nil {
+		t.Fatal(err)
+	}
+	ctx, err = ContextWithTraceStateMember(ctx, "tutorial", "off")
+	if err != nil {
+		t.Fatal(err)
+	}
+	_, child := tracer.Start(ctx, "child")
+	child.End()
+	root.End()
+
+	spans := sr.Ended()
+	if got, want := spans[0].SpanContext().TraceState().String(), "tutorial=off,vendor=1"; got != want {
+		t.Errorf("child tracestate = %q, want %q", got, want)
+	}
+	if got, want := spans[1].SpanContext().TraceState().String(), "tutorial=debug"; got != want {
+		t.Errorf("root tracestate = %q, want %q", got, want)
+	}
+	if spans[0].Parent().SpanID() != root.SpanContext().SpanID() {
+		t.Errorf("child has parent %s, want the root span %s", spans[0].Parent().SpanID(), root.SpanContext().SpanID())
+	}
+
+	// the member is injected in the tracestate header
+	header := http.Header{}
+	propagation.TraceContext{}.Inject(ctx, propagation.HeaderCarrier(header))
+	if got, want := header.Get("tracestate"), "tutorial=off,vendor=1"; got != want {
+		t.Errorf("tracestate = %q, want %q", got, want)
+	}
+
+	if _, err := ContextWithTraceStateMember(context.Background(), "Tutorial", "a=b"); err == nil {
+		t.Error("ContextWithTraceStateMember() accepted an invalid member")
+	}
+	if got := TraceStateMember(context.Background(), "tutorial"); got != "" {
+		t.Errorf("TraceStateMember() = %q without a span context, want none", got)
+	}
+}
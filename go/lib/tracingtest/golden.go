@@ -0,0 +1,204 @@
+package tracingtest
+
+import (
+	"bytes"
+	"encoding/json"
+	"flag"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"go.opentelemetry.io/otel/attribute"
+	traceSdk "go.opentelemetry.io/otel/sdk/trace"
+	"go.opentelemetry.io/otel/sdk/trace/tracetest"
+	"go.opentelemetry.io/otel/trace"
+)
+
+// IGNORED is the value written in the golden files in place of the ignored attributes
+const IGNORED = "<ignored>"
+
+// update makes AssertGolden write the golden files instead of comparing them, e.g. go test ./... -update
+var update = flag.Bool("update", false, "update the golden files of the span snapshots")
+
+// snapshotSpan is the normalized form of a span written to the golden files. The IDs are replaced by their order of
+// appearance, and the timestamps are left out, so that a snapshot only changes when the structure of the trace does.
+type snapshotSpan struct {
+	Name         string          `json:"name"`
+	Kind         string          `json:"kind"`
+	Scope        string          `json:"scope"`
+	TraceID      string          `json:"trace_id"`
+	SpanID       string          `json:"span_id"`
+	ParentSpanID string          `json:"parent_span_id,omitempty"`
+	RemoteParent bool            `json:"remote_parent,omitempty"`
+	Attributes   map[string]any  `json:"attributes,omitempty"`
+	Events       []snapshotEvent `json:"events,omitempty"`
+	Links        []snapshotLink  `json:"links,omitempty"`
+	Status       snapshotStatus  `json:"status"`
+}
+
+type snapshotEvent struct {
+	Name       string         `json:"name"`
+	Attributes map[string]any `json:"attributes,omitempty"`
+}
+
+type snapshotLink struct {
+	TraceID    string         `json:"trace_id"`
+	SpanID     string         `json:"span_id"`
+	Attributes map[string]any `json:"attributes,omitempty"`
+}
+
+type snapshotStatus struct {
+	Code        string `json:"code"`
+	Description string `json:"description,omitempty"`
+}
+
+// AssertGolden compares the spans ended in sr with the golden file at path, usually under testdata, after normalizing
+// them: the trace and span IDs are replaced by their order of appearance, the timestamps are left out, and the values
+// of the ignored attributes, such as URLs holding the ephemeral port of an httptest server, are replaced by IGNORED.
+// The spans are written tree by tree, depth first, the spans of a node in the order they started. With the -update
+// flag, the golden file is written instead.
+func AssertGolden(t testing.TB, sr *tracetest.SpanRecorder, path string, ignore ...attribute.Key) bool {
+	t.Helper()
+
+	got, err := Snapshot(sr.Ended(), ignore...)
+	if err != nil {
+		t.Fatalf("failed to snapshot the spans: %v", err)
+	}
+
+	if *update {
+		if err := os.MkdirAll(filepath.Dir(path), 0o755); err != nil {
+			t.Fatal(err)
+		}
+		if err := os.WriteFile(path, got, 0o644); err != nil {
+			t.Fatal(err)
+		}
+		return true
+	}
+
+	want, err := os.ReadFile(path)
+	if err != nil {
+		t.Fatalf("failed to read the golden file, run the test with -update to create it: %v", err)
+	}
+	if !bytes.Equal(got, want) {
+		t.Errorf("spans differ from %s, run the test with -update if the change is expected:\n%s", path, diff(string(want), string(got)))
+		return false
+	}
+	return true
+}
+
+// Snapshot returns the normalized JSON form of the spans used by AssertGolden.
+func Snapshot(spans []traceSdk.ReadOnlySpan, ignore ...attribute.Key) ([]byte, error) {
+	ignored := make(map[attribute.Key]bool, len(ignore))
+	for _, key := range ignore {
+		ignored[key] = true
+	}
+
+	ids := newIDs()
+	var out []snapshotSpan
+	tree := newTree(spans)
+	var walk func(spans []traceSdk.ReadOnlySpan)
+	walk = func(spans []traceSdk.ReadOnlySpan) {
+		for _, s := range spans {
+			out = append(out, snapshot(s, ids, ignored))
+			walk(tree.children[s.SpanContext().SpanID()])
+		}
+	}
+	walk(tree.roots)
+
+	var b bytes.Buffer
+	enc := json.NewEncoder(&b)
+	enc.SetEscapeHTML(false)
+	enc.SetIndent("", "  ")
+	if err := enc.Encode(out); err != nil {
+		return nil, err
+	}
+	return b.Bytes(), nil
+}
+
+// snapshot returns the normalized form of s
+func snapshot(s traceSdk.ReadOnlySpan, ids *ids, ignored map[attribute.Key]bool) snapshotSpan {
+	out := snapshotSpan{
+		Name:         s.Name(),
+		Kind:         s.SpanKind().String(),
+		Scope:        s.InstrumentationScope().Name,
+		TraceID:      ids.trace(s.SpanContext().TraceID()),
+		SpanID:       ids.span(s.SpanContext().SpanID()),
+		RemoteParent: s.Parent().IsRemote(),
+		Attributes:   attributes(s.Attributes(), ignored),
+		Status:       snapshotStatus{Code: s.Status().Code.String(), Description: s.Status().Description},
+	}
+	if s.Parent().IsValid() {
+		out.ParentSpanID = ids.span(s.Parent().SpanID())
+	}
+	for _, e := range s.Events() {
+		out.Events = append(out.Events, snapshotEvent{Name: e.Name, Attributes: attributes(e.Attributes, ignored)})
+	}
+	for _, l := range s.Links() {
+		out.Links = append(out.Links, snapshotLink{
+			TraceID:    ids.trace(l.SpanContext.TraceID()),
+			SpanID:     ids.span(l.SpanContext.SpanID()),
+			Attributes: attributes(l.Attributes, ignored),
+		})
+	}
+	return out
+}
+
+// attributes returns the attributes as a map, the values of the ignored ones replaced by IGNORED
+func attributes(kvs []attribute.KeyValue, ignored map[attribute.Key]bool) map[string]any {
+	if len(kvs) == 0 {
+		return nil
+	}
+	m := make(map[string]any, len(kvs))
+	for _, kv := range kvs {
+		if ignored[kv.Key] {
+			m[string(kv.Key)] = IGNORED
+			continue
+		}
+		m[string(kv.Key)] = kv.Value.AsInterface()
+	}
+	return m
+}
+
+// ids replaces the trace and span IDs by their order of appearance
+type ids struct {
+	traces map[trace.TraceID]string
+	spans  map[trace.SpanID]string
+}
+
+func newIDs() *ids {
+	return &ids{traces: make(map[trace.TraceID]string), spans: make(map[trace.SpanID]string)}
+}
+
+func (i *ids) trace(id trace.TraceID) string {
+	if _, ok := i.traces[id]; !ok {
+		i.traces[id] = fmt.Sprintf("trace-%d", len(i.traces)+1)
+	}
+	return i.traces[id]
+}
+
+func (i *ids) span(id trace.SpanID) string {
+	if _, ok := i.spans[id]; !ok {
+		i.spans[id] = fmt.Sprintf("span-%d", len(i.spans)+1)
+	}
+	return i.spans[id]
+}
+
+// diff returns the first line differing between want and got, with its line number
+func diff(want, got string) string {
+	wantLines, gotLines := strings.Split(want, "\n"), strings.Split(got, "\n")
+	for i := 0; i < len(wantLines) || i < len(gotLines); i++ {
+		var w, g string
+		if i < len(wantLines) {
+			w = wantLines[i]
+		}
+		if i < len(gotLines) {
+			g = gotLines[i]
+		}
+		if w != g {
+			return fmt.Sprintf("line %d:\n\twant: %s\n\tgot:  %s", i+1, strings.TrimSpace(w), strings.TrimSpace(g))
+		}
+	}
+	return ""
+}
@@ -0,0 +1,95 @@
+package tracingtest
+
+import (
+	"fmt"
+
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/codes"
+	traceSdk "go.opentelemetry.io/otel/sdk/trace"
+	"go.opentelemetry.io/otel/trace"
+)
+
+// Matcher checks a property of a span, returning an error describing the mismatch.
+type Matcher func(s traceSdk.ReadOnlySpan) error
+
+// HasKind matches the spans of the given kind.
+func HasKind(kind trace.SpanKind) Matcher {
+	return func(s traceSdk.ReadOnlySpan) error {
+		if s.SpanKind() != kind {
+			return fmt.Errorf("kind = %s, want %s", s.SpanKind(), kind)
+		}
+		return nil
+	}
+}
+
+// HasAttributes matches the spans holding all the given attributes, with the same values. The other attributes of
+// the span are ignored.
+func HasAttributes(kvs ...attribute.KeyValue) Matcher {
+	return func(s traceSdk.ReadOnlySpan) error {
+		return matchAttributes("attribute", s.Attributes(), kvs)
+	}
+}
+
+// HasAttributeKeys matches the spans holding attributes with all the given keys, whatever their values, e.g. for
+// the IDs and timings which change from one run to the next.
+func HasAttributeKeys(keys ...attribute.Key) Matcher {
+	return func(s traceSdk.ReadOnlySpan) error {
+		set := attribute.NewSet(s.Attributes()...)
+		for _, key := range keys {
+			if !set.HasValue(key) {
+				return fmt.Errorf("attribute %s missing", key)
+			}
+		}
+		return nil
+	}
+}
+
+// HasEvent matches the spans holding an event with the given name and attributes, the other attributes of the event
+// being ignored.
+func HasEvent(name string, kvs ...attribute.KeyValue) Matcher {
+	return func(s traceSdk.ReadOnlySpan) error {
+		var names []string
+		for _, e := range s.Events() {
+			if e.Name == name && matchAttributes("event attribute", e.Attributes, kvs) == nil {
+				return nil
+			}
+			names = append(names, e.Name)
+		}
+		return fmt.Errorf("no event %q with attributes %v, events are %q", name, kvs, names)
+	}
+}
+
+// HasStatus matches the spans with the given status code, whatever its description.
+func HasStatus(code codes.Code) Matcher {
+	return func(s traceSdk.ReadOnlySpan) error {
+		if s.Status().Code != code {
+			return fmt.Errorf("status = %s, want %s", s.Status().Code, code)
+		}
+		return nil
+	}
+}
+
+// HasRemoteParent matches the spans whose parent was extracted from a carrier, such as the spans of the servers.
+func HasRemoteParent() Matcher {
+	return func(s traceSdk.ReadOnlySpan) error {
+		if !s.Parent().IsRemote() {
+			return fmt.Errorf("parent is not remote")
+		}
+		return nil
+	}
+}
+
+// matchAttributes returns an error if one of want is missing from got, or has another value
+func matchAttributes(what string, got, want []attribute.KeyValue) error {
+	set := attribute.NewSet(got...)
+	for _, kv := range want {
+		v, ok := set.Value(kv.Key)
+		if !ok {
+			return fmt.Errorf("%s %s missing", what, kv.Key)
+		}
+		if v != kv.Value {
+			return fmt.Errorf("%s %s = %s, want %s", what, kv.Key, v.Emit(), kv.Value.Emit())
+		}
+	}
+	return nil
+}
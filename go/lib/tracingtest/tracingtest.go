@@ -0,0 +1,167 @@
+// Package tracingtest asserts the shape of the traces recorded by a tracetest.SpanRecorder. Rather than checking the
+// spans one by one, a test describes the trace it expects as a tree of spans, each with the properties it must have:
+//
+//	tracingtest.AssertSpanTree(t, sr, tracingtest.Span{
+//		Name: "say-hello",
+//		Children: []tracingtest.Span{
+//			{Name: "formatString", Matchers: []tracingtest.Matcher{tracingtest.HasKind(trace.SpanKindClient)}},
+//			{Name: "printHello"},
+//		},
+//	})
+package tracingtest
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+	"testing"
+
+	traceSdk "go.opentelemetry.io/otel/sdk/trace"
+	"go.opentelemetry.io/otel/sdk/trace/tracetest"
+	"go.opentelemetry.io/otel/trace"
+)
+
+// Span describes a span expected in a trace.
+type Span struct {
+	// Name is the name of the span
+	Name string
+	// Matchers are the properties the span must have
+	Matchers []Matcher
+	// Children are the children the span must have, in any order. A span without Children must have no child.
+	Children []Span
+}
+
+// NewRecorder returns a TracerProvider whose spans are recorded by the returned SpanRecorder as soon as they end.
+func NewRecorder() (*traceSdk.TracerProvider, *tracetest.SpanRecorder) {
+	sr := tracetest.NewSpanRecorder()
+	return traceSdk.NewTracerProvider(traceSdk.WithSpanProcessor(sr)), sr
+}
+
+// AssertSpanTree checks that the spans ended in sr hold a trace of the shape of want: a root span, whose parent is
+// not among the recorded spans, matching want, and whose children match the children of want, recursively. The other
+// traces of sr are ignored. Every mismatch is reported with the path of the span, along with the recorded spans.
+func AssertSpanTree(t testing.TB, sr *tracetest.SpanRecorder, want Span) bool {
+	t.Helper()
+
+	spans := sr.Ended()
+	tree := newTree(spans)
+	if errs := tree.match(tree.roots, want, nil); len(errs) > 0 {
+		t.Errorf("unexpected trace:\n\t%s\nrecorded spans:\n%s", strings.Join(errs, "\n\t"), tree)
+		return false
+	}
+	return true
+}
+
+// tree indexes the recorded spans by parent
+type tree struct {
+	roots    []traceSdk.ReadOnlySpan
+	children map[trace.SpanID][]traceSdk.ReadOnlySpan
+}
+
+// newTree returns the tree of the spans, the spans of a node sorted by start time
+func newTree(spans []traceSdk.ReadOnlySpan) *tree {
+	recorded := make(map[trace.SpanID]bool, len(spans))
+	for _, s := range spans {
+		recorded[s.SpanContext().SpanID()] = true
+	}
+
+	t := &tree{children: make(map[trace.SpanID][]traceSdk.ReadOnlySpan)}
+	for _, s := range spans {
+		if parent := s.Parent().SpanID(); s.Parent().IsValid() && recorded[parent] {
+			t.children[parent] = append(t.children[parent], s)
+		} else {
+			t.roots = append(t.roots, s)
+		}
+	}
+
+	byStart := func(spans []traceSdk.ReadOnlySpan) {
+		sort.SliceStable(spans, func(i, j int) bool { return spans[i].StartTime().Before(spans[j].StartTime()) })
+	}
+	byStart(t.roots)
+	for _, c := range t.children {
+		byStart(c)
+	}
+	return t
+}
+
+// match returns the mismatches of the best candidate span for want: none when a candidate matches, the mismatches of
+// the first candidate named after want otherwise
+func (t *tree) match(candidates []traceSdk.ReadOnlySpan, want Span, path []string) []string {
+	path = append(path, want.Name)
+
+	var first []string
+	found := false
+	for _, s := range candidates {
+		if s.Name() != want.Name {
+			continue
+		}
+		errs := t.matchSpan(s, want, path)
+		if len(errs) == 0 {
+			return nil
+		}
+		if !found {
+			first, found = errs, true
+		}
+	}
+	if !found {
+		return []string{fmt.Sprintf("%s: no such span", strings.Join(path, " > "))}
+	}
+	return first
+}
+
+// matchSpan returns the mismatches of s, and of its children, with want
+func (t *tree) matchSpan(s traceSdk.ReadOnlySpan, want Span, path []string) []string {
+	var errs []string
+	for _, m := range want.Matchers {
+		if err := m(s); err != nil {
+			errs = append(errs, fmt.Sprintf("%s: %v", strings.Join(path, " > "), err))
+		}
+	}
+
+	// matching every expected child with a distinct child of the span
+	children := append([]traceSdk.ReadOnlySpan(nil), t.children[s.SpanContext().SpanID()]...)
+	for _, w := range want.Children {
+		errs = append(errs, t.match(children, w, path)...)
+		children = t.take(children, w, path)
+	}
+	for _, c := range children {
+		errs = append(errs, fmt.Sprintf("%s: unexpected child %q", strings.Join(path, " > "), c.Name()))
+	}
+	return errs
+}
+
+// take removes from spans the span matched with want: the first one matching it, or else the first one named after it
+func (t *tree) take(spans []traceSdk.ReadOnlySpan, want Span, path []string) []traceSdk.ReadOnlySpan {
+	path = append(path, want.Name)
+	taken := -1
+	for i, s := range spans {
+		if s.Name() != want.Name {
+			continue
+		}
+		if len(t.matchSpan(s, want, path)) == 0 {
+			taken = i
+			break
+		}
+		if taken < 0 {
+			taken = i
+		}
+	}
+	if taken < 0 {
+		return spans
+	}
+	return append(spans[:taken], spans[taken+1:]...)
+}
+
+// String renders the recorded spans as indented trees, one line per span
+func (t *tree) String() string {
+	var b strings.Builder
+	var write func(spans []traceSdk.ReadOnlySpan, depth int)
+	write = func(spans []traceSdk.ReadOnlySpan, depth int) {
+		for _, s := range spans {
+			fmt.Fprintf(&b, "\t%s%s (%s, %s)\n", strings.Repeat("  ", depth), s.Name(), s.SpanKind(), s.Status().Code)
+			write(t.children[s.SpanContext().SpanID()], depth+1)
+		}
+	}
+	write(t.roots, 0)
+	return b.String()
+}
@@ -0,0 +1,161 @@
+package tracingtest
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"testing"
+
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/codes"
+	"go.opentelemetry.io/otel/sdk/trace/tracetest"
+	"go.opentelemetry.io/otel/trace"
+)
+
+// recordingT records the failures reported through it instead of failing the test
+type recordingT struct {
+	testing.TB
+	errors []string
+}
+
+func (t *recordingT) Helper() {}
+
+func (t *recordingT) Errorf(format string, args ...any) {
+	t.errors = append(t.errors, fmt.Sprintf(format, args...))
+}
+
+// recordHello records the spans of a greeting, as the client, the formatter and the publisher create them
+func recordHello() *tracetest.SpanRecorder {
+	tp, sr := NewRecorder()
+	tracer := tp.Tracer("test")
+
+	ctx, root := tracer.Start(context.Background(), "say-hello", trace.WithAttributes(attribute.String("hello-to", "Alice")))
+	for _, names := range [][2]string{{"formatString", "format"}, {"printHello", "publish"}} {
+		ctx, client := tracer.Start(ctx, names[0], trace.WithSpanKind(trace.SpanKindClient))
+		_, server := tracer.Start(ctx, names[1], trace.WithSpanKind(trace.SpanKindServer))
+		server.AddEvent("event", trace.WithAttributes(attribute.String("string-format", "Hello, Alice!")))
+		server.End()
+		client.End()
+	}
+	root.SetStatus(codes.Ok, "")
+	root.End()
+
+	// a span of another trace, which the assertions must ignore
+	_, other := tracer.Start(context.Background(), "other")
+	other.End()
+
+	return sr
+}
+
+// helloTree is the tree of the spans recorded by recordHello
+func helloTree() Span {
+	return Span{
+		Name:     "say-hello",
+		Matchers: []Matcher{HasAttributes(attribute.String("hello-to", "Alice")), HasStatus(codes.Ok)},
+		Children: []Span{
+			{
+				Name:     "printHello",
+				Matchers: []Matcher{HasKind(trace.SpanKindClient)},
+				Children: []Span{{Name: "publish"}},
+			},
+			{
+				Name:     "formatString",
+				Matchers: []Matcher{HasKind(trace.SpanKindClient)},
+				Children: []Span{{
+					Name:     "format",
+					Matchers: []Matcher{HasKind(trace.SpanKindServer), HasEvent("event", attribute.String("string-format", "Hello, Alice!"))},
+				}},
+			},
+		},
+	}
+}
+
+func TestAssertSpanTree(t *testing.T) {
+	sr := recordHello()
+	rt := &recordingT{TB: t}
+	if !AssertSpanTree(rt, sr, helloTree()) || len(rt.errors) > 0 {
+		t.Errorf("AssertSpanTree() failed: %v", rt.errors)
+	}
+}
+
+func TestAssertSpanTreeMismatch(t *testing.T) {
+	sr := recordHello()
+
+	for _, tc := range []struct {
+		name   string
+		modify func(want *Span)
+		report string
+	}{
+		{
+			name:   "attribute",
+			modify: func(want *Span) { want.Matchers[0] = HasAttributes(attribute.String("hello-to", "Bob")) },
+			report: "say-hello: attribute hello-to = Alice, want Bob",
+		},
+		{
+			name:   "kind",
+			modify: func(want *Span) { want.Children[0].Matchers[0] = HasKind(trace.SpanKindServer) },
+			report: "say-hello > printHello: kind = client, want server",
+		},
+		{
+			name:   "missing child",
+			modify: func(want *Span) { want.Children[0].Children[0].Name = "print" },
+			report: "say-hello > printHello > print: no such span",
+		},
+		{
+			name:   "unexpected child",
+			modify: func(want *Span) { want.Children[1].Children = nil },
+			report: `say-hello > formatString: unexpected child "format"`,
+		},
+		{
+			name:   "event",
+			modify: func(want *Span) { want.Children[1].Children[0].Matchers[1] = HasEvent("exception") },
+			report: `say-hello > formatString > format: no event "exception"`,
+		},
+		{
+			name:   "root",
+			modify: func(want *Span) { want.Name = "greet" },
+			report: "greet: no such span",
+		},
+	} {
+		t.Run(tc.name, func(t *testing.T) {
+			want := helloTree()
+			tc.modify(&want)
+
+			rt := &recordingT{TB: t}
+			if AssertSpanTree(rt, sr, want) {
+				t.Fatal("AssertSpanTree() = true, want false")
+			}
+			if len(rt.errors) != 1 || !strings.Contains(rt.errors[0], tc.report) {
+				t.Errorf("reported %q, want %q", rt.errors, tc.report)
+			}
+		})
+	}
+}
+
+func TestAssertGolden(t *testing.T) {
+	AssertGolden(t, recordHello(), "testdata/hello.golden.json")
+}
+
+func TestSnapshotNormalizes(t *testing.T) {
+	// the same trace recorded twice gets different IDs and timestamps, but the same snapshot
+	first, err := Snapshot(recordHello().Ended())
+	if err != nil {
+		t.Fatal(err)
+	}
+	second, err := Snapshot(recordHello().Ended())
+	if err != nil {
+		t.Fatal(err)
+	}
+	if string(first) != string(second) {
+		t.Errorf("snapshots differ:\n%s\n%s", first, second)
+	}
+
+	// an ignored attribute keeps its key, but not its value
+	ignored, err := Snapshot(recordHello().Ended(), "hello-to")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !strings.Contains(string(ignored), `"hello-to": "<ignored>"`) {
+		t.Errorf("hello-to is not ignored:\n%s", ignored)
+	}
+}